@@ -0,0 +1,70 @@
+package collector
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// apiVersionsDescriptors describes the per-application API version usage metrics
+func apiVersionsDescriptors(config CollectorConfig) map[string]*prometheus.Desc {
+	labels := []string{"instance", "replica_set", "shard"}
+
+	return map[string]*prometheus.Desc{
+		"api_version_info": prometheus.NewDesc(
+			config.metricName("mongodb_api_version_info"),
+			"API version used by a client application since startup, always 1; \"default\" means no Stable API version was requested",
+			append(labels, "app_name", "api_version"),
+			nil,
+		),
+		"api_version_apps": prometheus.NewDesc(
+			config.metricName("mongodb_api_version_apps"),
+			"Number of client applications that used the API version since startup",
+			append(labels, "api_version"),
+			nil,
+		),
+	}
+}
+
+// apiVersionUsage returns the API versions used per application name from
+// serverStatus apiVersions, which maps each appName to the versions it sent
+func apiVersionUsage(result bson.M) map[string][]string {
+	apiVersions, ok := result["apiVersions"].(bson.M)
+	if !ok {
+		return nil
+	}
+
+	usage := make(map[string][]string, len(apiVersions))
+	for appName, value := range apiVersions {
+		versions, ok := value.(bson.A)
+		if !ok {
+			continue
+		}
+		for _, v := range versions {
+			if version, ok := v.(string); ok {
+				usage[appName] = append(usage[appName], version)
+			}
+		}
+	}
+	return usage
+}
+
+// collectAPIVersions exports which applications use the Stable API, as
+// reported by MongoDB 5.0+
+func (c *ServerStatusCollector) collectAPIVersions(ch chan<- prometheus.Metric, result bson.M, instance map[string]string) {
+	usage := apiVersionUsage(result)
+	if usage == nil {
+		return
+	}
+
+	labels := []string{instance["instance"], instance["replica_set"], instance["shard"]}
+	apps := make(map[string]int)
+	for appName, versions := range usage {
+		for _, version := range versions {
+			apps[version]++
+			ch <- prometheus.MustNewConstMetric(c.descriptors["api_version_info"], prometheus.GaugeValue, 1, append(labels, appName, version)...)
+		}
+	}
+	for version, count := range apps {
+		ch <- prometheus.MustNewConstMetric(c.descriptors["api_version_apps"], prometheus.GaugeValue, float64(count), append(labels, version)...)
+	}
+}
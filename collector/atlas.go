@@ -0,0 +1,280 @@
+package collector
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// defaultAtlasProcessMeasurements are the host hardware measurements read per process
+var defaultAtlasProcessMeasurements = []string{
+	"SYSTEM_NORMALIZED_CPU_USER",
+	"SYSTEM_NORMALIZED_CPU_KERNEL",
+	"SYSTEM_NORMALIZED_CPU_IOWAIT",
+	"SYSTEM_MEMORY_USED",
+	"SYSTEM_MEMORY_AVAILABLE",
+	"SYSTEM_NETWORK_IN",
+	"SYSTEM_NETWORK_OUT",
+}
+
+// defaultAtlasDiskMeasurements are the measurements read per disk partition
+var defaultAtlasDiskMeasurements = []string{
+	"DISK_PARTITION_IOPS_READ",
+	"DISK_PARTITION_IOPS_WRITE",
+	"DISK_PARTITION_LATENCY_READ",
+	"DISK_PARTITION_LATENCY_WRITE",
+	"DISK_PARTITION_SPACE_PERCENT_USED",
+}
+
+// atlasProcess is one mongod or mongos of the project as listed by the Atlas API
+type atlasProcess struct {
+	ID             string `json:"id"`
+	Hostname       string `json:"hostname"`
+	TypeName       string `json:"typeName"`
+	ReplicaSetName string `json:"replicaSetName"`
+	UserAlias      string `json:"userAlias"`
+}
+
+// atlasMeasurements is the response of the process and disk measurement endpoints
+type atlasMeasurements struct {
+	Measurements []struct {
+		Name       string `json:"name"`
+		Units      string `json:"units"`
+		DataPoints []struct {
+			Value *float64 `json:"value"`
+		} `json:"dataPoints"`
+	} `json:"measurements"`
+}
+
+// atlasAlert is an open alert of the project
+type atlasAlert struct {
+	EventTypeName string `json:"eventTypeName"`
+	ClusterName   string `json:"clusterName"`
+}
+
+// AtlasCollector reads hardware measurements and open alerts from the Atlas
+// Administration API, for Atlas tiers where serverStatus is restricted
+type AtlasCollector struct {
+	*BaseCollector
+	descriptors         map[string]*prometheus.Desc
+	api                 *atlasClient
+	projectID           string
+	cluster             string
+	processMeasurements []string
+	diskMeasurements    []string
+}
+
+func NewAtlasCollector(client *mongo.Client, logger *zap.Logger, config CollectorConfig) *AtlasCollector {
+	labels := []string{"instance", "replica_set", "shard"}
+
+	descriptors := map[string]*prometheus.Desc{
+		"atlas_process_measurement": prometheus.NewDesc(
+			config.metricName("mongodb_atlas_process_measurement"),
+			"Latest Atlas measurement of the process host, in the unit given by the units label",
+			append(labels, "process_type", "measurement", "units"),
+			nil,
+		),
+		"atlas_disk_measurement": prometheus.NewDesc(
+			config.metricName("mongodb_atlas_disk_measurement"),
+			"Latest Atlas measurement of a disk partition of the process host, in the unit given by the units label",
+			append(labels, "partition", "measurement", "units"),
+			nil,
+		),
+		"atlas_open_alerts": prometheus.NewDesc(
+			config.metricName("mongodb_atlas_open_alerts"),
+			"Number of open Atlas alerts by cluster and event type",
+			[]string{"cluster", "event_type"},
+			nil,
+		),
+	}
+
+	options := collectorOptions(config, "atlas")
+	collector := &AtlasCollector{
+		BaseCollector:       NewBaseCollector(client, logger, config),
+		descriptors:         descriptors,
+		projectID:           getStringOption(options, "project_id", ""),
+		cluster:             getStringOption(options, "cluster", ""),
+		processMeasurements: getStringSliceOption(options, "process_measurements"),
+		diskMeasurements:    getStringSliceOption(options, "disk_measurements"),
+	}
+	if len(collector.processMeasurements) == 0 {
+		collector.processMeasurements = defaultAtlasProcessMeasurements
+	}
+	if len(collector.diskMeasurements) == 0 {
+		collector.diskMeasurements = defaultAtlasDiskMeasurements
+	}
+
+	publicKey := getStringOption(options, "public_key", "")
+	privateKey := getStringOption(options, "private_key", "")
+	if publicKey != "" && privateKey != "" && collector.projectID != "" {
+		collector.api = newAtlasClient(getStringOption(options, "base_url", ""), publicKey, privateKey)
+	}
+	return collector
+}
+
+func (c *AtlasCollector) Collect(ch chan<- prometheus.Metric) {
+	if !c.isMetricExplicitlyEnabled("atlas") {
+		return
+	}
+	if c.api == nil {
+		c.logger.Debug("No Atlas API key or project configured, skipping Atlas metrics")
+		return
+	}
+
+	ctx, done := c.collectContext("atlas", 30*time.Second)
+	defer done()
+
+	processes, err := c.listProcesses(ctx)
+	if err != nil {
+		c.logger.Error("Failed to list Atlas processes", zap.Error(err))
+	}
+	for _, process := range processes {
+		c.collectProcess(ctx, ch, process)
+	}
+
+	c.collectOpenAlerts(ctx, ch)
+}
+
+// listProcesses returns the project's processes that belong to the configured cluster
+func (c *AtlasCollector) listProcesses(ctx context.Context) ([]atlasProcess, error) {
+	var response struct {
+		Results []atlasProcess `json:"results"`
+	}
+	if err := c.api.get(ctx, c.projectPath("/processes"), url.Values{"itemsPerPage": {"500"}}, &response); err != nil {
+		return nil, err
+	}
+
+	processes := make([]atlasProcess, 0, len(response.Results))
+	for _, process := range response.Results {
+		if atlasProcessInCluster(process, c.cluster) {
+			processes = append(processes, process)
+		}
+	}
+	return processes, nil
+}
+
+// atlasProcessInCluster reports whether process belongs to cluster. Atlas has
+// no cluster field on processes, but names every host after its cluster, as in
+// cluster0-shard-00-00.abcde.mongodb.net
+func atlasProcessInCluster(process atlasProcess, cluster string) bool {
+	if cluster == "" {
+		return true
+	}
+	host := process.UserAlias
+	if host == "" {
+		host = process.Hostname
+	}
+	return strings.HasPrefix(strings.ToLower(host), strings.ToLower(cluster)+"-")
+}
+
+func (c *AtlasCollector) collectProcess(ctx context.Context, ch chan<- prometheus.Metric, process atlasProcess) {
+	labels := []string{process.ID, process.ReplicaSetName, "unknown"}
+	processPath := c.projectPath("/processes/" + url.PathEscape(process.ID))
+
+	measurements, err := c.measurements(ctx, processPath+"/measurements", c.processMeasurements)
+	if err != nil {
+		c.logger.Warn("Failed to read Atlas process measurements", zap.String("process", process.ID), zap.Error(err))
+	} else {
+		emitAtlasMeasurements(ch, c.descriptors["atlas_process_measurement"], measurements, append(labels, process.TypeName))
+	}
+
+	var disks struct {
+		Results []struct {
+			PartitionName string `json:"partitionName"`
+		} `json:"results"`
+	}
+	if err := c.api.get(ctx, processPath+"/disks", nil, &disks); err != nil {
+		c.logger.Warn("Failed to list Atlas disk partitions", zap.String("process", process.ID), zap.Error(err))
+		return
+	}
+	for _, disk := range disks.Results {
+		measurements, err := c.measurements(ctx, processPath+"/disks/"+url.PathEscape(disk.PartitionName)+"/measurements", c.diskMeasurements)
+		if err != nil {
+			c.logger.Warn("Failed to read Atlas disk measurements",
+				zap.String("process", process.ID),
+				zap.String("partition", disk.PartitionName),
+				zap.Error(err))
+			continue
+		}
+		emitAtlasMeasurements(ch, c.descriptors["atlas_disk_measurement"], measurements, append(labels, disk.PartitionName))
+	}
+}
+
+// measurements reads the last five minutes of the named measurements at one minute granularity
+func (c *AtlasCollector) measurements(ctx context.Context, path string, names []string) (atlasMeasurements, error) {
+	query := url.Values{
+		"granularity": {"PT1M"},
+		"period":      {"PT5M"},
+		"m":           names,
+	}
+	var result atlasMeasurements
+	err := c.api.get(ctx, path, query, &result)
+	return result, err
+}
+
+// emitAtlasMeasurements exports the latest data point of each measurement.
+// Atlas reports null for intervals it has no data for yet, so the newest
+// non-null point is used and measurements without one are skipped
+func emitAtlasMeasurements(ch chan<- prometheus.Metric, desc *prometheus.Desc, result atlasMeasurements, labels []string) {
+	for _, measurement := range result.Measurements {
+		for i := len(measurement.DataPoints) - 1; i >= 0; i-- {
+			if value := measurement.DataPoints[i].Value; value != nil {
+				ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, *value, append(labels, measurement.Name, measurement.Units)...)
+				break
+			}
+		}
+	}
+}
+
+func (c *AtlasCollector) collectOpenAlerts(ctx context.Context, ch chan<- prometheus.Metric) {
+	var response struct {
+		Results []atlasAlert `json:"results"`
+	}
+	if err := c.api.get(ctx, c.projectPath("/alerts"), url.Values{"status": {"OPEN"}, "itemsPerPage": {"500"}}, &response); err != nil {
+		c.logger.Error("Failed to list open Atlas alerts", zap.Error(err))
+		return
+	}
+
+	for key, count := range countAtlasAlerts(response.Results, c.cluster) {
+		ch <- prometheus.MustNewConstMetric(c.descriptors["atlas_open_alerts"], prometheus.GaugeValue, float64(count), key.cluster, key.eventType)
+	}
+}
+
+// atlasAlertKey identifies an open alerts series
+type atlasAlertKey struct {
+	cluster   string
+	eventType string
+}
+
+// countAtlasAlerts counts alerts by cluster and event type. With a cluster
+// configured, alerts of other clusters are dropped; project-level alerts,
+// which have no cluster, are always kept
+func countAtlasAlerts(alerts []atlasAlert, cluster string) map[atlasAlertKey]int {
+	counts := make(map[atlasAlertKey]int)
+	for _, alert := range alerts {
+		if cluster != "" && alert.ClusterName != "" && alert.ClusterName != cluster {
+			continue
+		}
+		counts[atlasAlertKey{cluster: alert.ClusterName, eventType: alert.EventTypeName}]++
+	}
+	return counts
+}
+
+func (c *AtlasCollector) projectPath(path string) string {
+	return "/api/atlas/v2/groups/" + url.PathEscape(c.projectID) + path
+}
+
+func (c *AtlasCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range c.descriptors {
+		ch <- desc
+	}
+}
+
+func (c *AtlasCollector) Name() string {
+	return "atlas"
+}
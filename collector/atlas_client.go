@@ -0,0 +1,222 @@
+package collector
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// defaultAtlasBaseURL is the Atlas Administration API host
+const defaultAtlasBaseURL = "https://cloud.mongodb.com"
+
+// atlasAPIAccept selects the versioned Atlas Administration API v2 resource representation
+const atlasAPIAccept = "application/vnd.atlas.2023-01-01+json"
+
+// opsManagerAPIAccept is the media type of the Ops Manager and Cloud Manager API v1.0
+const opsManagerAPIAccept = "application/json"
+
+// atlasClient calls the Atlas Administration API, or the Ops Manager and Cloud
+// Manager API that shares its authentication, with a programmatic API key.
+// API keys authenticate with HTTP Digest; the last challenge is reused for
+// later requests so each call normally needs a single round trip
+type atlasClient struct {
+	httpClient *http.Client
+	baseURL    string
+	accept     string
+	publicKey  string
+	privateKey string
+
+	mu        sync.Mutex
+	challenge *digestChallenge
+	nonceUses int
+}
+
+func newAtlasClient(baseURL, publicKey, privateKey string) *atlasClient {
+	if baseURL == "" {
+		baseURL = defaultAtlasBaseURL
+	}
+	return &atlasClient{
+		httpClient: &http.Client{},
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		accept:     atlasAPIAccept,
+		publicKey:  publicKey,
+		privateKey: privateKey,
+	}
+}
+
+// newOpsManagerClient returns a client for the Ops Manager or Cloud Manager API at baseURL
+func newOpsManagerClient(baseURL, publicKey, privateKey string) *atlasClient {
+	client := newAtlasClient(baseURL, publicKey, privateKey)
+	client.accept = opsManagerAPIAccept
+	return client
+}
+
+// get fetches path with query and decodes the JSON response into result
+func (c *atlasClient) get(ctx context.Context, path string, query url.Values, result interface{}) error {
+	target := c.baseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	resp, err := c.do(ctx, target, false)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		// The cached nonce expired or none was cached yet: answer the new challenge once
+		resp.Body.Close()
+		if err := c.setChallenge(resp.Header.Get("WWW-Authenticate")); err != nil {
+			return err
+		}
+		if resp, err = c.do(ctx, target, true); err != nil {
+			return err
+		}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("API %s returned %s: %s", path, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+func (c *atlasClient) do(ctx context.Context, target string, required bool) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", c.accept)
+
+	authorization, err := c.authorization(req.Method, req.URL.RequestURI())
+	if err != nil {
+		return nil, err
+	}
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	} else if required {
+		return nil, fmt.Errorf("API did not send a digest challenge")
+	}
+	return c.httpClient.Do(req)
+}
+
+func (c *atlasClient) setChallenge(header string) error {
+	challenge, err := parseDigestChallenge(header)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.challenge = challenge
+	c.nonceUses = 0
+	return nil
+}
+
+// authorization answers the cached challenge for one request, or returns ""
+// when no challenge was received yet
+func (c *atlasClient) authorization(method, uri string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.challenge == nil {
+		return "", nil
+	}
+	c.nonceUses++
+	return c.challenge.answer(c.publicKey, c.privateKey, method, uri, c.nonceUses)
+}
+
+// digestChallenge is a parsed WWW-Authenticate Digest header (RFC 7616, MD5 with qop=auth)
+type digestChallenge struct {
+	realm  string
+	nonce  string
+	opaque string
+	qop    string
+}
+
+func parseDigestChallenge(header string) (*digestChallenge, error) {
+	scheme, params, _ := strings.Cut(header, " ")
+	if !strings.EqualFold(scheme, "Digest") {
+		return nil, fmt.Errorf("API sent an unsupported authentication challenge %q", scheme)
+	}
+
+	values := make(map[string]string)
+	for _, param := range splitDigestParams(params) {
+		key, value, ok := strings.Cut(param, "=")
+		if !ok {
+			continue
+		}
+		values[strings.ToLower(strings.TrimSpace(key))] = strings.Trim(strings.TrimSpace(value), `"`)
+	}
+
+	if algorithm := values["algorithm"]; algorithm != "" && !strings.EqualFold(algorithm, "MD5") {
+		return nil, fmt.Errorf("API requested unsupported digest algorithm %q", algorithm)
+	}
+	if values["nonce"] == "" {
+		return nil, fmt.Errorf("API digest challenge has no nonce")
+	}
+
+	challenge := &digestChallenge{realm: values["realm"], nonce: values["nonce"], opaque: values["opaque"]}
+	for _, qop := range strings.Split(values["qop"], ",") {
+		if strings.TrimSpace(qop) == "auth" {
+			challenge.qop = "auth"
+		}
+	}
+	return challenge, nil
+}
+
+// splitDigestParams splits challenge parameters on commas outside quoted values
+func splitDigestParams(params string) []string {
+	var parts []string
+	var current strings.Builder
+	quoted := false
+	for _, r := range params {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			current.WriteRune(r)
+		case r == ',' && !quoted:
+			parts = append(parts, current.String())
+			current.Reset()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	return append(parts, current.String())
+}
+
+func (d *digestChallenge) answer(username, password, method, uri string, nc int) (string, error) {
+	ha1 := md5Hex(username + ":" + d.realm + ":" + password)
+	ha2 := md5Hex(method + ":" + uri)
+
+	header := fmt.Sprintf(`Digest username="%s", realm="%s", nonce="%s", uri="%s", algorithm=MD5`, username, d.realm, d.nonce, uri)
+	if d.qop == "" {
+		header += fmt.Sprintf(`, response="%s"`, md5Hex(ha1+":"+d.nonce+":"+ha2))
+	} else {
+		cnonceBytes := make([]byte, 8)
+		if _, err := rand.Read(cnonceBytes); err != nil {
+			return "", err
+		}
+		cnonce := hex.EncodeToString(cnonceBytes)
+		count := fmt.Sprintf("%08x", nc)
+		response := md5Hex(ha1 + ":" + d.nonce + ":" + count + ":" + cnonce + ":" + d.qop + ":" + ha2)
+		header += fmt.Sprintf(`, qop=%s, nc=%s, cnonce="%s", response="%s"`, d.qop, count, cnonce, response)
+	}
+	if d.opaque != "" {
+		header += fmt.Sprintf(`, opaque="%s"`, d.opaque)
+	}
+	return header, nil
+}
+
+func md5Hex(s string) string {
+	sum := md5.Sum([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
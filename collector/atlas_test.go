@@ -0,0 +1,144 @@
+package collector
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// atlasTestServer serves canned Atlas API responses behind a digest challenge
+// and verifies every digest answer against the expected credentials
+func atlasTestServer(t *testing.T, accept string, responses map[string]string) *httptest.Server {
+	const nonce = "abc123"
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization := r.Header.Get("Authorization")
+		if authorization == "" {
+			w.Header().Set("WWW-Authenticate", `Digest realm="MMS Public API", domain="", nonce="`+nonce+`", algorithm=MD5, qop="auth", stale=false`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		params := make(map[string]string)
+		for _, param := range splitDigestParams(strings.TrimPrefix(authorization, "Digest ")) {
+			key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			params[key] = strings.Trim(value, `"`)
+		}
+		ha1 := md5Hex("public:MMS Public API:private")
+		ha2 := md5Hex(r.Method + ":" + r.URL.RequestURI())
+		expected := md5Hex(ha1 + ":" + nonce + ":" + params["nc"] + ":" + params["cnonce"] + ":auth:" + ha2)
+		if params["response"] != expected || params["uri"] != r.URL.RequestURI() {
+			t.Errorf("Digest answer for %s does not match the credentials", r.URL.RequestURI())
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Header.Get("Accept") != accept {
+			t.Errorf("Requests should ask for the versioned API, got %q", r.Header.Get("Accept"))
+		}
+
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+}
+
+func TestAtlasCollector(t *testing.T) {
+	server := atlasTestServer(t, atlasAPIAccept, map[string]string{
+		"/api/atlas/v2/groups/proj1/processes": `{"results": [
+			{"id": "cluster0-shard-00-00.ab1cd.mongodb.net:27017", "typeName": "REPLICA_PRIMARY", "replicaSetName": "atlas-x-shard-0", "userAlias": "cluster0-shard-00-00.ab1cd.mongodb.net"},
+			{"id": "other-shard-00-00.ab1cd.mongodb.net:27017", "typeName": "REPLICA_PRIMARY", "replicaSetName": "atlas-y-shard-0", "userAlias": "other-shard-00-00.ab1cd.mongodb.net"}
+		]}`,
+		"/api/atlas/v2/groups/proj1/processes/cluster0-shard-00-00.ab1cd.mongodb.net:27017/measurements": `{"measurements": [
+			{"name": "SYSTEM_NORMALIZED_CPU_USER", "units": "PERCENT", "dataPoints": [{"value": 12.5}, {"value": null}]},
+			{"name": "SYSTEM_MEMORY_USED", "units": "KILOBYTES", "dataPoints": [{"value": null}]}
+		]}`,
+		"/api/atlas/v2/groups/proj1/processes/cluster0-shard-00-00.ab1cd.mongodb.net:27017/disks": `{"results": [{"partitionName": "data"}]}`,
+		"/api/atlas/v2/groups/proj1/processes/cluster0-shard-00-00.ab1cd.mongodb.net:27017/disks/data/measurements": `{"measurements": [
+			{"name": "DISK_PARTITION_IOPS_READ", "units": "SCALAR_PER_SECOND", "dataPoints": [{"value": 40}]}
+		]}`,
+		"/api/atlas/v2/groups/proj1/alerts": `{"results": [
+			{"eventTypeName": "OUTSIDE_METRIC_THRESHOLD", "clusterName": "Cluster0"},
+			{"eventTypeName": "OUTSIDE_METRIC_THRESHOLD", "clusterName": "Cluster0"},
+			{"eventTypeName": "OUTSIDE_METRIC_THRESHOLD", "clusterName": "Other"}
+		]}`,
+	})
+	defer server.Close()
+
+	collector := NewAtlasCollector(nil, zap.NewNop(), CollectorConfig{
+		EnabledMetrics: []string{"atlas"},
+		Collectors: map[string]interface{}{"atlas": map[string]interface{}{
+			"public_key":  "public",
+			"private_key": "private",
+			"project_id":  "proj1",
+			"cluster":     "Cluster0",
+			"base_url":    server.URL,
+		}},
+	})
+
+	ch := make(chan prometheus.Metric, 20)
+	collector.Collect(ch)
+	close(ch)
+
+	names := make(map[string]int)
+	for metric := range ch {
+		names[descName(metric.Desc())]++
+	}
+	if names["mongodb_atlas_process_measurement"] != 1 {
+		t.Errorf("Only measurements with a data point of the cluster's process should be exported, got %d", names["mongodb_atlas_process_measurement"])
+	}
+	if names["mongodb_atlas_disk_measurement"] != 1 {
+		t.Errorf("Disk partition measurements should be exported, got %d", names["mongodb_atlas_disk_measurement"])
+	}
+	if names["mongodb_atlas_open_alerts"] != 1 {
+		t.Errorf("Open alerts of other clusters should be dropped, got %d series", names["mongodb_atlas_open_alerts"])
+	}
+}
+
+func TestAtlasCollectorWithoutKeys(t *testing.T) {
+	collector := NewAtlasCollector(nil, zap.NewNop(), CollectorConfig{EnabledMetrics: []string{"atlas"}})
+
+	ch := make(chan prometheus.Metric, 1)
+	collector.Collect(ch)
+	close(ch)
+	if len(ch) != 0 {
+		t.Error("Collector without an API key should export nothing")
+	}
+}
+
+func TestAtlasClientError(t *testing.T) {
+	server := atlasTestServer(t, atlasAPIAccept, nil)
+	defer server.Close()
+
+	api := newAtlasClient(server.URL, "public", "private")
+	var result struct{}
+	err := api.get(context.Background(), "/api/atlas/v2/groups/missing/processes", nil, &result)
+	if err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("Non-200 responses should be returned as errors, got %v", err)
+	}
+}
+
+func TestCountAtlasAlerts(t *testing.T) {
+	alerts := []atlasAlert{
+		{EventTypeName: "HOST_DOWN", ClusterName: "Cluster0"},
+		{EventTypeName: "USERS_WITHOUT_MULTI_FACTOR_AUTH"},
+		{EventTypeName: "HOST_DOWN", ClusterName: "Other"},
+	}
+
+	counts := countAtlasAlerts(alerts, "Cluster0")
+	if len(counts) != 2 {
+		t.Errorf("Project-level alerts should be kept alongside the cluster's, got %v", counts)
+	}
+	if counts[atlasAlertKey{cluster: "Other", eventType: "HOST_DOWN"}] != 0 {
+		t.Error("Alerts of other clusters should be dropped")
+	}
+	if len(countAtlasAlerts(alerts, "")) != 3 {
+		t.Error("Without a cluster every alert should be counted")
+	}
+}
@@ -0,0 +1,163 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.uber.org/zap"
+)
+
+// Default namespace of the canary documents
+const (
+	defaultCanaryDatabase   = "mongodb_exporter"
+	defaultCanaryCollection = "canary"
+)
+
+// canaryOperations are the steps of one canary cycle, in order
+var canaryOperations = []string{"insert", "find", "delete"}
+
+// canaryCleanupTimeout bounds the delete of a cycle, which gets a context of
+// its own because a slow find may have used up the scrape's
+const canaryCleanupTimeout = 5 * time.Second
+
+// CanaryCollector inserts, reads back and deletes a small document in a
+// dedicated collection, checking end to end that clients can write
+type CanaryCollector struct {
+	*BaseCollector
+	descriptors map[string]*prometheus.Desc
+	database    string
+	collection  string
+}
+
+func NewCanaryCollector(client *mongo.Client, logger *zap.Logger, config CollectorConfig) *CanaryCollector {
+	labels := []string{"instance", "replica_set", "shard", "operation"}
+
+	descriptors := map[string]*prometheus.Desc{
+		"canary_success": prometheus.NewDesc(
+			config.metricName("mongodb_canary_success"),
+			"Whether the canary operation succeeded (1) or failed (0); operations after a failed one are not attempted",
+			labels,
+			nil,
+		),
+		"canary_duration_seconds": prometheus.NewDesc(
+			config.metricName("mongodb_canary_duration_seconds"),
+			"Duration of the last successful canary operation",
+			labels,
+			nil,
+		),
+	}
+
+	options := collectorOptions(config, "canary")
+	return &CanaryCollector{
+		BaseCollector: NewBaseCollector(client, logger, config),
+		descriptors:   descriptors,
+		database:      getStringOption(options, "database", defaultCanaryDatabase),
+		collection:    getStringOption(options, "collection", defaultCanaryCollection),
+	}
+}
+
+func (c *CanaryCollector) Collect(ch chan<- prometheus.Metric) {
+	if !c.isMetricExplicitlyEnabled("canary") {
+		return
+	}
+
+	ctx, done := c.collectContext("canary", 10*time.Second)
+	defer done()
+
+	instance := c.getInstanceInfo(bson.M{})
+	for _, result := range c.runCycle(ctx) {
+		labels := []string{instance["instance"], instance["replica_set"], instance["shard"], result.operation}
+		success := 0.0
+		if result.err == nil {
+			success = 1
+			ch <- prometheus.MustNewConstMetric(c.descriptors["canary_duration_seconds"], prometheus.GaugeValue, result.duration.Seconds(), labels...)
+		}
+		ch <- prometheus.MustNewConstMetric(c.descriptors["canary_success"], prometheus.GaugeValue, success, labels...)
+	}
+}
+
+// canaryResult is the outcome of one canary operation
+type canaryResult struct {
+	operation string
+	duration  time.Duration
+	err       error
+}
+
+// runCycle inserts a document, reads it back from the primary and deletes it
+func (c *CanaryCollector) runCycle(ctx context.Context) []canaryResult {
+	coll := c.client.Database(c.database).Collection(c.collection, options.Collection().SetReadPreference(readpref.Primary()))
+	hostname, _ := os.Hostname()
+	id := primitive.NewObjectID()
+
+	steps := map[string]func() error{
+		"insert": func() error {
+			_, err := coll.InsertOne(ctx, bson.D{{"_id", id}, {"exporter", hostname}, {"at", time.Now()}})
+			return err
+		},
+		"find": func() error {
+			return coll.FindOne(ctx, bson.D{{"_id", id}}).Err()
+		},
+		"delete": func() error {
+			ctx, cancel := context.WithTimeout(context.Background(), canaryCleanupTimeout)
+			defer cancel()
+			result, err := coll.DeleteOne(ctx, bson.D{{"_id", id}})
+			if err == nil && result.DeletedCount != 1 {
+				err = fmt.Errorf("deleted %d canary documents, expected 1", result.DeletedCount)
+			}
+			return err
+		},
+	}
+	return c.runSteps(steps)
+}
+
+// runSteps runs the canaryOperations in order. Nothing follows a failed
+// insert, but a successful one is always deleted, so a failed find does not
+// leave documents behind
+func (c *CanaryCollector) runSteps(steps map[string]func() error) []canaryResult {
+	results := make([]canaryResult, 0, len(canaryOperations))
+	for _, operation := range canaryOperations {
+		start := time.Now()
+		err := steps[operation]()
+		results = append(results, canaryResult{operation: operation, duration: time.Since(start), err: err})
+		if err != nil {
+			c.logger.Warn("Canary operation failed",
+				zap.String("operation", operation),
+				zap.String("namespace", c.database+"."+c.collection),
+				zap.Error(err))
+			if operation == "insert" {
+				break
+			}
+		}
+	}
+	return results
+}
+
+// privilegeProbes runs one canary cycle as commands on the configured namespace,
+// each needing the readWrite role there
+func (c *CanaryCollector) privilegeProbes() []privilegeProbe {
+	id := primitive.NewObjectID()
+	hint := "readWrite role on the " + c.database + " database"
+	return []privilegeProbe{
+		{"insert " + c.collection, c.database, bson.D{{Key: "insert", Value: c.collection}, {Key: "documents", Value: bson.A{bson.D{{Key: "_id", Value: id}}}}}, hint},
+		{"find " + c.collection, c.database, bson.D{{Key: "find", Value: c.collection}, {Key: "filter", Value: bson.D{{Key: "_id", Value: id}}}}, hint},
+		{"delete " + c.collection, c.database, bson.D{{Key: "delete", Value: c.collection}, {Key: "deletes", Value: bson.A{bson.D{{Key: "q", Value: bson.D{{Key: "_id", Value: id}}}, {Key: "limit", Value: 1}}}}}, hint},
+	}
+}
+
+func (c *CanaryCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range c.descriptors {
+		ch <- desc
+	}
+}
+
+func (c *CanaryCollector) Name() string {
+	return "canary"
+}
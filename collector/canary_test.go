@@ -0,0 +1,57 @@
+package collector
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+func TestCanaryCollectorOptions(t *testing.T) {
+	collector := NewCanaryCollector(nil, zap.NewNop(), CollectorConfig{})
+	if collector.database != "mongodb_exporter" || collector.collection != "canary" {
+		t.Errorf("Expected the default canary namespace, got %s.%s", collector.database, collector.collection)
+	}
+
+	collector = NewCanaryCollector(nil, zap.NewNop(), CollectorConfig{
+		Collectors: map[string]interface{}{"canary": map[string]interface{}{"database": "monitoring", "collection": "probe"}},
+	})
+	if collector.database != "monitoring" || collector.collection != "probe" {
+		t.Errorf("Expected monitoring.probe, got %s.%s", collector.database, collector.collection)
+	}
+
+	// The canary writes, so an empty enabled list must not turn it on
+	ch := make(chan prometheus.Metric, 10)
+	collector.Collect(ch)
+	close(ch)
+	if len(ch) != 0 {
+		t.Error("Canary collector should only run when listed in enabled_metrics")
+	}
+}
+
+func TestCanaryDeletesAfterFailedFind(t *testing.T) {
+	collector := NewCanaryCollector(nil, zap.NewNop(), CollectorConfig{})
+
+	deleted := false
+	results := collector.runSteps(map[string]func() error{
+		"insert": func() error { return nil },
+		"find":   func() error { return errors.New("timed out") },
+		"delete": func() error { deleted = true; return nil },
+	})
+	if !deleted {
+		t.Error("The canary document should be deleted after a failed find")
+	}
+	if len(results) != 3 || results[1].err == nil || results[2].err != nil {
+		t.Errorf("Expected find reported as failed and delete as succeeded, got %+v", results)
+	}
+
+	results = collector.runSteps(map[string]func() error{
+		"insert": func() error { return errors.New("not primary") },
+		"find":   func() error { t.Error("find should not run after a failed insert"); return nil },
+		"delete": func() error { t.Error("delete should not run after a failed insert"); return nil },
+	})
+	if len(results) != 1 {
+		t.Errorf("Expected only the failed insert, got %+v", results)
+	}
+}
@@ -0,0 +1,340 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// capabilityRetryInterval is how long to wait before retrying a failed detection
+const capabilityRetryInterval = time.Minute
+
+// errCommandUnsupported is returned instead of running a command the server does not have
+var errCommandUnsupported = errors.New("command not supported by the server")
+
+// featureVersions are the server features collectors use that listCommands
+// cannot reveal, such as serverStatus sections and aggregation stages, with
+// the version adding them
+var featureVersions = map[string]serverVersion{
+	"mirroredReads": {4, 4},
+	"flowControl":   {4, 2},
+	"$queryStats":   {7, 1},
+}
+
+// serverVersion is the major and minor part of a MongoDB version
+type serverVersion struct {
+	major int
+	minor int
+}
+
+// parseServerVersion parses a buildInfo version like "6.0.12" or "7.0.0-rc1"
+func parseServerVersion(version string) (serverVersion, bool) {
+	parts := strings.SplitN(version, ".", 3)
+	if len(parts) < 2 {
+		return serverVersion{}, false
+	}
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return serverVersion{}, false
+	}
+	minor, err := strconv.Atoi(strings.SplitN(parts[1], "-", 2)[0])
+	if err != nil {
+		return serverVersion{}, false
+	}
+	return serverVersion{major: major, minor: minor}, true
+}
+
+func (v serverVersion) atLeast(other serverVersion) bool {
+	if v.major != other.major {
+		return v.major > other.major
+	}
+	return v.minor >= other.minor
+}
+
+func (v serverVersion) String() string {
+	return fmt.Sprintf("%d.%d", v.major, v.minor)
+}
+
+// serverCapabilities detects once which commands and features the server
+// supports, so collectors skip what does not exist on the target version
+// instead of failing and logging the same error on every scrape. Until
+// detection succeeds everything is assumed to be supported
+type serverCapabilities struct {
+	logger *zap.Logger
+
+	mu          sync.Mutex
+	flavor      string
+	ferretDB    string
+	psmdb       string
+	enterprise  bool
+	detected    bool
+	detecting   bool
+	lastAttempt time.Time
+	version     serverVersion
+	commands    map[string]bool
+	skipped     map[string]bool
+	unsupported *prometheus.GaugeVec
+}
+
+func newServerCapabilities(logger *zap.Logger) *serverCapabilities {
+	return &serverCapabilities{
+		logger:  logger,
+		skipped: make(map[string]bool),
+		unsupported: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "mongodb_exporter_unsupported_feature_info",
+			Help: "Command, feature or collector skipped because the server does not support it, always 1; reason is missing_command, server_version or target_flavor",
+		}, []string{"feature", "reason"}),
+	}
+}
+
+// ensure runs detection on first use, retrying a failed one after capabilityRetryInterval.
+// s.mu is not held during detection, so callers arriving meanwhile proceed as
+// if everything were supported instead of waiting on the server
+func (s *serverCapabilities) ensure(ctx context.Context, client *mongo.Client) {
+	s.mu.Lock()
+	if s.detected || s.detecting || client == nil || time.Since(s.lastAttempt) < capabilityRetryInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.lastAttempt = time.Now()
+	s.detecting = true
+	s.mu.Unlock()
+
+	info, commands, err := detectCapabilities(ctx, client)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.detecting = false
+	if err != nil {
+		s.logger.Warn("Failed to detect server capabilities, running every collector command", zap.Error(err))
+		return
+	}
+	s.apply(info.Version, commands)
+	if info.FerretDBVersion != "" && s.flavor != flavorDocumentDB {
+		s.ferretDB = info.FerretDBVersion
+		s.restrictFlavor(flavorFerretDB)
+	}
+	s.psmdb = info.PSMDBVersion
+	for _, module := range info.Modules {
+		if module == "enterprise" {
+			s.enterprise = true
+		}
+	}
+}
+
+// buildInfo is the part of the buildInfo reply used to detect capabilities
+type buildInfo struct {
+	Version string `bson:"version"`
+	// FerretDBVersion is only reported by FerretDB
+	FerretDBVersion string `bson:"ferretdbVersion"`
+	// PSMDBVersion is only reported by Percona Server for MongoDB
+	PSMDBVersion string `bson:"psmdbVersion"`
+	// Modules lists "enterprise" for MongoDB Enterprise builds
+	Modules []string `bson:"modules"`
+}
+
+// detectCapabilities reads the server version from buildInfo and the available commands from listCommands
+func detectCapabilities(ctx context.Context, client *mongo.Client) (buildInfo, []string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	admin := client.Database("admin")
+
+	var info buildInfo
+	if err := admin.RunCommand(ctx, withMaxTime(ctx, bson.D{{"buildInfo", 1}})).Decode(&info); err != nil {
+		return info, nil, fmt.Errorf("buildInfo: %w", err)
+	}
+
+	var listCommands struct {
+		Commands map[string]bson.Raw `bson:"commands"`
+	}
+	if err := admin.RunCommand(ctx, withMaxTime(ctx, bson.D{{"listCommands", 1}})).Decode(&listCommands); err != nil {
+		return info, nil, fmt.Errorf("listCommands: %w", err)
+	}
+
+	commands := make([]string, 0, len(listCommands.Commands))
+	for name := range listCommands.Commands {
+		commands = append(commands, name)
+	}
+	return info, commands, nil
+}
+
+// apply records a detection result and exports the features the version lacks.
+// Command names are matched case-insensitively, as the server does for aliases
+// such as isMaster and ismaster. Callers must hold s.mu
+func (s *serverCapabilities) apply(version string, commands []string) {
+	parsed, ok := parseServerVersion(version)
+	if !ok {
+		s.logger.Warn("Unrecognized server version, skipping version checks", zap.String("version", version))
+	}
+
+	s.commands = make(map[string]bool, len(commands))
+	for _, name := range commands {
+		s.commands[strings.ToLower(name)] = true
+	}
+	s.version = parsed
+	s.detected = true
+
+	var disabled []string
+	for feature, required := range featureVersions {
+		if ok && !parsed.atLeast(required) {
+			disabled = append(disabled, feature+" (requires "+required.String()+")")
+			s.unsupported.WithLabelValues(feature, "server_version").Set(1)
+		}
+	}
+	s.logger.Info("Detected server capabilities",
+		zap.String("version", version),
+		zap.Int("commands", len(commands)),
+		zap.Strings("disabled_features", disabled))
+}
+
+// supportsCommand reports whether the server has the command, logging the
+// first skip of each missing one
+func (s *serverCapabilities) supportsCommand(ctx context.Context, client *mongo.Client, name string) bool {
+	s.ensure(ctx, client)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := strings.ToLower(name)
+	if !s.detected || s.commands[key] {
+		return true
+	}
+	if !s.skipped[key] {
+		s.skipped[key] = true
+		s.unsupported.WithLabelValues(name, "missing_command").Set(1)
+		s.logger.Info("Skipping command the server does not support", zap.String("command", name))
+	}
+	return false
+}
+
+// supportsFeature reports whether the server version has a feature from featureVersions
+func (s *serverCapabilities) supportsFeature(ctx context.Context, client *mongo.Client, feature string) bool {
+	s.ensure(ctx, client)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	required, known := featureVersions[feature]
+	if _, restricted := flavorUnsupportedCollectors[s.flavor]; known && restricted {
+		return false
+	}
+	if !s.detected || !known || s.version == (serverVersion{}) {
+		return true
+	}
+	return s.version.atLeast(required)
+}
+
+// supports reports whether the server has a version-gated feature such as a serverStatus section
+func (bc *BaseCollector) supports(ctx context.Context, feature string) bool {
+	if bc.config.capabilities == nil {
+		return true
+	}
+	return bc.config.capabilities.supportsFeature(ctx, bc.client, feature)
+}
+
+// gate returns errCommandUnsupported instead of letting collector run command
+// or use feature when the server lacks them or its flavor rules the collector
+// out. feature is empty for plain commands
+func (bc *BaseCollector) gate(ctx context.Context, collector, command, feature string) error {
+	caps := bc.config.capabilities
+	if caps == nil {
+		return nil
+	}
+	if !caps.supportsCommand(ctx, bc.client, command) || caps.collectorUnsupported(collector) {
+		return errCommandUnsupported
+	}
+	if feature != "" && !caps.supportsFeature(ctx, bc.client, feature) {
+		return errCommandUnsupported
+	}
+	return nil
+}
+
+// runGatedCommand is runCommandWithTimeout through the capability gate, for
+// per-namespace commands whose Unauthorized failures are not tracked
+func (bc *BaseCollector) runGatedCommand(ctx context.Context, collector string, db *mongo.Database, command bson.D, timeout time.Duration, result interface{}) error {
+	if err := bc.gate(ctx, collector, command[0].Key, ""); err != nil {
+		return err
+	}
+	return runCommandWithTimeout(ctx, db, command, timeout, result)
+}
+
+// aggregator is a database or collection an aggregation runs on
+type aggregator interface {
+	Aggregate(ctx context.Context, pipeline interface{}, opts ...*options.AggregateOptions) (*mongo.Cursor, error)
+}
+
+// aggregate runs pipeline on target through the capability gate. A first
+// stage listed in featureVersions, such as $queryStats, is gated by version
+func (bc *BaseCollector) aggregate(ctx context.Context, collector string, target aggregator, pipeline interface{}, opts ...*options.AggregateOptions) (*mongo.Cursor, error) {
+	if err := bc.gate(ctx, collector, "aggregate", firstStage(pipeline)); err != nil {
+		return nil, err
+	}
+	return target.Aggregate(ctx, pipeline, opts...)
+}
+
+// find runs a find on coll through the capability gate
+func (bc *BaseCollector) find(ctx context.Context, collector string, coll *mongo.Collection, filter interface{}, opts ...*options.FindOptions) (*mongo.Cursor, error) {
+	if err := bc.gate(ctx, collector, "find", ""); err != nil {
+		return nil, err
+	}
+	return coll.Find(ctx, filter, opts...)
+}
+
+// findOne decodes the first document matching filter into result through the capability gate
+func (bc *BaseCollector) findOne(ctx context.Context, collector string, coll *mongo.Collection, filter, result interface{}, opts ...*options.FindOneOptions) error {
+	if err := bc.gate(ctx, collector, "find", ""); err != nil {
+		return err
+	}
+	return coll.FindOne(ctx, filter, opts...).Decode(result)
+}
+
+// countDocuments counts the documents matching filter through the capability
+// gate; the driver counts with an aggregation
+func (bc *BaseCollector) countDocuments(ctx context.Context, collector string, coll *mongo.Collection, filter interface{}, opts ...*options.CountOptions) (int64, error) {
+	if err := bc.gate(ctx, collector, "aggregate", ""); err != nil {
+		return 0, err
+	}
+	return coll.CountDocuments(ctx, filter, opts...)
+}
+
+// listIndexes returns the index specifications of coll through the capability gate
+func (bc *BaseCollector) listIndexes(ctx context.Context, collector string, coll *mongo.Collection) ([]*mongo.IndexSpecification, error) {
+	if err := bc.gate(ctx, collector, "listIndexes", ""); err != nil {
+		return nil, err
+	}
+	return coll.Indexes().ListSpecifications(ctx)
+}
+
+// firstStage returns the name of a pipeline's first stage, or "" if it has none
+func firstStage(pipeline interface{}) string {
+	var stage interface{}
+	switch p := pipeline.(type) {
+	case []bson.D:
+		if len(p) > 0 {
+			stage = p[0]
+		}
+	case mongo.Pipeline:
+		if len(p) > 0 {
+			stage = p[0]
+		}
+	case bson.A:
+		if len(p) > 0 {
+			stage = p[0]
+		}
+	}
+	if d, ok := stage.(bson.D); ok && len(d) > 0 {
+		return d[0].Key
+	}
+	return ""
+}
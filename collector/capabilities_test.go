@@ -0,0 +1,173 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+func TestParseServerVersion(t *testing.T) {
+	tests := map[string]serverVersion{
+		"6.0.12":    {6, 0},
+		"7.0.0-rc1": {7, 0},
+		"4.4":       {4, 4},
+		"8.1-rc0":   {8, 1},
+	}
+	for version, expected := range tests {
+		if got, ok := parseServerVersion(version); !ok || got != expected {
+			t.Errorf("Version %q should parse as %v, got %v", version, expected, got)
+		}
+	}
+	if _, ok := parseServerVersion("unknown"); ok {
+		t.Error("Non-numeric versions should not parse")
+	}
+}
+
+func TestServerCapabilities(t *testing.T) {
+	caps := newServerCapabilities(zap.NewNop())
+	ctx := context.Background()
+
+	if !caps.supportsCommand(ctx, nil, "balancerStatus") || !caps.supportsFeature(ctx, nil, "mirroredReads") {
+		t.Error("Everything should be supported until detection succeeds")
+	}
+
+	caps.apply("4.2.0", []string{"serverStatus", "isMaster", "buildInfo"})
+
+	if !caps.supportsCommand(ctx, nil, "ismaster") {
+		t.Error("Command names should match case-insensitively")
+	}
+	if caps.supportsCommand(ctx, nil, "hello") {
+		t.Error("Commands missing from listCommands should be skipped")
+	}
+	if caps.supportsFeature(ctx, nil, "mirroredReads") {
+		t.Error("mirroredReads should be skipped before 4.4")
+	}
+	if !caps.supportsFeature(ctx, nil, "unknownFeature") {
+		t.Error("Features without a version requirement should be supported")
+	}
+
+	if got := testutil.ToFloat64(caps.unsupported.WithLabelValues("hello", "missing_command")); got != 1 {
+		t.Errorf("Skipped command should be exported, got %v", got)
+	}
+	if got := testutil.ToFloat64(caps.unsupported.WithLabelValues("mirroredReads", "server_version")); got != 1 {
+		t.Errorf("Version-gated feature should be exported, got %v", got)
+	}
+
+	caps = newServerCapabilities(zap.NewNop())
+	caps.apply("8.0.1", nil)
+	if !caps.supportsFeature(ctx, nil, "mirroredReads") {
+		t.Error("mirroredReads should be supported from 4.4")
+	}
+	if got := testutil.CollectAndCount(caps.unsupported); got != 0 {
+		t.Errorf("Nothing should be exported as unsupported on 8.0, got %d", got)
+	}
+}
+
+func TestCapabilityGateCoversQueries(t *testing.T) {
+	caps := newServerCapabilities(zap.NewNop())
+	caps.apply("7.0.2", []string{"aggregate", "listIndexes"})
+	collector := NewBaseCollector(nil, zap.NewNop(), CollectorConfig{capabilities: caps})
+	ctx := context.Background()
+
+	// Gated calls return before touching the nil database and collection
+	queryStats := bson.A{bson.D{{"$queryStats", bson.D{}}}}
+	if _, err := collector.aggregate(ctx, "test", (*mongo.Database)(nil), queryStats); !errors.Is(err, errCommandUnsupported) {
+		t.Errorf("$queryStats should be skipped before 7.1, got %v", err)
+	}
+	if _, err := collector.find(ctx, "test", nil, bson.D{}); !errors.Is(err, errCommandUnsupported) {
+		t.Errorf("find should be skipped when listCommands lacks it, got %v", err)
+	}
+	if err := collector.findOne(ctx, "test", nil, bson.D{}, &bson.M{}); !errors.Is(err, errCommandUnsupported) {
+		t.Errorf("findOne should be skipped when listCommands lacks find, got %v", err)
+	}
+	if err := collector.runGatedCommand(ctx, "test", nil, bson.D{{"top", 1}}, time.Second, &bson.M{}); !errors.Is(err, errCommandUnsupported) {
+		t.Errorf("top should be skipped when listCommands lacks it, got %v", err)
+	}
+}
+
+func TestFirstStage(t *testing.T) {
+	tests := map[string]interface{}{
+		"$indexStats": []bson.D{{{"$indexStats", bson.D{}}}, {{"$project", bson.D{}}}},
+		"$currentOp":  mongo.Pipeline{{{"$currentOp", bson.D{}}}},
+		"$queryStats": bson.A{bson.D{{"$queryStats", bson.D{}}}},
+		"":            []bson.D{},
+	}
+	for expected, pipeline := range tests {
+		if got := firstStage(pipeline); got != expected {
+			t.Errorf("Expected first stage %q, got %q", expected, got)
+		}
+	}
+}
+
+func TestDocumentDBFlavor(t *testing.T) {
+	caps := newServerCapabilities(zap.NewNop())
+	config := CollectorConfig{TargetFlavor: flavorDocumentDB, capabilities: caps}
+	caps.setFlavor(config.TargetFlavor)
+	collector := NewBaseCollector(nil, zap.NewNop(), config)
+
+	if collector.isMetricEnabled("profile") || collector.isMetricEnabled("sharding") {
+		t.Error("Collectors DocumentDB does not support should be disabled")
+	}
+	if !collector.isMetricEnabled("server_status") {
+		t.Error("Supported collectors should stay enabled")
+	}
+
+	caps.apply("5.0.0", nil)
+	if collector.supports(context.Background(), "mirroredReads") {
+		t.Error("Version-gated features should be skipped on DocumentDB")
+	}
+	if got := testutil.ToFloat64(caps.unsupported.WithLabelValues("profile", "target_flavor")); got != 1 {
+		t.Errorf("Skipped collector should be exported, got %v", got)
+	}
+}
+
+func TestFlavorUnsupportedCollectorsExist(t *testing.T) {
+	names := make(map[string]bool)
+	for _, collector := range InitializeCollectors(nil, zap.NewNop(), CollectorConfig{}) {
+		names[collector.Name()] = true
+	}
+
+	for flavor, unsupported := range flavorUnsupportedCollectors {
+		for name := range unsupported {
+			if !names[name] {
+				t.Errorf("Flavor %s turns off %q, which is not a collector name", flavor, name)
+			}
+		}
+	}
+}
+
+func TestFlavorCheckDoesNotWaitForDetection(t *testing.T) {
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI("mongodb://127.0.0.1:1").SetServerSelectionTimeout(3*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect(context.Background())
+
+	caps := newServerCapabilities(zap.NewNop())
+	collector := NewBaseCollector(client, zap.NewNop(), CollectorConfig{capabilities: caps})
+
+	start := time.Now()
+	if !collector.isMetricEnabled("locks") {
+		t.Error("Collectors should stay enabled until a flavor is known")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Checking whether a collector is enabled should not run detection, took %s", elapsed)
+	}
+
+	caps.mu.Lock()
+	caps.restrictFlavor(flavorFerretDB)
+	caps.mu.Unlock()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	caps.detecting = true
+	if err := collector.runCommand(ctx, "locks", client.Database("admin"), bson.D{{"serverStatus", 1}}, &bson.M{}); !errors.Is(err, errCommandUnsupported) {
+		t.Errorf("Commands of collectors the flavor rules out should be refused, got %v", err)
+	}
+}
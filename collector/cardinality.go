@@ -0,0 +1,90 @@
+package collector
+
+import (
+	"regexp"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var descNamePattern = regexp.MustCompile(`fqName: "([^"]+)"`)
+
+// seriesLimiter caps the number of series emitted per metric family during one scrape
+type seriesLimiter struct {
+	defaultLimit int
+	limits       map[string]int
+	dropped      *prometheus.CounterVec
+
+	mu     sync.Mutex
+	counts map[string]int
+	names  map[*prometheus.Desc]string
+}
+
+func newSeriesLimiter(defaultLimit int, limits map[string]int, dropped *prometheus.CounterVec) *seriesLimiter {
+	return &seriesLimiter{
+		defaultLimit: defaultLimit,
+		limits:       limits,
+		dropped:      dropped,
+		counts:       make(map[string]int),
+		names:        make(map[*prometheus.Desc]string),
+	}
+}
+
+// allow reports whether another series of the metric's family fits in the budget
+func (l *seriesLimiter) allow(metric prometheus.Metric) bool {
+	desc := metric.Desc()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	name, ok := l.names[desc]
+	if !ok {
+		name = descName(desc)
+		l.names[desc] = name
+	}
+
+	limit := l.defaultLimit
+	if familyLimit, ok := l.limits[name]; ok {
+		limit = familyLimit
+	}
+
+	if limit <= 0 {
+		return true
+	}
+
+	l.counts[name]++
+	if l.counts[name] > limit {
+		l.dropped.WithLabelValues(name).Inc()
+		return false
+	}
+
+	return true
+}
+
+// forward relays metrics to send until in is closed, dropping series over the limit,
+// and returns how many metrics the collector produced
+func (l *seriesLimiter) forward(in <-chan prometheus.Metric, send func(prometheus.Metric)) int {
+	received := 0
+	for metric := range in {
+		received++
+		if l.allow(metric) {
+			send(metric)
+		}
+	}
+	return received
+}
+
+// descName extracts the fully-qualified metric name, which prometheus.Desc does not expose
+func descName(desc *prometheus.Desc) string {
+	if match := descNamePattern.FindStringSubmatch(desc.String()); len(match) == 2 {
+		return match[1]
+	}
+	return "unknown"
+}
+
+func newSeriesDroppedCounter() *prometheus.CounterVec {
+	return prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "mongodb_exporter_series_dropped_total",
+		Help: "Total number of series dropped because a metric family exceeded its cardinality limit",
+	}, []string{"metric"})
+}
@@ -0,0 +1,148 @@
+package collector
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// changelogEvents are the config.changelog events counted by the sharding collector
+var changelogEvents = []string{
+	"moveChunk.from",
+	"moveChunk.to",
+	"moveChunk.commit",
+	"moveChunk.error",
+	"split",
+	"multi-split",
+	"merge",
+}
+
+// changelogEntry is the part of a config.changelog document the counters need
+type changelogEntry struct {
+	What string    `bson:"what"`
+	Time time.Time `bson:"time"`
+}
+
+// changelogCounters keeps monotonic per-event counts of config.changelog.
+// The first run counts the whole changelog once; later runs read only the
+// entries logged after the newest one already counted
+type changelogCounters struct {
+	mu     sync.Mutex
+	seeded bool
+	// newest is the config server time of the newest counted entry. Using
+	// server times rather than the exporter's clock makes the window immune
+	// to clock skew between the two
+	newest time.Time
+	counts map[string]float64
+}
+
+func newChangelogCounters() *changelogCounters {
+	return &changelogCounters{counts: make(map[string]float64)}
+}
+
+// refresh brings the counts up to date with the changelog and returns a copy of them
+func (t *changelogCounters) refresh(ctx context.Context, bc *BaseCollector, changelog *mongo.Collection) (map[string]float64, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var err error
+	if t.seeded {
+		err = t.readNewEntries(ctx, bc, changelog)
+	} else {
+		err = t.seed(ctx, bc, changelog)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]float64, len(t.counts))
+	for what, count := range t.counts {
+		counts[what] = count
+	}
+	return counts, nil
+}
+
+// seed counts every tracked event in the changelog with one aggregation
+func (t *changelogCounters) seed(ctx context.Context, bc *BaseCollector, changelog *mongo.Collection) error {
+	pipeline := []bson.D{
+		{{"$match", bson.D{{"what", bson.D{{"$in", changelogEvents}}}}}},
+		{{"$group", bson.D{
+			{"_id", "$what"},
+			{"count", bson.D{{"$sum", 1}}},
+			{"newest", bson.D{{"$max", "$time"}}},
+		}}},
+	}
+
+	cursor, err := bc.aggregate(ctx, "sharding", changelog, pipeline, &options.AggregateOptions{MaxTime: maxTime(ctx)})
+	if err != nil {
+		return err
+	}
+
+	var groups []struct {
+		What   string    `bson:"_id"`
+		Count  float64   `bson:"count"`
+		Newest time.Time `bson:"newest"`
+	}
+	if err := cursor.All(ctx, &groups); err != nil {
+		return err
+	}
+
+	for _, group := range groups {
+		t.counts[group.What] += group.Count
+		if group.Newest.After(t.newest) {
+			t.newest = group.Newest
+		}
+	}
+	t.seeded = true
+	return nil
+}
+
+// readNewEntries walks the capped changelog from its newest entry backwards
+// and stops at the first entry already counted, so a scrape reads only the
+// events logged since the previous one instead of the whole collection
+func (t *changelogCounters) readNewEntries(ctx context.Context, bc *BaseCollector, changelog *mongo.Collection) error {
+	findOptions := options.Find().
+		SetSort(bson.D{{"$natural", -1}}).
+		SetProjection(bson.D{{"what", 1}, {"time", 1}})
+	findOptions.MaxTime = maxTime(ctx)
+
+	cursor, err := bc.find(ctx, "sharding", changelog, bson.D{{"what", bson.D{{"$in", changelogEvents}}}}, findOptions)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	var entries []changelogEntry
+	for cursor.Next(ctx) {
+		var entry changelogEntry
+		if err := cursor.Decode(&entry); err != nil {
+			return err
+		}
+		if !entry.Time.After(t.newest) {
+			break
+		}
+		entries = append(entries, entry)
+	}
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+
+	// Entries are only counted once the whole window was read, so a failed
+	// read is retried in full on the next scrape instead of being half counted
+	t.add(entries)
+	return nil
+}
+
+// add counts entries and advances the newest counted time
+func (t *changelogCounters) add(entries []changelogEntry) {
+	for _, entry := range entries {
+		t.counts[entry.What]++
+		if entry.Time.After(t.newest) {
+			t.newest = entry.Time
+		}
+	}
+}
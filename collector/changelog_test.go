@@ -0,0 +1,25 @@
+package collector
+
+import (
+	"testing"
+	"time"
+)
+
+func TestChangelogCountersAdd(t *testing.T) {
+	counters := newChangelogCounters()
+	start := time.Unix(1700000000, 0)
+	counters.counts["split"] = 4
+	counters.newest = start
+
+	counters.add([]changelogEntry{
+		{What: "split", Time: start.Add(2 * time.Second)},
+		{What: "moveChunk.commit", Time: start.Add(time.Second)},
+	})
+
+	if counters.counts["split"] != 5 || counters.counts["moveChunk.commit"] != 1 {
+		t.Errorf("New entries should increment the seeded counts, got %v", counters.counts)
+	}
+	if !counters.newest.Equal(start.Add(2 * time.Second)) {
+		t.Errorf("Newest counted time should advance to the latest entry, got %v", counters.newest)
+	}
+}
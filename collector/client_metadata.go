@@ -0,0 +1,98 @@
+package collector
+
+import (
+	"context"
+	"sort"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// defaultMaxClientGroups caps the app and driver combinations exported per scrape
+const defaultMaxClientGroups = 50
+
+// clientGroupOther labels the connections of combinations beyond the cap
+const clientGroupOther = "other"
+
+// clientMetadataPipeline lists every client connection and groups them by the
+// handshake metadata the driver sent, so only one document per combination is
+// returned. Internal connections carry no clientMetadata and are left out
+var clientMetadataPipeline = []bson.D{
+	{{"$currentOp", bson.D{
+		{"allUsers", true},
+		{"idleConnections", true},
+	}}},
+	{{"$match", bson.D{
+		{"type", "op"},
+		{"clientMetadata", bson.D{{"$exists", true}}},
+	}}},
+	{{"$group", bson.D{
+		{"_id", bson.D{
+			{"appName", "$clientMetadata.application.name"},
+			{"driverName", "$clientMetadata.driver.name"},
+			{"driverVersion", "$clientMetadata.driver.version"},
+		}},
+		{"count", bson.D{{"$sum", 1}}},
+	}}},
+}
+
+// clientMetadataGroup is one $group result of clientMetadataPipeline
+type clientMetadataGroup struct {
+	ID struct {
+		AppName       string `bson:"appName"`
+		DriverName    string `bson:"driverName"`
+		DriverVersion string `bson:"driverVersion"`
+	} `bson:"_id"`
+	Count int64 `bson:"count"`
+}
+
+// capClientGroups keeps the max largest groups and folds the rest into a
+// single "other" group, so a fleet of distinct app names cannot blow up
+// cardinality while the total connection count stays correct
+func capClientGroups(groups []clientMetadataGroup, max int) []clientMetadataGroup {
+	if max <= 0 || len(groups) <= max {
+		return groups
+	}
+
+	sorted := append([]clientMetadataGroup(nil), groups...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Count > sorted[j].Count })
+
+	var other clientMetadataGroup
+	other.ID.AppName = clientGroupOther
+	other.ID.DriverName = clientGroupOther
+	other.ID.DriverVersion = clientGroupOther
+	for _, g := range sorted[max:] {
+		other.Count += g.Count
+	}
+	return append(sorted[:max], other)
+}
+
+// collectClientMetadata exports client connection counts by appName and driver
+func (c *ConnectionPoolCollector) collectClientMetadata(ctx context.Context, ch chan<- prometheus.Metric, instance map[string]string) {
+	cursor, err := c.aggregate(ctx, "connection_pool", c.client.Database("admin"), clientMetadataPipeline, &options.AggregateOptions{MaxTime: maxTime(ctx)})
+	if err != nil {
+		c.logCommandError("Failed to run $currentOp for client metadata", err)
+		return
+	}
+
+	var groups []clientMetadataGroup
+	if err := cursor.All(ctx, &groups); err != nil {
+		c.logCommandError("Failed to read $currentOp client metadata", err)
+		return
+	}
+
+	for _, g := range capClientGroups(groups, c.maxClientGroups) {
+		ch <- prometheus.MustNewConstMetric(
+			c.descriptors["client_connections"],
+			prometheus.GaugeValue,
+			float64(g.Count),
+			instance["instance"],
+			instance["replica_set"],
+			instance["shard"],
+			g.ID.AppName,
+			g.ID.DriverName,
+			g.ID.DriverVersion,
+		)
+	}
+}
@@ -0,0 +1,40 @@
+package collector
+
+import "testing"
+
+func newClientMetadataGroup(appName, driverName, driverVersion string, count int64) clientMetadataGroup {
+	var g clientMetadataGroup
+	g.ID.AppName = appName
+	g.ID.DriverName = driverName
+	g.ID.DriverVersion = driverVersion
+	g.Count = count
+	return g
+}
+
+func TestCapClientGroups(t *testing.T) {
+	groups := []clientMetadataGroup{
+		newClientMetadataGroup("api", "nodejs", "6.3.0", 120),
+		newClientMetadataGroup("worker", "mongo-go-driver", "v1.13.1", 40),
+		newClientMetadataGroup("cron-a", "PyMongo", "3.12.0", 2),
+		newClientMetadataGroup("cron-b", "PyMongo", "4.6.1", 3),
+	}
+
+	if capped := capClientGroups(groups, 10); len(capped) != len(groups) {
+		t.Errorf("Groups under the cap should be kept as is, got %d", len(capped))
+	}
+
+	capped := capClientGroups(groups, 2)
+	if len(capped) != 3 {
+		t.Fatalf("Capped groups should keep the largest two plus other, got %d", len(capped))
+	}
+	if capped[0].ID.AppName != "api" || capped[1].ID.AppName != "worker" {
+		t.Errorf("Largest groups should be kept, got %s and %s", capped[0].ID.AppName, capped[1].ID.AppName)
+	}
+	other := capped[2]
+	if other.ID.AppName != clientGroupOther || other.ID.DriverName != clientGroupOther || other.Count != 5 {
+		t.Errorf("Remaining connections should be summed into other, got %+v", other)
+	}
+	if groups[2].ID.AppName != "cron-a" {
+		t.Error("Capping should not reorder the caller's groups")
+	}
+}
@@ -0,0 +1,52 @@
+package collector
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// clockSkewDescriptors describes the clock skew between the exporter and the server
+func clockSkewDescriptors(config CollectorConfig) map[string]*prometheus.Desc {
+	labels := []string{"instance", "replica_set", "shard"}
+
+	return map[string]*prometheus.Desc{
+		"clock_skew_seconds": prometheus.NewDesc(
+			config.metricName("mongodb_clock_skew_seconds"),
+			"Server clock minus the exporter clock; positive when the server is ahead. Accurate to half the serverStatus round trip",
+			labels,
+			nil,
+		),
+	}
+}
+
+// clockSkew compares the server clock in serverStatus localTime with the
+// exporter clock halfway through the command, which is when the server most
+// likely read it
+func clockSkew(result bson.M, sent, received time.Time) (time.Duration, bool) {
+	localTime, ok := result["localTime"].(primitive.DateTime)
+	if !ok {
+		return 0, false
+	}
+	midpoint := sent.Add(received.Sub(sent) / 2)
+	return localTime.Time().Sub(midpoint), true
+}
+
+// collectClockSkew exports the clock skew measured by the serverStatus command sent and answered at the given times
+func (c *ServerStatusCollector) collectClockSkew(ch chan<- prometheus.Metric, result bson.M, sent, received time.Time) {
+	skew, ok := clockSkew(result, sent, received)
+	if !ok {
+		return
+	}
+	instance := c.getInstanceInfo(result)
+	ch <- prometheus.MustNewConstMetric(
+		c.descriptors["clock_skew_seconds"],
+		prometheus.GaugeValue,
+		skew.Seconds(),
+		instance["instance"],
+		instance["replica_set"],
+		instance["shard"],
+	)
+}
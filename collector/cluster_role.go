@@ -0,0 +1,75 @@
+package collector
+
+import (
+	"context"
+
+	"github.com/jimohabdol/mongodb-exporter/database"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Cluster roles reported by DetectClusterRole
+const (
+	ClusterRoleMongos        = "mongos"
+	ClusterRoleConfigServer  = "configsvr"
+	ClusterRoleShardServer   = "shardsvr"
+	ClusterRoleReplicaMember = "replica_member"
+	ClusterRoleStandalone    = "standalone"
+	ClusterRoleUnknown       = "unknown"
+)
+
+// DetectClusterRole determines whether the target is a mongos, config server,
+// shard server, plain replica set member or standalone from hello/isMaster
+func DetectClusterRole(ctx context.Context, client *mongo.Client) (string, error) {
+	if client == nil {
+		return ClusterRoleUnknown, nil
+	}
+
+	run := database.RunAdminCommand(client)
+	return detectClusterRole(ctx, run, func(ctx context.Context) bson.M {
+		var cmdLineOpts bson.M
+		if err := run(ctx, bson.D{{"getCmdLineOpts", 1}}, &cmdLineOpts); err != nil {
+			return nil
+		}
+		parsed, _ := cmdLineOpts["parsed"].(bson.M)
+		return parsed
+	})
+}
+
+// detectClusterRole classifies the target from hello run through run. hello
+// does not tell shard members apart from plain replica sets; the parsed
+// startup options do, but reading them needs extra privileges, so options may
+// return nil
+func detectClusterRole(ctx context.Context, run database.AdminCommand, options func(ctx context.Context) bson.M) (string, error) {
+	hello, err := database.Hello(ctx, run)
+	if err != nil {
+		return ClusterRoleUnknown, err
+	}
+
+	var shardingRole string
+	if _, ok := hello["setName"]; ok {
+		if sharding, ok := options(ctx)["sharding"].(bson.M); ok {
+			shardingRole, _ = sharding["clusterRole"].(string)
+		}
+	}
+	return classifyClusterRole(hello, shardingRole), nil
+}
+
+func classifyClusterRole(hello bson.M, shardingRole string) string {
+	if msg, ok := hello["msg"].(string); ok && msg == "isdbgrid" {
+		return ClusterRoleMongos
+	}
+
+	if _, ok := hello["configsvr"]; ok || shardingRole == ClusterRoleConfigServer {
+		return ClusterRoleConfigServer
+	}
+
+	if _, ok := hello["setName"]; ok {
+		if shardingRole == ClusterRoleShardServer {
+			return ClusterRoleShardServer
+		}
+		return ClusterRoleReplicaMember
+	}
+
+	return ClusterRoleStandalone
+}
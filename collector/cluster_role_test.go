@@ -0,0 +1,28 @@
+package collector
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestClassifyClusterRole(t *testing.T) {
+	cases := []struct {
+		name         string
+		hello        bson.M
+		shardingRole string
+		expected     string
+	}{
+		{"mongos", bson.M{"msg": "isdbgrid"}, "", ClusterRoleMongos},
+		{"config server", bson.M{"setName": "cfg", "configsvr": int32(2)}, "", ClusterRoleConfigServer},
+		{"shard server", bson.M{"setName": "shard01"}, "shardsvr", ClusterRoleShardServer},
+		{"replica member", bson.M{"setName": "rs0"}, "", ClusterRoleReplicaMember},
+		{"standalone", bson.M{"isWritablePrimary": true}, "", ClusterRoleStandalone},
+	}
+
+	for _, tc := range cases {
+		if got := classifyClusterRole(tc.hello, tc.shardingRole); got != tc.expected {
+			t.Errorf("%s: expected %s, got %s", tc.name, tc.expected, got)
+		}
+	}
+}
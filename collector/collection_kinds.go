@@ -0,0 +1,73 @@
+package collector
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// collectionKind distinguishes collections whose collStats output needs special handling
+type collectionKind int
+
+const (
+	// kindCollection is a regular collection
+	kindCollection collectionKind = iota
+	// kindView has no storage of its own; collStats fails on it
+	kindView
+	// kindTimeseries stores its data in a system.buckets collection
+	kindTimeseries
+	// kindClustered is clustered by its _id, so there is no separate _id index
+	kindClustered
+)
+
+// nonRegularCollectionsFilter keeps listCollections output to the collections
+// that are not plain, which is usually a handful per database
+var nonRegularCollectionsFilter = bson.D{{"$or", bson.A{
+	bson.D{{"type", bson.D{{"$in", bson.A{"view", "timeseries"}}}}},
+	bson.D{{"options.clusteredIndex", bson.D{{"$exists", true}}}},
+}}}
+
+// listCollectionKinds returns the kind of every collection in dbName that is not a
+// regular collection; collections missing from the result are regular
+func listCollectionKinds(ctx context.Context, db *mongo.Database, timeout time.Duration) (map[string]collectionKind, error) {
+	timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	specs, err := db.ListCollectionSpecifications(timeoutCtx, nonRegularCollectionsFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	kinds := make(map[string]collectionKind, len(specs))
+	for _, spec := range specs {
+		kinds[spec.Name] = classifyCollection(spec)
+	}
+	return kinds, nil
+}
+
+// classifyCollection determines the kind of a listCollections entry
+func classifyCollection(spec *mongo.CollectionSpecification) collectionKind {
+	switch spec.Type {
+	case "view":
+		return kindView
+	case "timeseries":
+		return kindTimeseries
+	}
+	if spec.Options != nil {
+		if _, err := spec.Options.LookupErr("clusteredIndex"); err == nil {
+			return kindClustered
+		}
+	}
+	return kindCollection
+}
+
+// statsCollection is the collection collStats should run against; time-series
+// data lives in the system.buckets collection behind the user-facing name
+func (ns namespace) statsCollection() string {
+	if ns.Kind == kindTimeseries {
+		return "system.buckets." + ns.Collection
+	}
+	return ns.Collection
+}
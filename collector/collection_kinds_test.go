@@ -0,0 +1,42 @@
+package collector
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestClassifyCollection(t *testing.T) {
+	clusteredOptions, err := bson.Marshal(bson.D{{"clusteredIndex", bson.D{{"key", bson.D{{"_id", 1}}}, {"unique", true}}}})
+	if err != nil {
+		t.Fatalf("Failed to marshal options: %v", err)
+	}
+
+	tests := []struct {
+		spec mongo.CollectionSpecification
+		want collectionKind
+	}{
+		{mongo.CollectionSpecification{Name: "orders", Type: "collection"}, kindCollection},
+		{mongo.CollectionSpecification{Name: "active_orders", Type: "view"}, kindView},
+		{mongo.CollectionSpecification{Name: "readings", Type: "timeseries"}, kindTimeseries},
+		{mongo.CollectionSpecification{Name: "events", Type: "collection", Options: clusteredOptions}, kindClustered},
+	}
+	for _, tt := range tests {
+		if got := classifyCollection(&tt.spec); got != tt.want {
+			t.Errorf("Collection %q should be kind %d, got %d", tt.spec.Name, tt.want, got)
+		}
+	}
+}
+
+func TestNamespaceStatsCollection(t *testing.T) {
+	ns := namespace{Database: "iot", Collection: "readings", Kind: kindTimeseries}
+	if got := ns.statsCollection(); got != "system.buckets.readings" {
+		t.Errorf("Time-series stats should come from the bucket collection, got %q", got)
+	}
+
+	ns.Kind = kindClustered
+	if got := ns.statsCollection(); got != "readings" {
+		t.Errorf("Clustered collections should use collStats directly, got %q", got)
+	}
+}
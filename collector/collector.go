@@ -2,12 +2,18 @@ package collector
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net"
+	"os"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
@@ -27,6 +33,73 @@ type CollectorConfig struct {
 	EnabledMetrics  []string
 	DisabledMetrics []string
 	Collectors      map[string]interface{}
+
+	// MaxSeriesPerMetric caps series per metric family per scrape (0 = unlimited)
+	MaxSeriesPerMetric int
+	// SeriesLimits overrides MaxSeriesPerMetric for individual metric families
+	SeriesLimits map[string]int
+	// Intervals runs the named collectors at most once per interval, serving cached values in between
+	Intervals map[string]time.Duration
+	// StaleAfterRuns evicts a cached series once this many consecutive runs miss it (<= 1 = on the first miss)
+	StaleAfterRuns int
+	// NamespaceCacheTTL caches database and collection listings shared by all collectors (0 = no caching)
+	NamespaceCacheTTL time.Duration
+	// Parallelism bounds how many namespaces per-namespace collectors query concurrently (<= 1 = sequential)
+	Parallelism int
+
+	// Namespace replaces the default "mongodb" metric name prefix (e.g. "acme_mongodb")
+	Namespace string
+
+	// InstanceLabel overrides the instance label; {host}, {port} and {hostname} are expanded
+	InstanceLabel string
+	// StripInstancePort drops the port from the reported host when InstanceLabel is empty
+	StripInstancePort bool
+
+	// TimeoutMin and TimeoutMax bound adaptive collector deadlines; both zero keeps fixed timeouts
+	TimeoutMin time.Duration
+	TimeoutMax time.Duration
+
+	// Watchdog abandons a collector run still going after this long so the scrape can finish (0 = wait indefinitely)
+	Watchdog time.Duration
+
+	// Tracing records OpenTelemetry spans for scrapes and collector runs
+	Tracing bool
+
+	// ScrapeProfiles are named collector sets a scrape can be limited to
+	ScrapeProfiles map[string][]string
+
+	// ClusterRoleLabel tells collectors the registerer adds cluster_role as a
+	// const label, so they must not export a variable label of that name
+	ClusterRoleLabel bool
+
+	// TargetFlavor adapts collectors to MongoDB-compatible servers; "documentdb"
+	// and "ferretdb" skip what those servers do not support. FerretDB is also
+	// detected from buildInfo
+	TargetFlavor string
+
+	// MemberClient connects directly to one replica set member by host:port (nil disables per-member checks)
+	MemberClient func(ctx context.Context, host string) (*mongo.Client, error)
+
+	// database limits per-namespace collectors to one database (empty = all)
+	database string
+
+	inventory    *namespaceInventory
+	timeouts     *timeoutTuner
+	unauthorized *unauthorizedTracker
+	capabilities *serverCapabilities
+	tracer       *scrapeTracer
+	lifetime     *runTracker
+}
+
+// defaultNamespace is the prefix every collector's metric names are written with
+const defaultNamespace = "mongodb"
+
+// metricName swaps the default prefix of name for the configured Namespace
+func (c CollectorConfig) metricName(name string) string {
+	if c.Namespace == "" || c.Namespace == defaultNamespace {
+		return name
+	}
+	return c.Namespace + strings.TrimPrefix(name, defaultNamespace)
 }
 
 func NewBaseCollector(client *mongo.Client, logger *zap.Logger, config CollectorConfig) *BaseCollector {
@@ -58,10 +131,50 @@ func (bc *BaseCollector) getInstanceInfo(result bson.M) map[string]string {
 		instance["shard"] = shard
 	}
 
+	instance["instance"] = bc.formatInstance(instance["instance"])
+
 	return instance
 }
 
+// helloInstance reshapes a hello reply into the serverStatus fields getInstanceInfo reads
+func helloInstance(hello bson.M) bson.M {
+	instance := bson.M{}
+	if me, ok := hello["me"].(string); ok {
+		instance["host"] = me
+	}
+	if setName, ok := hello["setName"].(string); ok {
+		instance["repl"] = bson.M{"setName": setName}
+	}
+	return instance
+}
+
+// formatInstance applies the configured instance label template or port stripping to host
+func (bc *BaseCollector) formatInstance(host string) string {
+	if bc.config.InstanceLabel == "" && !bc.config.StripInstancePort {
+		return host
+	}
+
+	hostOnly, port := host, ""
+	if h, p, err := net.SplitHostPort(host); err == nil {
+		hostOnly, port = h, p
+	}
+
+	if bc.config.InstanceLabel == "" {
+		return hostOnly
+	}
+
+	hostname, _ := os.Hostname()
+	return strings.NewReplacer(
+		"{host}", hostOnly,
+		"{port}", port,
+		"{hostname}", hostname,
+	).Replace(bc.config.InstanceLabel)
+}
+
 func (bc *BaseCollector) isMetricEnabled(metricName string) bool {
+	if bc.flavorUnsupported(metricName) {
+		return false
+	}
 	for _, disabled := range bc.config.DisabledMetrics {
 		if disabled == metricName {
 			return false
@@ -81,6 +194,25 @@ func (bc *BaseCollector) isMetricEnabled(metricName string) bool {
 	return false
 }
 
+// optInCollectors are the collectors that check isMetricExplicitlyEnabled
+var optInCollectors = map[string]bool{
+	"atlas":                  true,
+	"canary":                 true,
+	"dbhash":                 true,
+	"index_selectivity":      true,
+	"opsmanager":             true,
+	"shard_key_distribution": true,
+}
+
+// isMetricExplicitlyEnabled is isMetricEnabled for opt-in collectors: an empty
+// enabled list does not turn them on, they must be listed by name or preset
+func (bc *BaseCollector) isMetricExplicitlyEnabled(metricName string) bool {
+	if len(bc.config.EnabledMetrics) == 0 {
+		return false
+	}
+	return bc.isMetricEnabled(metricName)
+}
+
 func (bc *BaseCollector) addCustomLabels(labels prometheus.Labels) {
 	for key, value := range bc.config.CustomLabels {
 		labels[key] = value
@@ -91,19 +223,81 @@ func (bc *BaseCollector) getNumericValue(value interface{}) *float64 {
 	return safeGetNumericValue(value)
 }
 
+func (bc *BaseCollector) getSignedValue(value interface{}) *float64 {
+	return safeGetSignedValue(value)
+}
+
 type MultiCollector struct {
 	collectors []Collector
 	logger     *zap.Logger
 	wg         sync.WaitGroup
 	mu         sync.Mutex
 	errors     []error
+
+	maxSeriesPerMetric int
+	seriesLimits       map[string]int
+	seriesDropped      *prometheus.CounterVec
+	unauthorized       *unauthorizedTracker
+	capabilities       *serverCapabilities
+	tracer             *scrapeTracer
+	lifetime           *runTracker
+
+	// watchdog abandons collector runs that exceed it (0 = wait indefinitely)
+	watchdog  time.Duration
+	abandoned *prometheus.CounterVec
+	// inFlight marks collectors with a run in progress and stuck those whose
+	// run in progress was abandoned; runChanged signals changes to either
+	inFlight   map[string]bool
+	stuck      map[string]bool
+	runChanged *sync.Cond
+
+	runs     map[string]collectorRun
+	panics   *prometheus.CounterVec
+	failures *prometheus.CounterVec
+
+	// statuses reports every collector's enablement for mongodb_exporter_collector_enabled (nil = not exported)
+	statuses func() []CollectorStatus
+	// configured reports whether configuration lets a collector run (nil = all may run).
+	// Collectors it excludes return without metrics, which would count as a failed run
+	configured func(name string) bool
+}
+
+// collectorRun remembers the outcome of a collector's recent scrapes for health reporting
+type collectorRun struct {
+	lastSuccess time.Time
+	lastError   string
+	lastErrorAt time.Time
 }
 
 func NewMultiCollector(logger *zap.Logger) *MultiCollector {
-	return &MultiCollector{
-		collectors: make([]Collector, 0),
-		logger:     logger,
+	mc := &MultiCollector{
+		collectors:    make([]Collector, 0),
+		logger:        logger,
+		seriesDropped: newSeriesDroppedCounter(),
+		abandoned:     newAbandonedCounter(),
+		inFlight:      make(map[string]bool),
+		stuck:         make(map[string]bool),
+		runs:          make(map[string]collectorRun),
+		panics:        newPanicsCounter(),
+		failures:      newFailuresCounter(),
 	}
+	mc.runChanged = sync.NewCond(&mc.mu)
+	return mc
+}
+
+// SetWatchdog abandons collector runs still going after timeout so they cannot stall the scrape
+func (mc *MultiCollector) SetWatchdog(timeout time.Duration) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	mc.watchdog = timeout
+}
+
+// SetSeriesLimits configures the per-family cardinality guardrails
+func (mc *MultiCollector) SetSeriesLimits(defaultLimit int, limits map[string]int) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	mc.maxSeriesPerMetric = defaultLimit
+	mc.seriesLimits = limits
 }
 
 func (mc *MultiCollector) AddCollector(collector Collector) {
@@ -126,36 +320,90 @@ func (mc *MultiCollector) RemoveCollector(name string) {
 	}
 }
 
+// hasCollector reports whether a collector with the given name is registered
+func (mc *MultiCollector) hasCollector(name string) bool {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	for _, collector := range mc.collectors {
+		if collector.Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
 func (mc *MultiCollector) Collect(ch chan<- prometheus.Metric) {
+	mc.collect(ch, nil)
+}
+
+// collect runs the registered collectors, or only those include accepts when it is set
+func (mc *MultiCollector) collect(ch chan<- prometheus.Metric, include func(name string) bool) {
 	mc.mu.Lock()
-	collectors := make([]Collector, len(mc.collectors))
-	copy(collectors, mc.collectors)
+	collectors := make([]Collector, 0, len(mc.collectors))
+	for _, collector := range mc.collectors {
+		if mc.configured != nil && !mc.configured(collector.Name()) {
+			continue
+		}
+		if include == nil || include(collector.Name()) {
+			collectors = append(collectors, collector)
+		}
+	}
+	limiter := newSeriesLimiter(mc.maxSeriesPerMetric, mc.seriesLimits, mc.seriesDropped)
+	tracer := mc.tracer
+	lifetime := mc.lifetime
+	watchdog := mc.watchdog
 	mc.mu.Unlock()
 
+	scrapeCtx := context.Background()
+	if tracer != nil {
+		var span trace.Span
+		scrapeCtx, span = tracer.startScrape()
+		defer span.End()
+	}
+
 	var errors []error
 	var errorsMu sync.Mutex
+	reportError := func(err error) {
+		errorsMu.Lock()
+		errors = append(errors, err)
+		errorsMu.Unlock()
+	}
 
 	var wg sync.WaitGroup
 	for _, collector := range collectors {
+		// Export zero counts up front so rate() and increase() see the first failure
+		mc.panics.WithLabelValues(collector.Name())
+		mc.failures.WithLabelValues(collector.Name())
+
+		// Once shutdown has begun, no new runs may start against the client
+		if lifetime != nil && !lifetime.start() {
+			continue
+		}
+
 		wg.Add(1)
 		go func(c Collector) {
 			defer wg.Done()
-			defer func() {
-				if r := recover(); r != nil {
-					errorsMu.Lock()
-					errors = append(errors, fmt.Errorf("panic in collector %s: %v", c.Name(), r))
-					errorsMu.Unlock()
-					mc.logger.Error("Collector panicked",
-						zap.String("collector", c.Name()),
-						zap.Any("panic", r))
-				}
-			}()
-			c.Collect(ch)
+			mc.run(scrapeCtx, c, ch, limiter, tracer, lifetime, watchdog, reportError)
 		}(collector)
 	}
 
 	wg.Wait()
 
+	mc.seriesDropped.Collect(ch)
+	mc.abandoned.Collect(ch)
+	mc.panics.Collect(ch)
+	mc.failures.Collect(ch)
+	if mc.statuses != nil {
+		collectEnablement(ch, mc.statuses())
+	}
+	if mc.unauthorized != nil {
+		mc.unauthorized.blocked.Collect(ch)
+	}
+	if mc.capabilities != nil {
+		mc.capabilities.unsupported.Collect(ch)
+	}
+
 	if len(errors) > 0 {
 		mc.logger.Error("Errors occurred during collection",
 			zap.Int("error_count", len(errors)),
@@ -163,28 +411,220 @@ func (mc *MultiCollector) Collect(ch chan<- prometheus.Metric) {
 	}
 }
 
+// run runs one collector for which lifetime.start has succeeded, relaying its
+// metrics to ch, and returns once it finishes or the watchdog abandons it
+func (mc *MultiCollector) run(ctx context.Context, c Collector, ch chan<- prometheus.Metric, limiter *seriesLimiter, tracer *scrapeTracer, lifetime *runTracker, watchdog time.Duration, reportError func(error)) {
+	// Overlapping scrapes take turns running a collector. A run abandoned
+	// by the watchdog may still be stuck, though; starting another one each
+	// scrape would pile up goroutines and MongoDB operations
+	if !mc.startRun(c.Name()) {
+		runErr := fmt.Errorf("collector %s skipped: abandoned run still in progress", c.Name())
+		mc.recordRun(c.Name(), runErr)
+		reportError(runErr)
+		if lifetime != nil {
+			lifetime.done()
+		}
+		return
+	}
+
+	gate := &runGate{out: ch}
+	done := make(chan struct{})
+	go mc.runCollector(ctx, c, limiter, tracer, lifetime, gate, reportError, done)
+
+	if watchdog <= 0 {
+		<-done
+		return
+	}
+
+	timer := time.NewTimer(watchdog)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+	case <-timer.C:
+		if !gate.abandon(func() { mc.abandonRun(c.Name()) }) {
+			<-done
+			return
+		}
+		runErr := fmt.Errorf("collector %s abandoned after exceeding the %s watchdog deadline", c.Name(), watchdog)
+		mc.recordRun(c.Name(), runErr)
+		mc.abandoned.WithLabelValues(c.Name()).Inc()
+		reportError(runErr)
+	}
+}
+
+// runCollector runs one collector, relaying its metrics through gate, and closes done when it returns
+func (mc *MultiCollector) runCollector(ctx context.Context, c Collector, limiter *seriesLimiter, tracer *scrapeTracer, lifetime *runTracker, gate *runGate, reportError func(error), done chan<- struct{}) {
+	defer close(done)
+	if lifetime != nil {
+		defer lifetime.done()
+	}
+	defer mc.finishRun(c.Name())
+
+	endSpan := func(int, error) {}
+	if tracer != nil {
+		endSpan = tracer.startCollector(ctx, c.Name())
+	}
+
+	var received int
+	defer func() {
+		var runErr error
+		panicked := false
+		if r := recover(); r != nil {
+			runErr = fmt.Errorf("panic in collector %s: %v", c.Name(), r)
+			panicked = true
+			mc.panics.WithLabelValues(c.Name()).Inc()
+			mc.logger.Error("Collector panicked",
+				zap.String("collector", c.Name()),
+				zap.Any("panic", r))
+		} else if received == 0 {
+			// Collectors log and swallow query failures, so an empty run is the failure signal
+			runErr = fmt.Errorf("collector %s produced no metrics", c.Name())
+		}
+
+		// The watchdog already recorded an abandoned run as failed
+		if !gate.finish() {
+			endSpan(received, fmt.Errorf("collector %s abandoned by watchdog", c.Name()))
+			return
+		}
+		if panicked {
+			reportError(runErr)
+		}
+		mc.recordRun(c.Name(), runErr)
+		endSpan(received, runErr)
+	}()
+
+	limited := make(chan prometheus.Metric)
+	forwarded := make(chan struct{})
+	go func() {
+		received = limiter.forward(limited, gate.send)
+		close(forwarded)
+	}()
+	defer func() {
+		close(limited)
+		<-forwarded
+	}()
+
+	c.Collect(limited)
+}
+
+// startRun marks a collector as running once its run in progress, if any,
+// returns. It fails while an abandoned run of it is still in progress
+func (mc *MultiCollector) startRun(name string) bool {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	for mc.inFlight[name] && !mc.stuck[name] {
+		mc.runChanged.Wait()
+	}
+	if mc.stuck[name] {
+		return false
+	}
+	mc.inFlight[name] = true
+	return true
+}
+
+// abandonRun marks the collector's run in progress as abandoned, so scrapes
+// waiting for it skip the collector instead
+func (mc *MultiCollector) abandonRun(name string) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	mc.stuck[name] = true
+	mc.runChanged.Broadcast()
+}
+
+func (mc *MultiCollector) finishRun(name string) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	delete(mc.inFlight, name)
+	delete(mc.stuck, name)
+	mc.runChanged.Broadcast()
+}
+
+func (mc *MultiCollector) recordRun(name string, err error) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	run := mc.runs[name]
+	if err != nil {
+		mc.failures.WithLabelValues(name).Inc()
+		run.lastError = err.Error()
+		run.lastErrorAt = time.Now()
+	} else {
+		run.lastSuccess = time.Now()
+	}
+	mc.runs[name] = run
+}
+
+func (mc *MultiCollector) lastRun(name string) collectorRun {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	return mc.runs[name]
+}
+
 func (mc *MultiCollector) Describe(ch chan<- *prometheus.Desc) {
-	for _, collector := range mc.collectors {
+	mc.mu.Lock()
+	collectors := make([]Collector, len(mc.collectors))
+	copy(collectors, mc.collectors)
+	mc.mu.Unlock()
+
+	for _, collector := range collectors {
 		collector.Describe(ch)
 	}
+	mc.seriesDropped.Describe(ch)
+	mc.abandoned.Describe(ch)
+	mc.panics.Describe(ch)
+	mc.failures.Describe(ch)
+	if mc.statuses != nil {
+		ch <- collectorEnabledDesc
+	}
+	if mc.unauthorized != nil {
+		mc.unauthorized.blocked.Describe(ch)
+	}
+	if mc.capabilities != nil {
+		mc.capabilities.unsupported.Describe(ch)
+	}
 }
 
 func (mc *MultiCollector) Name() string {
 	return "multi_collector"
 }
 
+var (
+	// ErrUnknownCollector is returned when managing a collector that was never initialized
+	ErrUnknownCollector = errors.New("unknown collector")
+	// ErrCollectorDisabledByConfig is returned when enabling a collector excluded by enabled/disabled metrics
+	ErrCollectorDisabledByConfig = errors.New("collector is disabled in configuration")
+)
+
+// CollectorStatus reports whether a collector currently runs on scrapes and how its last runs went
+type CollectorStatus struct {
+	Name        string     `json:"name"`
+	Enabled     bool       `json:"enabled"`
+	Configured  bool       `json:"configured"`
+	LastSuccess *time.Time `json:"last_success,omitempty"`
+	LastError   string     `json:"last_error,omitempty"`
+	LastErrorAt *time.Time `json:"last_error_at,omitempty"`
+}
+
 type CollectorManager struct {
 	multiCollector *MultiCollector
+	available      []Collector
+	initialized    bool
 	logger         *zap.Logger
 	client         *mongo.Client
 	config         CollectorConfig
 	ctx            context.Context
 	cancel         context.CancelFunc
+
+	// databases holds the collectors created by DatabaseCollector, by database
+	databases   map[string]*MultiCollector
+	databasesMu sync.Mutex
 }
 
 func NewCollectorManager(client *mongo.Client, logger *zap.Logger, config CollectorConfig) *CollectorManager {
 	ctx, cancel := context.WithCancel(context.Background())
-	return &CollectorManager{
+	config.lifetime = newRunTracker(ctx)
+	cm := &CollectorManager{
 		multiCollector: NewMultiCollector(logger),
 		logger:         logger,
 		client:         client,
@@ -192,9 +632,14 @@ func NewCollectorManager(client *mongo.Client, logger *zap.Logger, config Collec
 		ctx:            ctx,
 		cancel:         cancel,
 	}
+	cm.multiCollector.configured = cm.isCollectorEnabled
+	return cm
 }
 
 func (cm *CollectorManager) isMetricEnabled(metricName string) bool {
+	if caps := cm.config.capabilities; caps != nil && caps.collectorUnsupported(metricName) {
+		return false
+	}
 	for _, disabled := range cm.config.DisabledMetrics {
 		if disabled == metricName {
 			return false
@@ -202,7 +647,7 @@ func (cm *CollectorManager) isMetricEnabled(metricName string) bool {
 	}
 
 	if len(cm.config.EnabledMetrics) == 0 {
-		return true
+		return !optInCollectors[metricName]
 	}
 
 	for _, enabled := range cm.config.EnabledMetrics {
@@ -214,7 +659,33 @@ func (cm *CollectorManager) isMetricEnabled(metricName string) bool {
 	return false
 }
 
+// isCollectorEnabled reports whether configuration lets the named collector
+// run, accepting the alternative names collectors check for themselves
+func (cm *CollectorManager) isCollectorEnabled(name string) bool {
+	if name == "collstats" {
+		return cm.isMetricEnabled("collstats") || cm.isMetricEnabled("collection_stats")
+	}
+	return cm.isMetricEnabled(name)
+}
+
 func InitializeCollectors(client *mongo.Client, logger *zap.Logger, config CollectorConfig) []Collector {
+	if config.NamespaceCacheTTL > 0 && config.inventory == nil {
+		config.inventory = newNamespaceInventory(client, logger, config.NamespaceCacheTTL)
+	}
+	if config.TimeoutMax > 0 && config.timeouts == nil {
+		config.timeouts = newTimeoutTuner(config.TimeoutMin, config.TimeoutMax)
+	}
+	if config.unauthorized == nil {
+		config.unauthorized = newUnauthorizedTracker(logger)
+	}
+	if config.capabilities == nil {
+		config.capabilities = newServerCapabilities(logger)
+	}
+	config.capabilities.setFlavor(config.TargetFlavor)
+	if config.Tracing && config.tracer == nil {
+		config.tracer = newScrapeTracer()
+	}
+
 	collectors := []Collector{
 		NewServerStatusCollector(client, logger, config),
 		NewReplicaSetCollector(client, logger, config),
@@ -229,26 +700,131 @@ func InitializeCollectors(client *mongo.Client, logger *zap.Logger, config Colle
 		NewCursorCollector(client, logger, config),
 		NewProfileCollector(client, logger, config),
 		NewConnectionPoolCollector(client, logger, config),
+		NewIndexSelectivityCollector(client, logger, config),
+		NewShardKeyDistributionCollector(client, logger, config),
+		NewDBHashCollector(client, logger, config),
+		NewMaintenanceCollector(client, logger, config),
+		NewAtlasCollector(client, logger, config),
+		NewOpsManagerCollector(client, logger, config),
+		NewPingCollector(client, logger, config),
+		NewCanaryCollector(client, logger, config),
 	}
 
 	return collectors
 }
 
 func (cm *CollectorManager) InitializeCollectors() error {
+	if cm.config.unauthorized == nil {
+		cm.config.unauthorized = newUnauthorizedTracker(cm.logger)
+	}
+	if cm.config.capabilities == nil {
+		cm.config.capabilities = newServerCapabilities(cm.logger)
+	}
+	if cm.config.Tracing && cm.config.tracer == nil {
+		cm.config.tracer = newScrapeTracer()
+	}
 	collectors := InitializeCollectors(cm.client, cm.logger, cm.config)
 
 	// Verify collectors before registering
-	for _, collector := range collectors {
+	for i, collector := range collectors {
 		if collector == nil {
 			return fmt.Errorf("nil collector found")
 		}
+
+		if interval := cm.config.Intervals[collector.Name()]; interval > 0 {
+			cm.logger.Info("Scheduling collector on its own interval",
+				zap.String("collector", collector.Name()),
+				zap.Duration("interval", interval))
+			collectors[i] = newScheduledCollector(collector, interval, cm.config.StaleAfterRuns)
+		}
+	}
+
+	cm.available = collectors
+	cm.multiCollector = NewMultiCollector(cm.logger)
+	cm.multiCollector.collectors = append([]Collector(nil), collectors...)
+	cm.multiCollector.SetSeriesLimits(cm.config.MaxSeriesPerMetric, cm.config.SeriesLimits)
+	cm.multiCollector.unauthorized = cm.config.unauthorized
+	cm.multiCollector.capabilities = cm.config.capabilities
+	cm.multiCollector.tracer = cm.config.tracer
+	cm.multiCollector.lifetime = cm.config.lifetime
+	cm.multiCollector.SetWatchdog(cm.config.Watchdog)
+	cm.multiCollector.statuses = cm.Collectors
+	cm.multiCollector.configured = cm.isCollectorEnabled
+	cm.initialized = true
+
+	return nil
+}
+
+// Initialized reports whether InitializeCollectors has completed
+func (cm *CollectorManager) Initialized() bool {
+	return cm.initialized
+}
+
+// Collectors lists every initialized collector, whether it currently runs on scrapes and its last outcome
+func (cm *CollectorManager) Collectors() []CollectorStatus {
+	statuses := make([]CollectorStatus, 0, len(cm.available))
+	for _, collector := range cm.available {
+		status := CollectorStatus{
+			Name:       collector.Name(),
+			Enabled:    cm.multiCollector.hasCollector(collector.Name()),
+			Configured: cm.isCollectorEnabled(collector.Name()),
+		}
+
+		run := cm.multiCollector.lastRun(collector.Name())
+		if !run.lastSuccess.IsZero() {
+			lastSuccess := run.lastSuccess
+			status.LastSuccess = &lastSuccess
+		}
+		if run.lastError != "" {
+			lastErrorAt := run.lastErrorAt
+			status.LastError = run.lastError
+			status.LastErrorAt = &lastErrorAt
+		}
+
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// EnableCollector adds a collector removed with DisableCollector back to scrapes and
+// retries any of its commands skipped after repeated Unauthorized errors
+func (cm *CollectorManager) EnableCollector(name string) error {
+	collector := cm.findCollector(name)
+	if collector == nil {
+		return fmt.Errorf("%w: %s", ErrUnknownCollector, name)
+	}
+	// Collectors check enabled/disabled metrics themselves, so re-adding one
+	// excluded by configuration would silently export nothing
+	if !cm.isCollectorEnabled(name) {
+		return fmt.Errorf("%w: %s", ErrCollectorDisabledByConfig, name)
+	}
+	if cm.config.unauthorized != nil {
+		cm.config.unauthorized.reset(name)
+	}
+	if cm.multiCollector.hasCollector(name) {
+		return nil
 	}
 
-	cm.multiCollector = &MultiCollector{
-		collectors: collectors,
-		logger:     cm.logger,
+	cm.multiCollector.AddCollector(collector)
+	return nil
+}
+
+// DisableCollector stops a collector from running on scrapes until it is enabled again
+func (cm *CollectorManager) DisableCollector(name string) error {
+	if cm.findCollector(name) == nil {
+		return fmt.Errorf("%w: %s", ErrUnknownCollector, name)
 	}
 
+	cm.multiCollector.RemoveCollector(name)
+	return nil
+}
+
+func (cm *CollectorManager) findCollector(name string) Collector {
+	for _, collector := range cm.available {
+		if collector.Name() == name {
+			return collector
+		}
+	}
 	return nil
 }
 
@@ -256,9 +832,15 @@ func (cm *CollectorManager) GetCollector() Collector {
 	return cm.multiCollector
 }
 
-func (cm *CollectorManager) Shutdown() {
+// Shutdown cancels in-flight collector runs and waits for them to return until
+// ctx expires, so the MongoDB client can be disconnected safely afterwards
+func (cm *CollectorManager) Shutdown(ctx context.Context) error {
 	cm.cancel()
+	if err := cm.config.lifetime.drain(ctx); err != nil {
+		return err
+	}
 	cm.logger.Info("Collector manager shutdown")
+	return nil
 }
 
 func (cm *CollectorManager) Context() context.Context {
@@ -2,10 +2,14 @@ package collector
 
 import (
 	"context"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
@@ -128,6 +132,49 @@ func TestBaseCollector(t *testing.T) {
 	}
 }
 
+func TestHelloInstance(t *testing.T) {
+	bc := NewBaseCollector(nil, zap.NewNop(), CollectorConfig{})
+
+	instance := bc.getInstanceInfo(helloInstance(bson.M{"me": "db-2:27017", "setName": "rs0"}))
+	if instance["instance"] != "db-2:27017" || instance["replica_set"] != "rs0" {
+		t.Errorf("Expected the host and replica set from hello, got %v", instance)
+	}
+
+	instance = bc.getInstanceInfo(helloInstance(nil))
+	if instance["instance"] != "unknown" || instance["replica_set"] != "unknown" {
+		t.Errorf("Expected unknown labels without a hello reply, got %v", instance)
+	}
+}
+
+func TestMetricNameNamespace(t *testing.T) {
+	if got := (CollectorConfig{}).metricName("mongodb_connections"); got != "mongodb_connections" {
+		t.Errorf("Expected default prefix to be kept, got %s", got)
+	}
+
+	if got := (CollectorConfig{Namespace: "acme_mongodb"}).metricName("mongodb_connections"); got != "acme_mongodb_connections" {
+		t.Errorf("Expected prefix to be replaced, got %s", got)
+	}
+}
+
+func TestFormatInstance(t *testing.T) {
+	host := "db-01.prod.example.com:27017"
+
+	collector := NewBaseCollector(nil, zap.NewNop(), CollectorConfig{})
+	if got := collector.formatInstance(host); got != host {
+		t.Errorf("Expected host unchanged by default, got %s", got)
+	}
+
+	collector = NewBaseCollector(nil, zap.NewNop(), CollectorConfig{StripInstancePort: true})
+	if got := collector.formatInstance(host); got != "db-01.prod.example.com" {
+		t.Errorf("Expected port to be stripped, got %s", got)
+	}
+
+	collector = NewBaseCollector(nil, zap.NewNop(), CollectorConfig{InstanceLabel: "mongo-{host}-{port}"})
+	if got := collector.formatInstance(host); got != "mongo-db-01.prod.example.com-27017" {
+		t.Errorf("Expected template to be expanded, got %s", got)
+	}
+}
+
 func TestMultiCollector(t *testing.T) {
 	logger := zap.NewNop()
 	mc := NewMultiCollector(logger)
@@ -215,7 +262,9 @@ func TestCollectorManager(t *testing.T) {
 		t.Error("CollectorManager should return a context")
 	}
 
-	cm.Shutdown()
+	if err := cm.Shutdown(context.Background()); err != nil {
+		t.Errorf("Shutdown without runs in flight should not fail: %v", err)
+	}
 }
 
 func TestInitializeCollectors(t *testing.T) {
@@ -249,6 +298,268 @@ func TestInitializeCollectors(t *testing.T) {
 	}
 }
 
+func TestMultiCollectorSeriesLimits(t *testing.T) {
+	mc := NewMultiCollector(zap.NewNop())
+	mc.AddCollector(&MockCollector{name: "first"})
+	mc.AddCollector(&MockCollector{name: "second"})
+	mc.SetSeriesLimits(0, map[string]int{"mock_metric": 1})
+
+	ch := make(chan prometheus.Metric, 10)
+	mc.Collect(ch)
+	close(ch)
+
+	mockSeries := 0
+	droppedSeries := 0
+	for metric := range ch {
+		switch descName(metric.Desc()) {
+		case "mock_metric":
+			mockSeries++
+		case "mongodb_exporter_series_dropped_total":
+			droppedSeries++
+		}
+	}
+
+	if mockSeries != 1 {
+		t.Errorf("Expected 1 mock_metric series after limiting, got %d", mockSeries)
+	}
+
+	if droppedSeries != 1 {
+		t.Errorf("Expected dropped series counter to be emitted, got %d", droppedSeries)
+	}
+}
+
+type panickingCollector struct {
+	MockCollector
+}
+
+func (c *panickingCollector) Collect(ch chan<- prometheus.Metric) {
+	panic("boom")
+}
+
+func TestMultiCollectorRunStatus(t *testing.T) {
+	mc := NewMultiCollector(zap.NewNop())
+	mc.AddCollector(&MockCollector{name: "healthy"})
+	mc.AddCollector(&panickingCollector{MockCollector{name: "broken"}})
+
+	ch := make(chan prometheus.Metric, 10)
+	mc.Collect(ch)
+	close(ch)
+
+	if run := mc.lastRun("healthy"); run.lastSuccess.IsZero() || run.lastError != "" {
+		t.Errorf("Expected a successful run without error, got %+v", run)
+	}
+
+	if run := mc.lastRun("broken"); !run.lastSuccess.IsZero() || !strings.Contains(run.lastError, "boom") {
+		t.Errorf("Expected the panic to be recorded as the last error, got %+v", run)
+	}
+
+	if got := testutil.ToFloat64(mc.panics.WithLabelValues("broken")); got != 1 {
+		t.Errorf("Expected 1 panic for the broken collector, got %v", got)
+	}
+	if got := testutil.ToFloat64(mc.failures.WithLabelValues("broken")); got != 1 {
+		t.Errorf("Expected 1 error for the broken collector, got %v", got)
+	}
+	if got := testutil.ToFloat64(mc.failures.WithLabelValues("healthy")); got != 0 {
+		t.Errorf("Expected no errors for the healthy collector, got %v", got)
+	}
+}
+
+func TestMultiCollectorSkipsUnconfiguredCollectors(t *testing.T) {
+	mc := NewMultiCollector(zap.NewNop())
+	mc.AddCollector(&MockCollector{name: "on"})
+	mc.AddCollector(&emptyCollector{MockCollector{name: "off"}})
+	mc.configured = func(name string) bool { return name != "off" }
+
+	for i := 0; i < 3; i++ {
+		ch := make(chan prometheus.Metric, 10)
+		mc.Collect(ch)
+		close(ch)
+	}
+
+	if run := mc.lastRun("off"); run.lastError != "" {
+		t.Errorf("Expected no error for a collector excluded by configuration, got %q", run.lastError)
+	}
+	if got := testutil.ToFloat64(mc.failures.WithLabelValues("off")); got != 0 {
+		t.Errorf("Expected no errors for a collector excluded by configuration, got %v", got)
+	}
+	if run := mc.lastRun("on"); run.lastSuccess.IsZero() {
+		t.Errorf("Expected the configured collector to run, got %+v", run)
+	}
+}
+
+// emptyCollector returns without metrics, as collectors excluded by configuration do
+type emptyCollector struct {
+	MockCollector
+}
+
+func (c *emptyCollector) Collect(ch chan<- prometheus.Metric) {}
+
+type hangingCollector struct {
+	MockCollector
+	release chan struct{}
+}
+
+func (c *hangingCollector) Collect(ch chan<- prometheus.Metric) {
+	<-c.release
+	c.MockCollector.Collect(ch)
+}
+
+func TestMultiCollectorWatchdog(t *testing.T) {
+	hung := &hangingCollector{MockCollector{name: "hung"}, make(chan struct{})}
+	mc := NewMultiCollector(zap.NewNop())
+	mc.AddCollector(&MockCollector{name: "healthy"})
+	mc.AddCollector(hung)
+	mc.SetWatchdog(50 * time.Millisecond)
+
+	ch := make(chan prometheus.Metric, 10)
+	mc.Collect(ch)
+	close(ch)
+
+	mockSeries := 0
+	for metric := range ch {
+		if descName(metric.Desc()) == "mock_metric" {
+			mockSeries++
+		}
+	}
+	if mockSeries != 1 {
+		t.Errorf("Expected only the healthy collector's metric, got %d", mockSeries)
+	}
+
+	if run := mc.lastRun("hung"); !run.lastSuccess.IsZero() || !strings.Contains(run.lastError, "watchdog") {
+		t.Errorf("Expected the abandoned run to be recorded as failed, got %+v", run)
+	}
+	if got := testutil.ToFloat64(mc.abandoned.WithLabelValues("hung")); got != 1 {
+		t.Errorf("Expected 1 abandoned run, got %v", got)
+	}
+
+	// The hung run is still in flight, so the next scrape must not start another
+	ch = make(chan prometheus.Metric, 10)
+	mc.Collect(ch)
+	close(ch)
+	if run := mc.lastRun("hung"); !strings.Contains(run.lastError, "skipped") {
+		t.Errorf("Expected the hung collector to be skipped, got %+v", run)
+	}
+
+	// Once released, the late metric is dropped and the collector runs again
+	close(hung.release)
+	deadline := time.Now().Add(time.Second)
+	for !mc.startRun("hung") {
+		if time.Now().After(deadline) {
+			t.Fatal("Released collector should finish its abandoned run")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	mc.finishRun("hung")
+
+	ch = make(chan prometheus.Metric, 10)
+	mc.Collect(ch)
+	close(ch)
+	if run := mc.lastRun("hung"); run.lastSuccess.IsZero() {
+		t.Errorf("Expected the released collector to succeed, got %+v", run)
+	}
+}
+
+func TestMultiCollectorOverlappingScrapes(t *testing.T) {
+	slow := &hangingCollector{MockCollector{name: "slow"}, make(chan struct{})}
+	mc := NewMultiCollector(zap.NewNop())
+	mc.AddCollector(slow)
+	mc.SetWatchdog(time.Minute)
+
+	// A scrape overlapping a run that is merely slow waits for it rather than skipping
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ch := make(chan prometheus.Metric, 10)
+			mc.Collect(ch)
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(slow.release)
+	wg.Wait()
+
+	if run := mc.lastRun("slow"); run.lastSuccess.IsZero() || run.lastError != "" {
+		t.Errorf("Expected both overlapping runs to succeed, got %+v", run)
+	}
+	if got := testutil.ToFloat64(mc.failures.WithLabelValues("slow")); got != 0 {
+		t.Errorf("Expected no failures, got %v", got)
+	}
+}
+
+type countingCollector struct {
+	MockCollector
+	runs int
+}
+
+func (c *countingCollector) Collect(ch chan<- prometheus.Metric) {
+	c.runs++
+	c.MockCollector.Collect(ch)
+}
+
+func TestScheduledCollector(t *testing.T) {
+	inner := &countingCollector{MockCollector: MockCollector{name: "collstats"}}
+	scheduled := newScheduledCollector(inner, time.Hour, 2)
+
+	for i := 0; i < 3; i++ {
+		ch := make(chan prometheus.Metric, 10)
+		scheduled.Collect(ch)
+		close(ch)
+
+		if len(ch) != 1 {
+			t.Errorf("Scrape %d: expected 1 cached or fresh metric, got %d", i, len(ch))
+		}
+	}
+
+	if inner.runs != 1 {
+		t.Errorf("Expected collector to run once within its interval, ran %d times", inner.runs)
+	}
+
+	if scheduled.Name() != "collstats" {
+		t.Errorf("Scheduled collector should keep the wrapped name, got %s", scheduled.Name())
+	}
+}
+
+type namespaceCollector struct {
+	MockCollector
+	collections []string
+}
+
+func (c *namespaceCollector) Collect(ch chan<- prometheus.Metric) {
+	desc := prometheus.NewDesc("mock_collection_size", "Mock collection size", []string{"collection"}, nil)
+	for _, collection := range c.collections {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, 1.0, collection)
+	}
+}
+
+func TestScheduledCollectorEvictsStaleSeries(t *testing.T) {
+	inner := &namespaceCollector{MockCollector: MockCollector{name: "collstats"}, collections: []string{"orders", "users"}}
+	scheduled := newScheduledCollector(inner, 0, 2)
+
+	collect := func() int {
+		ch := make(chan prometheus.Metric, 10)
+		scheduled.Collect(ch)
+		close(ch)
+		return len(ch)
+	}
+
+	collect()
+	inner.collections = []string{"orders"}
+
+	// One missed run may be a transient failure, so the last value is still served
+	if got := collect(); got != 2 {
+		t.Errorf("Series missed once should still be reported, got %d series", got)
+	}
+	if got := collect(); got != 1 {
+		t.Errorf("Series missed twice should be evicted, got %d series", got)
+	}
+
+	inner.collections = []string{"orders", "users"}
+	if got := collect(); got != 2 {
+		t.Errorf("Evicted series should come back once observed again, got %d series", got)
+	}
+}
+
 type MockCollector struct {
 	name string
 }
@@ -268,3 +579,79 @@ func (m *MockCollector) Collect(ch chan<- prometheus.Metric) {
 func (m *MockCollector) Name() string {
 	return m.name
 }
+
+func TestScheduledCollectorRefresh(t *testing.T) {
+	inner := &countingCollector{MockCollector: MockCollector{name: "collstats"}}
+	scheduled := newScheduledCollector(inner, time.Hour, 2)
+
+	ch := make(chan prometheus.Metric, 10)
+	scheduled.Collect(ch)
+	close(ch)
+
+	mc := NewMultiCollector(zap.NewNop())
+	scheduled.expire()
+	if series, err := mc.runNow(scheduled); err != nil || series != 1 {
+		t.Errorf("Expected refresh to report 1 series, got %d (%v)", series, err)
+	}
+	if run := mc.lastRun("collstats"); run.lastSuccess.IsZero() {
+		t.Errorf("Expected the refresh to be recorded as a run, got %+v", run)
+	}
+	if inner.runs != 2 {
+		t.Errorf("Expected refresh to run the collector within its interval, ran %d times", inner.runs)
+	}
+
+	// The forced run restarts the interval, so the next scrape is served from cache
+	ch = make(chan prometheus.Metric, 10)
+	scheduled.Collect(ch)
+	close(ch)
+	if inner.runs != 2 {
+		t.Errorf("Expected the scrape after a refresh to be cached, collector ran %d times", inner.runs)
+	}
+}
+
+func TestRefreshRecoversPanics(t *testing.T) {
+	mc := NewMultiCollector(zap.NewNop())
+	broken := newScheduledCollector(&panickingCollector{MockCollector{name: "collstats"}}, time.Hour, 1)
+
+	if _, err := mc.runNow(broken); err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("Expected the panic to be reported, got %v", err)
+	}
+	if got := testutil.ToFloat64(mc.panics.WithLabelValues("collstats")); got != 1 {
+		t.Errorf("Expected 1 panic counted for the refresh, got %v", got)
+	}
+}
+
+func TestCollectorEnabledMetric(t *testing.T) {
+	cm := NewCollectorManager(nil, zap.NewNop(), CollectorConfig{DisabledMetrics: []string{"profile"}})
+	if err := cm.InitializeCollectors(); err != nil {
+		t.Fatalf("Failed to initialize collectors: %v", err)
+	}
+	if err := cm.DisableCollector("collstats"); err != nil {
+		t.Fatalf("DisableCollector failed: %v", err)
+	}
+
+	ch := make(chan prometheus.Metric, 100)
+	collectEnablement(ch, cm.Collectors())
+	close(ch)
+
+	enabled := make(map[string]float64)
+	for metric := range ch {
+		var m dto.Metric
+		if err := metric.Write(&m); err != nil {
+			t.Fatalf("Failed to read metric: %v", err)
+		}
+		enabled[m.GetLabel()[0].GetValue()] = m.GetGauge().GetValue()
+	}
+
+	expected := map[string]float64{
+		"server_status": 1,
+		"profile":       0, // disabled_metrics
+		"collstats":     0, // disabled at runtime
+		"atlas":         0, // opt-in, not listed in enabled_metrics
+	}
+	for name, want := range expected {
+		if got, ok := enabled[name]; !ok || got != want {
+			t.Errorf("Expected %s enabled = %v, got %v (reported: %v)", name, want, got, ok)
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.uber.org/zap"
 )
 
@@ -14,138 +15,171 @@ type CollStatsCollector struct {
 	*BaseCollector
 	descriptors          map[string]*prometheus.Desc
 	monitoredCollections []string
+	topNBySize           int
+	topNByActivity       int
+	wiredTigerDetail     bool
+	growth               *growthTracker
+	exactCounts          bool
+	exactCollections     map[string]bool
 }
 
 func NewCollStatsCollector(client *mongo.Client, logger *zap.Logger, config CollectorConfig) *CollStatsCollector {
 	labels := []string{"instance", "replica_set", "shard", "database", "collection"}
 	indexLabels := append(labels, "index")
 
-	// Load monitored collections from config
-	var configMonitoredCollections []string
-	if collStatsConfig, ok := config.Collectors["collstats"]; ok {
-		if collStats, ok := collStatsConfig.(map[string]interface{}); ok {
-			if monitored, ok := collStats["monitored_collections"].([]string); ok {
-				configMonitoredCollections = monitored
-			} else if monitored, ok := collStats["monitored_collections"].([]interface{}); ok {
-				for _, coll := range monitored {
-					if collStr, ok := coll.(string); ok {
-						configMonitoredCollections = append(configMonitoredCollections, collStr)
-					}
-				}
-			}
+	// Load monitored collections and top-N selection from config
+	options := collectorOptions(config, "collstats")
+	configMonitoredCollections := getStringSliceOption(options, "monitored_collections")
+	topNBySize := getIntOption(options, "top_n_by_size", 0)
+	topNByActivity := getIntOption(options, "top_n_by_activity", 0)
+	wiredTigerDetail := getBoolOption(options, "wiredtiger_detail", false)
+	exactCounts := getStringOption(options, "count_mode", "estimated") == "exact"
+
+	var exactCollections map[string]bool
+	if names := getStringSliceOption(options, "exact_count_collections"); len(names) > 0 {
+		exactCollections = make(map[string]bool, len(names))
+		for _, name := range names {
+			exactCollections[name] = true
 		}
 	}
 
 	// Log the configuration for debugging
 	logger.Debug("Collection stats collector configuration",
 		zap.Strings("monitored_collections", configMonitoredCollections),
+		zap.Int("top_n_by_size", topNBySize),
+		zap.Int("top_n_by_activity", topNByActivity),
 		zap.Strings("enabled_metrics", config.EnabledMetrics))
 
 	descriptors := map[string]*prometheus.Desc{
 		"collection_size_bytes": prometheus.NewDesc(
-			"mongodb_collstats_size_bytes",
+			config.metricName("mongodb_collstats_size_bytes"),
 			"The total size of all records in the collection in bytes",
 			labels,
 			nil,
 		),
 		"collection_storage_size_bytes": prometheus.NewDesc(
-			"mongodb_collstats_storage_size_bytes",
+			config.metricName("mongodb_collstats_storage_size_bytes"),
 			"Total amount of storage allocated to the collection in bytes",
 			labels,
 			nil,
 		),
 		"collection_avg_obj_size_bytes": prometheus.NewDesc(
-			"mongodb_collstats_avg_obj_size_bytes",
+			config.metricName("mongodb_collstats_avg_obj_size_bytes"),
 			"Average object size in the collection in bytes",
 			labels,
 			nil,
 		),
 		"collection_count": prometheus.NewDesc(
-			"mongodb_collstats_count",
+			config.metricName("mongodb_collstats_count"),
 			"Number of documents in the collection",
 			labels,
 			nil,
 		),
 		"collection_indexes_count": prometheus.NewDesc(
-			"mongodb_collstats_indexes_count",
+			config.metricName("mongodb_collstats_indexes_count"),
 			"Number of indexes in the collection",
 			labels,
 			nil,
 		),
 		"collection_total_index_size_bytes": prometheus.NewDesc(
-			"mongodb_collstats_total_index_size_bytes",
+			config.metricName("mongodb_collstats_total_index_size_bytes"),
 			"Total size of all indexes in the collection in bytes",
 			labels,
 			nil,
 		),
 		"collection_total_size_bytes": prometheus.NewDesc(
-			"mongodb_collstats_total_size_bytes",
+			config.metricName("mongodb_collstats_total_size_bytes"),
 			"Total size of collection including documents and indexes in bytes",
 			labels,
 			nil,
 		),
 		"collection_index_size_bytes": prometheus.NewDesc(
-			"mongodb_collstats_index_size_bytes",
+			config.metricName("mongodb_collstats_index_size_bytes"),
 			"Size of specific index in bytes",
 			indexLabels,
 			nil,
 		),
 		"collection_capped": prometheus.NewDesc(
-			"mongodb_collstats_capped",
+			config.metricName("mongodb_collstats_capped"),
 			"Whether the collection is capped (1) or not (0)",
 			labels,
 			nil,
 		),
+		"collection_timeseries": prometheus.NewDesc(
+			config.metricName("mongodb_collstats_timeseries"),
+			"Whether the collection is a time-series collection (1) or not (0); its stats describe the bucket collection",
+			labels,
+			nil,
+		),
+		"collection_clustered": prometheus.NewDesc(
+			config.metricName("mongodb_collstats_clustered"),
+			"Whether the collection is clustered by _id (1) or not (0); clustered collections have no separate _id index",
+			labels,
+			nil,
+		),
 		"collection_max_documents": prometheus.NewDesc(
-			"mongodb_collstats_max_documents",
+			config.metricName("mongodb_collstats_max_documents"),
 			"Maximum number of documents in capped collection",
 			labels,
 			nil,
 		),
 		"collection_max_size_bytes": prometheus.NewDesc(
-			"mongodb_collstats_max_size_bytes",
+			config.metricName("mongodb_collstats_max_size_bytes"),
 			"Maximum size of capped collection in bytes",
 			labels,
 			nil,
 		),
 		"collection_wiredtiger_cache_bytes": prometheus.NewDesc(
-			"mongodb_collstats_wiredtiger_cache_bytes",
+			config.metricName("mongodb_collstats_wiredtiger_cache_bytes"),
 			"WiredTiger cache usage for collection in bytes",
 			labels,
 			nil,
 		),
 		"collection_wiredtiger_block_checkpoint_bytes": prometheus.NewDesc(
-			"mongodb_collstats_wiredtiger_block_checkpoint_bytes",
+			config.metricName("mongodb_collstats_wiredtiger_block_checkpoint_bytes"),
 			"WiredTiger block manager checkpoint bytes for collection",
 			labels,
 			nil,
 		),
 		"collection_wiredtiger_compression_ratio": prometheus.NewDesc(
-			"mongodb_collstats_wiredtiger_compression_ratio",
+			config.metricName("mongodb_collstats_wiredtiger_compression_ratio"),
 			"WiredTiger compression ratio for collection",
 			labels,
 			nil,
 		),
 		"collection_ops_total": prometheus.NewDesc(
-			"mongodb_collstats_ops_total",
+			config.metricName("mongodb_collstats_ops_total"),
 			"Total number of operations performed on the collection",
 			append(labels, "operation"),
 			nil,
 		),
 		"collection_latency_microseconds": prometheus.NewDesc(
-			"mongodb_collstats_latency_microseconds",
+			config.metricName("mongodb_collstats_latency_microseconds"),
 			"Average latency for operations on the collection in microseconds",
 			append(labels, "operation"),
 			nil,
 		),
 		"collection_read_concern_counters": prometheus.NewDesc(
-			"mongodb_collstats_read_concern_counters",
+			config.metricName("mongodb_collstats_read_concern_counters"),
 			"Read concern usage counters for collection",
 			append(labels, "read_concern"),
 			nil,
 		),
 	}
 
+	for key, desc := range growthDescriptors(config) {
+		descriptors[key] = desc
+	}
+	for key, desc := range schemaValidationDescriptors(config) {
+		descriptors[key] = desc
+	}
+
+	if wiredTigerDetail {
+		for key, desc := range wiredTigerDetailDescriptors(config) {
+			descriptors[key] = desc
+		}
+	}
+
 	// Parse monitored collections from config if provided
 	var monitoredCollections []string
 	if len(config.EnabledMetrics) > 0 {
@@ -168,6 +202,12 @@ func NewCollStatsCollector(client *mongo.Client, logger *zap.Logger, config Coll
 		BaseCollector:        NewBaseCollector(client, logger, config),
 		descriptors:          descriptors,
 		monitoredCollections: monitoredCollections,
+		topNBySize:           topNBySize,
+		topNByActivity:       topNByActivity,
+		wiredTigerDetail:     wiredTigerDetail,
+		growth:               newGrowthTracker(),
+		exactCounts:          exactCounts,
+		exactCollections:     exactCollections,
 	}
 }
 
@@ -179,11 +219,11 @@ func (c *CollStatsCollector) Collect(ch chan<- prometheus.Metric) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
-	defer cancel()
+	ctx, done := c.collectContext("collstats", 15*time.Second)
+	defer done()
 
 	// Get list of databases with optimized timeout
-	databases, err := getDatabasesWithTimeout(ctx, c.client, 10*time.Second)
+	databases, err := c.listDatabases(ctx, 10*time.Second)
 	if err != nil {
 		c.logger.Error("Failed to list databases", zap.Error(err))
 		return
@@ -193,6 +233,7 @@ func (c *CollStatsCollector) Collect(ch chan<- prometheus.Metric) {
 
 	instance := c.getInstanceInfo(bson.M{})
 
+	var namespaces []namespace
 	for _, dbName := range databases {
 		// Skip system databases unless explicitly requested
 		if c.shouldSkipDatabase(dbName) {
@@ -201,26 +242,49 @@ func (c *CollStatsCollector) Collect(ch chan<- prometheus.Metric) {
 		}
 
 		c.logger.Debug("Processing database", zap.String("database", dbName))
-		c.collectDatabaseCollectionStats(ctx, ch, dbName, instance)
+		namespaces = append(namespaces, c.listMonitoredNamespaces(ctx, dbName)...)
+		c.collectSchemaValidation(ctx, ch, dbName, instance)
+	}
+
+	if c.topNBySize > 0 || c.topNByActivity > 0 {
+		namespaces = c.selectTopNamespaces(ctx, namespaces)
+	}
+
+	forEachNamespace(ctx, namespaces, c.config.Parallelism, func(ns namespace) {
+		c.logger.Debug("Processing collection", zap.String("database", ns.Database), zap.String("collection", ns.Collection))
+		c.collectCollectionStats(ctx, ch, ns, instance)
+	})
+
+	if ctx.Err() != nil {
+		c.logger.Warn("Collection stats collection hit the scrape deadline",
+			zap.Int("namespaces", len(namespaces)))
 	}
 
 	c.logger.Debug("Collection stats collector completed")
 }
 
-func (c *CollStatsCollector) collectDatabaseCollectionStats(ctx context.Context, ch chan<- prometheus.Metric, dbName string, instance map[string]string) {
-	db := c.client.Database(dbName)
-
+// listMonitoredNamespaces returns the non-system collections of dbName that pass the monitored list
+func (c *CollStatsCollector) listMonitoredNamespaces(ctx context.Context, dbName string) []namespace {
 	// Get list of collections with optimized timeout
-	collections, err := getCollectionsWithTimeout(ctx, db, 10*time.Second)
+	collections, err := c.listCollections(ctx, dbName, 10*time.Second)
 	if err != nil {
 		c.logger.Error("Failed to list collections",
 			zap.String("database", dbName),
 			zap.Error(err))
-		return
+		return nil
 	}
 
 	c.logger.Debug("Found collections", zap.String("database", dbName), zap.Strings("collections", collections))
 
+	// Views, time-series and clustered collections need different handling
+	kinds, err := listCollectionKinds(ctx, c.client.Database(dbName), 10*time.Second)
+	if err != nil {
+		c.logger.Debug("Failed to list collection types",
+			zap.String("database", dbName),
+			zap.Error(err))
+	}
+
+	var namespaces []namespace
 	for _, collName := range collections {
 		// Skip system collections unless explicitly requested
 		if c.shouldSkipCollection(collName) {
@@ -234,15 +298,92 @@ func (c *CollStatsCollector) collectDatabaseCollectionStats(ctx context.Context,
 			continue
 		}
 
-		c.logger.Debug("Processing collection", zap.String("database", dbName), zap.String("collection", collName))
-		c.collectCollectionStats(ctx, ch, dbName, collName, instance)
+		kind := kinds[collName]
+		if kind == kindView {
+			c.logger.Debug("Skipping view", zap.String("database", dbName), zap.String("collection", collName))
+			continue
+		}
+
+		namespaces = append(namespaces, namespace{Database: dbName, Collection: collName, Kind: kind})
+	}
+
+	return namespaces
+}
+
+// selectTopNamespaces narrows namespaces to the union of the largest and the busiest
+// collections, using cheap dataSize estimates and the admin top command instead of collStats
+func (c *CollStatsCollector) selectTopNamespaces(ctx context.Context, namespaces []namespace) []namespace {
+	selected := make(map[namespace]bool)
+
+	if c.topNBySize > 0 {
+		sizes := make(map[namespace]float64, len(namespaces))
+		for _, ns := range namespaces {
+			var result bson.M
+			err := c.runGatedCommand(ctx, "collstats", c.client.Database(ns.Database), bson.D{
+				{"dataSize", ns.Database + "." + ns.statsCollection()},
+				{"estimate", true},
+			}, 5*time.Second, &result)
+			if err != nil {
+				c.logger.Debug("Failed to get collection size estimate",
+					zap.String("namespace", ns.String()),
+					zap.Error(err))
+				continue
+			}
+			if size := c.getNumericValue(result["size"]); size != nil {
+				sizes[ns] = *size
+			}
+		}
+
+		for _, ns := range topNamespaces(sizes, c.topNBySize) {
+			selected[ns] = true
+		}
+	}
+
+	if c.topNByActivity > 0 {
+		activity := make(map[namespace]float64, len(namespaces))
+
+		var result bson.M
+		err := c.runGatedCommand(ctx, "collstats", c.client.Database("admin"), bson.D{{"top", 1}}, 5*time.Second, &result)
+		if err != nil {
+			c.logger.Debug("Failed to run top command", zap.Error(err))
+		} else if totals, ok := result["totals"].(bson.M); ok {
+			for _, ns := range namespaces {
+				if nsTotals, ok := totals[ns.String()].(bson.M); ok {
+					if total, ok := nsTotals["total"].(bson.M); ok {
+						if count := c.getNumericValue(total["count"]); count != nil {
+							activity[ns] = *count
+						}
+					}
+				}
+			}
+		}
+
+		for _, ns := range topNamespaces(activity, c.topNByActivity) {
+			selected[ns] = true
+		}
+	}
+
+	// Keep the original listing order for stable output
+	var result []namespace
+	for _, ns := range namespaces {
+		if selected[ns] {
+			result = append(result, ns)
+		}
 	}
+
+	c.logger.Debug("Selected top collections for collStats",
+		zap.Int("candidates", len(namespaces)),
+		zap.Int("selected", len(result)))
+
+	return result
 }
 
-func (c *CollStatsCollector) collectCollectionStats(ctx context.Context, ch chan<- prometheus.Metric, dbName, collName string, instance map[string]string) {
+func (c *CollStatsCollector) collectCollectionStats(ctx context.Context, ch chan<- prometheus.Metric, ns namespace, instance map[string]string) {
+	dbName, collName := ns.Database, ns.Collection
+
 	var stats bson.M
-	err := runCommandWithTimeout(ctx, c.client.Database(dbName), bson.D{
-		{"collStats", collName},
+	err := c.runGatedCommand(ctx, "collstats", c.client.Database(dbName), bson.D{
+		{"collStats", ns.statsCollection()},
 	}, 10*time.Second, &stats)
 
 	if err != nil {
@@ -253,13 +394,38 @@ func (c *CollStatsCollector) collectCollectionStats(ctx context.Context, ch chan
 		return
 	}
 
+	if c.exactCounts && (c.exactCollections == nil || c.exactCollections[ns.String()]) {
+		c.applyExactCount(ctx, stats, ns)
+	}
+
 	c.collectBasicCollectionMetrics(ch, stats, dbName, collName, instance)
+	c.collectKindMetrics(ch, ns, instance)
+	c.collectGrowthMetrics(ch, stats, dbName, collName, instance)
 	c.collectIndexMetrics(ch, stats, dbName, collName, instance)
 	c.collectWiredTigerMetrics(ch, stats, dbName, collName, instance)
+	if c.wiredTigerDetail {
+		c.collectWiredTigerDetail(ch, stats, dbName, collName, instance)
+	}
 	c.collectLatencyMetrics(ch, stats, dbName, collName, instance)
 	c.collectReadConcernMetrics(ch, stats, dbName, collName, instance)
 }
 
+// applyExactCount replaces the metadata document count in stats with a
+// countDocuments result. collStats counts can drift after unclean shutdowns and
+// include orphaned documents on shards, at the cost of scanning the _id index.
+// Time-series collections are counted by measurement rather than by bucket
+func (c *CollStatsCollector) applyExactCount(ctx context.Context, stats bson.M, ns namespace) {
+	count, err := c.countDocuments(ctx, "collstats", c.client.Database(ns.Database).Collection(ns.Collection), bson.D{},
+		&options.CountOptions{MaxTime: maxTime(ctx)})
+	if err != nil {
+		c.logger.Debug("Failed to count documents, keeping the estimated count",
+			zap.String("namespace", ns.String()),
+			zap.Error(err))
+		return
+	}
+	stats["count"] = count
+}
+
 func (c *CollStatsCollector) collectBasicCollectionMetrics(ch chan<- prometheus.Metric, stats bson.M, dbName, collName string, instance map[string]string) {
 	labels := []string{instance["instance"], instance["replica_set"], instance["shard"], dbName, collName}
 
@@ -327,10 +493,26 @@ func (c *CollStatsCollector) collectBasicCollectionMetrics(ch chan<- prometheus.
 	}
 }
 
+// collectKindMetrics reports whether the collection is time-series or clustered
+func (c *CollStatsCollector) collectKindMetrics(ch chan<- prometheus.Metric, ns namespace, instance map[string]string) {
+	labels := []string{instance["instance"], instance["replica_set"], instance["shard"], ns.Database, ns.Collection}
+
+	for kind, descKey := range map[collectionKind]string{
+		kindTimeseries: "collection_timeseries",
+		kindClustered:  "collection_clustered",
+	} {
+		value := 0.0
+		if ns.Kind == kind {
+			value = 1
+		}
+		ch <- prometheus.MustNewConstMetric(c.descriptors[descKey], prometheus.GaugeValue, value, labels...)
+	}
+}
+
 func (c *CollStatsCollector) collectIndexMetrics(ch chan<- prometheus.Metric, stats bson.M, dbName, collName string, instance map[string]string) {
 	if indexSizes, ok := stats["indexSizes"].(bson.M); ok {
 		for indexName, size := range indexSizes {
-			if sizeValue, ok := size.(int64); ok {
+			if sizeValue, ok := toInt64(size); ok {
 				ch <- prometheus.MustNewConstMetric(
 					c.descriptors["collection_index_size_bytes"],
 					prometheus.GaugeValue,
@@ -353,7 +535,7 @@ func (c *CollStatsCollector) collectWiredTigerMetrics(ch chan<- prometheus.Metri
 
 		// Cache metrics
 		if cache, ok := wiredTiger["cache"].(bson.M); ok {
-			if cacheBytes, ok := cache["bytes currently in the cache"].(int64); ok {
+			if cacheBytes, ok := toInt64(cache["bytes currently in the cache"]); ok {
 				ch <- prometheus.MustNewConstMetric(
 					c.descriptors["collection_wiredtiger_cache_bytes"],
 					prometheus.GaugeValue,
@@ -365,7 +547,7 @@ func (c *CollStatsCollector) collectWiredTigerMetrics(ch chan<- prometheus.Metri
 
 		// Block manager metrics
 		if blockManager, ok := wiredTiger["block-manager"].(bson.M); ok {
-			if checkpointSize, ok := blockManager["checkpoint size"].(int64); ok {
+			if checkpointSize, ok := toInt64(blockManager["checkpoint size"]); ok {
 				ch <- prometheus.MustNewConstMetric(
 					c.descriptors["collection_wiredtiger_block_checkpoint_bytes"],
 					prometheus.GaugeValue,
@@ -377,7 +559,7 @@ func (c *CollStatsCollector) collectWiredTigerMetrics(ch chan<- prometheus.Metri
 
 		// Compression metrics
 		if compression, ok := wiredTiger["compression"].(bson.M); ok {
-			if ratio, ok := compression["compression ratio"].(float64); ok {
+			if ratio, ok := toFloat64(compression["compression ratio"]); ok {
 				ch <- prometheus.MustNewConstMetric(
 					c.descriptors["collection_wiredtiger_compression_ratio"],
 					prometheus.GaugeValue,
@@ -395,7 +577,7 @@ func (c *CollStatsCollector) collectLatencyMetrics(ch chan<- prometheus.Metric,
 
 		for _, operation := range operations {
 			if opStats, ok := latencyStats[operation].(bson.M); ok {
-				if ops, ok := opStats["ops"].(int64); ok && ops > 0 {
+				if ops, ok := toInt64(opStats["ops"]); ok && ops > 0 {
 					ch <- prometheus.MustNewConstMetric(
 						c.descriptors["collection_ops_total"],
 						prometheus.CounterValue,
@@ -409,7 +591,7 @@ func (c *CollStatsCollector) collectLatencyMetrics(ch chan<- prometheus.Metric,
 					)
 				}
 
-				if latency, ok := opStats["latency"].(int64); ok {
+				if latency, ok := toInt64(opStats["latency"]); ok {
 					ch <- prometheus.MustNewConstMetric(
 						c.descriptors["collection_latency_microseconds"],
 						prometheus.GaugeValue,
@@ -432,7 +614,7 @@ func (c *CollStatsCollector) collectReadConcernMetrics(ch chan<- prometheus.Metr
 		readConcernLevels := []string{"local", "available", "majority", "linearizable", "snapshot"}
 
 		for _, level := range readConcernLevels {
-			if count, ok := readConcern[level].(int64); ok {
+			if count, ok := toInt64(readConcern[level]); ok {
 				ch <- prometheus.MustNewConstMetric(
 					c.descriptors["collection_read_concern_counters"],
 					prometheus.CounterValue,
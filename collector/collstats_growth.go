@@ -0,0 +1,94 @@
+package collector
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// growthSampleTTL bounds how old a previous sample may be to derive a rate.
+// Older samples belong to dropped or no longer selected collections
+const growthSampleTTL = time.Hour
+
+// growthSample is a collection's size and document count at one scrape
+type growthSample struct {
+	at    time.Time
+	size  float64
+	count float64
+}
+
+// growthTracker remembers the last collStats sample of each namespace so
+// growth rates can be derived in the exporter instead of with PromQL
+type growthTracker struct {
+	mu      sync.Mutex
+	samples map[namespace]growthSample
+}
+
+func newGrowthTracker() *growthTracker {
+	return &growthTracker{samples: make(map[namespace]growthSample)}
+}
+
+// observe records a sample and returns the byte and document growth per
+// second since the previous one. ok is false for the first sample of a
+// namespace, so a restart never reports a rate over an unknown interval
+func (t *growthTracker) observe(ns namespace, sample growthSample) (bytesPerSecond, docsPerSecond float64, ok bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	previous, seen := t.samples[ns]
+	t.samples[ns] = sample
+
+	// Forget namespaces that stopped reporting
+	for key, s := range t.samples {
+		if sample.at.Sub(s.at) > growthSampleTTL {
+			delete(t.samples, key)
+		}
+	}
+
+	elapsed := sample.at.Sub(previous.at).Seconds()
+	if !seen || elapsed <= 0 || elapsed > growthSampleTTL.Seconds() {
+		return 0, 0, false
+	}
+	return (sample.size - previous.size) / elapsed, (sample.count - previous.count) / elapsed, true
+}
+
+// growthDescriptors describes the derived growth rate gauges
+func growthDescriptors(config CollectorConfig) map[string]*prometheus.Desc {
+	labels := []string{"instance", "replica_set", "shard", "database", "collection"}
+
+	return map[string]*prometheus.Desc{
+		"collection_growth_bytes_per_second": prometheus.NewDesc(
+			config.metricName("mongodb_collstats_growth_bytes_per_second"),
+			"Change in collection data size per second since the previous scrape",
+			labels,
+			nil,
+		),
+		"collection_growth_documents_per_second": prometheus.NewDesc(
+			config.metricName("mongodb_collstats_growth_documents_per_second"),
+			"Change in collection document count per second since the previous scrape",
+			labels,
+			nil,
+		),
+	}
+}
+
+// collectGrowthMetrics emits the collection's growth since its previous sample
+func (c *CollStatsCollector) collectGrowthMetrics(ch chan<- prometheus.Metric, stats bson.M, dbName, collName string, instance map[string]string) {
+	size := c.getNumericValue(stats["size"])
+	count := c.getNumericValue(stats["count"])
+	if !validateMetricValue(size) || !validateMetricValue(count) {
+		return
+	}
+
+	ns := namespace{Database: dbName, Collection: collName}
+	bytesPerSecond, docsPerSecond, ok := c.growth.observe(ns, growthSample{at: time.Now(), size: *size, count: *count})
+	if !ok {
+		return
+	}
+
+	labels := []string{instance["instance"], instance["replica_set"], instance["shard"], dbName, collName}
+	ch <- prometheus.MustNewConstMetric(c.descriptors["collection_growth_bytes_per_second"], prometheus.GaugeValue, bytesPerSecond, labels...)
+	ch <- prometheus.MustNewConstMetric(c.descriptors["collection_growth_documents_per_second"], prometheus.GaugeValue, docsPerSecond, labels...)
+}
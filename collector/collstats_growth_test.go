@@ -0,0 +1,48 @@
+package collector
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGrowthTrackerObserve(t *testing.T) {
+	tracker := newGrowthTracker()
+	ns := namespace{Database: "app", Collection: "orders"}
+	start := time.Unix(1700000000, 0)
+
+	if _, _, ok := tracker.observe(ns, growthSample{at: start, size: 1000, count: 10}); ok {
+		t.Error("First sample should not report a rate")
+	}
+
+	bytesPerSecond, docsPerSecond, ok := tracker.observe(ns, growthSample{at: start.Add(10 * time.Second), size: 3000, count: 5})
+	if !ok {
+		t.Fatal("Second sample should report a rate")
+	}
+	if bytesPerSecond != 200 {
+		t.Errorf("Byte growth should be 200/s, got %v", bytesPerSecond)
+	}
+	if docsPerSecond != -0.5 {
+		t.Errorf("Document growth should be negative after deletes, got %v", docsPerSecond)
+	}
+
+	if _, _, ok := tracker.observe(ns, growthSample{at: start.Add(10*time.Second + 2*growthSampleTTL), size: 3000, count: 5}); ok {
+		t.Error("Sample after a long gap should not report a rate")
+	}
+}
+
+func TestGrowthTrackerForgetsStaleNamespaces(t *testing.T) {
+	tracker := newGrowthTracker()
+	dropped := namespace{Database: "app", Collection: "dropped"}
+	active := namespace{Database: "app", Collection: "active"}
+	start := time.Unix(1700000000, 0)
+
+	tracker.observe(dropped, growthSample{at: start})
+	tracker.observe(active, growthSample{at: start.Add(2 * growthSampleTTL)})
+
+	if _, ok := tracker.samples[dropped]; ok {
+		t.Error("Namespace not sampled within the TTL should be forgotten")
+	}
+	if _, ok := tracker.samples[active]; !ok {
+		t.Error("Recently sampled namespace should be kept")
+	}
+}
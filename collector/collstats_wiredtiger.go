@@ -0,0 +1,96 @@
+package collector
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// wiredTigerDetailStat maps one per-table WiredTiger statistic to its stat label
+type wiredTigerDetailStat struct {
+	section   string
+	key       string
+	name      string
+	valueType prometheus.ValueType
+}
+
+// wiredTigerDetailStats is the per-collection WiredTiger set exported with
+// wiredtiger_detail, chosen for finding tables that churn the cache, split
+// pages or spend time in reconciliation
+var wiredTigerDetailStats = []wiredTigerDetailStat{
+	{"reconciliation", "page reconciliation calls", "page_reconciliation_calls", prometheus.CounterValue},
+	{"reconciliation", "page reconciliation calls for eviction", "page_reconciliation_calls_for_eviction", prometheus.CounterValue},
+	{"reconciliation", "pages deleted", "pages_deleted", prometheus.CounterValue},
+	{"reconciliation", "overflow values written", "overflow_values_written", prometheus.CounterValue},
+
+	{"btree", "row-store internal pages", "row_store_internal_pages", prometheus.GaugeValue},
+	{"btree", "row-store leaf pages", "row_store_leaf_pages", prometheus.GaugeValue},
+	{"btree", "overflow pages", "overflow_pages", prometheus.GaugeValue},
+	{"btree", "maximum tree depth", "maximum_tree_depth", prometheus.GaugeValue},
+	{"btree", "number of key/value pairs", "key_value_pairs", prometheus.GaugeValue},
+
+	{"cache", "bytes read into cache", "bytes_read_into_cache", prometheus.CounterValue},
+	{"cache", "bytes written from cache", "bytes_written_from_cache", prometheus.CounterValue},
+	{"cache", "tracked dirty bytes in the cache", "tracked_dirty_bytes", prometheus.GaugeValue},
+	{"cache", "pages read into cache", "pages_read_into_cache", prometheus.CounterValue},
+	{"cache", "pages written from cache", "pages_written_from_cache", prometheus.CounterValue},
+	{"cache", "modified pages evicted", "modified_pages_evicted", prometheus.CounterValue},
+	{"cache", "unmodified pages evicted", "unmodified_pages_evicted", prometheus.CounterValue},
+	{"cache", "internal pages split during eviction", "internal_pages_split_during_eviction", prometheus.CounterValue},
+	{"cache", "leaf pages split during eviction", "leaf_pages_split_during_eviction", prometheus.CounterValue},
+}
+
+// wiredTigerDetailDescriptors describes the counter and gauge families of the detailed set
+func wiredTigerDetailDescriptors(config CollectorConfig) map[string]*prometheus.Desc {
+	labels := []string{"instance", "replica_set", "shard", "database", "collection", "section", "stat"}
+
+	return map[string]*prometheus.Desc{
+		"collection_wiredtiger_detail_total": prometheus.NewDesc(
+			config.metricName("mongodb_collstats_wiredtiger_detail_total"),
+			"Per-collection WiredTiger counters from collStats, by section and statistic",
+			labels,
+			nil,
+		),
+		"collection_wiredtiger_detail": prometheus.NewDesc(
+			config.metricName("mongodb_collstats_wiredtiger_detail"),
+			"Per-collection WiredTiger gauges from collStats, by section and statistic",
+			labels,
+			nil,
+		),
+	}
+}
+
+// collectWiredTigerDetail emits the detailed WiredTiger set for one collection
+func (c *CollStatsCollector) collectWiredTigerDetail(ch chan<- prometheus.Metric, stats bson.M, dbName, collName string, instance map[string]string) {
+	wiredTiger, ok := stats["wiredTiger"].(bson.M)
+	if !ok {
+		return
+	}
+
+	for _, stat := range wiredTigerDetailStats {
+		section, ok := wiredTiger[stat.section].(bson.M)
+		if !ok {
+			continue
+		}
+		value := c.getNumericValue(section[stat.key])
+		if !validateMetricValue(value) {
+			continue
+		}
+
+		descKey := "collection_wiredtiger_detail"
+		if stat.valueType == prometheus.CounterValue {
+			descKey = "collection_wiredtiger_detail_total"
+		}
+		ch <- prometheus.MustNewConstMetric(
+			c.descriptors[descKey],
+			stat.valueType,
+			*value,
+			instance["instance"],
+			instance["replica_set"],
+			instance["shard"],
+			dbName,
+			collName,
+			stat.section,
+			stat.name,
+		)
+	}
+}
@@ -0,0 +1,52 @@
+package collector
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.uber.org/zap"
+)
+
+func TestCollStatsWiredTigerDetail(t *testing.T) {
+	collector := NewCollStatsCollector(nil, zap.NewNop(), CollectorConfig{})
+	if _, ok := collector.descriptors["collection_wiredtiger_detail_total"]; ok {
+		t.Error("Detailed WiredTiger statistics should be opt-in")
+	}
+
+	config := CollectorConfig{
+		Collectors: map[string]interface{}{
+			"collstats": map[string]interface{}{"wiredtiger_detail": true},
+		},
+	}
+	collector = NewCollStatsCollector(nil, zap.NewNop(), config)
+
+	stats := bson.M{
+		"wiredTiger": bson.M{
+			"reconciliation": bson.M{"page reconciliation calls": int64(12)},
+			"btree":          bson.M{"maximum tree depth": int32(4)},
+			"cache": bson.M{
+				"leaf pages split during eviction": int64(3),
+				"bytes currently in the cache":     int64(4096),
+			},
+		},
+	}
+	instance := map[string]string{"instance": "db-1:27017"}
+
+	ch := make(chan prometheus.Metric, 10)
+	collector.collectWiredTigerDetail(ch, stats, "app", "orders", instance)
+	close(ch)
+
+	counters, gauges := 0, 0
+	for metric := range ch {
+		switch descName(metric.Desc()) {
+		case "mongodb_collstats_wiredtiger_detail_total":
+			counters++
+		case "mongodb_collstats_wiredtiger_detail":
+			gauges++
+		}
+	}
+	if counters != 2 || gauges != 1 {
+		t.Errorf("Expected 2 counters and 1 gauge from the curated set, got %d and %d", counters, gauges)
+	}
+}
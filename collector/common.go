@@ -2,9 +2,15 @@ package collector
 
 import (
 	"context"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
@@ -47,12 +53,158 @@ func runCommandWithTimeout(ctx context.Context, db *mongo.Database, command bson
 	timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
-	return db.RunCommand(timeoutCtx, command).Decode(result)
+	return db.RunCommand(timeoutCtx, withMaxTime(timeoutCtx, command)).Decode(result)
+}
+
+// namespace identifies a collection within a database
+type namespace struct {
+	Database   string
+	Collection string
+	Kind       collectionKind
+}
+
+func (ns namespace) String() string {
+	return ns.Database + "." + ns.Collection
+}
+
+// topNamespaces returns the n namespaces with the highest score, highest first
+func topNamespaces(scores map[namespace]float64, n int) []namespace {
+	ranked := make([]namespace, 0, len(scores))
+	for ns := range scores {
+		ranked = append(ranked, ns)
+	}
+
+	sort.Slice(ranked, func(i, j int) bool {
+		if scores[ranked[i]] != scores[ranked[j]] {
+			return scores[ranked[i]] > scores[ranked[j]]
+		}
+		return ranked[i].String() < ranked[j].String()
+	})
+
+	if n > 0 && len(ranked) > n {
+		ranked = ranked[:n]
+	}
+	return ranked
+}
+
+// forEachNamespace calls fn for every namespace using up to parallelism workers.
+// Dispatch stops once ctx is done so a slow scrape does not overrun its deadline.
+func forEachNamespace(ctx context.Context, namespaces []namespace, parallelism int, fn func(ns namespace)) {
+	if parallelism < 1 {
+		parallelism = 1
+	}
+
+	work := make(chan namespace)
+	var wg sync.WaitGroup
+	for i := 0; i < parallelism; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ns := range work {
+				fn(ns)
+			}
+		}()
+	}
+
+dispatch:
+	for _, ns := range namespaces {
+		if ctx.Err() != nil {
+			break
+		}
+
+		select {
+		case work <- ns:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+
+	close(work)
+	wg.Wait()
+}
+
+// collectorOptions returns the collector-specific options map for name, if configured
+func collectorOptions(config CollectorConfig, name string) map[string]interface{} {
+	if options, ok := config.Collectors[name].(map[string]interface{}); ok {
+		return options
+	}
+	return nil
+}
+
+// getIntOption reads an integer collector option, accepting the numeric types YAML decoders produce
+func getIntOption(options map[string]interface{}, key string, defaultValue int) int {
+	switch v := options[key].(type) {
+	case int:
+		return v
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	default:
+		return defaultValue
+	}
+}
+
+// getStringOption reads a string collector option
+func getStringOption(options map[string]interface{}, key string, defaultValue string) string {
+	if v, ok := options[key].(string); ok && v != "" {
+		return v
+	}
+	return defaultValue
+}
+
+// getBoolOption reads a boolean collector option
+func getBoolOption(options map[string]interface{}, key string, defaultValue bool) bool {
+	if v, ok := options[key].(bool); ok {
+		return v
+	}
+	return defaultValue
+}
+
+// getDurationOption reads a duration collector option given as time.Duration or a duration string
+func getDurationOption(options map[string]interface{}, key string, defaultValue time.Duration) time.Duration {
+	switch v := options[key].(type) {
+	case time.Duration:
+		return v
+	case string:
+		if d, err := time.ParseDuration(v); err == nil {
+			return d
+		}
+	}
+	return defaultValue
+}
+
+// getStringSliceOption reads a string list collector option
+func getStringSliceOption(options map[string]interface{}, key string) []string {
+	switch v := options[key].(type) {
+	case []string:
+		return v
+	case []interface{}:
+		var result []string
+		for _, item := range v {
+			if str, ok := item.(string); ok {
+				result = append(result, str)
+			}
+		}
+		return result
+	default:
+		return nil
+	}
+}
+
+// parseMajorVersion extracts the major version from a version string like "6.0.12"
+func parseMajorVersion(version string) (int, bool) {
+	major, _, _ := strings.Cut(version, ".")
+	n, err := strconv.Atoi(major)
+	if err != nil {
+		return 0, false
+	}
+	return n, true
 }
 
 // validateMetricValue ensures metric values are valid
 func validateMetricValue(value *float64) bool {
-	if value == nil {
+	if !validateSignedMetricValue(value) {
 		return false
 	}
 	if *value < 0 {
@@ -61,33 +213,84 @@ func validateMetricValue(value *float64) bool {
 	return true
 }
 
+// validateSignedMetricValue ensures metric values are finite, allowing negatives
+func validateSignedMetricValue(value *float64) bool {
+	if value == nil {
+		return false
+	}
+	return !math.IsNaN(*value) && !math.IsInf(*value, 0)
+}
+
 // safeGetNumericValue safely extracts numeric values from BSON
 func safeGetNumericValue(value interface{}) *float64 {
+	v, ok := toFloat64(value)
+	if !ok || v < 0 || math.IsNaN(v) {
+		return nil
+	}
+	return &v
+}
+
+// safeGetSignedValue extracts numeric values from BSON, keeping negatives. Use it
+// for gauges that can legitimately go below zero, such as lag deltas, clock skew
+// and WiredTiger statistics that are updated without synchronization.
+func safeGetSignedValue(value interface{}) *float64 {
+	v, ok := toFloat64(value)
+	if !ok {
+		return nil
+	}
+	return &v
+}
+
+// toFloat64 converts any BSON numeric type to float64. DateTime values are
+// returned as seconds since the epoch and Timestamps as their seconds component.
+func toFloat64(value interface{}) (float64, bool) {
 	switch v := value.(type) {
 	case int64:
-		if v < 0 {
-			return nil
-		}
-		result := float64(v)
-		return &result
+		return float64(v), true
 	case int32:
-		if v < 0 {
-			return nil
-		}
-		result := float64(v)
-		return &result
+		return float64(v), true
 	case int:
-		if v < 0 {
-			return nil
-		}
-		result := float64(v)
-		return &result
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case int8:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint:
+		return float64(v), true
 	case float64:
-		if v < 0 {
-			return nil
+		return v, true
+	case float32:
+		return float64(v), true
+	case primitive.Decimal128:
+		f, err := strconv.ParseFloat(v.String(), 64)
+		if err != nil {
+			return 0, false
 		}
-		return &v
+		return f, true
+	case primitive.DateTime:
+		return float64(v) / 1000, true
+	case primitive.Timestamp:
+		return float64(v.T), true
 	default:
-		return nil
+		return 0, false
 	}
 }
+
+// toInt64 converts any BSON numeric type to int64, truncating fractional values
+func toInt64(value interface{}) (int64, bool) {
+	switch v := value.(type) {
+	case int64:
+		return v, true
+	case int32:
+		return int64(v), true
+	case int:
+		return int64(v), true
+	}
+
+	f, ok := toFloat64(value)
+	return int64(f), ok
+}
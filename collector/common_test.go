@@ -2,10 +2,14 @@ package collector
 
 import (
 	"context"
+	"fmt"
+	"math"
+	"sync"
 	"testing"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 func TestShouldSkipDatabase(t *testing.T) {
@@ -184,3 +188,171 @@ func TestSafeGetNumericValue(t *testing.T) {
 		t.Error("negative float64 should return nil")
 	}
 }
+
+func TestTopNamespaces(t *testing.T) {
+	scores := map[namespace]float64{
+		{Database: "app", Collection: "small"}:  10,
+		{Database: "app", Collection: "large"}:  1000,
+		{Database: "app", Collection: "medium"}: 100,
+	}
+
+	top := topNamespaces(scores, 2)
+	if len(top) != 2 {
+		t.Fatalf("Expected 2 namespaces, got %d", len(top))
+	}
+	if top[0].Collection != "large" || top[1].Collection != "medium" {
+		t.Errorf("Unexpected ranking: %v", top)
+	}
+
+	if all := topNamespaces(scores, 0); len(all) != 3 {
+		t.Errorf("n <= 0 should return all namespaces, got %d", len(all))
+	}
+}
+
+func TestDiffNames(t *testing.T) {
+	added, removed := diffNames([]string{"app", "logs"}, []string{"app", "billing"})
+
+	if len(added) != 1 || added[0] != "billing" {
+		t.Errorf("Expected billing to be added, got %v", added)
+	}
+
+	if len(removed) != 1 || removed[0] != "logs" {
+		t.Errorf("Expected logs to be removed, got %v", removed)
+	}
+}
+
+func TestForEachNamespace(t *testing.T) {
+	var namespaces []namespace
+	for i := 0; i < 20; i++ {
+		namespaces = append(namespaces, namespace{Database: "app", Collection: fmt.Sprintf("coll%d", i)})
+	}
+
+	var mu sync.Mutex
+	var active, maxActive, visited int
+	forEachNamespace(context.Background(), namespaces, 3, func(ns namespace) {
+		mu.Lock()
+		active++
+		visited++
+		if active > maxActive {
+			maxActive = active
+		}
+		mu.Unlock()
+
+		time.Sleep(5 * time.Millisecond)
+
+		mu.Lock()
+		active--
+		mu.Unlock()
+	})
+
+	if visited != len(namespaces) {
+		t.Errorf("Expected %d namespaces visited, got %d", len(namespaces), visited)
+	}
+
+	if maxActive > 3 {
+		t.Errorf("Expected at most 3 concurrent workers, got %d", maxActive)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	visited = 0
+	forEachNamespace(ctx, namespaces, 2, func(ns namespace) {
+		mu.Lock()
+		visited++
+		mu.Unlock()
+	})
+
+	if visited != 0 {
+		t.Errorf("Expected no namespaces dispatched after the deadline, got %d", visited)
+	}
+}
+
+func TestTimeoutTuner(t *testing.T) {
+	tuner := newTimeoutTuner(time.Second, 30*time.Second)
+
+	if timeout := tuner.Timeout("collstats", 15*time.Second); timeout != 15*time.Second {
+		t.Errorf("Expected fallback timeout without history, got %v", timeout)
+	}
+
+	tuner.Observe("collstats", 200*time.Millisecond)
+	if timeout := tuner.Timeout("collstats", 15*time.Second); timeout != time.Second {
+		t.Errorf("Expected fast collector to be clamped to the minimum, got %v", timeout)
+	}
+
+	tuner.Observe("collstats", 8*time.Second)
+	if timeout := tuner.Timeout("collstats", 15*time.Second); timeout != 16*time.Second {
+		t.Errorf("Expected timeout to follow the slowest recent run, got %v", timeout)
+	}
+
+	tuner.Observe("collstats", 20*time.Second)
+	if timeout := tuner.Timeout("collstats", 15*time.Second); timeout != 30*time.Second {
+		t.Errorf("Expected timeout to be clamped to the maximum, got %v", timeout)
+	}
+}
+
+func TestSafeGetNumericValueBSONTypes(t *testing.T) {
+	decimal, _ := primitive.ParseDecimal128("1234.5")
+
+	cases := []struct {
+		name     string
+		value    interface{}
+		expected float64
+	}{
+		{"uint64", uint64(42), 42},
+		{"float32", float32(1.5), 1.5},
+		{"Decimal128", decimal, 1234.5},
+		{"DateTime", primitive.DateTime(1700000000000), 1700000000},
+		{"Timestamp", primitive.Timestamp{T: 1700000000, I: 3}, 1700000000},
+	}
+
+	for _, tc := range cases {
+		value := safeGetNumericValue(tc.value)
+		if value == nil {
+			t.Errorf("%s should be converted", tc.name)
+			continue
+		}
+		if *value != tc.expected {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.expected, *value)
+		}
+	}
+
+	if v, ok := toInt64(float64(3)); !ok || v != 3 {
+		t.Errorf("toInt64 should accept float64 values, got %v, %v", v, ok)
+	}
+}
+
+func TestSafeGetSignedValue(t *testing.T) {
+	value := safeGetSignedValue(int64(-250))
+	if value == nil || *value != -250 {
+		t.Errorf("Expected negative value to be kept, got %v", value)
+	}
+
+	if safeGetSignedValue("invalid") != nil {
+		t.Error("string should return nil")
+	}
+
+	if !validateSignedMetricValue(value) {
+		t.Error("Negative value should be valid for signed metrics")
+	}
+
+	if validateMetricValue(value) {
+		t.Error("Negative value should be invalid for unsigned metrics")
+	}
+
+	nan := math.NaN()
+	if validateSignedMetricValue(&nan) {
+		t.Error("NaN should be invalid")
+	}
+}
+
+func TestParseMajorVersion(t *testing.T) {
+	if major, ok := parseMajorVersion("6.0.12"); !ok || major != 6 {
+		t.Errorf("Expected major version 6, got %d", major)
+	}
+	if major, ok := parseMajorVersion("4.4.0-rc1"); !ok || major != 4 {
+		t.Errorf("Expected major version 4, got %d", major)
+	}
+	if _, ok := parseMajorVersion(""); ok {
+		t.Error("Empty version should not parse")
+	}
+}
@@ -1,7 +1,6 @@
 package collector
 
 import (
-	"context"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -22,7 +21,7 @@ func NewCompatibilityCollector(client *mongo.Client, logger *zap.Logger, config
 	descriptors := map[string]*prometheus.Desc{
 		// Only include metrics that aren't already provided by other collectors
 		"op_counters_repl_total": prometheus.NewDesc(
-			"mongodb_op_counters_repl_total",
+			config.metricName("mongodb_op_counters_repl_total"),
 			"Replication operation counters for dashboard 2583 compatibility",
 			opLabels,
 			nil,
@@ -40,12 +39,12 @@ func (c *CompatibilityCollector) Collect(ch chan<- prometheus.Metric) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+	ctx, done := c.collectContext("compatibility", 10*time.Second)
+	defer done()
 
 	var result bson.M
-	if err := c.client.Database("admin").RunCommand(ctx, bson.D{{"serverStatus", 1}}).Decode(&result); err != nil {
-		c.logger.Error("Failed to collect compatibility metrics", zap.Error(err))
+	if err := c.runCommand(ctx, "compatibility", c.client.Database("admin"), serverStatusCommand("opcountersRepl"), &result); err != nil {
+		c.logCommandError("Failed to collect compatibility metrics", err)
 		return
 	}
 
@@ -54,7 +53,7 @@ func (c *CompatibilityCollector) Collect(ch chan<- prometheus.Metric) {
 	// Collect replication operation counters
 	if opCountersRepl, ok := result["opcountersRepl"].(bson.M); ok {
 		for opType, value := range opCountersRepl {
-			if val, ok := value.(int64); ok {
+			if val, ok := toInt64(value); ok {
 				ch <- prometheus.MustNewConstMetric(
 					c.descriptors["op_counters_repl_total"],
 					prometheus.CounterValue,
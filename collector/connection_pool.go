@@ -12,7 +12,10 @@ import (
 
 type ConnectionPoolCollector struct {
 	*BaseCollector
-	descriptors map[string]*prometheus.Desc
+	descriptors     map[string]*prometheus.Desc
+	sessionStates   bool
+	clientMetadata  bool
+	maxClientGroups int
 }
 
 func NewConnectionPoolCollector(client *mongo.Client, logger *zap.Logger, config CollectorConfig) *ConnectionPoolCollector {
@@ -22,106 +25,144 @@ func NewConnectionPoolCollector(client *mongo.Client, logger *zap.Logger, config
 
 	descriptors := map[string]*prometheus.Desc{
 		"connection_pool_current_checked_out": prometheus.NewDesc(
-			"mongodb_connection_pool_current_checked_out",
+			config.metricName("mongodb_connection_pool_current_checked_out"),
 			"The number of connections currently checked out of the pool",
 			poolLabels,
 			nil,
 		),
 		"connection_pool_current_checked_in": prometheus.NewDesc(
-			"mongodb_connection_pool_current_checked_in",
+			config.metricName("mongodb_connection_pool_current_checked_in"),
 			"The number of connections currently available in the pool",
 			poolLabels,
 			nil,
 		),
 		"connection_pool_current_created": prometheus.NewDesc(
-			"mongodb_connection_pool_current_created",
+			config.metricName("mongodb_connection_pool_current_created"),
 			"The total number of connections currently created in the pool",
 			poolLabels,
 			nil,
 		),
 		"connection_pool_max_size": prometheus.NewDesc(
-			"mongodb_connection_pool_max_size",
+			config.metricName("mongodb_connection_pool_max_size"),
 			"Maximum number of connections in the pool",
 			poolLabels,
 			nil,
 		),
 		"connection_pool_min_size": prometheus.NewDesc(
-			"mongodb_connection_pool_min_size",
+			config.metricName("mongodb_connection_pool_min_size"),
 			"Minimum number of connections in the pool",
 			poolLabels,
 			nil,
 		),
 		"connection_pool_total_created": prometheus.NewDesc(
-			"mongodb_connection_pool_total_created",
+			config.metricName("mongodb_connection_pool_total_created"),
 			"Total number of connections created since startup",
 			poolLabels,
 			nil,
 		),
 		"connection_pool_total_destroyed": prometheus.NewDesc(
-			"mongodb_connection_pool_total_destroyed",
+			config.metricName("mongodb_connection_pool_total_destroyed"),
 			"Total number of connections destroyed since startup",
 			poolLabels,
 			nil,
 		),
 		"connection_pool_requests_total": prometheus.NewDesc(
-			"mongodb_connection_pool_requests_total",
+			config.metricName("mongodb_connection_pool_requests_total"),
 			"Total number of connection requests",
 			append(poolLabels, "result"),
 			nil,
 		),
 		"connection_pool_wait_queue_size": prometheus.NewDesc(
-			"mongodb_connection_pool_wait_queue_size",
+			config.metricName("mongodb_connection_pool_wait_queue_size"),
 			"Current number of operations waiting for a connection",
 			poolLabels,
 			nil,
 		),
 		"connection_pool_wait_queue_timeout_total": prometheus.NewDesc(
-			"mongodb_connection_pool_wait_queue_timeout_total",
+			config.metricName("mongodb_connection_pool_wait_queue_timeout_total"),
 			"Total number of connection wait queue timeouts",
 			poolLabels,
 			nil,
 		),
 		"connection_pool_wait_time_milliseconds": prometheus.NewDesc(
-			"mongodb_connection_pool_wait_time_milliseconds",
+			config.metricName("mongodb_connection_pool_wait_time_milliseconds"),
 			"Average time spent waiting for connections in milliseconds",
 			poolLabels,
 			nil,
 		),
 		"connection_pool_checkout_time_milliseconds": prometheus.NewDesc(
-			"mongodb_connection_pool_checkout_time_milliseconds",
+			config.metricName("mongodb_connection_pool_checkout_time_milliseconds"),
 			"Average time to checkout a connection in milliseconds",
 			poolLabels,
 			nil,
 		),
 		"connection_errors_total": prometheus.NewDesc(
-			"mongodb_connection_errors_total",
+			config.metricName("mongodb_connection_errors_total"),
 			"Total number of connection errors by type",
 			append(labels, "error_type", "host"),
 			nil,
 		),
 		"connection_establishment_time_milliseconds": prometheus.NewDesc(
-			"mongodb_connection_establishment_time_milliseconds",
+			config.metricName("mongodb_connection_establishment_time_milliseconds"),
 			"Average time to establish new connections in milliseconds",
 			hostLabels,
 			nil,
 		),
 		"connection_auth_time_milliseconds": prometheus.NewDesc(
-			"mongodb_connection_auth_time_milliseconds",
+			config.metricName("mongodb_connection_auth_time_milliseconds"),
 			"Average time to authenticate connections in milliseconds",
 			hostLabels,
 			nil,
 		),
 		"connection_handshake_time_milliseconds": prometheus.NewDesc(
-			"mongodb_connection_handshake_time_milliseconds",
+			config.metricName("mongodb_connection_handshake_time_milliseconds"),
 			"Average time for connection handshake in milliseconds",
 			hostLabels,
 			nil,
 		),
 	}
 
+	// Breaking clients down by state needs a $currentOp listing every idle
+	// connection, which is expensive on busy servers, so it is opt-in
+	options := collectorOptions(config, "connection_pool")
+	sessionStates := getBoolOption(options, "session_states", false)
+	if sessionStates {
+		stateLabels := []string{"instance", "replica_set", "shard", "state", "app_name"}
+		descriptors["currentop_connections"] = prometheus.NewDesc(
+			config.metricName("mongodb_currentop_connections"),
+			"Number of client connections by state (active, idle, in_transaction) and appName",
+			stateLabels,
+			nil,
+		)
+		descriptors["currentop_sessions"] = prometheus.NewDesc(
+			config.metricName("mongodb_currentop_sessions"),
+			"Number of logical sessions by state (active, idle, in_transaction) and appName",
+			stateLabels,
+			nil,
+		)
+	}
+
+	// The driver breakdown lists every idle connection as well, so it is opt-in too
+	clientMetadata := getBoolOption(options, "client_metadata", false)
+	if clientMetadata {
+		descriptors["client_connections"] = prometheus.NewDesc(
+			config.metricName("mongodb_client_connections"),
+			"Number of client connections by appName and driver name and version from the connection handshake",
+			[]string{"instance", "replica_set", "shard", "app_name", "driver_name", "driver_version"},
+			nil,
+		)
+	}
+	maxClientGroups := getIntOption(options, "max_client_groups", 0)
+	if maxClientGroups <= 0 {
+		maxClientGroups = defaultMaxClientGroups
+	}
+
 	return &ConnectionPoolCollector{
-		BaseCollector: NewBaseCollector(client, logger, config),
-		descriptors:   descriptors,
+		BaseCollector:   NewBaseCollector(client, logger, config),
+		descriptors:     descriptors,
+		sessionStates:   sessionStates,
+		clientMetadata:  clientMetadata,
+		maxClientGroups: maxClientGroups,
 	}
 }
 
@@ -130,12 +171,12 @@ func (c *ConnectionPoolCollector) Collect(ch chan<- prometheus.Metric) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
-	defer cancel()
+	ctx, done := c.collectContext("connection_pool", 15*time.Second)
+	defer done()
 
 	var result bson.M
-	if err := c.client.Database("admin").RunCommand(ctx, bson.D{{"serverStatus", 1}}).Decode(&result); err != nil {
-		c.logger.Error("Failed to collect connection pool metrics", zap.Error(err))
+	if err := c.runCommand(ctx, "connection_pool", c.client.Database("admin"), serverStatusCommand("connections", "metrics"), &result); err != nil {
+		c.logCommandError("Failed to collect connection pool metrics", err)
 		return
 	}
 
@@ -149,6 +190,13 @@ func (c *ConnectionPoolCollector) Collect(ch chan<- prometheus.Metric) {
 
 	// Collect detailed pool statistics if available
 	c.collectDetailedPoolMetrics(ctx, ch, instance)
+
+	if c.sessionStates {
+		c.collectSessionStates(ctx, ch, instance)
+	}
+	if c.clientMetadata {
+		c.collectClientMetadata(ctx, ch, instance)
+	}
 }
 
 func (c *ConnectionPoolCollector) collectConnectionPoolMetrics(ch chan<- prometheus.Metric, result bson.M, instance map[string]string) {
@@ -170,7 +218,7 @@ func (c *ConnectionPoolCollector) collectBasicConnectionMetrics(ch chan<- promet
 	labels := []string{instance["instance"], instance["replica_set"], instance["shard"], poolName}
 
 	// Current connections (assuming these are checked out)
-	if current, ok := connections["current"].(int64); ok {
+	if current, ok := toInt64(connections["current"]); ok {
 		ch <- prometheus.MustNewConstMetric(
 			c.descriptors["connection_pool_current_checked_out"],
 			prometheus.GaugeValue,
@@ -180,7 +228,7 @@ func (c *ConnectionPoolCollector) collectBasicConnectionMetrics(ch chan<- promet
 	}
 
 	// Available connections
-	if available, ok := connections["available"].(int64); ok {
+	if available, ok := toInt64(connections["available"]); ok {
 		ch <- prometheus.MustNewConstMetric(
 			c.descriptors["connection_pool_current_checked_in"],
 			prometheus.GaugeValue,
@@ -189,7 +237,7 @@ func (c *ConnectionPoolCollector) collectBasicConnectionMetrics(ch chan<- promet
 		)
 
 		// Calculate total created (approximation)
-		if current, ok := connections["current"].(int64); ok {
+		if current, ok := toInt64(connections["current"]); ok {
 			totalCreated := current + available
 			ch <- prometheus.MustNewConstMetric(
 				c.descriptors["connection_pool_current_created"],
@@ -201,7 +249,7 @@ func (c *ConnectionPoolCollector) collectBasicConnectionMetrics(ch chan<- promet
 	}
 
 	// Total created connections
-	if totalCreated, ok := connections["totalCreated"].(int64); ok {
+	if totalCreated, ok := toInt64(connections["totalCreated"]); ok {
 		ch <- prometheus.MustNewConstMetric(
 			c.descriptors["connection_pool_total_created"],
 			prometheus.CounterValue,
@@ -224,7 +272,7 @@ func (c *ConnectionPoolCollector) emitPoolSpecificMetrics(ch chan<- prometheus.M
 	labels := []string{instance["instance"], instance["replica_set"], instance["shard"], poolName}
 
 	// Current pool state
-	if currentCheckedOut, ok := poolData["currentCheckedOut"].(int64); ok {
+	if currentCheckedOut, ok := toInt64(poolData["currentCheckedOut"]); ok {
 		ch <- prometheus.MustNewConstMetric(
 			c.descriptors["connection_pool_current_checked_out"],
 			prometheus.GaugeValue,
@@ -233,7 +281,7 @@ func (c *ConnectionPoolCollector) emitPoolSpecificMetrics(ch chan<- prometheus.M
 		)
 	}
 
-	if currentAvailable, ok := poolData["currentAvailable"].(int64); ok {
+	if currentAvailable, ok := toInt64(poolData["currentAvailable"]); ok {
 		ch <- prometheus.MustNewConstMetric(
 			c.descriptors["connection_pool_current_checked_in"],
 			prometheus.GaugeValue,
@@ -242,7 +290,7 @@ func (c *ConnectionPoolCollector) emitPoolSpecificMetrics(ch chan<- prometheus.M
 		)
 	}
 
-	if currentCreated, ok := poolData["currentCreated"].(int64); ok {
+	if currentCreated, ok := toInt64(poolData["currentCreated"]); ok {
 		ch <- prometheus.MustNewConstMetric(
 			c.descriptors["connection_pool_current_created"],
 			prometheus.GaugeValue,
@@ -252,7 +300,7 @@ func (c *ConnectionPoolCollector) emitPoolSpecificMetrics(ch chan<- prometheus.M
 	}
 
 	// Pool configuration
-	if maxSize, ok := poolData["maxPoolSize"].(int64); ok {
+	if maxSize, ok := toInt64(poolData["maxPoolSize"]); ok {
 		ch <- prometheus.MustNewConstMetric(
 			c.descriptors["connection_pool_max_size"],
 			prometheus.GaugeValue,
@@ -261,7 +309,7 @@ func (c *ConnectionPoolCollector) emitPoolSpecificMetrics(ch chan<- prometheus.M
 		)
 	}
 
-	if minSize, ok := poolData["minPoolSize"].(int64); ok {
+	if minSize, ok := toInt64(poolData["minPoolSize"]); ok {
 		ch <- prometheus.MustNewConstMetric(
 			c.descriptors["connection_pool_min_size"],
 			prometheus.GaugeValue,
@@ -271,7 +319,7 @@ func (c *ConnectionPoolCollector) emitPoolSpecificMetrics(ch chan<- prometheus.M
 	}
 
 	// Lifetime counters
-	if totalCreated, ok := poolData["totalCreated"].(int64); ok {
+	if totalCreated, ok := toInt64(poolData["totalCreated"]); ok {
 		ch <- prometheus.MustNewConstMetric(
 			c.descriptors["connection_pool_total_created"],
 			prometheus.CounterValue,
@@ -280,7 +328,7 @@ func (c *ConnectionPoolCollector) emitPoolSpecificMetrics(ch chan<- prometheus.M
 		)
 	}
 
-	if totalDestroyed, ok := poolData["totalDestroyed"].(int64); ok {
+	if totalDestroyed, ok := toInt64(poolData["totalDestroyed"]); ok {
 		ch <- prometheus.MustNewConstMetric(
 			c.descriptors["connection_pool_total_destroyed"],
 			prometheus.CounterValue,
@@ -290,7 +338,7 @@ func (c *ConnectionPoolCollector) emitPoolSpecificMetrics(ch chan<- prometheus.M
 	}
 
 	// Request metrics
-	if successful, ok := poolData["requestsSuccessful"].(int64); ok {
+	if successful, ok := toInt64(poolData["requestsSuccessful"]); ok {
 		ch <- prometheus.MustNewConstMetric(
 			c.descriptors["connection_pool_requests_total"],
 			prometheus.CounterValue,
@@ -299,7 +347,7 @@ func (c *ConnectionPoolCollector) emitPoolSpecificMetrics(ch chan<- prometheus.M
 		)
 	}
 
-	if failed, ok := poolData["requestsFailed"].(int64); ok {
+	if failed, ok := toInt64(poolData["requestsFailed"]); ok {
 		ch <- prometheus.MustNewConstMetric(
 			c.descriptors["connection_pool_requests_total"],
 			prometheus.CounterValue,
@@ -309,7 +357,7 @@ func (c *ConnectionPoolCollector) emitPoolSpecificMetrics(ch chan<- prometheus.M
 	}
 
 	// Wait queue metrics
-	if waitQueueSize, ok := poolData["waitQueueSize"].(int64); ok {
+	if waitQueueSize, ok := toInt64(poolData["waitQueueSize"]); ok {
 		ch <- prometheus.MustNewConstMetric(
 			c.descriptors["connection_pool_wait_queue_size"],
 			prometheus.GaugeValue,
@@ -318,7 +366,7 @@ func (c *ConnectionPoolCollector) emitPoolSpecificMetrics(ch chan<- prometheus.M
 		)
 	}
 
-	if waitQueueTimeouts, ok := poolData["waitQueueTimeouts"].(int64); ok {
+	if waitQueueTimeouts, ok := toInt64(poolData["waitQueueTimeouts"]); ok {
 		ch <- prometheus.MustNewConstMetric(
 			c.descriptors["connection_pool_wait_queue_timeout_total"],
 			prometheus.CounterValue,
@@ -328,7 +376,7 @@ func (c *ConnectionPoolCollector) emitPoolSpecificMetrics(ch chan<- prometheus.M
 	}
 
 	// Timing metrics
-	if avgWaitTime, ok := poolData["avgWaitTimeMs"].(float64); ok {
+	if avgWaitTime, ok := toFloat64(poolData["avgWaitTimeMs"]); ok {
 		ch <- prometheus.MustNewConstMetric(
 			c.descriptors["connection_pool_wait_time_milliseconds"],
 			prometheus.GaugeValue,
@@ -337,7 +385,7 @@ func (c *ConnectionPoolCollector) emitPoolSpecificMetrics(ch chan<- prometheus.M
 		)
 	}
 
-	if avgCheckoutTime, ok := poolData["avgCheckoutTimeMs"].(float64); ok {
+	if avgCheckoutTime, ok := toFloat64(poolData["avgCheckoutTimeMs"]); ok {
 		ch <- prometheus.MustNewConstMetric(
 			c.descriptors["connection_pool_checkout_time_milliseconds"],
 			prometheus.GaugeValue,
@@ -351,7 +399,7 @@ func (c *ConnectionPoolCollector) collectConnectionErrorMetrics(ch chan<- promet
 	if metrics, ok := result["metrics"].(bson.M); ok {
 		if cursor, ok := metrics["cursor"].(bson.M); ok {
 			// Connection timeout errors
-			if timeouts, ok := cursor["timedOut"].(int64); ok {
+			if timeouts, ok := toInt64(cursor["timedOut"]); ok {
 				ch <- prometheus.MustNewConstMetric(
 					c.descriptors["connection_errors_total"],
 					prometheus.CounterValue,
@@ -374,7 +422,7 @@ func (c *ConnectionPoolCollector) collectConnectionErrorMetrics(ch chan<- promet
 			}
 
 			for errorKey, errorType := range networkErrors {
-				if errorCount, ok := network[errorKey].(int64); ok {
+				if errorCount, ok := toInt64(network[errorKey]); ok {
 					ch <- prometheus.MustNewConstMetric(
 						c.descriptors["connection_errors_total"],
 						prometheus.CounterValue,
@@ -394,11 +442,7 @@ func (c *ConnectionPoolCollector) collectConnectionErrorMetrics(ch chan<- promet
 func (c *ConnectionPoolCollector) collectDetailedPoolMetrics(ctx context.Context, ch chan<- prometheus.Metric, instance map[string]string) {
 	// Try to get more detailed connection pool information using serverStatus with additional details
 	var detailedResult bson.M
-	err := c.client.Database("admin").RunCommand(ctx, bson.D{
-		{"serverStatus", 1},
-		{"connections", 1},
-		{"network", 1},
-	}).Decode(&detailedResult)
+	err := c.runCommand(ctx, "connection_pool", c.client.Database("admin"), serverStatusCommand("connections", "network"), &detailedResult)
 
 	if err != nil {
 		c.logger.Debug("Failed to get detailed connection metrics", zap.Error(err))
@@ -421,7 +465,7 @@ func (c *ConnectionPoolCollector) collectNetworkConnectionMetrics(ch chan<- prom
 		// This might include compression ratio, compression errors, etc.
 		for compressor, stats := range compression {
 			if compressorStats, ok := stats.(bson.M); ok {
-				if compressorRequests, ok := compressorStats["requests"].(int64); ok {
+				if compressorRequests, ok := toInt64(compressorStats["requests"]); ok {
 					// Emit compression-related metrics
 					c.logger.Debug("Compression stats",
 						zap.String("compressor", compressor),
@@ -435,10 +479,10 @@ func (c *ConnectionPoolCollector) collectNetworkConnectionMetrics(ch chan<- prom
 func (c *ConnectionPoolCollector) collectCurrentOpConnectionMetrics(ctx context.Context, ch chan<- prometheus.Metric, instance map[string]string) {
 	// Get current operations to analyze active connections
 	var currentOp bson.M
-	err := c.client.Database("admin").RunCommand(ctx, bson.D{
+	err := c.runCommand(ctx, "connection_pool", c.client.Database("admin"), bson.D{
 		{"currentOp", 1},
 		{"$all", true},
-	}).Decode(&currentOp)
+	}, &currentOp)
 
 	if err != nil {
 		c.logger.Debug("Failed to get current operations for connection analysis", zap.Error(err))
@@ -0,0 +1,66 @@
+package collector
+
+import (
+	"math"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// cursorLifespanBuckets are the metrics.cursor.lifespan fields in ascending
+// order with their upper bound in seconds. Each field counts only the cursors
+// whose lifespan fell in its own range
+var cursorLifespanBuckets = []struct {
+	key        string
+	upperBound float64
+}{
+	{"lessThan1Second", 1},
+	{"lessThan5Seconds", 5},
+	{"lessThan15Seconds", 15},
+	{"lessThan30Seconds", 30},
+	{"lessThan1Minute", 60},
+	{"lessThan10Minutes", 600},
+}
+
+// cursorLifespanHistogram turns the per-range lifespan counts into cumulative
+// histogram buckets; greaterThanOrEqual10Minutes only adds to the total count
+func cursorLifespanHistogram(lifespan bson.M) (count uint64, buckets map[float64]uint64, ok bool) {
+	buckets = make(map[float64]uint64, len(cursorLifespanBuckets))
+	for _, bucket := range cursorLifespanBuckets {
+		value, found := toInt64(lifespan[bucket.key])
+		if !found || value < 0 {
+			return 0, nil, false
+		}
+		count += uint64(value)
+		buckets[bucket.upperBound] = count
+	}
+
+	if value, found := toInt64(lifespan["greaterThanOrEqual10Minutes"]); found && value > 0 {
+		count += uint64(value)
+	}
+	return count, buckets, true
+}
+
+// collectCursorLifespan exports the lifespan of closed cursors as a histogram.
+// The server only counts cursors per range, so the sum is reported as NaN
+func (c *CursorCollector) collectCursorLifespan(ch chan<- prometheus.Metric, result bson.M, instance map[string]string) {
+	lifespan, ok := lookupPath(result, []string{"metrics", "cursor", "lifespan"}).(bson.M)
+	if !ok {
+		return
+	}
+
+	count, buckets, ok := cursorLifespanHistogram(lifespan)
+	if !ok {
+		return
+	}
+
+	ch <- prometheus.MustNewConstHistogram(
+		c.descriptors["cursor_lifespan_seconds"],
+		count,
+		math.NaN(),
+		buckets,
+		instance["instance"],
+		instance["replica_set"],
+		instance["shard"],
+	)
+}
@@ -0,0 +1,39 @@
+package collector
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestCursorLifespanHistogram(t *testing.T) {
+	lifespan := bson.M{
+		"lessThan1Second":             int64(10),
+		"lessThan5Seconds":            int64(5),
+		"lessThan15Seconds":           int64(0),
+		"lessThan30Seconds":           int64(2),
+		"lessThan1Minute":             int64(1),
+		"lessThan10Minutes":           int64(1),
+		"greaterThanOrEqual10Minutes": int64(3),
+	}
+
+	count, buckets, ok := cursorLifespanHistogram(lifespan)
+	if !ok {
+		t.Fatal("Complete lifespan section should convert to a histogram")
+	}
+	if count != 22 {
+		t.Errorf("Count should include cursors open 10 minutes or more, got %d", count)
+	}
+
+	expected := map[float64]uint64{1: 10, 5: 15, 15: 15, 30: 17, 60: 18, 600: 19}
+	for bound, want := range expected {
+		if buckets[bound] != want {
+			t.Errorf("Bucket le=%v should be cumulative %d, got %d", bound, want, buckets[bound])
+		}
+	}
+
+	delete(lifespan, "lessThan1Minute")
+	if _, _, ok := cursorLifespanHistogram(lifespan); ok {
+		t.Error("Lifespan section missing a bucket should be skipped")
+	}
+}
@@ -22,61 +22,67 @@ func NewCursorCollector(client *mongo.Client, logger *zap.Logger, config Collect
 
 	descriptors := map[string]*prometheus.Desc{
 		"cursors_open": prometheus.NewDesc(
-			"mongodb_cursors_open",
+			config.metricName("mongodb_cursors_open"),
 			"Number of open cursors by type",
 			cursorLabels,
 			nil,
 		),
 		"cursors_timed_out_total": prometheus.NewDesc(
-			"mongodb_cursors_timed_out_total",
+			config.metricName("mongodb_cursors_timed_out_total"),
 			"Total number of cursors that have timed out since the server was started",
 			labels,
 			nil,
 		),
 		"cursor_timeout_seconds": prometheus.NewDesc(
-			"mongodb_cursor_timeout_seconds",
+			config.metricName("mongodb_cursor_timeout_seconds"),
 			"Current cursor timeout value in seconds",
 			labels,
 			nil,
 		),
 		"cursors_killed_total": prometheus.NewDesc(
-			"mongodb_cursors_killed_total",
+			config.metricName("mongodb_cursors_killed_total"),
 			"Total number of cursors killed by operation",
 			operationLabels,
 			nil,
 		),
 		"cursors_created_total": prometheus.NewDesc(
-			"mongodb_cursors_created_total",
+			config.metricName("mongodb_cursors_created_total"),
 			"Total number of cursors created since server start",
 			labels,
 			nil,
 		),
 		"cursor_pool_size": prometheus.NewDesc(
-			"mongodb_cursor_pool_size",
+			config.metricName("mongodb_cursor_pool_size"),
 			"Current size of the cursor pool",
 			labels,
 			nil,
 		),
 		"cursor_memory_usage_bytes": prometheus.NewDesc(
-			"mongodb_cursor_memory_usage_bytes",
+			config.metricName("mongodb_cursor_memory_usage_bytes"),
 			"Total memory usage by open cursors in bytes",
 			labels,
 			nil,
 		),
 		"cursor_getmore_operations_total": prometheus.NewDesc(
-			"mongodb_cursor_getmore_operations_total",
+			config.metricName("mongodb_cursor_getmore_operations_total"),
 			"Total number of getMore operations performed",
 			labels,
 			nil,
 		),
 		"cursor_batch_size_avg": prometheus.NewDesc(
-			"mongodb_cursor_batch_size_avg",
+			config.metricName("mongodb_cursor_batch_size_avg"),
 			"Average batch size of cursor operations",
 			labels,
 			nil,
 		),
+		"cursor_lifespan_seconds": prometheus.NewDesc(
+			config.metricName("mongodb_cursor_lifespan_seconds"),
+			"Lifespan of closed cursors from serverStatus metrics.cursor.lifespan; the sum is not reported by the server",
+			labels,
+			nil,
+		),
 		"pinned_cursors": prometheus.NewDesc(
-			"mongodb_pinned_cursors",
+			config.metricName("mongodb_pinned_cursors"),
 			"Number of pinned cursors",
 			labels,
 			nil,
@@ -94,12 +100,12 @@ func (c *CursorCollector) Collect(ch chan<- prometheus.Metric) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+	ctx, done := c.collectContext("cursors", 10*time.Second)
+	defer done()
 
 	var result bson.M
-	if err := c.client.Database("admin").RunCommand(ctx, bson.D{{"serverStatus", 1}}).Decode(&result); err != nil {
-		c.logger.Error("Failed to collect cursor metrics", zap.Error(err))
+	if err := c.runCommand(ctx, "cursors", c.client.Database("admin"), serverStatusCommand("metrics", "opcounters"), &result); err != nil {
+		c.logCommandError("Failed to collect cursor metrics", err)
 		return
 	}
 
@@ -116,6 +122,9 @@ func (c *CursorCollector) Collect(ch chan<- prometheus.Metric) {
 
 	// Collect global cursor timeout settings
 	c.collectCursorTimeoutSettings(ctx, ch, instance)
+
+	// Collect the lifespan distribution of closed cursors
+	c.collectCursorLifespan(ch, result, instance)
 }
 
 func (c *CursorCollector) collectBasicCursorMetrics(ch chan<- prometheus.Metric, result bson.M, instance map[string]string) {
@@ -123,7 +132,7 @@ func (c *CursorCollector) collectBasicCursorMetrics(ch chan<- prometheus.Metric,
 	if metrics, ok := result["metrics"].(bson.M); ok {
 		if cursor, ok := metrics["cursor"].(bson.M); ok {
 			// Total cursors timed out
-			if timedOut, ok := cursor["timedOut"].(int64); ok {
+			if timedOut, ok := toInt64(cursor["timedOut"]); ok {
 				ch <- prometheus.MustNewConstMetric(
 					c.descriptors["cursors_timed_out_total"],
 					prometheus.CounterValue,
@@ -135,7 +144,7 @@ func (c *CursorCollector) collectBasicCursorMetrics(ch chan<- prometheus.Metric,
 			}
 
 			// Total cursors created
-			if totalOpened, ok := cursor["totalOpened"].(int64); ok {
+			if totalOpened, ok := toInt64(cursor["totalOpened"]); ok {
 				ch <- prometheus.MustNewConstMetric(
 					c.descriptors["cursors_created_total"],
 					prometheus.CounterValue,
@@ -155,7 +164,7 @@ func (c *CursorCollector) collectBasicCursorMetrics(ch chan<- prometheus.Metric,
 				}
 
 				for serverKey, labelValue := range cursorTypes {
-					if count, ok := open[serverKey].(int64); ok {
+					if count, ok := toInt64(open[serverKey]); ok {
 						ch <- prometheus.MustNewConstMetric(
 							c.descriptors["cursors_open"],
 							prometheus.GaugeValue,
@@ -169,7 +178,7 @@ func (c *CursorCollector) collectBasicCursorMetrics(ch chan<- prometheus.Metric,
 				}
 
 				// Pinned cursors (specific metric)
-				if pinned, ok := open["pinned"].(int64); ok {
+				if pinned, ok := toInt64(open["pinned"]); ok {
 					ch <- prometheus.MustNewConstMetric(
 						c.descriptors["pinned_cursors"],
 						prometheus.GaugeValue,
@@ -184,7 +193,7 @@ func (c *CursorCollector) collectBasicCursorMetrics(ch chan<- prometheus.Metric,
 
 		// GetMore operations from operation metrics
 		if operation, ok := metrics["operation"].(bson.M); ok {
-			if getmore, ok := operation["getmore"].(int64); ok {
+			if getmore, ok := toInt64(operation["getmore"]); ok {
 				ch <- prometheus.MustNewConstMetric(
 					c.descriptors["cursor_getmore_operations_total"],
 					prometheus.CounterValue,
@@ -200,10 +209,10 @@ func (c *CursorCollector) collectBasicCursorMetrics(ch chan<- prometheus.Metric,
 
 func (c *CursorCollector) collectCurrentOpCursorMetrics(ctx context.Context, ch chan<- prometheus.Metric, instance map[string]string) {
 	var currentOp bson.M
-	err := c.client.Database("admin").RunCommand(ctx, bson.D{
+	err := c.runCommand(ctx, "cursors", c.client.Database("admin"), bson.D{
 		{"currentOp", 1},
 		{"$all", true},
-	}).Decode(&currentOp)
+	}, &currentOp)
 
 	if err != nil {
 		c.logger.Debug("Failed to run currentOp command for cursor metrics", zap.Error(err))
@@ -291,10 +300,10 @@ func (c *CursorCollector) collectCursorKillMetrics(ctx context.Context, ch chan<
 
 func (c *CursorCollector) collectCursorTimeoutSettings(ctx context.Context, ch chan<- prometheus.Metric, instance map[string]string) {
 	var params bson.M
-	err := c.client.Database("admin").RunCommand(ctx, bson.D{{"getParameter", 1}, {"cursorTimeoutMillis", 1}}).Decode(&params)
+	err := c.runCommand(ctx, "cursors", c.client.Database("admin"), bson.D{{"getParameter", 1}, {"cursorTimeoutMillis", 1}}, &params)
 	if err != nil {
 		c.logger.Debug("Failed to get cursor timeout parameters", zap.Error(err))
-		err = c.client.Database("admin").RunCommand(ctx, bson.D{{"getParameter", 1}, {"clientCursorMonitorFrequencySecs", 1}}).Decode(&params)
+		err = c.runCommand(ctx, "cursors", c.client.Database("admin"), bson.D{{"getParameter", 1}, {"clientCursorMonitorFrequencySecs", 1}}, &params)
 		if err != nil {
 			return
 		}
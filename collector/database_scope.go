@@ -0,0 +1,88 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ErrUnknownDatabase is returned when scoping collectors to a database the server does not list
+var ErrUnknownDatabase = errors.New("unknown database")
+
+// databaseScopedCollectors are the per-namespace collectors that can run for a single database
+var databaseScopedCollectors = []string{"collstats", "index_stats", "profile"}
+
+// DatabaseCollector returns a collector running the database-scoped
+// collectors (collstats, index_stats and profile) for database alone, whether
+// or not they are enabled for regular scrapes. Collectors are created on first
+// use and kept while the server lists the database, so the profile collector
+// resumes where its last run stopped
+func (cm *CollectorManager) DatabaseCollector(ctx context.Context, database string) (prometheus.Collector, error) {
+	// Listing may be slow, so it does not hold up scrapes of other databases
+	lister := NewBaseCollector(cm.client, cm.logger, cm.config)
+	databases, listErr := lister.listDatabases(ctx, 10*time.Second)
+
+	cm.databasesMu.Lock()
+	defer cm.databasesMu.Unlock()
+
+	if listErr != nil {
+		if collector, ok := cm.databases[database]; ok {
+			return collector, nil
+		}
+		return nil, fmt.Errorf("failed to list databases: %w", listErr)
+	}
+
+	// Only databases that exist get collectors, so arbitrary names cannot grow the cache
+	listed := make(map[string]bool, len(databases))
+	for _, name := range databases {
+		listed[name] = true
+	}
+	cm.evictUnlistedDatabases(listed)
+	if !listed[database] {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownDatabase, database)
+	}
+	if collector, ok := cm.databases[database]; ok {
+		return collector, nil
+	}
+
+	multi := cm.newDatabaseCollector(database)
+	if cm.databases == nil {
+		cm.databases = make(map[string]*MultiCollector)
+	}
+	cm.databases[database] = multi
+	return multi, nil
+}
+
+// newDatabaseCollector builds the per-namespace collectors scoped to one
+// database. They run whatever enabled_metrics lists, but disabled_metrics
+// still turns them off
+func (cm *CollectorManager) newDatabaseCollector(database string) *MultiCollector {
+	config := cm.config
+	config.database = database
+	config.EnabledMetrics = databaseScopedCollectors
+
+	multi := NewMultiCollector(cm.logger)
+	multi.collectors = []Collector{
+		NewCollStatsCollector(cm.client, cm.logger, config),
+		NewIndexStatsCollector(cm.client, cm.logger, config),
+		NewProfileCollector(cm.client, cm.logger, config),
+	}
+	multi.SetSeriesLimits(config.MaxSeriesPerMetric, config.SeriesLimits)
+	multi.lifetime = config.lifetime
+	multi.SetWatchdog(config.Watchdog)
+	multi.configured = (&CollectorManager{config: config}).isCollectorEnabled
+	return multi
+}
+
+// evictUnlistedDatabases drops the collectors of databases that were dropped
+// since they were created. Callers must hold cm.databasesMu
+func (cm *CollectorManager) evictUnlistedDatabases(listed map[string]bool) {
+	for name := range cm.databases {
+		if !listed[name] {
+			delete(cm.databases, name)
+		}
+	}
+}
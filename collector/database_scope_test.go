@@ -0,0 +1,51 @@
+package collector
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestDatabaseScopedListing(t *testing.T) {
+	collector := NewBaseCollector(nil, zap.NewNop(), CollectorConfig{database: "tenant_a"})
+
+	databases, err := collector.listDatabases(context.Background(), time.Second)
+	if err != nil {
+		t.Fatalf("Scoped listing should not query the server: %v", err)
+	}
+	if len(databases) != 1 || databases[0] != "tenant_a" {
+		t.Errorf("Scoped collectors should only see their database, got %v", databases)
+	}
+}
+
+func TestEvictUnlistedDatabases(t *testing.T) {
+	cm := &CollectorManager{databases: map[string]*MultiCollector{
+		"tenant_a": NewMultiCollector(zap.NewNop()),
+		"tenant_b": NewMultiCollector(zap.NewNop()),
+	}}
+
+	cm.evictUnlistedDatabases(map[string]bool{"tenant_a": true, "tenant_c": true})
+	if _, ok := cm.databases["tenant_a"]; !ok {
+		t.Error("Collectors of listed databases should be kept")
+	}
+	if _, ok := cm.databases["tenant_b"]; ok {
+		t.Error("Collectors of dropped databases should be evicted")
+	}
+}
+
+func TestDatabaseCollectorKeepsDisabledMetrics(t *testing.T) {
+	cm := NewCollectorManager(nil, zap.NewNop(), CollectorConfig{
+		EnabledMetrics:  []string{"server_status"},
+		DisabledMetrics: []string{"profile"},
+	})
+
+	multi := cm.newDatabaseCollector("tenant_a")
+	if multi.configured("profile") {
+		t.Error("Collectors in disabled_metrics should stay off for database scrapes")
+	}
+	if !multi.configured("collstats") || !multi.configured("index_stats") {
+		t.Error("Per-namespace collectors should run for database scrapes whatever enabled_metrics lists")
+	}
+}
@@ -0,0 +1,250 @@
+package collector
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// defaultDBHashConfirmations is how many consecutive checks must disagree
+// before a collection is reported as diverged
+const defaultDBHashConfirmations = 2
+
+// DBHashCollector compares dbHash results of the selected databases across
+// the data-bearing members of the replica set to detect diverged data
+type DBHashCollector struct {
+	*BaseCollector
+	descriptors   map[string]*prometheus.Desc
+	databases     []string
+	confirmations int
+
+	mu         sync.Mutex
+	mismatches map[string]int
+}
+
+func NewDBHashCollector(client *mongo.Client, logger *zap.Logger, config CollectorConfig) *DBHashCollector {
+	labels := []string{"instance", "replica_set", "shard", "database"}
+
+	descriptors := map[string]*prometheus.Desc{
+		"dbhash_mismatch": prometheus.NewDesc(
+			config.metricName("mongodb_dbhash_mismatch"),
+			"Whether the collection's dbHash differed between replica set members on consecutive checks (1) or not (0)",
+			append(labels, "collection"),
+			nil,
+		),
+		"dbhash_members_compared": prometheus.NewDesc(
+			config.metricName("mongodb_dbhash_members_compared"),
+			"Number of replica set members whose dbHash of the database was compared",
+			labels,
+			nil,
+		),
+	}
+
+	options := collectorOptions(config, "dbhash")
+	confirmations := getIntOption(options, "confirmations", 0)
+	if confirmations <= 0 {
+		confirmations = defaultDBHashConfirmations
+	}
+
+	return &DBHashCollector{
+		BaseCollector: NewBaseCollector(client, logger, config),
+		descriptors:   descriptors,
+		databases:     getStringSliceOption(options, "databases"),
+		confirmations: confirmations,
+		mismatches:    make(map[string]int),
+	}
+}
+
+func (c *DBHashCollector) Collect(ch chan<- prometheus.Metric) {
+	if !c.isMetricExplicitlyEnabled("dbhash") || len(c.databases) == 0 {
+		return
+	}
+	if c.config.MemberClient == nil {
+		c.logger.Debug("No per-member connections available, skipping dbHash checks")
+		return
+	}
+
+	ctx, done := c.collectContext("dbhash", 60*time.Second)
+	defer done()
+
+	var status bson.M
+	if err := c.runCommand(ctx, "dbhash", c.client.Database("admin"), bson.D{{"replSetGetStatus", 1}}, &status); err != nil {
+		c.logger.Debug("Failed to get replica set status, skipping dbHash checks", zap.Error(err))
+		return
+	}
+
+	// Only the primary's exporter compares, so each replica set is checked once
+	if state, _ := toInt64(status["myState"]); state != 1 {
+		c.logger.Debug("Not the primary, skipping dbHash checks")
+		return
+	}
+
+	members := dataBearingMembers(status)
+	if len(members) < 2 {
+		return
+	}
+
+	// Every member hashes the same snapshot, so writes made while the members
+	// are contacted one after another cannot look like divergence
+	at, ok := committedOpTime(status)
+	if !ok {
+		c.logger.Debug("No majority commit point in replica set status, skipping dbHash checks")
+		return
+	}
+
+	instance := c.getInstanceInfo(status)
+	if setName, ok := status["set"].(string); ok {
+		instance["replica_set"] = setName
+	}
+
+	for _, dbName := range c.databases {
+		c.checkDatabase(ctx, ch, dbName, members, at, instance)
+	}
+}
+
+// committedOpTime returns the majority commit point from replSetGetStatus,
+// which every data-bearing member can read a snapshot at
+func committedOpTime(status bson.M) (primitive.Timestamp, bool) {
+	optimes, _ := status["optimes"].(bson.M)
+	committed, _ := optimes["lastCommittedOpTime"].(bson.M)
+	ts, ok := committed["ts"].(primitive.Timestamp)
+	return ts, ok && !ts.IsZero()
+}
+
+// dataBearingMembers returns the host:port of every healthy primary and secondary
+func dataBearingMembers(status bson.M) []string {
+	members, ok := status["members"].(bson.A)
+	if !ok {
+		return nil
+	}
+
+	var hosts []string
+	for _, m := range members {
+		member, ok := m.(bson.M)
+		if !ok {
+			continue
+		}
+		health, _ := toInt64(member["health"])
+		state, _ := member["stateStr"].(string)
+		host, _ := member["name"].(string)
+		if health == 1 && host != "" && (state == "PRIMARY" || state == "SECONDARY") {
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts
+}
+
+func (c *DBHashCollector) checkDatabase(ctx context.Context, ch chan<- prometheus.Metric, dbName string, members []string, at primitive.Timestamp, instance map[string]string) {
+	cmd := bson.D{
+		{"dbHash", 1},
+		{"readConcern", bson.D{{"level", "snapshot"}, {"atClusterTime", at}}},
+	}
+
+	hashes := make(map[string]map[string]string, len(members))
+	for _, host := range members {
+		client, err := c.config.MemberClient(ctx, host)
+		if err != nil {
+			c.logger.Warn("Failed to connect to replica set member", zap.String("host", host), zap.Error(err))
+			continue
+		}
+
+		var result struct {
+			Collections map[string]string `bson:"collections"`
+		}
+		if err := client.Database(dbName).RunCommand(ctx, withMaxTime(ctx, cmd)).Decode(&result); err != nil {
+			c.logCommandError("Failed to run dbHash", err)
+			continue
+		}
+		hashes[host] = result.Collections
+	}
+
+	labels := []string{instance["instance"], instance["replica_set"], instance["shard"], dbName}
+	ch <- prometheus.MustNewConstMetric(c.descriptors["dbhash_members_compared"], prometheus.GaugeValue, float64(len(hashes)), labels...)
+	if len(hashes) < 2 {
+		return
+	}
+
+	mismatched := mismatchedCollections(hashes)
+	for _, collName := range hashedCollections(hashes) {
+		value := 0.0
+		if c.confirm(dbName+"."+collName, mismatched[collName]) {
+			value = 1
+			c.logger.Warn("Collection data differs between replica set members",
+				zap.String("database", dbName),
+				zap.String("collection", collName))
+		}
+		ch <- prometheus.MustNewConstMetric(c.descriptors["dbhash_mismatch"], prometheus.GaugeValue, value, append(labels, collName)...)
+	}
+}
+
+// confirm records one check of a collection and reports whether it has now
+// mismatched on enough consecutive checks, so a transient difference, such as
+// a member rolling back, is not reported as divergence
+func (c *DBHashCollector) confirm(key string, mismatched bool) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !mismatched {
+		delete(c.mismatches, key)
+		return false
+	}
+	c.mismatches[key]++
+	return c.mismatches[key] >= c.confirmations
+}
+
+// hashedCollections returns the sorted union of collections hashed by any member
+func hashedCollections(hashes map[string]map[string]string) []string {
+	seen := make(map[string]bool)
+	for _, collections := range hashes {
+		for collName := range collections {
+			seen[collName] = true
+		}
+	}
+
+	names := make([]string, 0, len(seen))
+	for collName := range seen {
+		names = append(names, collName)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// mismatchedCollections returns the collections whose hash is not the same on
+// every member, including collections missing on some members
+func mismatchedCollections(hashes map[string]map[string]string) map[string]bool {
+	mismatched := make(map[string]bool)
+	for _, collName := range hashedCollections(hashes) {
+		var first string
+		for _, collections := range hashes {
+			hash, ok := collections[collName]
+			if !ok {
+				mismatched[collName] = true
+				break
+			}
+			if first == "" {
+				first = hash
+			} else if hash != first {
+				mismatched[collName] = true
+				break
+			}
+		}
+	}
+	return mismatched
+}
+
+func (c *DBHashCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range c.descriptors {
+		ch <- desc
+	}
+}
+
+func (c *DBHashCollector) Name() string {
+	return "dbhash"
+}
@@ -0,0 +1,70 @@
+package collector
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+func TestMismatchedCollections(t *testing.T) {
+	hashes := map[string]map[string]string{
+		"db1:27017": {"orders": "a1", "users": "b1", "events": "c1"},
+		"db2:27017": {"orders": "a1", "users": "b2", "events": "c1"},
+		"db3:27017": {"orders": "a1", "users": "b1"},
+	}
+
+	mismatched := mismatchedCollections(hashes)
+	if mismatched["orders"] {
+		t.Error("Collection with equal hashes on every member should not mismatch")
+	}
+	if !mismatched["users"] {
+		t.Error("Collection with a differing hash should mismatch")
+	}
+	if !mismatched["events"] {
+		t.Error("Collection missing on a member should mismatch")
+	}
+}
+
+func TestDBHashConfirm(t *testing.T) {
+	c := NewDBHashCollector(nil, zap.NewNop(), CollectorConfig{})
+
+	if c.confirm("app.users", true) {
+		t.Error("A single mismatch should not be reported")
+	}
+	if !c.confirm("app.users", true) {
+		t.Error("Mismatch on consecutive checks should be reported")
+	}
+	if c.confirm("app.users", false) {
+		t.Error("Matching hashes should clear the mismatch")
+	}
+	if c.confirm("app.users", true) {
+		t.Error("Confirmation count should restart after a match")
+	}
+}
+
+func TestDataBearingMembers(t *testing.T) {
+	status := bson.M{"members": bson.A{
+		bson.M{"name": "db1:27017", "health": 1.0, "stateStr": "PRIMARY"},
+		bson.M{"name": "db2:27017", "health": 1.0, "stateStr": "SECONDARY"},
+		bson.M{"name": "db3:27017", "health": 1.0, "stateStr": "ARBITER"},
+		bson.M{"name": "db4:27017", "health": 0.0, "stateStr": "(not reachable/healthy)"},
+	}}
+
+	members := dataBearingMembers(status)
+	if len(members) != 2 || members[0] != "db1:27017" || members[1] != "db2:27017" {
+		t.Errorf("Only healthy primaries and secondaries should be compared, got %v", members)
+	}
+}
+
+func TestCommittedOpTime(t *testing.T) {
+	ts := primitive.Timestamp{T: 1700000000, I: 3}
+	status := bson.M{"optimes": bson.M{"lastCommittedOpTime": bson.M{"ts": ts, "t": int64(5)}}}
+	if got, ok := committedOpTime(status); !ok || got != ts {
+		t.Errorf("Expected commit point %v, got %v (ok=%v)", ts, got, ok)
+	}
+	if _, ok := committedOpTime(bson.M{}); ok {
+		t.Error("A status without optimes has no commit point")
+	}
+}
@@ -0,0 +1,66 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// runTracker ties collector runs to the manager's lifetime: their contexts are
+// cancelled on shutdown, which then waits for them to return so the MongoDB
+// client is not disconnected underneath them
+type runTracker struct {
+	ctx context.Context
+
+	mu       sync.Mutex
+	stopped  bool
+	inFlight int
+	wg       sync.WaitGroup
+}
+
+func newRunTracker(ctx context.Context) *runTracker {
+	return &runTracker{ctx: ctx}
+}
+
+// start registers a run, which fails once shutdown has begun
+func (t *runTracker) start() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.stopped {
+		return false
+	}
+	t.inFlight++
+	t.wg.Add(1)
+	return true
+}
+
+func (t *runTracker) done() {
+	t.mu.Lock()
+	t.inFlight--
+	t.mu.Unlock()
+	t.wg.Done()
+}
+
+// drain refuses new runs and waits for in-flight ones until ctx expires.
+// Callers cancel the tracker's context first so runs stop waiting on MongoDB.
+func (t *runTracker) drain(ctx context.Context) error {
+	t.mu.Lock()
+	t.stopped = true
+	t.mu.Unlock()
+
+	finished := make(chan struct{})
+	go func() {
+		t.wg.Wait()
+		close(finished)
+	}()
+
+	select {
+	case <-finished:
+		return nil
+	case <-ctx.Done():
+		t.mu.Lock()
+		remaining := t.inFlight
+		t.mu.Unlock()
+		return fmt.Errorf("%d collector runs still in progress: %w", remaining, ctx.Err())
+	}
+}
@@ -0,0 +1,79 @@
+package collector
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+type blockingCollector struct {
+	*BaseCollector
+	started chan struct{}
+}
+
+func (c *blockingCollector) Describe(ch chan<- *prometheus.Desc) {}
+
+func (c *blockingCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, done := c.collectContext("blocking", time.Hour)
+	defer done()
+
+	close(c.started)
+	<-ctx.Done()
+}
+
+func (c *blockingCollector) Name() string {
+	return "blocking"
+}
+
+func TestCollectorManagerShutdownDrainsRuns(t *testing.T) {
+	cm := NewCollectorManager(nil, zap.NewNop(), CollectorConfig{})
+	cm.multiCollector.lifetime = cm.config.lifetime
+
+	blocking := &blockingCollector{BaseCollector: NewBaseCollector(nil, zap.NewNop(), cm.config), started: make(chan struct{})}
+	cm.multiCollector.AddCollector(blocking)
+
+	scraped := make(chan struct{})
+	go func() {
+		cm.multiCollector.Collect(make(chan prometheus.Metric, 10))
+		close(scraped)
+	}()
+	<-blocking.started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := cm.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown should cancel and wait for the in-flight run: %v", err)
+	}
+
+	select {
+	case <-scraped:
+	case <-time.After(time.Second):
+		t.Fatal("Scrape should return once its collector run is cancelled")
+	}
+
+	// Runs must not start against a client about to be disconnected
+	if cm.config.lifetime.start() {
+		t.Error("No collector run should start after shutdown")
+	}
+}
+
+func TestRunTrackerDrainDeadline(t *testing.T) {
+	tracker := newRunTracker(context.Background())
+	if !tracker.start() {
+		t.Fatal("Run should start before shutdown")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := tracker.drain(ctx); err == nil {
+		t.Error("Drain should fail while a run is still in progress at the deadline")
+	}
+
+	tracker.done()
+	if err := tracker.drain(context.Background()); err != nil {
+		t.Errorf("Drain should succeed once the run finished: %v", err)
+	}
+}
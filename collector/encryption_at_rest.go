@@ -0,0 +1,136 @@
+package collector
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+// encryptionAtRestDescriptors describes the encrypted storage engine metrics
+func encryptionAtRestDescriptors(config CollectorConfig) map[string]*prometheus.Desc {
+	labels := []string{"instance", "replica_set", "shard"}
+
+	return map[string]*prometheus.Desc{
+		"encryption_at_rest_enabled": prometheus.NewDesc(
+			config.metricName("mongodb_encryption_at_rest_enabled"),
+			"Whether the encrypted storage engine is enabled (1) or not (0)",
+			labels,
+			nil,
+		),
+		"encryption_at_rest_info": prometheus.NewDesc(
+			config.metricName("mongodb_encryption_at_rest_info"),
+			"Encryption at rest configuration, always 1; key_management is kmip, vault or keyfile",
+			append(labels, "key_management", "cipher_mode"),
+			nil,
+		),
+		"encryption_at_rest_timestamp_seconds": prometheus.NewDesc(
+			config.metricName("mongodb_encryption_at_rest_timestamp_seconds"),
+			"Timestamp reported in serverStatus encryptionAtRest, such as the last master key rotation; field is the dotted path",
+			append(labels, "field"),
+			nil,
+		),
+	}
+}
+
+// cmdLineOptions returns the parsed startup options, read once because they
+// only change on restart. Reading them needs the clusterMonitor role
+func (c *ServerStatusCollector) cmdLineOptions(ctx context.Context) bson.M {
+	if c.startupOptions != nil {
+		return c.startupOptions
+	}
+
+	var cmdLineOpts struct {
+		Parsed bson.M `bson:"parsed"`
+	}
+	if err := c.runCommand(ctx, "server_status", c.client.Database("admin"), bson.D{{"getCmdLineOpts", 1}}, &cmdLineOpts); err != nil {
+		c.logCommandError("Failed to read startup options", err)
+		return nil
+	}
+	c.startupOptions = cmdLineOpts.Parsed
+	return c.startupOptions
+}
+
+// encryptionKeyManagement returns how the master key is managed according to
+// the security startup options, or "" when encryption is not enabled
+func encryptionKeyManagement(security bson.M) string {
+	if enabled, _ := security["enableEncryption"].(bool); !enabled {
+		return ""
+	}
+	switch {
+	case security["kmip"] != nil:
+		return "kmip"
+	case security["vault"] != nil:
+		return "vault"
+	default:
+		return "keyfile"
+	}
+}
+
+// encryptionTimestamps returns the date fields of doc keyed by their dotted path
+func encryptionTimestamps(prefix string, doc bson.M, timestamps map[string]time.Time) {
+	for key, value := range doc {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+		switch v := value.(type) {
+		case bson.M:
+			encryptionTimestamps(path, v, timestamps)
+		case primitive.DateTime:
+			timestamps[path] = v.Time()
+		case primitive.Timestamp:
+			timestamps[path] = time.Unix(int64(v.T), 0)
+		}
+	}
+}
+
+// collectEncryptionAtRest exports whether the encrypted storage engine is on,
+// how its master key is managed, and the timestamps serverStatus reports for
+// it, so overdue key rotations can be alerted on
+func (c *ServerStatusCollector) collectEncryptionAtRest(ctx context.Context, ch chan<- prometheus.Metric, result bson.M, instance map[string]string) {
+	labels := []string{instance["instance"], instance["replica_set"], instance["shard"]}
+	section, hasSection := result["encryptionAtRest"].(bson.M)
+
+	options := c.cmdLineOptions(ctx)
+	if options == nil && !hasSection {
+		return
+	}
+	security, _ := options["security"].(bson.M)
+	keyManagement := encryptionKeyManagement(security)
+
+	enabled := 0.0
+	if keyManagement != "" || hasSection {
+		enabled = 1
+	}
+	ch <- prometheus.MustNewConstMetric(c.descriptors["encryption_at_rest_enabled"], prometheus.GaugeValue, enabled, labels...)
+
+	if keyManagement != "" {
+		cipherMode, _ := security["encryptionCipherMode"].(string)
+		if cipherMode == "" {
+			cipherMode = "AES256-CBC"
+		}
+		ch <- prometheus.MustNewConstMetric(c.descriptors["encryption_at_rest_info"], prometheus.GaugeValue, 1, append(labels, keyManagement, cipherMode)...)
+	}
+
+	if !hasSection {
+		return
+	}
+	timestamps := make(map[string]time.Time)
+	encryptionTimestamps("", section, timestamps)
+	for field, timestamp := range timestamps {
+		if timestamp.IsZero() {
+			c.logger.Debug("Skipping empty encryption at rest timestamp", zap.String("field", field))
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(
+			c.descriptors["encryption_at_rest_timestamp_seconds"],
+			prometheus.GaugeValue,
+			float64(timestamp.Unix()),
+			append(labels, field)...,
+		)
+	}
+}
@@ -0,0 +1,47 @@
+package collector
+
+import (
+	"regexp"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// traceparentPattern matches a W3C traceparent, which applications can pass
+// as a command comment to tie profiled operations to their traces
+var traceparentPattern = regexp.MustCompile(`\b00-([0-9a-f]{32})-[0-9a-f]{16}-[0-9a-f]{2}\b`)
+
+// profileExemplar returns the exemplar labels identifying a profiled
+// operation: its query shape hash and, when the command carried one, its trace ID
+func profileExemplar(entry bson.M) prometheus.Labels {
+	labels := prometheus.Labels{}
+	if queryHash, ok := entry["queryHash"].(string); ok && queryHash != "" {
+		labels["query_hash"] = queryHash
+	}
+	if traceID := commentTraceID(entry); traceID != "" {
+		labels["trace_id"] = traceID
+	}
+	return labels
+}
+
+// commentTraceID extracts the trace ID of a traceparent found in the command's
+// comment, given either as a string or as a document with a traceparent field
+func commentTraceID(entry bson.M) string {
+	command, ok := entry["command"].(bson.M)
+	if !ok {
+		return ""
+	}
+
+	var traceparent string
+	switch comment := command["comment"].(type) {
+	case string:
+		traceparent = comment
+	case bson.M:
+		traceparent, _ = comment["traceparent"].(string)
+	}
+
+	if match := traceparentPattern.FindStringSubmatch(traceparent); len(match) == 2 {
+		return match[1]
+	}
+	return ""
+}
@@ -0,0 +1,85 @@
+package collector
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.uber.org/zap"
+)
+
+func TestProfileExemplar(t *testing.T) {
+	traceparent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	tests := []struct {
+		name     string
+		entry    bson.M
+		expected prometheus.Labels
+	}{
+		{"no identifiers", bson.M{"op": "query"}, prometheus.Labels{}},
+		{"query hash", bson.M{"queryHash": "8C9A7C41"}, prometheus.Labels{"query_hash": "8C9A7C41"}},
+		{
+			"traceparent comment",
+			bson.M{"queryHash": "8C9A7C41", "command": bson.M{"find": "orders", "comment": "traceparent='" + traceparent + "'"}},
+			prometheus.Labels{"query_hash": "8C9A7C41", "trace_id": "4bf92f3577b34da6a3ce929d0e0e4736"},
+		},
+		{
+			"traceparent document comment",
+			bson.M{"command": bson.M{"find": "orders", "comment": bson.M{"traceparent": traceparent}}},
+			prometheus.Labels{"trace_id": "4bf92f3577b34da6a3ce929d0e0e4736"},
+		},
+		{"unrelated comment", bson.M{"command": bson.M{"comment": "nightly report"}}, prometheus.Labels{}},
+	}
+
+	for _, tt := range tests {
+		got := profileExemplar(tt.entry)
+		if len(got) != len(tt.expected) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, got)
+			continue
+		}
+		for name, value := range tt.expected {
+			if got[name] != value {
+				t.Errorf("%s: expected %s=%s, got %v", tt.name, name, value, got)
+			}
+		}
+	}
+}
+
+func TestProfileDurationHistogramExemplars(t *testing.T) {
+	config := CollectorConfig{
+		Collectors: map[string]interface{}{
+			"profile": map[string]interface{}{"duration_histogram": true},
+		},
+	}
+	collector := NewProfileCollector(nil, zap.NewNop(), config)
+	if collector.histogram == nil {
+		t.Fatal("Duration histogram should be created when enabled")
+	}
+
+	collector.observeDuration(bson.M{"millis": int32(1500), "queryHash": "8C9A7C41"},
+		"host:27017", "", "", "app", "query", "orders")
+
+	registry := prometheus.NewPedanticRegistry()
+	registry.MustRegister(collector.histogram)
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Failed to gather profile histogram: %v", err)
+	}
+
+	found := false
+	for _, bucket := range families[0].GetMetric()[0].GetHistogram().GetBucket() {
+		if exemplar := bucket.GetExemplar(); exemplar != nil {
+			found = true
+			if bucket.GetUpperBound() != 2.5 || exemplar.GetValue() != 1.5 {
+				t.Errorf("Exemplar should sit in the 2.5s bucket with value 1.5, got bucket %v value %v",
+					bucket.GetUpperBound(), exemplar.GetValue())
+			}
+			if label := exemplar.GetLabel()[0]; label.GetName() != "query_hash" || label.GetValue() != "8C9A7C41" {
+				t.Errorf("Exemplar should carry the query hash, got %v", exemplar.GetLabel())
+			}
+		}
+	}
+	if !found {
+		t.Error("Histogram should carry an exemplar for the observed operation")
+	}
+}
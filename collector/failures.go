@@ -0,0 +1,38 @@
+package collector
+
+import "github.com/prometheus/client_golang/prometheus"
+
+func newPanicsCounter() *prometheus.CounterVec {
+	return prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "mongodb_exporter_collector_panics_total",
+		Help: "Collector runs that panicked",
+	}, []string{"collector"})
+}
+
+// newFailuresCounter counts every failed run: panics, runs producing no metrics,
+// runs abandoned by the watchdog and runs skipped while an abandoned one is in flight
+func newFailuresCounter() *prometheus.CounterVec {
+	return prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "mongodb_exporter_collector_errors_total",
+		Help: "Collector runs that failed",
+	}, []string{"collector"})
+}
+
+var collectorEnabledDesc = prometheus.NewDesc(
+	"mongodb_exporter_collector_enabled",
+	"Whether the collector runs on scrapes (1) or not (0), after enabled and disabled metrics, presets, server flavor and runtime toggles",
+	[]string{"collector"},
+	nil,
+)
+
+// collectEnablement exports whether each collector is both enabled in
+// configuration and not disabled at runtime
+func collectEnablement(ch chan<- prometheus.Metric, statuses []CollectorStatus) {
+	for _, status := range statuses {
+		enabled := 0.0
+		if status.Enabled && status.Configured {
+			enabled = 1
+		}
+		ch <- prometheus.MustNewConstMetric(collectorEnabledDesc, prometheus.GaugeValue, enabled, status.Name)
+	}
+}
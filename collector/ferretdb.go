@@ -0,0 +1,75 @@
+package collector
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// ferretDBDescriptors describes the FerretDB-specific metrics
+func ferretDBDescriptors(config CollectorConfig) map[string]*prometheus.Desc {
+	labels := []string{"instance", "replica_set", "shard"}
+
+	return map[string]*prometheus.Desc{
+		"ferretdb_info": prometheus.NewDesc(
+			config.metricName("mongodb_ferretdb_info"),
+			"FerretDB version serving the MongoDB protocol, always 1; only exported for FerretDB",
+			append(labels, "version"),
+			nil,
+		),
+		"ferretdb_status": prometheus.NewDesc(
+			config.metricName("mongodb_ferretdb_status"),
+			"Numeric field of the FerretDB serverStatus section, such as the PostgreSQL backend pool; field is the dotted path",
+			append(labels, "field"),
+			nil,
+		),
+	}
+}
+
+// ferretDBVersion returns the FerretDB version found by detection, or "" for other servers
+func (s *serverCapabilities) ferretDBVersion() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ferretDB
+}
+
+// flattenNumeric returns the numeric leaves of doc keyed by their dotted path
+func flattenNumeric(prefix string, doc bson.M, values map[string]float64) {
+	for key, value := range doc {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+		if nested, ok := value.(bson.M); ok {
+			flattenNumeric(path, nested, values)
+			continue
+		}
+		if number, ok := toFloat64(value); ok {
+			values[path] = number
+		}
+	}
+}
+
+// collectFerretDB exports the FerretDB version and the ferretdb serverStatus
+// section, whose fields depend on the FerretDB version and backend
+func (c *ServerStatusCollector) collectFerretDB(ch chan<- prometheus.Metric, result bson.M, instance map[string]string) {
+	if c.config.capabilities == nil {
+		return
+	}
+	version := c.config.capabilities.ferretDBVersion()
+	if version == "" {
+		return
+	}
+
+	labels := []string{instance["instance"], instance["replica_set"], instance["shard"]}
+	ch <- prometheus.MustNewConstMetric(c.descriptors["ferretdb_info"], prometheus.GaugeValue, 1, append(labels, version)...)
+
+	section, ok := result["ferretdb"].(bson.M)
+	if !ok {
+		return
+	}
+	values := make(map[string]float64)
+	flattenNumeric("", section, values)
+	for field, value := range values {
+		ch <- prometheus.MustNewConstMetric(c.descriptors["ferretdb_status"], prometheus.GaugeValue, value, append(labels, field)...)
+	}
+}
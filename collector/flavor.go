@@ -0,0 +1,95 @@
+package collector
+
+import (
+	"sort"
+
+	"go.uber.org/zap"
+)
+
+// Server flavors other than MongoDB that collectors adapt to
+const (
+	// flavorDocumentDB targets Amazon DocumentDB, which implements a subset of the MongoDB API
+	flavorDocumentDB = "documentdb"
+	// flavorFerretDB targets FerretDB, which serves the MongoDB protocol from a PostgreSQL backend
+	flavorFerretDB = "ferretdb"
+)
+
+// flavorUnsupportedCollectors are, per flavor, the collectors needing commands
+// or namespaces the server does not provide, with what is missing
+var flavorUnsupportedCollectors = map[string]map[string]string{
+	flavorDocumentDB: {
+		"profile":                "system.profile collections",
+		"sharding":               "the config database",
+		"shard_key_distribution": "the config database",
+		"replica_set_status":     "replSetGetStatus and the oplog",
+		"wiredtiger":             "WiredTiger statistics",
+		"locks":                  "lock statistics",
+		"dbhash":                 "the dbHash command",
+	},
+	flavorFerretDB: {
+		"profile":                "system.profile collections",
+		"sharding":               "sharding",
+		"shard_key_distribution": "sharding",
+		"replica_set_status":     "replication",
+		"wiredtiger":             "WiredTiger statistics",
+		"locks":                  "lock statistics",
+		"dbhash":                 "the dbHash command",
+		"index_stats":            "the $indexStats stage",
+		"maintenance":            "the $currentOp stage",
+	},
+}
+
+// flavorUnsupported reports whether the configured or detected server flavor
+// lacks what the named collector needs. It only reads the cached detection
+// state: detection runs from runCommand under the collect deadline, which also
+// refuses the commands of a collector a flavor detected on that scrape rules out
+func (bc *BaseCollector) flavorUnsupported(name string) bool {
+	caps := bc.config.capabilities
+	if caps == nil {
+		return false
+	}
+	return caps.collectorUnsupported(name)
+}
+
+// collectorUnsupported reports whether the server flavor lacks what the named collector needs
+func (s *serverCapabilities) collectorUnsupported(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, unsupported := flavorUnsupportedCollectors[s.flavor][name]
+	return unsupported
+}
+
+// setFlavor records the configured target flavor
+func (s *serverCapabilities) setFlavor(flavor string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.restrictFlavor(flavor)
+}
+
+// restrictFlavor switches to flavor and exports the collectors and features
+// it rules out. DocumentDB and FerretDB report the MongoDB API version they
+// emulate in buildInfo, so version-gated features are turned off rather than
+// trusting it. Callers must hold s.mu
+func (s *serverCapabilities) restrictFlavor(flavor string) {
+	s.flavor = flavor
+	unsupported, ok := flavorUnsupportedCollectors[flavor]
+	if !ok {
+		return
+	}
+
+	skipped := make([]string, 0, len(unsupported)+len(featureVersions))
+	for name := range unsupported {
+		skipped = append(skipped, name)
+	}
+	for feature := range featureVersions {
+		skipped = append(skipped, feature)
+	}
+	sort.Strings(skipped)
+	for _, name := range skipped {
+		s.unsupported.WithLabelValues(name, "target_flavor").Set(1)
+	}
+	s.logger.Info("Skipping collectors and features the server flavor does not support",
+		zap.String("flavor", flavor),
+		zap.Strings("skipped", skipped))
+}
@@ -0,0 +1,65 @@
+package collector
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// globalLockTypes maps the currentQueue and activeClients fields to type labels
+var globalLockTypes = map[string]string{
+	"total":   "total",
+	"readers": "reader",
+	"writers": "writer",
+}
+
+// globalLockDescriptors describes the serverStatus globalLock metrics, the
+// queue and client counts dashboards use as saturation indicators
+func globalLockDescriptors(config CollectorConfig) map[string]*prometheus.Desc {
+	labels := []string{"instance", "replica_set", "shard"}
+
+	return map[string]*prometheus.Desc{
+		"global_lock_current_queue": prometheus.NewDesc(
+			config.metricName("mongodb_global_lock_current_queue"),
+			"Number of operations queued waiting for a lock, by type (total, reader, writer)",
+			append(labels, "type"),
+			nil,
+		),
+		"global_lock_active_clients": prometheus.NewDesc(
+			config.metricName("mongodb_global_lock_active_clients"),
+			"Number of connected clients performing operations, by type (total, reader, writer)",
+			append(labels, "type"),
+			nil,
+		),
+		"global_lock_total_time_seconds": prometheus.NewDesc(
+			config.metricName("mongodb_global_lock_total_time_seconds"),
+			"Time since the server started and created the global lock",
+			labels,
+			nil,
+		),
+	}
+}
+
+// collectGlobalLock exports the globalLock section. totalTime is reported in microseconds
+func (c *ServerStatusCollector) collectGlobalLock(ch chan<- prometheus.Metric, result bson.M, instance map[string]string) {
+	globalLock, ok := result["globalLock"].(bson.M)
+	if !ok {
+		return
+	}
+	labels := []string{instance["instance"], instance["replica_set"], instance["shard"]}
+
+	if value := c.getNumericValue(globalLock["totalTime"]); value != nil {
+		ch <- prometheus.MustNewConstMetric(c.descriptors["global_lock_total_time_seconds"], prometheus.CounterValue, *value/1e6, labels...)
+	}
+
+	for section, key := range map[string]string{"currentQueue": "global_lock_current_queue", "activeClients": "global_lock_active_clients"} {
+		counts, ok := globalLock[section].(bson.M)
+		if !ok {
+			continue
+		}
+		for field, lockType := range globalLockTypes {
+			if value := c.getNumericValue(counts[field]); value != nil {
+				ch <- prometheus.MustNewConstMetric(c.descriptors[key], prometheus.GaugeValue, *value, append(labels, lockType)...)
+			}
+		}
+	}
+}
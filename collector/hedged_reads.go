@@ -0,0 +1,44 @@
+package collector
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// hedgingFields maps the mongos serverStatus hedgingMetrics counters to descriptors
+var hedgingFields = []struct {
+	key     string
+	descKey string
+	help    string
+}{
+	{"numTotalOperations", "hedged_reads_operations_total", "Operations the mongos ran with the hedged read option enabled"},
+	{"numTotalHedgedOperations", "hedged_reads_hedged_operations_total", "Operations the mongos actually hedged by sending them to an additional member"},
+	{"numAdvantageouslyHedgedOperations", "hedged_reads_advantageous_total", "Hedged operations answered first by the additional member"},
+}
+
+// hedgingDescriptors describes the hedged read counters of a mongos
+func hedgingDescriptors(config CollectorConfig) map[string]*prometheus.Desc {
+	labels := []string{"instance", "replica_set", "shard"}
+
+	descriptors := make(map[string]*prometheus.Desc, len(hedgingFields))
+	for _, field := range hedgingFields {
+		descriptors[field.descKey] = prometheus.NewDesc(config.metricName("mongodb_"+field.descKey), field.help, labels, nil)
+	}
+	return descriptors
+}
+
+// collectHedgingMetrics exports the hedgingMetrics section that mongos 4.4+
+// reports; mongod has no such section, so nothing is emitted there
+func (c *ServerStatusCollector) collectHedgingMetrics(ch chan<- prometheus.Metric, result bson.M, instance map[string]string) {
+	hedging, ok := result["hedgingMetrics"].(bson.M)
+	if !ok {
+		return
+	}
+
+	labels := []string{instance["instance"], instance["replica_set"], instance["shard"]}
+	for _, field := range hedgingFields {
+		if value := c.getNumericValue(hedging[field.key]); value != nil {
+			ch <- prometheus.MustNewConstMetric(c.descriptors[field.descKey], prometheus.CounterValue, *value, labels...)
+		}
+	}
+}
@@ -0,0 +1,261 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// defaultSelectivitySampleSize is the number of documents sampled per collection
+const defaultSelectivitySampleSize = 1000
+
+// IndexSelectivityCollector estimates how selective each index is by sampling
+// documents and counting the distinct key values among them
+type IndexSelectivityCollector struct {
+	*BaseCollector
+	descriptors map[string]*prometheus.Desc
+	sampleSize  int
+	collections map[string]bool
+}
+
+func NewIndexSelectivityCollector(client *mongo.Client, logger *zap.Logger, config CollectorConfig) *IndexSelectivityCollector {
+	labels := []string{"instance", "replica_set", "shard", "database", "collection"}
+	indexLabels := []string{"instance", "replica_set", "shard", "database", "collection", "index"}
+
+	descriptors := map[string]*prometheus.Desc{
+		"index_selectivity_ratio": prometheus.NewDesc(
+			config.metricName("mongodb_index_selectivity_ratio"),
+			"Estimated distinct index keys per sampled document (1 = every document has its own key)",
+			indexLabels,
+			nil,
+		),
+		"index_selectivity_sampled_documents": prometheus.NewDesc(
+			config.metricName("mongodb_index_selectivity_sampled_documents"),
+			"Number of documents sampled to estimate index selectivity",
+			labels,
+			nil,
+		),
+	}
+
+	options := collectorOptions(config, "index_selectivity")
+	sampleSize := getIntOption(options, "sample_size", 0)
+	if sampleSize <= 0 {
+		sampleSize = defaultSelectivitySampleSize
+	}
+
+	var collections map[string]bool
+	if names := getStringSliceOption(options, "collections"); len(names) > 0 {
+		collections = make(map[string]bool, len(names))
+		for _, name := range names {
+			collections[name] = true
+		}
+	}
+
+	return &IndexSelectivityCollector{
+		BaseCollector: NewBaseCollector(client, logger, config),
+		descriptors:   descriptors,
+		sampleSize:    sampleSize,
+		collections:   collections,
+	}
+}
+
+func (c *IndexSelectivityCollector) Collect(ch chan<- prometheus.Metric) {
+	if !c.isMetricExplicitlyEnabled("index_selectivity") {
+		return
+	}
+
+	ctx, done := c.collectContext("index_selectivity", 30*time.Second)
+	defer done()
+
+	databases, err := c.listDatabases(ctx, 10*time.Second)
+	if err != nil {
+		c.logger.Error("Failed to list databases", zap.Error(err))
+		return
+	}
+
+	instance := c.getInstanceInfo(bson.M{})
+
+	var namespaces []namespace
+	for _, dbName := range databases {
+		if shouldSkipDatabase(dbName) {
+			continue
+		}
+
+		collections, err := c.listCollections(ctx, dbName, 10*time.Second)
+		if err != nil {
+			c.logger.Error("Failed to list collections", zap.String("database", dbName), zap.Error(err))
+			continue
+		}
+
+		kinds, err := listCollectionKinds(ctx, c.client.Database(dbName), 10*time.Second)
+		if err != nil {
+			c.logger.Debug("Failed to list collection types", zap.String("database", dbName), zap.Error(err))
+		}
+
+		for _, collName := range collections {
+			ns := namespace{Database: dbName, Collection: collName, Kind: kinds[collName]}
+			// Views have no indexes and time-series indexes live on the buckets
+			if shouldSkipCollection(collName) || ns.Kind == kindView || ns.Kind == kindTimeseries {
+				continue
+			}
+			if c.collections != nil && !c.collections[ns.String()] {
+				continue
+			}
+			namespaces = append(namespaces, ns)
+		}
+	}
+
+	forEachNamespace(ctx, namespaces, c.config.Parallelism, func(ns namespace) {
+		c.collectSelectivity(ctx, ch, ns, instance)
+	})
+}
+
+// selectivityIndex is an index whose keys can be grouped on in a sample
+type selectivityIndex struct {
+	name   string
+	fields []string
+}
+
+// selectivityIndexes returns the indexes worth sampling. The _id index is
+// unique by definition, and text, wildcard and geo indexes do not index the
+// plain field values, so their selectivity cannot be read from documents
+func selectivityIndexes(specs []*mongo.IndexSpecification) []selectivityIndex {
+	var indexes []selectivityIndex
+	for _, spec := range specs {
+		if spec.Name == "_id_" {
+			continue
+		}
+
+		elements, err := spec.KeysDocument.Elements()
+		if err != nil || len(elements) == 0 {
+			continue
+		}
+
+		index := selectivityIndex{name: spec.Name}
+		for _, element := range elements {
+			if !plainIndexKey(element.Key(), element.Value()) {
+				index.fields = nil
+				break
+			}
+			index.fields = append(index.fields, element.Key())
+		}
+		if len(index.fields) > 0 {
+			indexes = append(indexes, index)
+		}
+	}
+	return indexes
+}
+
+// plainIndexKey reports whether an index key indexes the field's value as is.
+// Ascending, descending and hashed keys do; text, geo and wildcard keys do not
+func plainIndexKey(field string, value bson.RawValue) bool {
+	if field == "$**" || strings.HasSuffix(field, ".$**") {
+		return false
+	}
+	kind, isString := value.StringValueOK()
+	return !isString || kind == "hashed"
+}
+
+// selectivityPipeline samples the collection once and counts, in one $facet
+// branch per index, the distinct key tuples among the sampled documents
+func selectivityPipeline(indexes []selectivityIndex, sampleSize int) []bson.D {
+	facets := bson.D{{"sampled", bson.A{bson.D{{"$count", "n"}}}}}
+	for i, index := range indexes {
+		key := bson.D{}
+		for j, field := range index.fields {
+			key = append(key, bson.E{Key: fmt.Sprintf("k%d", j), Value: "$" + field})
+		}
+		facets = append(facets, bson.E{Key: fmt.Sprintf("i%d", i), Value: bson.A{
+			bson.D{{"$group", bson.D{{"_id", key}}}},
+			bson.D{{"$count", "n"}},
+		}})
+	}
+
+	return []bson.D{
+		{{"$sample", bson.D{{"size", sampleSize}}}},
+		{{"$facet", facets}},
+	}
+}
+
+// facetCount reads the n of a single-document $count facet branch
+func facetCount(result bson.M, branch string) int64 {
+	docs, ok := result[branch].(bson.A)
+	if !ok || len(docs) == 0 {
+		return 0
+	}
+	doc, ok := docs[0].(bson.M)
+	if !ok {
+		return 0
+	}
+	n, _ := toInt64(doc["n"])
+	return n
+}
+
+func (c *IndexSelectivityCollector) collectSelectivity(ctx context.Context, ch chan<- prometheus.Metric, ns namespace, instance map[string]string) {
+	coll := c.client.Database(ns.Database).Collection(ns.Collection)
+
+	specs, err := c.listIndexes(ctx, "index_selectivity", coll)
+	if err != nil {
+		c.logger.Debug("Failed to list indexes",
+			zap.String("namespace", ns.String()),
+			zap.Error(err))
+		return
+	}
+	indexes := selectivityIndexes(specs)
+	if len(indexes) == 0 {
+		return
+	}
+
+	cursor, err := c.aggregate(ctx, "index_selectivity", coll, selectivityPipeline(indexes, c.sampleSize), &options.AggregateOptions{MaxTime: maxTime(ctx)})
+	if err != nil {
+		c.logCommandError("Failed to sample collection for index selectivity", err)
+		return
+	}
+	var results []bson.M
+	if err := cursor.All(ctx, &results); err != nil || len(results) == 0 {
+		c.logger.Debug("Failed to read index selectivity sample",
+			zap.String("namespace", ns.String()),
+			zap.Error(err))
+		return
+	}
+
+	sampled := facetCount(results[0], "sampled")
+	if sampled == 0 {
+		return
+	}
+
+	labels := []string{instance["instance"], instance["replica_set"], instance["shard"], ns.Database, ns.Collection}
+	ch <- prometheus.MustNewConstMetric(
+		c.descriptors["index_selectivity_sampled_documents"],
+		prometheus.GaugeValue,
+		float64(sampled),
+		labels...,
+	)
+
+	for i, index := range indexes {
+		distinct := facetCount(results[0], fmt.Sprintf("i%d", i))
+		ch <- prometheus.MustNewConstMetric(
+			c.descriptors["index_selectivity_ratio"],
+			prometheus.GaugeValue,
+			float64(distinct)/float64(sampled),
+			append(labels, index.name)...,
+		)
+	}
+}
+
+func (c *IndexSelectivityCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range c.descriptors {
+		ch <- desc
+	}
+}
+
+func (c *IndexSelectivityCollector) Name() string {
+	return "index_selectivity"
+}
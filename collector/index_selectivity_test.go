@@ -0,0 +1,66 @@
+package collector
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+func indexSpec(t *testing.T, name string, keys bson.D) *mongo.IndexSpecification {
+	raw, err := bson.Marshal(keys)
+	if err != nil {
+		t.Fatalf("Failed to marshal index keys: %v", err)
+	}
+	return &mongo.IndexSpecification{Name: name, KeysDocument: raw}
+}
+
+func TestSelectivityIndexes(t *testing.T) {
+	specs := []*mongo.IndexSpecification{
+		indexSpec(t, "_id_", bson.D{{"_id", 1}}),
+		indexSpec(t, "status_1_created_-1", bson.D{{"status", 1}, {"created", -1}}),
+		indexSpec(t, "tenant_hashed", bson.D{{"tenant", "hashed"}}),
+		indexSpec(t, "body_text", bson.D{{"_fts", "text"}, {"_ftsx", 1}}),
+		indexSpec(t, "location_2dsphere", bson.D{{"location", "2dsphere"}}),
+		indexSpec(t, "attrs_wildcard", bson.D{{"attrs.$**", 1}}),
+	}
+
+	indexes := selectivityIndexes(specs)
+	if len(indexes) != 2 {
+		t.Fatalf("Only plain and hashed indexes besides _id should be sampled, got %v", indexes)
+	}
+	if indexes[0].name != "status_1_created_-1" || len(indexes[0].fields) != 2 {
+		t.Errorf("Compound index should group on all its fields, got %v", indexes[0])
+	}
+
+	pipeline := selectivityPipeline(indexes, 500)
+	if pipeline[0][0].Key != "$sample" || len(pipeline[1][0].Value.(bson.D)) != 3 {
+		t.Errorf("Pipeline should sample once and facet per index plus the sample count, got %v", pipeline)
+	}
+}
+
+func TestIndexSelectivityOptIn(t *testing.T) {
+	collector := NewIndexSelectivityCollector(nil, zap.NewNop(), CollectorConfig{})
+	if collector.isMetricExplicitlyEnabled("index_selectivity") {
+		t.Error("Index selectivity sampling should not run unless listed in enabled metrics")
+	}
+
+	collector = NewIndexSelectivityCollector(nil, zap.NewNop(), CollectorConfig{EnabledMetrics: []string{"index_selectivity"}})
+	if !collector.isMetricExplicitlyEnabled("index_selectivity") {
+		t.Error("Index selectivity sampling should run when listed in enabled metrics")
+	}
+}
+
+func TestFacetCount(t *testing.T) {
+	result := bson.M{
+		"sampled": bson.A{bson.M{"n": int32(500)}},
+		"i0":      bson.A{},
+	}
+	if got := facetCount(result, "sampled"); got != 500 {
+		t.Errorf("Expected 500 sampled documents, got %d", got)
+	}
+	if got := facetCount(result, "i0"); got != 0 {
+		t.Errorf("An empty facet should count zero, got %d", got)
+	}
+}
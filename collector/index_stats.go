@@ -1,7 +1,6 @@
 package collector
 
 import (
-	"context"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -12,7 +11,8 @@ import (
 
 type IndexStatsCollector struct {
 	*BaseCollector
-	descriptors map[string]*prometheus.Desc
+	descriptors    map[string]*prometheus.Desc
+	unusedLookback time.Duration
 }
 
 func NewIndexStatsCollector(client *mongo.Client, logger *zap.Logger, config CollectorConfig) *IndexStatsCollector {
@@ -20,58 +20,65 @@ func NewIndexStatsCollector(client *mongo.Client, logger *zap.Logger, config Col
 
 	descriptors := map[string]*prometheus.Desc{
 		"index_size_bytes": prometheus.NewDesc(
-			"mongodb_index_size_bytes",
+			config.metricName("mongodb_index_size_bytes"),
 			"Size of the index in bytes",
 			labels,
 			nil,
 		),
 		"index_accesses_total": prometheus.NewDesc(
-			"mongodb_index_accesses_total",
+			config.metricName("mongodb_index_accesses_total"),
 			"Number of times the index has been accessed",
 			labels,
 			nil,
 		),
 		"index_miss_ratio": prometheus.NewDesc(
-			"mongodb_index_miss_ratio",
+			config.metricName("mongodb_index_miss_ratio"),
 			"Ratio of index misses to total accesses",
 			labels,
 			nil,
 		),
 		"index_ops_total": prometheus.NewDesc(
-			"mongodb_index_ops_total",
+			config.metricName("mongodb_index_ops_total"),
 			"Number of operations on the index",
 			append(labels, "type"),
 			nil,
 		),
 		"index_usage_status": prometheus.NewDesc(
-			"mongodb_index_usage_status",
+			config.metricName("mongodb_index_usage_status"),
 			"Index usage status (1=used, 0=unused)",
 			labels,
 			nil,
 		),
 		"index_last_access_time": prometheus.NewDesc(
-			"mongodb_index_last_access_time",
+			config.metricName("mongodb_index_last_access_time"),
 			"Last time the index was accessed (Unix timestamp)",
 			labels,
 			nil,
 		),
 		"index_access_frequency": prometheus.NewDesc(
-			"mongodb_index_access_frequency",
+			config.metricName("mongodb_index_access_frequency"),
 			"Index access frequency (accesses per hour)",
 			labels,
 			nil,
 		),
 		"index_unused_duration_hours": prometheus.NewDesc(
-			"mongodb_index_unused_duration_hours",
+			config.metricName("mongodb_index_unused_duration_hours"),
 			"Duration since last index access in hours",
 			labels,
 			nil,
 		),
+		"index_unused_size_bytes": prometheus.NewDesc(
+			config.metricName("mongodb_index_unused_size_bytes"),
+			"Size of an index with no accesses within the unused lookback, reclaimable by dropping it",
+			labels,
+			nil,
+		),
 	}
 
 	return &IndexStatsCollector{
-		BaseCollector: NewBaseCollector(client, logger, config),
-		descriptors:   descriptors,
+		BaseCollector:  NewBaseCollector(client, logger, config),
+		descriptors:    descriptors,
+		unusedLookback: getDurationOption(collectorOptions(config, "index_stats"), "unused_lookback", defaultUnusedIndexLookback),
 	}
 }
 
@@ -80,11 +87,11 @@ func (c *IndexStatsCollector) Collect(ch chan<- prometheus.Metric) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+	ctx, done := c.collectContext("index_stats", 10*time.Second)
+	defer done()
 
 	// Get list of databases
-	databases, err := getDatabasesWithTimeout(ctx, c.client, 10*time.Second)
+	databases, err := c.listDatabases(ctx, 10*time.Second)
 	if err != nil {
 		c.logger.Error("Failed to list databases", zap.Error(err))
 		return
@@ -92,32 +99,37 @@ func (c *IndexStatsCollector) Collect(ch chan<- prometheus.Metric) {
 
 	instance := c.getInstanceInfo(bson.M{})
 
+	var namespaces []namespace
 	for _, dbName := range databases {
 		// Skip admin and local databases
 		if shouldSkipDatabase(dbName) {
 			continue
 		}
 
-		db := c.client.Database(dbName)
-		collections, err := getCollectionsWithTimeout(ctx, db, 10*time.Second)
+		collections, err := c.listCollections(ctx, dbName, 10*time.Second)
 		if err != nil {
 			c.logger.Error("Failed to list collections", zap.String("database", dbName), zap.Error(err))
 			continue
 		}
 
 		for _, collName := range collections {
-			var indexStats bson.M
-			if err := runCommandWithTimeout(ctx, db, bson.D{{"collStats", collName}}, 10*time.Second, &indexStats); err != nil {
-				c.logger.Debug("Failed to get collection stats",
-					zap.String("database", dbName),
-					zap.String("collection", collName),
-					zap.Error(err))
-				continue
-			}
-
-			c.collectIndexStats(ch, dbName, collName, indexStats, instance)
+			namespaces = append(namespaces, namespace{Database: dbName, Collection: collName})
 		}
 	}
+
+	forEachNamespace(ctx, namespaces, c.config.Parallelism, func(ns namespace) {
+		var indexStats bson.M
+		if err := c.runGatedCommand(ctx, "index_stats", c.client.Database(ns.Database), bson.D{{"collStats", ns.Collection}}, 10*time.Second, &indexStats); err != nil {
+			c.logger.Debug("Failed to get collection stats",
+				zap.String("database", ns.Database),
+				zap.String("collection", ns.Collection),
+				zap.Error(err))
+			return
+		}
+
+		c.collectIndexStats(ch, ns.Database, ns.Collection, indexStats, instance)
+		c.collectUnusedIndexSizes(ctx, ch, ns, indexStats, instance)
+	})
 }
 
 func (c *IndexStatsCollector) collectIndexStats(ch chan<- prometheus.Metric, dbName, collName string, stats bson.M, instance map[string]string) {
@@ -128,7 +140,7 @@ func (c *IndexStatsCollector) collectIndexStats(ch chan<- prometheus.Metric, dbN
 	if indexSizes, ok := stats["indexSizes"].(bson.M); ok {
 		if desc, ok := c.descriptors["index_size_bytes"]; ok {
 			for indexName, size := range indexSizes {
-				if sizeValue, ok := size.(int64); ok {
+				if sizeValue, ok := toInt64(size); ok {
 					ch <- prometheus.MustNewConstMetric(
 						desc,
 						prometheus.GaugeValue,
@@ -151,7 +163,7 @@ func (c *IndexStatsCollector) collectIndexStats(ch chan<- prometheus.Metric, dbN
 			if accessMap, ok := accesses.(bson.M); ok {
 				// Index access operations
 				if desc, ok := c.descriptors["index_accesses_total"]; ok {
-					if ops, ok := accessMap["ops"].(int64); ok {
+					if ops, ok := toInt64(accessMap["ops"]); ok {
 						ch <- prometheus.MustNewConstMetric(
 							desc,
 							prometheus.CounterValue,
@@ -168,7 +180,7 @@ func (c *IndexStatsCollector) collectIndexStats(ch chan<- prometheus.Metric, dbN
 
 				// Index miss ratio
 				if desc, ok := c.descriptors["index_miss_ratio"]; ok {
-					if missRatio, ok := accessMap["missRatio"].(float64); ok {
+					if missRatio, ok := toFloat64(accessMap["missRatio"]); ok {
 						ch <- prometheus.MustNewConstMetric(
 							desc,
 							prometheus.GaugeValue,
@@ -207,7 +219,7 @@ func (c *IndexStatsCollector) collectIndexStats(ch chan<- prometheus.Metric, dbN
 					}
 
 					for opField, opType := range ops {
-						if value, ok := indexStat[opField].(int64); ok {
+						if value, ok := toInt64(indexStat[opField]); ok {
 							ch <- prometheus.MustNewConstMetric(
 								desc,
 								prometheus.CounterValue,
@@ -241,7 +253,7 @@ func (c *IndexStatsCollector) collectUnusedIndexMetrics(ch chan<- prometheus.Met
 	if indexAccesses, ok := stats["indexAccesses"].(bson.M); ok {
 		for indexName, accesses := range indexAccesses {
 			if accessMap, ok := accesses.(bson.M); ok {
-				if ops, ok := accessMap["ops"].(int64); ok && ops > 0 {
+				if ops, ok := toInt64(accessMap["ops"]); ok && ops > 0 {
 					indexes[indexName] = true // Mark as used
 
 					// Index usage status (1=used, 0=unused)
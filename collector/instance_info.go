@@ -0,0 +1,98 @@
+package collector
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Server editions reported by mongodb_instance_info
+const (
+	editionCommunity  = "community"
+	editionEnterprise = "enterprise"
+	editionPercona    = "percona"
+)
+
+// instanceInfoDescriptors describes the target info metric. With the
+// cluster_role const label on, the registerer supplies that label instead
+func instanceInfoDescriptors(config CollectorConfig) map[string]*prometheus.Desc {
+	labels := []string{"instance", "replica_set", "shard", "version", "edition", "storage_engine"}
+	if !config.ClusterRoleLabel {
+		labels = append(labels, "cluster_role")
+	}
+
+	return map[string]*prometheus.Desc{
+		"instance_info": prometheus.NewDesc(
+			config.metricName("mongodb_instance_info"),
+			"What is being monitored, always 1: server version, edition (community, enterprise, percona, documentdb or ferretdb), storage engine and cluster role",
+			labels,
+			nil,
+		),
+	}
+}
+
+// edition returns the server edition found by detection, or community until detection succeeds
+func (s *serverCapabilities) edition() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case s.flavor == flavorDocumentDB:
+		return flavorDocumentDB
+	case s.ferretDB != "":
+		return flavorFerretDB
+	case s.psmdb != "":
+		return editionPercona
+	case s.enterprise:
+		return editionEnterprise
+	default:
+		return editionCommunity
+	}
+}
+
+// collectInstanceInfo exports mongodb_instance_info from the version and
+// storage engine in serverStatus, the edition found in buildInfo and the
+// cluster role from hello
+func (c *ServerStatusCollector) collectInstanceInfo(ctx context.Context, ch chan<- prometheus.Metric, result bson.M, instance map[string]string) {
+	role := ClusterRoleMongos
+	if c.config.ClusterRoleLabel {
+		role = ""
+	} else if process, _ := result["process"].(string); process != "mongos" {
+		role = c.clusterRole(ctx)
+	}
+	c.emitInstanceInfo(ch, result, role, instance)
+}
+
+func (c *ServerStatusCollector) emitInstanceInfo(ch chan<- prometheus.Metric, result bson.M, role string, instance map[string]string) {
+	version, _ := result["version"].(string)
+	if version == "" {
+		version = "unknown"
+	}
+	engine := "unknown"
+	if storageEngine, ok := result["storageEngine"].(bson.M); ok {
+		if name, ok := storageEngine["name"].(string); ok && name != "" {
+			engine = name
+		}
+	}
+	edition := editionCommunity
+	if c.config.capabilities != nil {
+		edition = c.config.capabilities.edition()
+	}
+
+	values := []string{instance["instance"], instance["replica_set"], instance["shard"], version, edition, engine}
+	if !c.config.ClusterRoleLabel {
+		values = append(values, role)
+	}
+	ch <- prometheus.MustNewConstMetric(c.descriptors["instance_info"], prometheus.GaugeValue, 1, values...)
+}
+
+// clusterRole classifies the target like DetectClusterRole, reusing the
+// cached startup options to tell shard members from plain replica sets
+func (c *ServerStatusCollector) clusterRole(ctx context.Context) string {
+	role, err := detectClusterRole(ctx, c.adminCommand("server_status"), c.cmdLineOptions)
+	if err != nil {
+		c.logCommandError("Failed to read the cluster role", err)
+	}
+	return role
+}
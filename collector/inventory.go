@@ -0,0 +1,139 @@
+package collector
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// namespaceInventory caches database and collection names for a TTL so the
+// per-namespace collectors share one listing instead of each issuing their own
+type namespaceInventory struct {
+	client *mongo.Client
+	logger *zap.Logger
+	ttl    time.Duration
+
+	mu          sync.Mutex
+	databases   []string
+	dbFetched   time.Time
+	collections map[string][]string
+	collFetched map[string]time.Time
+}
+
+func newNamespaceInventory(client *mongo.Client, logger *zap.Logger, ttl time.Duration) *namespaceInventory {
+	return &namespaceInventory{
+		client:      client,
+		logger:      logger,
+		ttl:         ttl,
+		collections: make(map[string][]string),
+		collFetched: make(map[string]time.Time),
+	}
+}
+
+// Databases returns the cached database names, refreshing them once the TTL expires
+func (inv *namespaceInventory) Databases(ctx context.Context, timeout time.Duration) ([]string, error) {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+
+	if !inv.dbFetched.IsZero() && time.Since(inv.dbFetched) < inv.ttl {
+		return inv.databases, nil
+	}
+
+	databases, err := getDatabasesWithTimeout(ctx, inv.client, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if !inv.dbFetched.IsZero() {
+		if added, removed := diffNames(inv.databases, databases); len(added) > 0 || len(removed) > 0 {
+			inv.logger.Info("Database inventory changed",
+				zap.Strings("added", added),
+				zap.Strings("removed", removed))
+
+			// Dropped databases take their cached collection lists with them
+			for _, dbName := range removed {
+				delete(inv.collections, dbName)
+				delete(inv.collFetched, dbName)
+			}
+		}
+	}
+
+	inv.databases = databases
+	inv.dbFetched = time.Now()
+	return databases, nil
+}
+
+// Collections returns the cached collection names of dbName, refreshing them once the TTL expires
+func (inv *namespaceInventory) Collections(ctx context.Context, dbName string, timeout time.Duration) ([]string, error) {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+
+	fetched, ok := inv.collFetched[dbName]
+	if ok && time.Since(fetched) < inv.ttl {
+		return inv.collections[dbName], nil
+	}
+
+	collections, err := getCollectionsWithTimeout(ctx, inv.client.Database(dbName), timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if ok {
+		if added, removed := diffNames(inv.collections[dbName], collections); len(added) > 0 || len(removed) > 0 {
+			inv.logger.Info("Collection inventory changed",
+				zap.String("database", dbName),
+				zap.Strings("added", added),
+				zap.Strings("removed", removed))
+		}
+	}
+
+	inv.collections[dbName] = collections
+	inv.collFetched[dbName] = time.Now()
+	return collections, nil
+}
+
+// diffNames returns the names present only in current (added) and only in previous (removed)
+func diffNames(previous, current []string) (added, removed []string) {
+	seen := make(map[string]bool, len(previous))
+	for _, name := range previous {
+		seen[name] = true
+	}
+
+	for _, name := range current {
+		if seen[name] {
+			delete(seen, name)
+		} else {
+			added = append(added, name)
+		}
+	}
+
+	for _, name := range previous {
+		if seen[name] {
+			removed = append(removed, name)
+		}
+	}
+
+	return added, removed
+}
+
+// listDatabases lists database names through the shared inventory when one is configured
+func (bc *BaseCollector) listDatabases(ctx context.Context, timeout time.Duration) ([]string, error) {
+	if bc.config.database != "" {
+		return []string{bc.config.database}, nil
+	}
+	if bc.config.inventory != nil {
+		return bc.config.inventory.Databases(ctx, timeout)
+	}
+	return getDatabasesWithTimeout(ctx, bc.client, timeout)
+}
+
+// listCollections lists collection names through the shared inventory when one is configured
+func (bc *BaseCollector) listCollections(ctx context.Context, dbName string, timeout time.Duration) ([]string, error) {
+	if bc.config.inventory != nil {
+		return bc.config.inventory.Collections(ctx, dbName, timeout)
+	}
+	return getCollectionsWithTimeout(ctx, bc.client.Database(dbName), timeout)
+}
@@ -0,0 +1,97 @@
+package collector
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// durFields maps the scalar fields of the serverStatus dur section to descriptor keys
+var durFields = map[string]string{
+	"commits":            "dur_commits",
+	"journaledMB":        "dur_journaled_megabytes",
+	"writeToDataFilesMB": "dur_write_to_data_files_megabytes",
+	"commitsInWriteLock": "dur_commits_in_write_lock",
+	"earlyCommits":       "dur_early_commits",
+}
+
+// durTimeStages maps the dur.timeMs fields to stage labels
+var durTimeStages = map[string]string{
+	"dt":                 "interval",
+	"prepLogBuffer":      "prep_log_buffer",
+	"writeToJournal":     "write_to_journal",
+	"writeToDataFiles":   "write_to_data_files",
+	"remapPrivateView":   "remap_private_view",
+	"commits":            "commits",
+	"commitsInWriteLock": "commits_in_write_lock",
+}
+
+// journalDescriptors describes the MMAPv1 journaling metrics. The dur section
+// reports the last journal interval, not running totals, so all are gauges
+func journalDescriptors(config CollectorConfig) map[string]*prometheus.Desc {
+	labels := []string{"instance", "replica_set", "shard"}
+
+	return map[string]*prometheus.Desc{
+		"dur_commits": prometheus.NewDesc(
+			config.metricName("mongodb_dur_commits"),
+			"Journal commits during the last journal interval",
+			labels,
+			nil,
+		),
+		"dur_journaled_megabytes": prometheus.NewDesc(
+			config.metricName("mongodb_dur_journaled_megabytes"),
+			"Megabytes written to the journal during the last journal interval",
+			labels,
+			nil,
+		),
+		"dur_write_to_data_files_megabytes": prometheus.NewDesc(
+			config.metricName("mongodb_dur_write_to_data_files_megabytes"),
+			"Megabytes written from the journal to the data files during the last journal interval",
+			labels,
+			nil,
+		),
+		"dur_commits_in_write_lock": prometheus.NewDesc(
+			config.metricName("mongodb_dur_commits_in_write_lock"),
+			"Journal commits that happened while a write lock was held during the last journal interval",
+			labels,
+			nil,
+		),
+		"dur_early_commits": prometheus.NewDesc(
+			config.metricName("mongodb_dur_early_commits"),
+			"Journal commits requested before the scheduled group commit interval during the last journal interval",
+			labels,
+			nil,
+		),
+		"dur_time_milliseconds": prometheus.NewDesc(
+			config.metricName("mongodb_dur_time_milliseconds"),
+			"Time spent in each journaling stage during the last journal interval; stage interval is the length of the interval itself",
+			append(labels, "stage"),
+			nil,
+		),
+	}
+}
+
+// collectJournal exports the dur section that MMAPv1 servers (MongoDB 4.0 and
+// older) report. WiredTiger servers have no such section and export nothing
+func (c *ServerStatusCollector) collectJournal(ch chan<- prometheus.Metric, result bson.M, instance map[string]string) {
+	dur, ok := result["dur"].(bson.M)
+	if !ok {
+		return
+	}
+	labels := []string{instance["instance"], instance["replica_set"], instance["shard"]}
+
+	for field, key := range durFields {
+		if value := c.getNumericValue(dur[field]); value != nil {
+			ch <- prometheus.MustNewConstMetric(c.descriptors[key], prometheus.GaugeValue, *value, labels...)
+		}
+	}
+
+	timeMs, ok := dur["timeMs"].(bson.M)
+	if !ok {
+		return
+	}
+	for field, stage := range durTimeStages {
+		if value := c.getNumericValue(timeMs[field]); value != nil {
+			ch <- prometheus.MustNewConstMetric(c.descriptors["dur_time_milliseconds"], prometheus.GaugeValue, *value, append(labels, stage)...)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package collector
+
+import (
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// ldapStatSections are the serverStatus paths holding LDAP pool and operation
+// statistics on Enterprise builds configured for LDAP
+var ldapStatSections = []string{"ldapOperations", "security.ldap"}
+
+// ldapDescriptors describes the LDAP and authentication metrics
+func ldapDescriptors(config CollectorConfig) map[string]*prometheus.Desc {
+	labels := []string{"instance", "replica_set", "shard"}
+
+	return map[string]*prometheus.Desc{
+		"ldap_stat": prometheus.NewDesc(
+			config.metricName("mongodb_ldap_stat"),
+			"LDAP connection pool and operation statistic from serverStatus, such as pooled connections or bind time; field is the dotted path",
+			append(labels, "field"),
+			nil,
+		),
+		"authentication_attempts_total": prometheus.NewDesc(
+			config.metricName("mongodb_authentication_attempts_total"),
+			"Authentication attempts by mechanism; LDAP authentication uses PLAIN",
+			append(labels, "mechanism"),
+			nil,
+		),
+		"authentication_successes_total": prometheus.NewDesc(
+			config.metricName("mongodb_authentication_successes_total"),
+			"Successful authentications by mechanism",
+			append(labels, "mechanism"),
+			nil,
+		),
+	}
+}
+
+// lookupSection returns the sub-document of result at a dotted path
+func lookupSection(result bson.M, path string) (bson.M, bool) {
+	current := result
+	for _, key := range strings.Split(path, ".") {
+		next, ok := current[key].(bson.M)
+		if !ok {
+			return nil, false
+		}
+		current = next
+	}
+	return current, true
+}
+
+// collectLDAP exports LDAP pool and operation statistics and authentication
+// attempts per mechanism. Slow or failing LDAP binds stall logins without an
+// error on the client side, so a falling success ratio for PLAIN or a growing
+// bind time are often the only signal
+func (c *ServerStatusCollector) collectLDAP(ch chan<- prometheus.Metric, result bson.M, instance map[string]string) {
+	labels := []string{instance["instance"], instance["replica_set"], instance["shard"]}
+
+	for _, path := range ldapStatSections {
+		section, ok := lookupSection(result, path)
+		if !ok {
+			continue
+		}
+		values := make(map[string]float64)
+		flattenNumeric(path, section, values)
+		for field, value := range values {
+			ch <- prometheus.MustNewConstMetric(c.descriptors["ldap_stat"], prometheus.GaugeValue, value, append(labels, field)...)
+		}
+	}
+
+	mechanisms, ok := lookupSection(result, "security.authentication.mechanisms")
+	if !ok {
+		return
+	}
+	for mechanism, value := range mechanisms {
+		stats, ok := value.(bson.M)
+		if !ok {
+			continue
+		}
+		authenticate, ok := stats["authenticate"].(bson.M)
+		if !ok {
+			continue
+		}
+		if received := c.getNumericValue(authenticate["received"]); received != nil {
+			ch <- prometheus.MustNewConstMetric(c.descriptors["authentication_attempts_total"], prometheus.CounterValue, *received, append(labels, mechanism)...)
+		}
+		if successful := c.getNumericValue(authenticate["successful"]); successful != nil {
+			ch <- prometheus.MustNewConstMetric(c.descriptors["authentication_successes_total"], prometheus.CounterValue, *successful, append(labels, mechanism)...)
+		}
+	}
+}
@@ -1,7 +1,7 @@
 package collector
 
 import (
-	"context"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"go.mongodb.org/mongo-driver/bson"
@@ -18,12 +18,12 @@ func NewLockMetricsCollector(client *mongo.Client, logger *zap.Logger, config Co
 	labels := []string{"instance", "replica_set", "shard"}
 
 	descriptors := map[string]*prometheus.Desc{
-		"locks_time_acquiring_global_microseconds_total":     prometheus.NewDesc("mongodb_locks_time_acquiring_global_microseconds_total", "Total time spent acquiring global locks in microseconds", labels, nil),
-		"locks_time_acquiring_database_microseconds_total":   prometheus.NewDesc("mongodb_locks_time_acquiring_database_microseconds_total", "Total time spent acquiring database locks in microseconds", labels, nil),
-		"locks_time_acquiring_collection_microseconds_total": prometheus.NewDesc("mongodb_locks_time_acquiring_collection_microseconds_total", "Total time spent acquiring collection locks in microseconds", labels, nil),
-		"locks_deadlock_count_total":                         prometheus.NewDesc("mongodb_locks_deadlock_count_total", "Total number of deadlocks", labels, nil),
-		"locks_acquire_count_total":                          prometheus.NewDesc("mongodb_locks_acquire_count_total", "Total number of lock acquisitions", labels, nil),
-		"locks_acquire_wait_count_total":                     prometheus.NewDesc("mongodb_locks_acquire_wait_count_total", "Total number of lock acquisitions that had to wait", labels, nil),
+		"locks_time_acquiring_global_microseconds_total":     prometheus.NewDesc(config.metricName("mongodb_locks_time_acquiring_global_microseconds_total"), "Total time spent acquiring global locks in microseconds", labels, nil),
+		"locks_time_acquiring_database_microseconds_total":   prometheus.NewDesc(config.metricName("mongodb_locks_time_acquiring_database_microseconds_total"), "Total time spent acquiring database locks in microseconds", labels, nil),
+		"locks_time_acquiring_collection_microseconds_total": prometheus.NewDesc(config.metricName("mongodb_locks_time_acquiring_collection_microseconds_total"), "Total time spent acquiring collection locks in microseconds", labels, nil),
+		"locks_deadlock_count_total":                         prometheus.NewDesc(config.metricName("mongodb_locks_deadlock_count_total"), "Total number of deadlocks", labels, nil),
+		"locks_acquire_count_total":                          prometheus.NewDesc(config.metricName("mongodb_locks_acquire_count_total"), "Total number of lock acquisitions", labels, nil),
+		"locks_acquire_wait_count_total":                     prometheus.NewDesc(config.metricName("mongodb_locks_acquire_wait_count_total"), "Total number of lock acquisitions that had to wait", labels, nil),
 	}
 
 	return &LockMetricsCollector{
@@ -37,9 +37,11 @@ func (c *LockMetricsCollector) Collect(ch chan<- prometheus.Metric) {
 		return
 	}
 
-	ctx := context.Background()
+	ctx, done := c.collectContext("lock_metrics", 10*time.Second)
+	defer done()
+
 	var result bson.M
-	err := c.client.Database("admin").RunCommand(ctx, bson.D{{"serverStatus", 1}}).Decode(&result)
+	err := c.client.Database("admin").RunCommand(ctx, withMaxTime(ctx, serverStatusCommand("locks"))).Decode(&result)
 	if err != nil {
 		c.logger.Error("Failed to get server status for lock metrics", zap.Error(err))
 		return
@@ -95,25 +97,25 @@ func (c *LockMetricsCollector) collectLockMetrics(ch chan<- prometheus.Metric, r
 func (c *LockMetricsCollector) collectGlobalLockMetrics(ch chan<- prometheus.Metric, locks bson.M, labels prometheus.Labels) {
 	if global, ok := locks["Global"].(bson.M); ok {
 		if acquireCount, ok := global["acquireCount"].(bson.M); ok {
-			if r, ok := acquireCount["r"].(int64); ok {
+			if r, ok := toInt64(acquireCount["r"]); ok {
 				ch <- prometheus.MustNewConstMetric(c.descriptors["locks_acquire_count_total"], prometheus.CounterValue, float64(r), labels["instance"], labels["replica_set"], labels["shard"])
 			}
 		}
 
 		if acquireWaitCount, ok := global["acquireWaitCount"].(bson.M); ok {
-			if r, ok := acquireWaitCount["r"].(int64); ok {
+			if r, ok := toInt64(acquireWaitCount["r"]); ok {
 				ch <- prometheus.MustNewConstMetric(c.descriptors["locks_acquire_wait_count_total"], prometheus.CounterValue, float64(r), labels["instance"], labels["replica_set"], labels["shard"])
 			}
 		}
 
 		if timeAcquiringMicros, ok := global["timeAcquiringMicros"].(bson.M); ok {
-			if r, ok := timeAcquiringMicros["r"].(int64); ok {
+			if r, ok := toInt64(timeAcquiringMicros["r"]); ok {
 				ch <- prometheus.MustNewConstMetric(c.descriptors["locks_time_acquiring_global_microseconds_total"], prometheus.CounterValue, float64(r), labels["instance"], labels["replica_set"], labels["shard"])
 			}
 		}
 
 		if deadlockCount, ok := global["deadlockCount"].(bson.M); ok {
-			if r, ok := deadlockCount["r"].(int64); ok {
+			if r, ok := toInt64(deadlockCount["r"]); ok {
 				ch <- prometheus.MustNewConstMetric(c.descriptors["locks_deadlock_count_total"], prometheus.CounterValue, float64(r), labels["instance"], labels["replica_set"], labels["shard"])
 			}
 		}
@@ -123,10 +125,10 @@ func (c *LockMetricsCollector) collectGlobalLockMetrics(ch chan<- prometheus.Met
 func (c *LockMetricsCollector) collectDatabaseLockMetrics(ch chan<- prometheus.Metric, locks bson.M, labels prometheus.Labels) {
 	if database, ok := locks["Database"].(bson.M); ok {
 		if timeAcquiringMicros, ok := database["timeAcquiringMicros"].(bson.M); ok {
-			if r, ok := timeAcquiringMicros["r"].(int64); ok {
+			if r, ok := toInt64(timeAcquiringMicros["r"]); ok {
 				ch <- prometheus.MustNewConstMetric(c.descriptors["locks_time_acquiring_database_microseconds_total"], prometheus.CounterValue, float64(r), labels["instance"], labels["replica_set"], labels["shard"])
 			}
-			if w, ok := timeAcquiringMicros["w"].(int64); ok {
+			if w, ok := toInt64(timeAcquiringMicros["w"]); ok {
 				ch <- prometheus.MustNewConstMetric(c.descriptors["locks_time_acquiring_database_microseconds_total"], prometheus.CounterValue, float64(w), labels["instance"], labels["replica_set"], labels["shard"])
 			}
 		}
@@ -136,10 +138,10 @@ func (c *LockMetricsCollector) collectDatabaseLockMetrics(ch chan<- prometheus.M
 func (c *LockMetricsCollector) collectCollectionLockMetrics(ch chan<- prometheus.Metric, locks bson.M, labels prometheus.Labels) {
 	if collection, ok := locks["Collection"].(bson.M); ok {
 		if timeAcquiringMicros, ok := collection["timeAcquiringMicros"].(bson.M); ok {
-			if r, ok := timeAcquiringMicros["r"].(int64); ok {
+			if r, ok := toInt64(timeAcquiringMicros["r"]); ok {
 				ch <- prometheus.MustNewConstMetric(c.descriptors["locks_time_acquiring_collection_microseconds_total"], prometheus.CounterValue, float64(r), labels["instance"], labels["replica_set"], labels["shard"])
 			}
-			if w, ok := timeAcquiringMicros["w"].(int64); ok {
+			if w, ok := toInt64(timeAcquiringMicros["w"]); ok {
 				ch <- prometheus.MustNewConstMetric(c.descriptors["locks_time_acquiring_collection_microseconds_total"], prometheus.CounterValue, float64(w), labels["instance"], labels["replica_set"], labels["shard"])
 			}
 		}
@@ -1,7 +1,6 @@
 package collector
 
 import (
-	"context"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -20,25 +19,25 @@ func NewLockCollector(client *mongo.Client, logger *zap.Logger, config Collector
 
 	descriptors := map[string]*prometheus.Desc{
 		"locks_time_acquiring_microseconds_total": prometheus.NewDesc(
-			"mongodb_locks_time_acquiring_microseconds_total",
+			config.metricName("mongodb_locks_time_acquiring_microseconds_total"),
 			"Time spent acquiring locks in microseconds",
 			labels,
 			nil,
 		),
 		"locks_held_total": prometheus.NewDesc(
-			"mongodb_locks_held_total",
+			config.metricName("mongodb_locks_held_total"),
 			"Number of locks held",
 			labels,
 			nil,
 		),
 		"locks_waiting_total": prometheus.NewDesc(
-			"mongodb_locks_waiting_total",
+			config.metricName("mongodb_locks_waiting_total"),
 			"Number of locks waiting to be acquired",
 			labels,
 			nil,
 		),
 		"locks_deadlock_total": prometheus.NewDesc(
-			"mongodb_locks_deadlock_total",
+			config.metricName("mongodb_locks_deadlock_total"),
 			"Number of deadlocks",
 			labels,
 			nil,
@@ -56,12 +55,12 @@ func (c *LockCollector) Collect(ch chan<- prometheus.Metric) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+	ctx, done := c.collectContext("locks", 10*time.Second)
+	defer done()
 
 	var result bson.M
-	if err := c.client.Database("admin").RunCommand(ctx, bson.D{{"serverStatus", 1}}).Decode(&result); err != nil {
-		c.logger.Error("Failed to collect lock metrics", zap.Error(err))
+	if err := c.runCommand(ctx, "locks", c.client.Database("admin"), serverStatusCommand("locks"), &result); err != nil {
+		c.logCommandError("Failed to collect lock metrics", err)
 		return
 	}
 
@@ -85,7 +84,7 @@ func (c *LockCollector) collectDatabaseLockMetrics(ch chan<- prometheus.Metric,
 			if acquireTime, ok := lockMetrics["acquireCount"].(bson.M); ok {
 				modes := map[string]string{"R": "read", "W": "write", "r": "intent_read", "w": "intent_write"}
 				for mode, modeLabel := range modes {
-					if count, ok := acquireTime[mode].(int64); ok {
+					if count, ok := toInt64(acquireTime[mode]); ok {
 						ch <- prometheus.MustNewConstMetric(
 							c.descriptors["locks_time_acquiring_microseconds_total"],
 							prometheus.CounterValue,
@@ -104,7 +103,7 @@ func (c *LockCollector) collectDatabaseLockMetrics(ch chan<- prometheus.Metric,
 			if deadlocks, ok := lockMetrics["deadlockCount"].(bson.M); ok {
 				modes := map[string]string{"R": "read", "W": "write", "r": "intent_read", "w": "intent_write"}
 				for mode, modeLabel := range modes {
-					if count, ok := deadlocks[mode].(int64); ok {
+					if count, ok := toInt64(deadlocks[mode]); ok {
 						ch <- prometheus.MustNewConstMetric(
 							c.descriptors["locks_deadlock_total"],
 							prometheus.CounterValue,
@@ -123,7 +122,7 @@ func (c *LockCollector) collectDatabaseLockMetrics(ch chan<- prometheus.Metric,
 			if queueLength, ok := lockMetrics["acquireWaitCount"].(bson.M); ok {
 				modes := map[string]string{"R": "read", "W": "write", "r": "intent_read", "w": "intent_write"}
 				for mode, modeLabel := range modes {
-					if count, ok := queueLength[mode].(int64); ok {
+					if count, ok := toInt64(queueLength[mode]); ok {
 						ch <- prometheus.MustNewConstMetric(
 							c.descriptors["locks_waiting_total"],
 							prometheus.GaugeValue,
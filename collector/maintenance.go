@@ -0,0 +1,169 @@
+package collector
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// maintenanceCommands are the command names reported as maintenance operations
+var maintenanceCommands = []string{"compact", "validate", "repairDatabase"}
+
+// maintenancePipeline lists only running maintenance commands, so the server
+// filters the operation list instead of returning every active operation
+func maintenancePipeline() []bson.D {
+	match := bson.A{}
+	for _, command := range maintenanceCommands {
+		match = append(match, bson.D{{"command." + command, bson.D{{"$exists", true}}}})
+	}
+	return []bson.D{
+		{{"$currentOp", bson.D{{"allUsers", true}}}},
+		{{"$match", bson.D{{"$or", match}}}},
+	}
+}
+
+// maintenanceOp is one $currentOp entry of a maintenance command
+type maintenanceOp struct {
+	NS               string `bson:"ns"`
+	Command          bson.M `bson:"command"`
+	MicrosecsRunning int64  `bson:"microsecs_running"`
+	Progress         struct {
+		Done  float64 `bson:"done"`
+		Total float64 `bson:"total"`
+	} `bson:"progress"`
+}
+
+// maintenanceKey identifies a maintenance operation series
+type maintenanceKey struct {
+	operation  string
+	database   string
+	collection string
+}
+
+// key returns the operation and namespace of op. The target collection is the
+// command's value; repairDatabase works on the whole database
+func (op maintenanceOp) key() (maintenanceKey, bool) {
+	for _, command := range maintenanceCommands {
+		value, ok := op.Command[command]
+		if !ok {
+			continue
+		}
+		dbName, _, _ := strings.Cut(op.NS, ".")
+		key := maintenanceKey{operation: command, database: dbName}
+		if collName, ok := value.(string); ok {
+			key.collection = collName
+		}
+		return key, true
+	}
+	return maintenanceKey{}, false
+}
+
+// MaintenanceCollector reports running compact, validate and repair operations
+// with their elapsed time and progress, so maintenance windows can be followed
+type MaintenanceCollector struct {
+	*BaseCollector
+	descriptors map[string]*prometheus.Desc
+}
+
+func NewMaintenanceCollector(client *mongo.Client, logger *zap.Logger, config CollectorConfig) *MaintenanceCollector {
+	labels := []string{"instance", "replica_set", "shard", "operation", "database", "collection"}
+
+	descriptors := map[string]*prometheus.Desc{
+		"maintenance_operation_elapsed_seconds": prometheus.NewDesc(
+			config.metricName("mongodb_maintenance_operation_elapsed_seconds"),
+			"Time the longest running maintenance operation on the namespace has been running",
+			labels,
+			nil,
+		),
+		"maintenance_operation_progress_ratio": prometheus.NewDesc(
+			config.metricName("mongodb_maintenance_operation_progress_ratio"),
+			"Completed fraction of the maintenance operation, when the server reports progress",
+			labels,
+			nil,
+		),
+	}
+
+	return &MaintenanceCollector{
+		BaseCollector: NewBaseCollector(client, logger, config),
+		descriptors:   descriptors,
+	}
+}
+
+func (c *MaintenanceCollector) Collect(ch chan<- prometheus.Metric) {
+	if !c.isMetricEnabled("maintenance") {
+		return
+	}
+
+	ctx, done := c.collectContext("maintenance", 10*time.Second)
+	defer done()
+
+	ops, err := c.listMaintenanceOps(ctx)
+	if err != nil {
+		c.logCommandError("Failed to run $currentOp for maintenance operations", err)
+		return
+	}
+
+	instance := c.getInstanceInfo(bson.M{})
+	for key, op := range longestMaintenanceOps(ops) {
+		labels := []string{instance["instance"], instance["replica_set"], instance["shard"], key.operation, key.database, key.collection}
+		ch <- prometheus.MustNewConstMetric(
+			c.descriptors["maintenance_operation_elapsed_seconds"],
+			prometheus.GaugeValue,
+			float64(op.MicrosecsRunning)/1e6,
+			labels...,
+		)
+		if op.Progress.Total > 0 {
+			ch <- prometheus.MustNewConstMetric(
+				c.descriptors["maintenance_operation_progress_ratio"],
+				prometheus.GaugeValue,
+				op.Progress.Done/op.Progress.Total,
+				labels...,
+			)
+		}
+	}
+}
+
+func (c *MaintenanceCollector) listMaintenanceOps(ctx context.Context) ([]maintenanceOp, error) {
+	cursor, err := c.aggregate(ctx, "maintenance", c.client.Database("admin"), maintenancePipeline(), &options.AggregateOptions{MaxTime: maxTime(ctx)})
+	if err != nil {
+		return nil, err
+	}
+
+	var ops []maintenanceOp
+	if err := cursor.All(ctx, &ops); err != nil {
+		return nil, err
+	}
+	return ops, nil
+}
+
+// longestMaintenanceOps keeps the longest running operation per operation and
+// namespace, so concurrent runs against one collection yield a single series
+func longestMaintenanceOps(ops []maintenanceOp) map[maintenanceKey]maintenanceOp {
+	longest := make(map[maintenanceKey]maintenanceOp, len(ops))
+	for _, op := range ops {
+		key, ok := op.key()
+		if !ok {
+			continue
+		}
+		if current, seen := longest[key]; !seen || op.MicrosecsRunning > current.MicrosecsRunning {
+			longest[key] = op
+		}
+	}
+	return longest
+}
+
+func (c *MaintenanceCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range c.descriptors {
+		ch <- desc
+	}
+}
+
+func (c *MaintenanceCollector) Name() string {
+	return "maintenance"
+}
@@ -0,0 +1,42 @@
+package collector
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestMaintenanceOpKey(t *testing.T) {
+	op := maintenanceOp{NS: "app.orders", Command: bson.M{"compact": "orders", "force": true}}
+	key, ok := op.key()
+	if !ok || key != (maintenanceKey{operation: "compact", database: "app", collection: "orders"}) {
+		t.Errorf("Compact should be keyed by its target collection, got %+v", key)
+	}
+
+	op = maintenanceOp{NS: "app.$cmd", Command: bson.M{"repairDatabase": int32(1)}}
+	key, ok = op.key()
+	if !ok || key != (maintenanceKey{operation: "repairDatabase", database: "app"}) {
+		t.Errorf("repairDatabase should have no collection, got %+v", key)
+	}
+
+	op = maintenanceOp{NS: "app.orders", Command: bson.M{"find": "orders"}}
+	if _, ok := op.key(); ok {
+		t.Error("Non-maintenance commands should not be keyed")
+	}
+}
+
+func TestLongestMaintenanceOps(t *testing.T) {
+	ops := []maintenanceOp{
+		{NS: "app.orders", Command: bson.M{"validate": "orders"}, MicrosecsRunning: 1000},
+		{NS: "app.orders", Command: bson.M{"validate": "orders"}, MicrosecsRunning: 5000},
+		{NS: "app.users", Command: bson.M{"validate": "users"}, MicrosecsRunning: 2000},
+	}
+
+	longest := longestMaintenanceOps(ops)
+	if len(longest) != 2 {
+		t.Fatalf("Concurrent runs on one namespace should collapse, got %d series", len(longest))
+	}
+	if op := longest[maintenanceKey{operation: "validate", database: "app", collection: "orders"}]; op.MicrosecsRunning != 5000 {
+		t.Errorf("Longest running operation should be kept, got %d", op.MicrosecsRunning)
+	}
+}
@@ -0,0 +1,43 @@
+package collector
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// maxTime returns the time left before ctx's deadline, or nil without a deadline.
+// Context deadlines only stop the exporter waiting; passed to MongoDB as
+// maxTimeMS they also stop the server working on a command nobody awaits.
+func maxTime(ctx context.Context) *time.Duration {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return nil
+	}
+
+	remaining := time.Until(deadline)
+	// maxTimeMS has millisecond granularity and 0 would mean no limit
+	if remaining < time.Millisecond {
+		remaining = time.Millisecond
+	}
+	return &remaining
+}
+
+// withMaxTime returns a copy of command with maxTimeMS set from ctx's deadline.
+// Commands without a deadline or with an explicit maxTimeMS are returned as is.
+func withMaxTime(ctx context.Context, command bson.D) bson.D {
+	limit := maxTime(ctx)
+	if limit == nil {
+		return command
+	}
+	for _, elem := range command {
+		if elem.Key == "maxTimeMS" {
+			return command
+		}
+	}
+
+	limited := make(bson.D, len(command), len(command)+1)
+	copy(limited, command)
+	return append(limited, bson.E{Key: "maxTimeMS", Value: limit.Milliseconds()})
+}
@@ -0,0 +1,46 @@
+package collector
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestWithMaxTime(t *testing.T) {
+	command := bson.D{{Key: "collStats", Value: "orders"}}
+
+	if limited := withMaxTime(context.Background(), command); len(limited) != 1 {
+		t.Errorf("Command without a deadline should be unchanged, got %v", limited)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	limited := withMaxTime(ctx, command)
+	if len(limited) != 2 || limited[1].Key != "maxTimeMS" {
+		t.Fatalf("Expected maxTimeMS to be appended, got %v", limited)
+	}
+	if ms, ok := limited[1].Value.(int64); !ok || ms <= 0 || ms > 10000 {
+		t.Errorf("maxTimeMS should be the time left before the deadline, got %v", limited[1].Value)
+	}
+	if len(command) != 1 {
+		t.Error("withMaxTime should not modify the caller's command")
+	}
+
+	explicit := bson.D{{Key: "collStats", Value: "orders"}, {Key: "maxTimeMS", Value: 500}}
+	if limited := withMaxTime(ctx, explicit); len(limited) != 2 || limited[1].Value != 500 {
+		t.Errorf("An explicit maxTimeMS should be kept, got %v", limited)
+	}
+}
+
+func TestMaxTimeExpiredDeadline(t *testing.T) {
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	// maxTimeMS 0 means no limit, so a passed deadline must still send a positive value
+	if limit := maxTime(ctx); limit == nil || *limit <= 0 {
+		t.Errorf("Expired deadline should give the minimum limit, got %v", limit)
+	}
+}
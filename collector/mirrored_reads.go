@@ -0,0 +1,61 @@
+package collector
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// mirroredReadsFields maps the serverStatus mirroredReads fields to descriptors.
+// seen and sent are reported from 4.4; the others by later versions
+var mirroredReadsFields = []struct {
+	key       string
+	descKey   string
+	valueType prometheus.ValueType
+}{
+	{"seen", "mirrored_reads_seen_total", prometheus.CounterValue},
+	{"sent", "mirrored_reads_sent_total", prometheus.CounterValue},
+	{"processedAsSecondary", "mirrored_reads_processed_as_secondary_total", prometheus.CounterValue},
+	{"resolved", "mirrored_reads_resolved_total", prometheus.CounterValue},
+	{"succeeded", "mirrored_reads_succeeded_total", prometheus.CounterValue},
+	{"pending", "mirrored_reads_pending", prometheus.GaugeValue},
+}
+
+// mirroredReadsDescriptors describes the mirrored reads metrics
+func mirroredReadsDescriptors(config CollectorConfig) map[string]*prometheus.Desc {
+	labels := []string{"instance", "replica_set", "shard"}
+	help := map[string]string{
+		"mirrored_reads_seen_total":                   "Operations the primary received that support mirroring",
+		"mirrored_reads_sent_total":                   "Mirrored reads the primary sent to secondaries",
+		"mirrored_reads_processed_as_secondary_total": "Mirrored reads this member processed while a secondary",
+		"mirrored_reads_resolved_total":               "Mirrored reads the primary received a response for",
+		"mirrored_reads_succeeded_total":              "Mirrored reads that succeeded on the secondary",
+		"mirrored_reads_pending":                      "Mirrored reads sent and not yet resolved",
+	}
+
+	descriptors := make(map[string]*prometheus.Desc, len(mirroredReadsFields))
+	for _, field := range mirroredReadsFields {
+		descriptors[field.descKey] = prometheus.NewDesc(
+			config.metricName("mongodb_"+field.descKey),
+			help[field.descKey],
+			labels,
+			nil,
+		)
+	}
+	return descriptors
+}
+
+// collectMirroredReads exports the serverStatus mirroredReads section, which
+// shows the load mirroring puts on secondaries to keep their caches warm
+func (c *ServerStatusCollector) collectMirroredReads(ch chan<- prometheus.Metric, result bson.M, instance map[string]string) {
+	mirrored, ok := result["mirroredReads"].(bson.M)
+	if !ok {
+		return
+	}
+
+	labels := []string{instance["instance"], instance["replica_set"], instance["shard"]}
+	for _, field := range mirroredReadsFields {
+		if value := c.getNumericValue(mirrored[field.key]); value != nil {
+			ch <- prometheus.MustNewConstMetric(c.descriptors[field.descKey], field.valueType, *value, labels...)
+		}
+	}
+}
@@ -1,7 +1,7 @@
 package collector
 
 import (
-	"context"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"go.mongodb.org/mongo-driver/bson"
@@ -18,15 +18,15 @@ func NewOperationMetricsCollector(client *mongo.Client, logger *zap.Logger, conf
 	labels := []string{"instance", "replica_set", "shard"}
 
 	descriptors := map[string]*prometheus.Desc{
-		"metrics_operation_total":                 prometheus.NewDesc("mongodb_metrics_operation_total", "General operation metrics", labels, nil),
-		"metrics_operation_fastmod_total":         prometheus.NewDesc("mongodb_metrics_operation_fastmod_total", "Total number of fast modify operations", labels, nil),
-		"metrics_operation_idhack_total":          prometheus.NewDesc("mongodb_metrics_operation_idhack_total", "Total number of ID hack operations", labels, nil),
-		"metrics_operation_scan_and_order_total":  prometheus.NewDesc("mongodb_metrics_operation_scan_and_order_total", "Total number of scan and order operations", labels, nil),
-		"metrics_operation_write_conflicts_total": prometheus.NewDesc("mongodb_metrics_operation_write_conflicts_total", "Total number of write conflicts", labels, nil),
-		"metrics_operation_commits_total":         prometheus.NewDesc("mongodb_metrics_operation_commits_total", "Total number of commits", labels, nil),
-		"metrics_operation_rollbacks_total":       prometheus.NewDesc("mongodb_metrics_operation_rollbacks_total", "Total number of rollbacks", labels, nil),
-		"metrics_operation_apply_ops_total":       prometheus.NewDesc("mongodb_metrics_operation_apply_ops_total", "Total number of apply operations", labels, nil),
-		"metrics_operation_commands_total":        prometheus.NewDesc("mongodb_metrics_operation_commands_total", "Total number of commands", labels, nil),
+		"metrics_operation_total":                 prometheus.NewDesc(config.metricName("mongodb_metrics_operation_total"), "General operation metrics", labels, nil),
+		"metrics_operation_fastmod_total":         prometheus.NewDesc(config.metricName("mongodb_metrics_operation_fastmod_total"), "Total number of fast modify operations", labels, nil),
+		"metrics_operation_idhack_total":          prometheus.NewDesc(config.metricName("mongodb_metrics_operation_idhack_total"), "Total number of ID hack operations", labels, nil),
+		"metrics_operation_scan_and_order_total":  prometheus.NewDesc(config.metricName("mongodb_metrics_operation_scan_and_order_total"), "Total number of scan and order operations", labels, nil),
+		"metrics_operation_write_conflicts_total": prometheus.NewDesc(config.metricName("mongodb_metrics_operation_write_conflicts_total"), "Total number of write conflicts", labels, nil),
+		"metrics_operation_commits_total":         prometheus.NewDesc(config.metricName("mongodb_metrics_operation_commits_total"), "Total number of commits", labels, nil),
+		"metrics_operation_rollbacks_total":       prometheus.NewDesc(config.metricName("mongodb_metrics_operation_rollbacks_total"), "Total number of rollbacks", labels, nil),
+		"metrics_operation_apply_ops_total":       prometheus.NewDesc(config.metricName("mongodb_metrics_operation_apply_ops_total"), "Total number of apply operations", labels, nil),
+		"metrics_operation_commands_total":        prometheus.NewDesc(config.metricName("mongodb_metrics_operation_commands_total"), "Total number of commands", labels, nil),
 	}
 
 	return &OperationMetricsCollector{
@@ -40,9 +40,11 @@ func (c *OperationMetricsCollector) Collect(ch chan<- prometheus.Metric) {
 		return
 	}
 
-	ctx := context.Background()
+	ctx, done := c.collectContext("operation_metrics", 10*time.Second)
+	defer done()
+
 	var result bson.M
-	err := c.client.Database("admin").RunCommand(ctx, bson.D{{"serverStatus", 1}}).Decode(&result)
+	err := c.client.Database("admin").RunCommand(ctx, withMaxTime(ctx, serverStatusCommand("metrics"))).Decode(&result)
 	if err != nil {
 		c.logger.Error("Failed to get server status for operation metrics", zap.Error(err))
 		return
@@ -108,7 +110,7 @@ func (c *OperationMetricsCollector) collectOperationCounters(ch chan<- prometheu
 	}
 
 	for key, metricName := range operationMetrics {
-		if value, ok := operation[key].(int64); ok {
+		if value, ok := toInt64(operation[key]); ok {
 			ch <- prometheus.MustNewConstMetric(c.descriptors[metricName], prometheus.CounterValue, float64(value), labels["instance"], labels["replica_set"], labels["shard"])
 		}
 	}
@@ -0,0 +1,63 @@
+package collector
+
+import (
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// oplogFullRatio is the fraction of its maximum size from which the oplog
+// counts as full: truncation then keeps its used bytes level however fast it grows
+const oplogFullRatio = 0.95
+
+// oplogUsage is what the growth tracker remembers of an instance's oplog
+type oplogUsage struct {
+	used int64
+	seen time.Time
+}
+
+// oplogGrowthTracker derives the oplog growth rate from the change in its
+// used bytes between scrapes
+type oplogGrowthTracker struct {
+	mu        sync.Mutex
+	instances map[string]oplogUsage
+}
+
+func newOplogGrowthTracker() *oplogGrowthTracker {
+	return &oplogGrowthTracker{instances: make(map[string]oplogUsage)}
+}
+
+// observe records the instance's oplog used bytes as seen at seen and returns
+// the growth in bytes per hour since the previous observation. There is no
+// rate on the first observation, nor when the used bytes went down because
+// the oplog was truncated or resized
+func (t *oplogGrowthTracker) observe(instance string, used int64, seen time.Time) (float64, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	previous, ok := t.instances[instance]
+	t.instances[instance] = oplogUsage{used: used, seen: seen}
+
+	elapsed := seen.Sub(previous.seen)
+	if !ok || elapsed <= 0 || used < previous.used {
+		return 0, false
+	}
+	return float64(used-previous.used) / elapsed.Hours(), true
+}
+
+// oplogFull reports whether the oplog reached the size at which the server truncates it
+func oplogFull(used, maxSize int64) bool {
+	return maxSize > 0 && float64(used) >= float64(maxSize)*oplogFullRatio
+}
+
+// oplogWindowGrowth estimates the growth in bytes per hour from the used bytes
+// and the time between the oldest and newest entries: the average rate the
+// oplog filled at over its current window
+func oplogWindowGrowth(used int64, head, tail primitive.Timestamp) (float64, bool) {
+	if head.T <= tail.T {
+		return 0, false
+	}
+	window := time.Duration(head.T-tail.T) * time.Second
+	return float64(used) / window.Hours(), true
+}
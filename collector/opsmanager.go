@@ -0,0 +1,291 @@
+package collector
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// opsManagerCluster is a cluster or replica set monitored by the project
+type opsManagerCluster struct {
+	ID             string `json:"id"`
+	ClusterName    string `json:"clusterName"`
+	ReplicaSetName string `json:"replicaSetName"`
+	TypeName       string `json:"typeName"`
+}
+
+// opsManagerAutomationStatus is the automation goal state of the project
+type opsManagerAutomationStatus struct {
+	GoalVersion int64 `json:"goalVersion"`
+	Processes   []struct {
+		Name                    string `json:"name"`
+		Hostname                string `json:"hostname"`
+		LastGoalVersionAchieved int64  `json:"lastGoalVersionAchieved"`
+	} `json:"processes"`
+}
+
+// opsManagerSnapshot is one backup snapshot of a cluster
+type opsManagerSnapshot struct {
+	Complete bool `json:"complete"`
+	Created  struct {
+		Date time.Time `json:"date"`
+	} `json:"created"`
+}
+
+// opsManagerAlert is an open alert of the project
+type opsManagerAlert struct {
+	EventTypeName  string `json:"eventTypeName"`
+	ClusterName    string `json:"clusterName"`
+	ReplicaSetName string `json:"replicaSetName"`
+}
+
+// OpsManagerCollector reads automation and backup health of an Ops Manager or
+// Cloud Manager project, for on-prem fleets managed by the automation agent
+type OpsManagerCollector struct {
+	*BaseCollector
+	descriptors map[string]*prometheus.Desc
+	api         *atlasClient
+	projectID   string
+	clusters    map[string]bool
+}
+
+func NewOpsManagerCollector(client *mongo.Client, logger *zap.Logger, config CollectorConfig) *OpsManagerCollector {
+	clusterLabels := []string{"cluster_name", "replica_set"}
+
+	descriptors := map[string]*prometheus.Desc{
+		"opsmanager_automation_goal_version": prometheus.NewDesc(
+			config.metricName("mongodb_opsmanager_automation_goal_version"),
+			"Version of the project's automation configuration the agents should apply",
+			nil,
+			nil,
+		),
+		"opsmanager_automation_process_in_goal_state": prometheus.NewDesc(
+			config.metricName("mongodb_opsmanager_automation_process_in_goal_state"),
+			"Whether the automation agent has applied the latest configuration to the process (1) or not (0)",
+			[]string{"process", "hostname"},
+			nil,
+		),
+		"opsmanager_backup_status": prometheus.NewDesc(
+			config.metricName("mongodb_opsmanager_backup_status"),
+			"Backup status of the cluster (STARTED, STOPPED, PROVISIONING, ...), always 1",
+			append(clusterLabels, "status"),
+			nil,
+		),
+		"opsmanager_backup_last_snapshot_age_seconds": prometheus.NewDesc(
+			config.metricName("mongodb_opsmanager_backup_last_snapshot_age_seconds"),
+			"Time since the newest complete backup snapshot of the cluster was taken",
+			clusterLabels,
+			nil,
+		),
+		"opsmanager_restore_jobs": prometheus.NewDesc(
+			config.metricName("mongodb_opsmanager_restore_jobs"),
+			"Number of restore jobs of the cluster by status (IN_PROGRESS, FINISHED, BROKEN, KILLED)",
+			append(clusterLabels, "status"),
+			nil,
+		),
+		"opsmanager_open_alerts": prometheus.NewDesc(
+			config.metricName("mongodb_opsmanager_open_alerts"),
+			"Number of open alerts by cluster and event type, such as OPLOG_BEHIND for backup oplog lag",
+			append(clusterLabels, "event_type"),
+			nil,
+		),
+	}
+
+	options := collectorOptions(config, "opsmanager")
+	collector := &OpsManagerCollector{
+		BaseCollector: NewBaseCollector(client, logger, config),
+		descriptors:   descriptors,
+		projectID:     getStringOption(options, "project_id", ""),
+		clusters:      make(map[string]bool),
+	}
+	for _, name := range getStringSliceOption(options, "clusters") {
+		collector.clusters[name] = true
+	}
+
+	baseURL := getStringOption(options, "base_url", "")
+	publicKey := getStringOption(options, "public_key", "")
+	privateKey := getStringOption(options, "private_key", "")
+	if baseURL != "" && publicKey != "" && privateKey != "" && collector.projectID != "" {
+		collector.api = newOpsManagerClient(baseURL, publicKey, privateKey)
+	}
+	return collector
+}
+
+func (c *OpsManagerCollector) Collect(ch chan<- prometheus.Metric) {
+	if !c.isMetricExplicitlyEnabled("opsmanager") {
+		return
+	}
+	if c.api == nil {
+		c.logger.Debug("No Ops Manager URL, API key or project configured, skipping Ops Manager metrics")
+		return
+	}
+
+	ctx, done := c.collectContext("opsmanager", 30*time.Second)
+	defer done()
+
+	c.collectAutomationStatus(ctx, ch)
+
+	clusters, err := c.listClusters(ctx)
+	if err != nil {
+		c.logger.Error("Failed to list Ops Manager clusters", zap.Error(err))
+	}
+	for _, cluster := range clusters {
+		c.collectBackup(ctx, ch, cluster)
+	}
+
+	c.collectOpenAlerts(ctx, ch)
+}
+
+func (c *OpsManagerCollector) collectAutomationStatus(ctx context.Context, ch chan<- prometheus.Metric) {
+	var status opsManagerAutomationStatus
+	if err := c.api.get(ctx, c.projectPath("/automationStatus"), nil, &status); err != nil {
+		// Projects without automation answer with an error; monitoring-only
+		// fleets still get backup metrics
+		c.logger.Debug("Failed to read Ops Manager automation status", zap.Error(err))
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.descriptors["opsmanager_automation_goal_version"], prometheus.GaugeValue, float64(status.GoalVersion))
+	for _, process := range status.Processes {
+		inGoalState := 0.0
+		if process.LastGoalVersionAchieved >= status.GoalVersion {
+			inGoalState = 1
+		}
+		ch <- prometheus.MustNewConstMetric(
+			c.descriptors["opsmanager_automation_process_in_goal_state"],
+			prometheus.GaugeValue,
+			inGoalState,
+			process.Name,
+			process.Hostname,
+		)
+	}
+}
+
+// listClusters returns the project's clusters, limited to the configured ones
+func (c *OpsManagerCollector) listClusters(ctx context.Context) ([]opsManagerCluster, error) {
+	var response struct {
+		Results []opsManagerCluster `json:"results"`
+	}
+	if err := c.api.get(ctx, c.projectPath("/clusters"), url.Values{"itemsPerPage": {"500"}}, &response); err != nil {
+		return nil, err
+	}
+
+	clusters := make([]opsManagerCluster, 0, len(response.Results))
+	for _, cluster := range response.Results {
+		if c.includesCluster(cluster.ClusterName) {
+			clusters = append(clusters, cluster)
+		}
+	}
+	return clusters, nil
+}
+
+func (c *OpsManagerCollector) includesCluster(name string) bool {
+	return len(c.clusters) == 0 || c.clusters[name]
+}
+
+// collectBackup exports the backup status, snapshot age and restore jobs of a
+// cluster. Clusters without backup answer with an error, which is expected
+func (c *OpsManagerCollector) collectBackup(ctx context.Context, ch chan<- prometheus.Metric, cluster opsManagerCluster) {
+	labels := []string{cluster.ClusterName, cluster.ReplicaSetName}
+
+	var config struct {
+		StatusName string `json:"statusName"`
+	}
+	if err := c.api.get(ctx, c.projectPath("/backupConfigs/"+url.PathEscape(cluster.ID)), nil, &config); err != nil {
+		c.logger.Debug("No Ops Manager backup configuration for cluster",
+			zap.String("cluster", cluster.ClusterName),
+			zap.String("replica_set", cluster.ReplicaSetName),
+			zap.Error(err))
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.descriptors["opsmanager_backup_status"], prometheus.GaugeValue, 1, append(labels, config.StatusName)...)
+
+	clusterPath := c.projectPath("/clusters/" + url.PathEscape(cluster.ID))
+
+	var snapshots struct {
+		Results []opsManagerSnapshot `json:"results"`
+	}
+	if err := c.api.get(ctx, clusterPath+"/snapshots", url.Values{"itemsPerPage": {"500"}}, &snapshots); err != nil {
+		c.logger.Warn("Failed to list Ops Manager snapshots", zap.String("cluster", cluster.ClusterName), zap.Error(err))
+	} else if newest, ok := newestSnapshot(snapshots.Results); ok {
+		ch <- prometheus.MustNewConstMetric(
+			c.descriptors["opsmanager_backup_last_snapshot_age_seconds"],
+			prometheus.GaugeValue,
+			time.Since(newest).Seconds(),
+			labels...,
+		)
+	}
+
+	var restoreJobs struct {
+		Results []struct {
+			StatusName string `json:"statusName"`
+		} `json:"results"`
+	}
+	if err := c.api.get(ctx, clusterPath+"/restoreJobs", url.Values{"itemsPerPage": {"500"}}, &restoreJobs); err != nil {
+		c.logger.Warn("Failed to list Ops Manager restore jobs", zap.String("cluster", cluster.ClusterName), zap.Error(err))
+		return
+	}
+	statuses := make(map[string]int)
+	for _, job := range restoreJobs.Results {
+		statuses[job.StatusName]++
+	}
+	for status, count := range statuses {
+		ch <- prometheus.MustNewConstMetric(c.descriptors["opsmanager_restore_jobs"], prometheus.GaugeValue, float64(count), append(labels, status)...)
+	}
+}
+
+// newestSnapshot returns the creation time of the newest complete snapshot
+func newestSnapshot(snapshots []opsManagerSnapshot) (time.Time, bool) {
+	var newest time.Time
+	for _, snapshot := range snapshots {
+		if snapshot.Complete && snapshot.Created.Date.After(newest) {
+			newest = snapshot.Created.Date
+		}
+	}
+	return newest, !newest.IsZero()
+}
+
+func (c *OpsManagerCollector) collectOpenAlerts(ctx context.Context, ch chan<- prometheus.Metric) {
+	var response struct {
+		Results []opsManagerAlert `json:"results"`
+	}
+	if err := c.api.get(ctx, c.projectPath("/alerts"), url.Values{"status": {"OPEN"}, "itemsPerPage": {"500"}}, &response); err != nil {
+		c.logger.Error("Failed to list open Ops Manager alerts", zap.Error(err))
+		return
+	}
+
+	type alertKey struct {
+		cluster    string
+		replicaSet string
+		eventType  string
+	}
+	counts := make(map[alertKey]int)
+	for _, alert := range response.Results {
+		// Alerts on hosts, agents or the project itself carry no cluster
+		if alert.ClusterName != "" && !c.includesCluster(alert.ClusterName) {
+			continue
+		}
+		counts[alertKey{alert.ClusterName, alert.ReplicaSetName, alert.EventTypeName}]++
+	}
+	for key, count := range counts {
+		ch <- prometheus.MustNewConstMetric(c.descriptors["opsmanager_open_alerts"], prometheus.GaugeValue, float64(count), key.cluster, key.replicaSet, key.eventType)
+	}
+}
+
+func (c *OpsManagerCollector) projectPath(path string) string {
+	return "/api/public/v1.0/groups/" + url.PathEscape(c.projectID) + path
+}
+
+func (c *OpsManagerCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range c.descriptors {
+		ch <- desc
+	}
+}
+
+func (c *OpsManagerCollector) Name() string {
+	return "opsmanager"
+}
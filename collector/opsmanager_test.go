@@ -0,0 +1,78 @@
+package collector
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+func TestOpsManagerCollector(t *testing.T) {
+	snapshotTime := time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339)
+	server := atlasTestServer(t, opsManagerAPIAccept, map[string]string{
+		"/api/public/v1.0/groups/proj1/automationStatus": `{"goalVersion": 29, "processes": [
+			{"name": "rs0_1", "hostname": "db1.example.net", "lastGoalVersionAchieved": 29},
+			{"name": "rs0_2", "hostname": "db2.example.net", "lastGoalVersionAchieved": 28}
+		]}`,
+		"/api/public/v1.0/groups/proj1/clusters": `{"results": [
+			{"id": "c1", "clusterName": "orders", "replicaSetName": "rs0", "typeName": "REPLICA_SET"},
+			{"id": "c2", "clusterName": "staging", "replicaSetName": "rs1", "typeName": "REPLICA_SET"}
+		]}`,
+		"/api/public/v1.0/groups/proj1/backupConfigs/c1":        `{"statusName": "STARTED"}`,
+		"/api/public/v1.0/groups/proj1/clusters/c1/snapshots":   `{"results": [{"complete": true, "created": {"date": "` + snapshotTime + `"}}, {"complete": false, "created": {"date": "` + time.Now().UTC().Format(time.RFC3339) + `"}}]}`,
+		"/api/public/v1.0/groups/proj1/clusters/c1/restoreJobs": `{"results": [{"statusName": "FINISHED"}, {"statusName": "FINISHED"}, {"statusName": "BROKEN"}]}`,
+		"/api/public/v1.0/groups/proj1/alerts":                  `{"results": [{"eventTypeName": "OPLOG_BEHIND", "clusterName": "orders", "replicaSetName": "rs0"}, {"eventTypeName": "HOST_DOWN", "clusterName": "staging"}]}`,
+	})
+	defer server.Close()
+
+	collector := NewOpsManagerCollector(nil, zap.NewNop(), CollectorConfig{
+		EnabledMetrics: []string{"opsmanager"},
+		Collectors: map[string]interface{}{"opsmanager": map[string]interface{}{
+			"base_url":    server.URL,
+			"public_key":  "public",
+			"private_key": "private",
+			"project_id":  "proj1",
+			"clusters":    []string{"orders"},
+		}},
+	})
+
+	ch := make(chan prometheus.Metric, 20)
+	collector.Collect(ch)
+	close(ch)
+
+	names := make(map[string]int)
+	for metric := range ch {
+		names[descName(metric.Desc())]++
+	}
+	expected := map[string]int{
+		"mongodb_opsmanager_automation_goal_version":          1,
+		"mongodb_opsmanager_automation_process_in_goal_state": 2,
+		"mongodb_opsmanager_backup_status":                    1,
+		"mongodb_opsmanager_backup_last_snapshot_age_seconds": 1,
+		"mongodb_opsmanager_restore_jobs":                     2,
+		"mongodb_opsmanager_open_alerts":                      1,
+	}
+	for name, count := range expected {
+		if names[name] != count {
+			t.Errorf("Expected %d %s series, got %d", count, name, names[name])
+		}
+	}
+}
+
+func TestNewestSnapshot(t *testing.T) {
+	older := opsManagerSnapshot{Complete: true}
+	older.Created.Date = time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	newer := opsManagerSnapshot{Complete: true}
+	newer.Created.Date = time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)
+	running := opsManagerSnapshot{}
+	running.Created.Date = time.Date(2024, 5, 3, 0, 0, 0, 0, time.UTC)
+
+	newest, ok := newestSnapshot([]opsManagerSnapshot{older, running, newer})
+	if !ok || !newest.Equal(newer.Created.Date) {
+		t.Errorf("Newest complete snapshot should be used, got %v", newest)
+	}
+	if _, ok := newestSnapshot([]opsManagerSnapshot{running}); ok {
+		t.Error("Incomplete snapshots should not count")
+	}
+}
@@ -0,0 +1,95 @@
+package collector
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// perconaInMemoryCacheFields maps the cache statistics of the Percona Memory
+// Engine, which reports WiredTiger statistics under inMemory, to type labels
+var perconaInMemoryCacheFields = map[string]string{
+	"bytes currently in the cache":     "used",
+	"tracked dirty bytes in the cache": "dirty",
+	"maximum bytes configured":         "max",
+}
+
+// perconaDescriptors describes the Percona Server for MongoDB metrics
+func perconaDescriptors(config CollectorConfig) map[string]*prometheus.Desc {
+	labels := []string{"instance", "replica_set", "shard"}
+
+	return map[string]*prometheus.Desc{
+		"psmdb_info": prometheus.NewDesc(
+			config.metricName("mongodb_psmdb_info"),
+			"Percona Server for MongoDB version, always 1; only exported for Percona builds",
+			append(labels, "version"),
+			nil,
+		),
+		"psmdb_inmemory_cache_bytes": prometheus.NewDesc(
+			config.metricName("mongodb_psmdb_inmemory_cache_bytes"),
+			"Percona Memory Engine cache size by type (used, dirty, max)",
+			append(labels, "type"),
+			nil,
+		),
+		"psmdb_profiler_rate_limit": prometheus.NewDesc(
+			config.metricName("mongodb_psmdb_profiler_rate_limit"),
+			"Profiler rate limit; 1 in this many operations is profiled",
+			labels,
+			nil,
+		),
+		"psmdb_audit_log_info": prometheus.NewDesc(
+			config.metricName("mongodb_psmdb_audit_log_info"),
+			"Audit log configuration, always 1; only exported when audit logging is enabled",
+			append(labels, "destination", "format"),
+			nil,
+		),
+	}
+}
+
+// psmdbVersion returns the Percona Server for MongoDB version found by detection, or "" for other builds
+func (s *serverCapabilities) psmdbVersion() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.psmdb
+}
+
+// collectPercona exports Percona-specific metrics when the server is a
+// Percona build: the in-memory engine cache from serverStatus, and the
+// profiler rate limit and audit log settings from the startup options
+func (c *ServerStatusCollector) collectPercona(ctx context.Context, ch chan<- prometheus.Metric, result bson.M, instance map[string]string) {
+	if c.config.capabilities == nil {
+		return
+	}
+	version := c.config.capabilities.psmdbVersion()
+	if version == "" {
+		return
+	}
+
+	labels := []string{instance["instance"], instance["replica_set"], instance["shard"]}
+	ch <- prometheus.MustNewConstMetric(c.descriptors["psmdb_info"], prometheus.GaugeValue, 1, append(labels, version)...)
+
+	if inMemory, ok := result["inMemory"].(bson.M); ok {
+		if cache, ok := inMemory["cache"].(bson.M); ok {
+			for field, cacheType := range perconaInMemoryCacheFields {
+				if value := c.getNumericValue(cache[field]); value != nil {
+					ch <- prometheus.MustNewConstMetric(c.descriptors["psmdb_inmemory_cache_bytes"], prometheus.GaugeValue, *value, append(labels, cacheType)...)
+				}
+			}
+		}
+	}
+
+	options := c.cmdLineOptions(ctx)
+	if operationProfiling, ok := options["operationProfiling"].(bson.M); ok {
+		if rateLimit := c.getNumericValue(operationProfiling["rateLimit"]); rateLimit != nil {
+			ch <- prometheus.MustNewConstMetric(c.descriptors["psmdb_profiler_rate_limit"], prometheus.GaugeValue, *rateLimit, labels...)
+		}
+	}
+	if auditLog, ok := options["auditLog"].(bson.M); ok {
+		destination, _ := auditLog["destination"].(string)
+		format, _ := auditLog["format"].(string)
+		if destination != "" {
+			ch <- prometheus.MustNewConstMetric(c.descriptors["psmdb_audit_log_info"], prometheus.GaugeValue, 1, append(labels, destination, format)...)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// unauthorizedCode is the server error code for commands the user lacks privileges for
+const unauthorizedCode = 13
+
+// privilegeProbe is a command a collector depends on, run as-is to find out
+// whether the connected user may execute it
+type privilegeProbe struct {
+	name     string
+	database string
+	command  bson.D
+	hint     string
+}
+
+var (
+	probeServerStatus     = privilegeProbe{"serverStatus", "admin", serverStatusCommand(), "clusterMonitor role"}
+	probeListDatabase     = privilegeProbe{"listDatabases", "admin", bson.D{{Key: "listDatabases", Value: 1}, {Key: "nameOnly", Value: true}}, "clusterMonitor role"}
+	probeReplSetGetStatus = privilegeProbe{"replSetGetStatus", "admin", bson.D{{Key: "replSetGetStatus", Value: 1}}, "clusterMonitor role"}
+	probeDBStats          = privilegeProbe{"dbStats", "admin", bson.D{{Key: "dbStats", Value: 1}}, "clusterMonitor role"}
+	probeCurrentOp        = privilegeProbe{"currentOp", "admin", bson.D{{Key: "currentOp", Value: 1}, {Key: "$all", Value: true}}, "clusterMonitor role"}
+)
+
+// collectorProbes lists the commands each collector needs to produce its
+// metrics. atlas and opsmanager read HTTP APIs rather than MongoDB, so they
+// need none; the canary's commands are built by privilegeProbesFor
+var collectorProbes = map[string][]privilegeProbe{
+	"server_status": {probeServerStatus},
+	"replica_set_status": {
+		probeReplSetGetStatus,
+		{"collStats local.oplog.rs", "local", bson.D{{Key: "collStats", Value: "oplog.rs"}}, "clusterMonitor role"},
+		{"find local.oplog.rs", "local", bson.D{{Key: "find", Value: "oplog.rs"}, {Key: "limit", Value: 1}}, "read role on the local database"},
+	},
+	"query_executor": {probeServerStatus},
+	"wiredtiger":     {probeServerStatus},
+	"locks":          {probeServerStatus},
+	"index_stats":    {probeListDatabase, {"collStats", "admin", bson.D{{Key: "collStats", Value: "system.version"}}, "clusterMonitor role"}},
+	"storage_stats":  {probeListDatabase, probeDBStats},
+	"compatibility":  {probeServerStatus},
+	"sharding": {
+		{"balancerStatus", "admin", bson.D{{Key: "balancerStatus", Value: 1}}, "clusterMonitor role"},
+		{"find config.shards", "config", bson.D{{Key: "find", Value: "shards"}, {Key: "limit", Value: 1}}, "clusterMonitor role or read on the config database"},
+	},
+	"collstats": {probeListDatabase, {"top", "admin", bson.D{{Key: "top", Value: 1}}, "clusterMonitor role"}},
+	"cursors": {
+		probeServerStatus,
+		probeCurrentOp,
+		{"getParameter", "admin", bson.D{{Key: "getParameter", Value: 1}, {Key: "cursorTimeoutMillis", Value: 1}}, "clusterMonitor role"},
+	},
+	"profile": {
+		probeListDatabase,
+		{"profile", "admin", bson.D{{Key: "profile", Value: -1}}, "dbAdmin role (or dbAdminAnyDatabase)"},
+		{"find system.profile", "admin", bson.D{{Key: "find", Value: "system.profile"}, {Key: "limit", Value: 1}}, "read role on each profiled database (or readAnyDatabase)"},
+	},
+	"connection_pool": {probeServerStatus, probeCurrentOp},
+	"index_selectivity": {
+		probeListDatabase,
+		{"aggregate admin.system.version", "admin", bson.D{{Key: "aggregate", Value: "system.version"}, {Key: "pipeline", Value: bson.A{bson.D{{Key: "$limit", Value: 1}}}}, {Key: "cursor", Value: bson.D{}}}, "read role on each sampled database (or readAnyDatabase)"},
+	},
+	"shard_key_distribution": {
+		{"find config.collections", "config", bson.D{{Key: "find", Value: "collections"}, {Key: "limit", Value: 1}}, "clusterMonitor role or read on the config database"},
+		{"aggregate config.chunks", "config", bson.D{{Key: "aggregate", Value: "chunks"}, {Key: "pipeline", Value: bson.A{bson.D{{Key: "$limit", Value: 1}}}}, {Key: "cursor", Value: bson.D{}}}, "clusterMonitor role or read on the config database"},
+	},
+	"dbhash": {
+		probeReplSetGetStatus,
+		{"dbHash", "admin", bson.D{{Key: "dbHash", Value: 1}, {Key: "collections", Value: bson.A{"system.version"}}}, "dbAdmin role on each selected database (or dbAdminAnyDatabase)"},
+	},
+	"maintenance": {
+		{"aggregate $currentOp", "admin", bson.D{{Key: "aggregate", Value: 1}, {Key: "pipeline", Value: bson.A{bson.D{{Key: "$currentOp", Value: bson.D{{Key: "allUsers", Value: true}}}}, bson.D{{Key: "$limit", Value: 1}}}}, {Key: "cursor", Value: bson.D{}}}, "clusterMonitor role"},
+	},
+	"atlas":      {},
+	"opsmanager": {},
+	"ping":       {{"ping", "admin", bson.D{{Key: "ping", Value: 1}}, "no role; ping is open to every user"}},
+}
+
+// PermissionCheck is the outcome of running one command a collector depends on
+type PermissionCheck struct {
+	Collector    string
+	Command      string
+	Database     string
+	Unauthorized bool
+	// Err is set for any failure; errors other than Unauthorized usually mean the
+	// command does not apply to this deployment (e.g. replSetGetStatus on a standalone)
+	Err  error
+	Hint string
+}
+
+// ConnectionPrivileges describes the authenticated user as reported by connectionStatus
+type ConnectionPrivileges struct {
+	Users []string
+	Roles []string
+}
+
+// CheckConnectionPrivileges reports the authenticated users and roles via connectionStatus
+func CheckConnectionPrivileges(ctx context.Context, client *mongo.Client) (*ConnectionPrivileges, error) {
+	var status struct {
+		AuthInfo struct {
+			AuthenticatedUsers []struct {
+				User string `bson:"user"`
+				DB   string `bson:"db"`
+			} `bson:"authenticatedUsers"`
+			AuthenticatedUserRoles []struct {
+				Role string `bson:"role"`
+				DB   string `bson:"db"`
+			} `bson:"authenticatedUserRoles"`
+		} `bson:"authInfo"`
+	}
+
+	if err := runCommandWithTimeout(ctx, client.Database("admin"), bson.D{{Key: "connectionStatus", Value: 1}}, 10*time.Second, &status); err != nil {
+		return nil, fmt.Errorf("failed to run connectionStatus: %w", err)
+	}
+
+	privileges := &ConnectionPrivileges{}
+	for _, user := range status.AuthInfo.AuthenticatedUsers {
+		privileges.Users = append(privileges.Users, user.User+"@"+user.DB)
+	}
+	for _, role := range status.AuthInfo.AuthenticatedUserRoles {
+		privileges.Roles = append(privileges.Roles, role.Role+"@"+role.DB)
+	}
+	return privileges, nil
+}
+
+// CheckPermissions runs the commands every enabled collector depends on and
+// reports which of them the connected user is not authorized to execute
+func CheckPermissions(ctx context.Context, client *mongo.Client, logger *zap.Logger, config CollectorConfig) []PermissionCheck {
+	enabled := NewBaseCollector(client, logger, config)
+
+	var checks []PermissionCheck
+	// Collectors are reported in the order InitializeCollectors creates them
+	for _, collector := range InitializeCollectors(nil, logger, config) {
+		name := collector.Name()
+		// Opt-in collectors are probed only when listed, so the canary writes nothing otherwise
+		if !enabled.isMetricEnabled(name) || (optInCollectors[name] && !enabled.isMetricExplicitlyEnabled(name)) {
+			continue
+		}
+
+		for _, probe := range privilegeProbesFor(name, logger, config) {
+			var result bson.Raw
+			err := runCommandWithTimeout(ctx, client.Database(probe.database), probe.command, 10*time.Second, &result)
+			checks = append(checks, PermissionCheck{
+				Collector:    name,
+				Command:      probe.name,
+				Database:     probe.database,
+				Unauthorized: isUnauthorized(err),
+				Err:          err,
+				Hint:         probe.hint,
+			})
+		}
+	}
+	return checks
+}
+
+// privilegeProbesFor returns the commands the named collector needs
+func privilegeProbesFor(name string, logger *zap.Logger, config CollectorConfig) []privilegeProbe {
+	if name == "canary" {
+		// The canary namespace is configurable, so its commands come from the collector
+		return NewCanaryCollector(nil, logger, config).privilegeProbes()
+	}
+	return collectorProbes[name]
+}
+
+func isUnauthorized(err error) bool {
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) {
+		return cmdErr.Code == unauthorizedCode || cmdErr.Name == "Unauthorized"
+	}
+	return false
+}
@@ -0,0 +1,67 @@
+package collector
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+func TestIsUnauthorized(t *testing.T) {
+	cases := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"unauthorized", mongo.CommandError{Code: 13, Name: "Unauthorized"}, true},
+		{"wrapped", fmt.Errorf("run: %w", mongo.CommandError{Code: 13}), true},
+		{"not a replica set", mongo.CommandError{Code: 76, Name: "NoReplicationEnabled"}, false},
+		{"other error", errors.New("connection refused"), false},
+		{"no error", nil, false},
+	}
+
+	for _, tc := range cases {
+		if got := isUnauthorized(tc.err); got != tc.expected {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.expected, got)
+		}
+	}
+}
+
+func TestCollectorProbesCoverEveryCollector(t *testing.T) {
+	names := make(map[string]bool)
+	for _, collector := range InitializeCollectors(nil, zap.NewNop(), CollectorConfig{}) {
+		name := collector.Name()
+		names[name] = true
+		if _, ok := collectorProbes[name]; !ok && len(privilegeProbesFor(name, zap.NewNop(), CollectorConfig{})) == 0 {
+			t.Errorf("Collector %s has no privilege probes", name)
+		}
+	}
+	for name := range collectorProbes {
+		if !names[name] {
+			t.Errorf("Privilege probes listed for unknown collector %s", name)
+		}
+	}
+}
+
+func TestCanaryPrivilegeProbes(t *testing.T) {
+	c := NewCanaryCollector(nil, zap.NewNop(), CollectorConfig{Collectors: map[string]interface{}{
+		"canary": map[string]interface{}{"database": "health", "collection": "probe"},
+	}})
+
+	var commands []string
+	for _, probe := range c.privilegeProbes() {
+		if probe.database != "health" {
+			t.Errorf("Probe %s should run on the canary database, got %s", probe.name, probe.database)
+		}
+		if probe.command[0].Value != "probe" {
+			t.Errorf("Probe %s should target the canary collection, got %v", probe.name, probe.command[0].Value)
+		}
+		commands = append(commands, probe.command[0].Key)
+	}
+	if strings.Join(commands, ",") != "insert,find,delete" {
+		t.Errorf("Expected insert, find and delete probes, got %v", commands)
+	}
+}
@@ -0,0 +1,112 @@
+package collector
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// PingCollector sends a few ping commands per scrape and buckets their round
+// trips, a latency signal covering server selection and the network only,
+// apart from how long commands take to execute
+type PingCollector struct {
+	*BaseCollector
+	// rtt is nil when latency_summaries replaces the histogram with summary
+	rtt     *prometheus.HistogramVec
+	summary *prometheus.SummaryVec
+	count   int
+}
+
+func NewPingCollector(client *mongo.Client, logger *zap.Logger, config CollectorConfig) *PingCollector {
+	options := collectorOptions(config, "ping")
+	labels := []string{"instance", "replica_set", "shard"}
+	help := "Round-trip time of ping commands sent by the ping probe, including server selection"
+
+	var summary *prometheus.SummaryVec
+	if objectives, ok := options["rtt_objectives"].(map[float64]float64); ok && len(objectives) > 0 {
+		summary = prometheus.NewSummaryVec(prometheus.SummaryOpts{
+			Name:       config.metricName("mongodb_ping_duration_summary_seconds"),
+			Help:       help,
+			Objectives: objectives,
+			MaxAge:     getDurationOption(options, "rtt_summary_max_age", prometheus.DefMaxAge),
+		}, labels)
+	}
+
+	var rtt *prometheus.HistogramVec
+	if summary == nil || !getBoolOption(options, "rtt_replace_histogram", false) {
+		rtt = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    config.metricName("mongodb_ping_duration_seconds"),
+			Help:    help,
+			Buckets: []float64{0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1},
+		}, labels)
+	}
+
+	return &PingCollector{
+		BaseCollector: NewBaseCollector(client, logger, config),
+		rtt:           rtt,
+		summary:       summary,
+		count:         getIntOption(options, "count", 3),
+	}
+}
+
+func (c *PingCollector) Collect(ch chan<- prometheus.Metric) {
+	if !c.isMetricEnabled("ping") {
+		return
+	}
+
+	ctx, done := c.collectContext("ping", 5*time.Second)
+	defer done()
+
+	instance := c.getInstanceInfo(bson.M{})
+	labels := []string{instance["instance"], instance["replica_set"], instance["shard"]}
+
+	admin := c.client.Database("admin")
+	succeeded := 0
+	for i := 0; i < c.count; i++ {
+		start := time.Now()
+		var result bson.M
+		if err := c.runCommand(ctx, "ping", admin, bson.D{{"ping", 1}}, &result); err != nil {
+			c.logCommandError("Failed to ping MongoDB", err)
+			break
+		}
+		c.observe(time.Since(start).Seconds(), labels)
+		succeeded++
+	}
+
+	// A failed ping has no round trip. Exporting nothing counts the run in
+	// mongodb_exporter_collector_errors_total instead of repeating the last histogram
+	if succeeded == 0 {
+		return
+	}
+	if c.rtt != nil {
+		c.rtt.Collect(ch)
+	}
+	if c.summary != nil {
+		c.summary.Collect(ch)
+	}
+}
+
+func (c *PingCollector) observe(seconds float64, labels []string) {
+	if c.rtt != nil {
+		c.rtt.WithLabelValues(labels...).Observe(seconds)
+	}
+	if c.summary != nil {
+		c.summary.WithLabelValues(labels...).Observe(seconds)
+	}
+}
+
+func (c *PingCollector) Describe(ch chan<- *prometheus.Desc) {
+	if c.rtt != nil {
+		c.rtt.Describe(ch)
+	}
+	if c.summary != nil {
+		c.summary.Describe(ch)
+	}
+}
+
+func (c *PingCollector) Name() string {
+	return "ping"
+}
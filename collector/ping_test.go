@@ -0,0 +1,50 @@
+package collector
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+func TestPingCollectorOptions(t *testing.T) {
+	collector := NewPingCollector(nil, zap.NewNop(), CollectorConfig{
+		Collectors: map[string]interface{}{"ping": map[string]interface{}{"count": 5}},
+	})
+	if collector.count != 5 {
+		t.Errorf("Expected 5 pings per scrape, got %d", collector.count)
+	}
+	if NewPingCollector(nil, zap.NewNop(), CollectorConfig{}).count != 3 {
+		t.Error("Expected 3 pings per scrape by default")
+	}
+
+	disabled := NewPingCollector(nil, zap.NewNop(), CollectorConfig{DisabledMetrics: []string{"ping"}})
+	ch := make(chan prometheus.Metric, 10)
+	disabled.Collect(ch)
+	close(ch)
+	if len(ch) != 0 {
+		t.Error("Disabled ping collector should not collect metrics")
+	}
+}
+
+func TestPingCollectorSummary(t *testing.T) {
+	options := map[string]interface{}{"rtt_objectives": map[float64]float64{0.5: 0.05, 0.99: 0.001}}
+	both := NewPingCollector(nil, zap.NewNop(), CollectorConfig{
+		Collectors: map[string]interface{}{"ping": options},
+	})
+	if both.rtt == nil || both.summary == nil {
+		t.Error("Expected the summary next to the histogram")
+	}
+
+	options["rtt_replace_histogram"] = true
+	replaced := NewPingCollector(nil, zap.NewNop(), CollectorConfig{
+		Collectors: map[string]interface{}{"ping": options},
+	})
+	if replaced.rtt != nil || replaced.summary == nil {
+		t.Error("Expected only the summary when replacing the histogram")
+	}
+
+	if plain := NewPingCollector(nil, zap.NewNop(), CollectorConfig{}); plain.rtt == nil || plain.summary != nil {
+		t.Error("Expected only the histogram without latency summaries")
+	}
+}
@@ -2,6 +2,7 @@ package collector
 
 import (
 	"context"
+	"regexp"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -16,92 +17,153 @@ type ProfileCollector struct {
 	*BaseCollector
 	descriptors map[string]*prometheus.Desc
 	lastCheck   time.Time
+	budget      profileBudget
+	memberScope string
+	databases   []*regexp.Regexp
+	// durations summarizes individual operation durations with quantiles when enabled
+	durations *prometheus.SummaryVec
+	// histogram buckets individual operation durations with exemplars when enabled
+	histogram *prometheus.HistogramVec
 }
 
 func NewProfileCollector(client *mongo.Client, logger *zap.Logger, config CollectorConfig) *ProfileCollector {
+	options := collectorOptions(config, "profile")
+	memberScope := getStringOption(options, "member_scope", profileScopeAll)
+
 	labels := []string{"instance", "replica_set", "shard", "database"}
+	if memberScope != profileScopeAll {
+		labels = []string{"instance", "replica_set", "shard", "member", "database"}
+	}
 	operationLabels := append(labels, "operation", "collection")
 	planSummaryLabels := append(labels, "plan_summary")
 
 	descriptors := map[string]*prometheus.Desc{
 		"profile_slow_operations_total": prometheus.NewDesc(
-			"mongodb_profile_slow_operations_total",
+			config.metricName("mongodb_profile_slow_operations_total"),
 			"Total number of slow operations by type",
 			operationLabels,
 			nil,
 		),
 		"profile_operations_duration_seconds": prometheus.NewDesc(
-			"mongodb_profile_operations_duration_seconds",
+			config.metricName("mongodb_profile_operations_duration_seconds"),
 			"Duration histogram of profiled operations in seconds",
 			operationLabels,
 			nil,
 		),
 		"profile_operations_examined_docs": prometheus.NewDesc(
-			"mongodb_profile_operations_examined_docs",
+			config.metricName("mongodb_profile_operations_examined_docs"),
 			"Number of documents examined by profiled operations",
 			operationLabels,
 			nil,
 		),
 		"profile_operations_docs_returned": prometheus.NewDesc(
-			"mongodb_profile_operations_docs_returned",
+			config.metricName("mongodb_profile_operations_docs_returned"),
 			"Number of documents returned by profiled operations",
 			operationLabels,
 			nil,
 		),
 		"profile_operations_keys_examined": prometheus.NewDesc(
-			"mongodb_profile_operations_keys_examined",
+			config.metricName("mongodb_profile_operations_keys_examined"),
 			"Number of index keys examined by profiled operations",
 			operationLabels,
 			nil,
 		),
 		"profile_operations_response_length_bytes": prometheus.NewDesc(
-			"mongodb_profile_operations_response_length_bytes",
+			config.metricName("mongodb_profile_operations_response_length_bytes"),
 			"Response length in bytes for profiled operations",
 			operationLabels,
 			nil,
 		),
 		"profile_operations_locks_acquired": prometheus.NewDesc(
-			"mongodb_profile_operations_locks_acquired",
+			config.metricName("mongodb_profile_operations_locks_acquired"),
 			"Number of locks acquired during profiled operations",
 			append(operationLabels, "lock_type"),
 			nil,
 		),
 		"profile_operations_lock_wait_time_microseconds": prometheus.NewDesc(
-			"mongodb_profile_operations_lock_wait_time_microseconds",
+			config.metricName("mongodb_profile_operations_lock_wait_time_microseconds"),
 			"Time spent waiting for locks during profiled operations in microseconds",
 			append(operationLabels, "lock_type"),
 			nil,
 		),
 		"profile_plan_summary_total": prometheus.NewDesc(
-			"mongodb_profile_plan_summary_total",
+			config.metricName("mongodb_profile_plan_summary_total"),
 			"Total number of operations by execution plan summary",
 			planSummaryLabels,
 			nil,
 		),
 		"profile_write_conflicts_total": prometheus.NewDesc(
-			"mongodb_profile_write_conflicts_total",
+			config.metricName("mongodb_profile_write_conflicts_total"),
 			"Total number of write conflicts in profiled operations",
 			operationLabels,
 			nil,
 		),
 		"profile_storage_stats_total": prometheus.NewDesc(
-			"mongodb_profile_storage_stats_total",
+			config.metricName("mongodb_profile_storage_stats_total"),
 			"Storage engine statistics from profiled operations",
 			append(operationLabels, "storage_stat"),
 			nil,
 		),
 		"profile_cpu_time_microseconds": prometheus.NewDesc(
-			"mongodb_profile_cpu_time_microseconds",
+			config.metricName("mongodb_profile_cpu_time_microseconds"),
 			"CPU time used by profiled operations in microseconds",
 			operationLabels,
 			nil,
 		),
 	}
 
+	if memberScope != profileScopeAll {
+		descriptors["profile_source_active"] = prometheus.NewDesc(
+			config.metricName("mongodb_profile_source_active"),
+			"Whether profiles are collected from this member under the configured member scope",
+			[]string{"instance", "replica_set", "shard", "member"},
+			nil,
+		)
+	}
+
+	budget := profileBudget{
+		maxOperations:    getIntOption(options, "max_tracked_operations", 0),
+		maxPlanSummaries: getIntOption(options, "max_plan_summaries", 0),
+		maxLockTypes:     getIntOption(options, "max_lock_types", 0),
+	}
+	if budget.maxOperations <= 0 {
+		budget.maxOperations = defaultProfileMaxOperations
+	}
+	if budget.maxPlanSummaries <= 0 {
+		budget.maxPlanSummaries = defaultProfileMaxPlanSummaries
+	}
+	if budget.maxLockTypes <= 0 {
+		budget.maxLockTypes = defaultProfileMaxLockTypes
+	}
+
+	var durations *prometheus.SummaryVec
+	if objectives, ok := options["duration_objectives"].(map[float64]float64); ok && len(objectives) > 0 {
+		durations = prometheus.NewSummaryVec(prometheus.SummaryOpts{
+			Name:       config.metricName("mongodb_profile_operations_duration_summary_seconds"),
+			Help:       "Duration of individual profiled operations in seconds",
+			Objectives: objectives,
+			MaxAge:     getDurationOption(options, "duration_summary_max_age", prometheus.DefMaxAge),
+		}, operationLabels)
+	}
+
+	var histogram *prometheus.HistogramVec
+	if getBoolOption(options, "duration_histogram", false) {
+		histogram = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    config.metricName("mongodb_profile_slow_operation_duration_seconds"),
+			Help:    "Duration of individual profiled operations in seconds, with exemplars identifying the query",
+			Buckets: []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+		}, operationLabels)
+	}
+
 	return &ProfileCollector{
 		BaseCollector: NewBaseCollector(client, logger, config),
 		descriptors:   descriptors,
 		lastCheck:     time.Now().Add(-1 * time.Hour), // Start 1 hour ago
+		budget:        budget,
+		memberScope:   memberScope,
+		databases:     compileDatabasePatterns(logger, getStringSliceOption(options, "databases")),
+		durations:     durations,
+		histogram:     histogram,
 	}
 }
 
@@ -110,18 +172,21 @@ func (c *ProfileCollector) Collect(ch chan<- prometheus.Metric) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
-	defer cancel()
+	ctx, done := c.collectContext("profile", 15*time.Second)
+	defer done()
+
+	instance := c.getInstanceInfo(bson.M{})
+	if !c.checkSource(ctx, ch, instance) {
+		return
+	}
 
 	// Get list of databases
-	databases, err := c.client.ListDatabaseNames(ctx, bson.D{})
+	databases, err := c.listDatabases(ctx, 10*time.Second)
 	if err != nil {
 		c.logger.Error("Failed to list databases for profiling", zap.Error(err))
 		return
 	}
 
-	instance := c.getInstanceInfo(bson.M{})
-
 	currentTime := time.Now()
 
 	for _, dbName := range databases {
@@ -134,6 +199,13 @@ func (c *ProfileCollector) Collect(ch chan<- prometheus.Metric) {
 	}
 
 	c.lastCheck = currentTime
+
+	if c.durations != nil {
+		c.durations.Collect(ch)
+	}
+	if c.histogram != nil {
+		c.histogram.Collect(ch)
+	}
 }
 
 func (c *ProfileCollector) collectDatabaseProfileMetrics(ctx context.Context, ch chan<- prometheus.Metric, dbName string, instance map[string]string, since, until time.Time) {
@@ -141,7 +213,7 @@ func (c *ProfileCollector) collectDatabaseProfileMetrics(ctx context.Context, ch
 
 	// Check if profiling is enabled
 	var profileStatus bson.M
-	err := db.RunCommand(ctx, bson.D{{"profile", -1}}).Decode(&profileStatus)
+	err := c.runCommand(ctx, "profile", db, bson.D{{"profile", -1}}, &profileStatus)
 	if err != nil {
 		c.logger.Debug("Failed to get profile status",
 			zap.String("database", dbName),
@@ -150,7 +222,7 @@ func (c *ProfileCollector) collectDatabaseProfileMetrics(ctx context.Context, ch
 	}
 
 	// Skip if profiling is disabled
-	if level, ok := profileStatus["was"].(int32); ok && level == 0 {
+	if level, ok := toInt64(profileStatus["was"]); ok && level == 0 {
 		return
 	}
 
@@ -164,7 +236,12 @@ func (c *ProfileCollector) collectDatabaseProfileMetrics(ctx context.Context, ch
 		}},
 	}
 
-	cursor, err := collection.Find(ctx, filter, options.Find().SetSort(bson.D{{"ts", -1}}))
+	findOptions := options.Find().
+		SetSort(bson.D{{"ts", -1}}).
+		SetBatchSize(profileBatchSize)
+	findOptions.MaxTime = maxTime(ctx)
+
+	cursor, err := c.find(ctx, "profile", collection, filter, findOptions)
 	if err != nil {
 		c.logger.Debug("Failed to query profile collection",
 			zap.String("database", dbName),
@@ -173,101 +250,192 @@ func (c *ProfileCollector) collectDatabaseProfileMetrics(ctx context.Context, ch
 	}
 	defer cursor.Close(ctx)
 
-	// Process profile entries
-	var profileEntries []bson.M
-	if err := cursor.All(ctx, &profileEntries); err != nil {
-		c.logger.Error("Failed to decode profile entries",
+	// Aggregate profile entries as they stream in rather than holding them all in memory
+	aggregation := newProfileAggregation(c.budget)
+	for cursor.Next(ctx) {
+		var entry bson.M
+		if err := cursor.Decode(&entry); err != nil {
+			c.logger.Debug("Failed to decode profile entry",
+				zap.String("database", dbName),
+				zap.Error(err))
+			continue
+		}
+		stats := c.aggregateProfileEntry(aggregation, entry)
+		c.observeDuration(entry, append(c.sourceLabelValues(instance, dbName), stats.Operation, stats.Collection)...)
+	}
+
+	// On deadline, emit what was aggregated so far instead of nothing
+	if err := cursor.Err(); err != nil {
+		c.logger.Warn("Profile scan stopped early, emitting partial aggregation",
 			zap.String("database", dbName),
+			zap.Int("operation_keys", len(aggregation.operationStats)),
 			zap.Error(err))
-		return
 	}
 
-	// Aggregate metrics from profile entries
-	c.aggregateProfileMetrics(ch, profileEntries, dbName, instance)
+	if aggregation.overflowed {
+		c.logger.Debug("Profile aggregation exceeded its memory budget, overflow bucketed as \"other\"",
+			zap.String("database", dbName),
+			zap.Int("max_tracked_operations", c.budget.maxOperations),
+			zap.Int("max_plan_summaries", c.budget.maxPlanSummaries),
+			zap.Int("max_lock_types", c.budget.maxLockTypes))
+	}
+
+	c.emitOperationMetrics(ch, aggregation.operationStats, dbName, instance)
+	c.emitPlanSummaryMetrics(ch, aggregation.planSummaryStats, dbName, instance)
 }
 
-func (c *ProfileCollector) aggregateProfileMetrics(ch chan<- prometheus.Metric, entries []bson.M, dbName string, instance map[string]string) {
-	operationStats := make(map[string]*OperationStats)
-	planSummaryStats := make(map[string]int64)
+// profileBatchSize bounds the number of profile entries fetched per cursor round trip
+const profileBatchSize = 500
 
-	for _, entry := range entries {
-		op := c.extractOperationType(entry)
-		collection := c.extractCollection(entry)
-		key := op + ":" + collection
+const (
+	defaultProfileMaxOperations    = 1000
+	defaultProfileMaxPlanSummaries = 200
+	defaultProfileMaxLockTypes     = 16
 
-		if _, exists := operationStats[key]; !exists {
-			operationStats[key] = &OperationStats{
-				Operation:  op,
-				Collection: collection,
-			}
-		}
+	// profileOverflowLabel replaces label values once a profile budget is exhausted
+	profileOverflowLabel = "other"
+)
+
+// profileBudget caps the distinct keys tracked while aggregating one database's profile
+type profileBudget struct {
+	maxOperations    int
+	maxPlanSummaries int
+	maxLockTypes     int
+}
 
-		stats := operationStats[key]
-		stats.Count++
+// profileAggregation accumulates per-(operation, collection) statistics across profile entries
+type profileAggregation struct {
+	budget           profileBudget
+	operationStats   map[string]*OperationStats
+	planSummaryStats map[string]int64
+	overflowed       bool
+}
 
-		// Duration
-		if millis, ok := entry["millis"].(int64); ok {
-			stats.TotalDurationMs += millis
-			if millis > stats.MaxDurationMs {
-				stats.MaxDurationMs = millis
-			}
+func newProfileAggregation(budget profileBudget) *profileAggregation {
+	return &profileAggregation{
+		budget:           budget,
+		operationStats:   make(map[string]*OperationStats),
+		planSummaryStats: make(map[string]int64),
+	}
+}
+
+// operationKey returns the aggregation key for op and collection, folding new
+// collections into "other" once maxOperations keys are tracked
+func (a *profileAggregation) operationKey(op, collection string) (string, string) {
+	key := op + ":" + collection
+	if _, exists := a.operationStats[key]; exists || len(a.operationStats) < a.budget.maxOperations {
+		return key, collection
+	}
+
+	a.overflowed = true
+	return op + ":" + profileOverflowLabel, profileOverflowLabel
+}
+
+// planSummaryKey returns planSummary, or "other" once maxPlanSummaries are tracked
+func (a *profileAggregation) planSummaryKey(planSummary string) string {
+	if _, exists := a.planSummaryStats[planSummary]; exists || len(a.planSummaryStats) < a.budget.maxPlanSummaries {
+		return planSummary
+	}
+
+	a.overflowed = true
+	return profileOverflowLabel
+}
+
+// observeDuration records the entry's duration in the summary and histogram, if enabled
+func (c *ProfileCollector) observeDuration(entry bson.M, labels ...string) {
+	if c.durations == nil && c.histogram == nil {
+		return
+	}
+	millis, ok := toInt64(entry["millis"])
+	if !ok {
+		return
+	}
+	seconds := float64(millis) / 1000
+
+	if c.durations != nil {
+		c.durations.WithLabelValues(labels...).Observe(seconds)
+	}
+	if c.histogram != nil {
+		observer := c.histogram.WithLabelValues(labels...)
+		if exemplar := profileExemplar(entry); len(exemplar) > 0 {
+			observer.(prometheus.ExemplarObserver).ObserveWithExemplar(seconds, exemplar)
+		} else {
+			observer.Observe(seconds)
 		}
+	}
+}
 
-		// Execution stats
-		if execStats, ok := entry["execStats"].(bson.M); ok {
-			if examined, ok := execStats["totalDocsExamined"].(int64); ok {
-				stats.TotalDocsExamined += examined
-			}
-			if returned, ok := execStats["totalDocsReturned"].(int64); ok {
-				stats.TotalDocsReturned += returned
-			}
-			if keysExamined, ok := execStats["totalKeysExamined"].(int64); ok {
-				stats.TotalKeysExamined += keysExamined
-			}
+// aggregateProfileEntry folds entry into aggregation and returns the statistics it was counted in
+func (c *ProfileCollector) aggregateProfileEntry(aggregation *profileAggregation, entry bson.M) *OperationStats {
+	operationStats := aggregation.operationStats
+	planSummaryStats := aggregation.planSummaryStats
+
+	op := c.extractOperationType(entry)
+	key, collection := aggregation.operationKey(op, c.extractCollection(entry))
+
+	if _, exists := operationStats[key]; !exists {
+		operationStats[key] = &OperationStats{
+			Operation:  op,
+			Collection: collection,
 		}
+	}
+
+	stats := operationStats[key]
+	stats.Count++
 
-		// Response length
-		if responseLength, ok := entry["responseLength"].(int64); ok {
-			stats.TotalResponseLength += responseLength
+	// Duration
+	if millis, ok := toInt64(entry["millis"]); ok {
+		stats.TotalDurationMs += millis
+		if millis > stats.MaxDurationMs {
+			stats.MaxDurationMs = millis
 		}
+	}
 
-		// Plan summary
-		if planSummary, ok := entry["planSummary"].(string); ok {
-			planSummaryStats[planSummary]++
+	// Execution stats
+	if execStats, ok := entry["execStats"].(bson.M); ok {
+		if examined, ok := toInt64(execStats["totalDocsExamined"]); ok {
+			stats.TotalDocsExamined += examined
+		}
+		if returned, ok := toInt64(execStats["totalDocsReturned"]); ok {
+			stats.TotalDocsReturned += returned
 		}
+		if keysExamined, ok := toInt64(execStats["totalKeysExamined"]); ok {
+			stats.TotalKeysExamined += keysExamined
+		}
+	}
 
-		// Lock statistics
-		c.collectLockStats(entry, stats)
+	// Response length
+	if responseLength, ok := toInt64(entry["responseLength"]); ok {
+		stats.TotalResponseLength += responseLength
+	}
 
-		// Write conflicts
-		if writeConflicts, ok := entry["writeConflicts"].(int64); ok {
-			stats.WriteConflicts += writeConflicts
-		}
+	// Plan summary
+	if planSummary, ok := entry["planSummary"].(string); ok {
+		planSummaryStats[aggregation.planSummaryKey(planSummary)]++
+	}
 
-		// Storage stats
-		c.collectStorageStats(entry, stats)
+	// Lock statistics
+	c.collectLockStats(entry, stats, aggregation)
 
-		// CPU time (if available)
-		if cpuTime, ok := entry["cpuNanos"].(int64); ok {
-			stats.CpuTimeMicros += cpuTime / 1000 // Convert nanos to micros
-		}
+	// Write conflicts
+	if writeConflicts, ok := toInt64(entry["writeConflicts"]); ok {
+		stats.WriteConflicts += writeConflicts
 	}
 
-	// Emit metrics
-	c.emitOperationMetrics(ch, operationStats, dbName, instance)
-	c.emitPlanSummaryMetrics(ch, planSummaryStats, dbName, instance)
+	// Storage stats
+	c.collectStorageStats(entry, stats)
+
+	// CPU time (if available)
+	if cpuTime, ok := toInt64(entry["cpuNanos"]); ok {
+		stats.CpuTimeMicros += cpuTime / 1000 // Convert nanos to micros
+	}
+
+	return stats
 }
 
 func (c *ProfileCollector) emitOperationMetrics(ch chan<- prometheus.Metric, stats map[string]*OperationStats, dbName string, instance map[string]string) {
 	for _, stat := range stats {
-		labels := []string{
-			instance["instance"],
-			instance["replica_set"],
-			instance["shard"],
-			dbName,
-			stat.Operation,
-			stat.Collection,
-		}
+		labels := append(c.sourceLabelValues(instance, dbName), stat.Operation, stat.Collection)
 
 		// Total operations
 		ch <- prometheus.MustNewConstMetric(
@@ -390,11 +558,7 @@ func (c *ProfileCollector) emitPlanSummaryMetrics(ch chan<- prometheus.Metric, p
 			c.descriptors["profile_plan_summary_total"],
 			prometheus.CounterValue,
 			float64(count),
-			instance["instance"],
-			instance["replica_set"],
-			instance["shard"],
-			dbName,
-			planSummary,
+			append(c.sourceLabelValues(instance, dbName), planSummary)...,
 		)
 	}
 }
@@ -427,7 +591,7 @@ func (c *ProfileCollector) extractCollection(entry bson.M) string {
 	return "unknown"
 }
 
-func (c *ProfileCollector) collectLockStats(entry bson.M, stats *OperationStats) {
+func (c *ProfileCollector) collectLockStats(entry bson.M, stats *OperationStats, aggregation *profileAggregation) {
 	if locks, ok := entry["locks"].(bson.M); ok {
 		if stats.LockStats == nil {
 			stats.LockStats = make(map[string]*LockStat)
@@ -435,6 +599,10 @@ func (c *ProfileCollector) collectLockStats(entry bson.M, stats *OperationStats)
 
 		for lockType, lockData := range locks {
 			if lockInfo, ok := lockData.(bson.M); ok {
+				if stats.LockStats[lockType] == nil && len(stats.LockStats) >= aggregation.budget.maxLockTypes {
+					aggregation.overflowed = true
+					lockType = profileOverflowLabel
+				}
 				if stats.LockStats[lockType] == nil {
 					stats.LockStats[lockType] = &LockStat{}
 				}
@@ -443,7 +611,7 @@ func (c *ProfileCollector) collectLockStats(entry bson.M, stats *OperationStats)
 
 				if acquireCount, ok := lockInfo["acquireCount"].(bson.M); ok {
 					for mode, count := range acquireCount {
-						if c, ok := count.(int64); ok && mode == "r" || mode == "w" {
+						if c, ok := toInt64(count); ok && mode == "r" || mode == "w" {
 							lockStat.AcquireCount += c
 						}
 					}
@@ -451,7 +619,7 @@ func (c *ProfileCollector) collectLockStats(entry bson.M, stats *OperationStats)
 
 				if timeAcquiring, ok := lockInfo["timeAcquiringMicros"].(bson.M); ok {
 					for mode, time := range timeAcquiring {
-						if t, ok := time.(int64); ok && mode == "r" || mode == "w" {
+						if t, ok := toInt64(time); ok && mode == "r" || mode == "w" {
 							lockStat.TimeAcquiringMicros += t
 						}
 					}
@@ -459,7 +627,7 @@ func (c *ProfileCollector) collectLockStats(entry bson.M, stats *OperationStats)
 
 				if acquireWaitCount, ok := lockInfo["acquireWaitCount"].(bson.M); ok {
 					for mode, count := range acquireWaitCount {
-						if c, ok := count.(int64); ok && mode == "r" || mode == "w" {
+						if c, ok := toInt64(count); ok && mode == "r" || mode == "w" {
 							lockStat.AcquireWaitCount += c
 						}
 					}
@@ -482,7 +650,7 @@ func (c *ProfileCollector) collectStorageStats(entry bson.M, stats *OperationSta
 		}
 
 		for _, metric := range storageMetrics {
-			if value, ok := storage[metric].(int64); ok {
+			if value, ok := toInt64(storage[metric]); ok {
 				stats.StorageStats[metric] += value
 			}
 		}
@@ -490,6 +658,12 @@ func (c *ProfileCollector) collectStorageStats(entry bson.M, stats *OperationSta
 }
 
 func (c *ProfileCollector) shouldSkipDatabase(dbName string) bool {
+	// Only the configured databases are profiled when a selection is set,
+	// which is also how system databases can be requested explicitly
+	if len(c.databases) > 0 {
+		return !c.matchesDatabase(dbName)
+	}
+
 	// Skip admin, config, and local databases unless explicitly requested
 	systemDatabases := []string{"admin", "config", "local"}
 	for _, sysDB := range systemDatabases {
@@ -526,6 +700,12 @@ func (c *ProfileCollector) Describe(ch chan<- *prometheus.Desc) {
 	for _, desc := range c.descriptors {
 		ch <- desc
 	}
+	if c.durations != nil {
+		c.durations.Describe(ch)
+	}
+	if c.histogram != nil {
+		c.histogram.Describe(ch)
+	}
 }
 
 func (c *ProfileCollector) Name() string {
@@ -0,0 +1,118 @@
+package collector
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/jimohabdol/mongodb-exporter/database"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// Profile member scopes. When every replica set member runs an exporter,
+// "all" lets each of them report the same operations; the others collect
+// system.profile from one member only and label entries with it.
+const (
+	// profileScopeAll reads system.profile wherever the client routes it (default)
+	profileScopeAll = "all"
+	// profileScopePrimary collects only while the connected member is the primary
+	profileScopePrimary = "primary"
+	// profileScopeSelf collects from the directly connected member, whatever its state
+	profileScopeSelf = "self"
+)
+
+// profileSource is the member system.profile is read from
+type profileSource struct {
+	member  string
+	primary bool
+}
+
+// currentSource asks the connected member who it is and whether it is the primary
+func (c *ProfileCollector) currentSource(ctx context.Context) (profileSource, error) {
+	hello, err := database.Hello(ctx, c.adminCommand("profile"))
+	if err != nil {
+		return profileSource{}, err
+	}
+
+	source := profileSource{member: "unknown"}
+	if me, ok := hello["me"].(string); ok && me != "" {
+		source.member = me
+	}
+	if primary, ok := hello["isWritablePrimary"].(bool); ok {
+		source.primary = primary
+	} else if primary, ok := hello["ismaster"].(bool); ok {
+		source.primary = primary
+	}
+	return source, nil
+}
+
+// checkSource reports whether this run should read system.profile under the
+// configured member scope, adding the member to instance and exporting
+// whether it is the active source
+func (c *ProfileCollector) checkSource(ctx context.Context, ch chan<- prometheus.Metric, instance map[string]string) bool {
+	if c.memberScope == profileScopeAll {
+		return true
+	}
+
+	source, err := c.currentSource(ctx)
+	if err != nil {
+		c.logCommandError("Failed to identify the member to read profiles from", err)
+		return false
+	}
+	instance["member"] = source.member
+
+	active := c.memberScope == profileScopeSelf || source.primary
+	value := 0.0
+	if active {
+		value = 1
+	}
+	ch <- prometheus.MustNewConstMetric(
+		c.descriptors["profile_source_active"],
+		prometheus.GaugeValue,
+		value,
+		instance["instance"],
+		instance["replica_set"],
+		instance["shard"],
+		instance["member"],
+	)
+
+	if !active {
+		c.logger.Debug("Skipping profile collection on a non-primary member",
+			zap.String("member", source.member))
+	}
+	return active
+}
+
+// sourceLabelValues returns the leading label values of every profile series
+func (c *ProfileCollector) sourceLabelValues(instance map[string]string, dbName string) []string {
+	if c.memberScope == profileScopeAll {
+		return []string{instance["instance"], instance["replica_set"], instance["shard"], dbName}
+	}
+	return []string{instance["instance"], instance["replica_set"], instance["shard"], instance["member"], dbName}
+}
+
+// compileDatabasePatterns turns the configured database names into anchored
+// patterns, so plain names match exactly and regular expressions match whole names
+func compileDatabasePatterns(logger *zap.Logger, patterns []string) []*regexp.Regexp {
+	var compiled []*regexp.Regexp
+	for _, pattern := range patterns {
+		re, err := regexp.Compile("^(?:" + pattern + ")$")
+		if err != nil {
+			logger.Warn("Ignoring invalid profile database pattern",
+				zap.String("pattern", pattern), zap.Error(err))
+			continue
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled
+}
+
+// matchesDatabase reports whether dbName is selected by the configured patterns
+func (c *ProfileCollector) matchesDatabase(dbName string) bool {
+	for _, re := range c.databases {
+		if re.MatchString(dbName) {
+			return true
+		}
+	}
+	return false
+}
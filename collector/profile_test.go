@@ -0,0 +1,144 @@
+package collector
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.uber.org/zap"
+)
+
+func TestProfileAggregationBudget(t *testing.T) {
+	config := CollectorConfig{
+		EnabledMetrics: []string{"profile"},
+		Collectors: map[string]interface{}{
+			"profile": map[string]interface{}{
+				"max_tracked_operations": 2,
+				"max_plan_summaries":     1,
+			},
+		},
+	}
+	collector := NewProfileCollector(nil, zap.NewNop(), config)
+
+	aggregation := newProfileAggregation(collector.budget)
+	for _, ns := range []string{"app.users", "app.orders", "app.events", "app.sessions"} {
+		collector.aggregateProfileEntry(aggregation, bson.M{
+			"op":          "query",
+			"ns":          ns,
+			"millis":      int64(120),
+			"planSummary": "IXSCAN { " + ns + ": 1 }",
+		})
+	}
+
+	if len(aggregation.operationStats) != 3 {
+		t.Errorf("Expected 2 tracked keys plus the overflow bucket, got %d", len(aggregation.operationStats))
+	}
+
+	overflow, ok := aggregation.operationStats["query:"+profileOverflowLabel]
+	if !ok {
+		t.Fatal("Expected overflow operations to be bucketed under other")
+	}
+	if overflow.Count != 2 || overflow.Collection != profileOverflowLabel {
+		t.Errorf("Expected 2 overflow operations on collection other, got %d on %s", overflow.Count, overflow.Collection)
+	}
+
+	if aggregation.planSummaryStats[profileOverflowLabel] != 3 {
+		t.Errorf("Expected 3 plan summaries bucketed as other, got %d", aggregation.planSummaryStats[profileOverflowLabel])
+	}
+
+	if !aggregation.overflowed {
+		t.Error("Aggregation should report that its budget overflowed")
+	}
+}
+
+func TestProfileDurationSummary(t *testing.T) {
+	if collector := NewProfileCollector(nil, zap.NewNop(), CollectorConfig{}); collector.durations != nil {
+		t.Error("Duration summary should be off unless objectives are configured")
+	}
+
+	config := CollectorConfig{
+		Collectors: map[string]interface{}{
+			"profile": map[string]interface{}{
+				"duration_objectives": map[float64]float64{0.9: 0.01},
+			},
+		},
+	}
+	collector := NewProfileCollector(nil, zap.NewNop(), config)
+	if collector.durations == nil {
+		t.Fatal("Duration summary should be created from the configured objectives")
+	}
+
+	collector.durations.WithLabelValues("host:27017", "", "", "app", "query", "orders").Observe(0.25)
+	descs := make(chan *prometheus.Desc, 32)
+	collector.Describe(descs)
+	close(descs)
+
+	found := false
+	for desc := range descs {
+		if descName(desc) == "mongodb_profile_operations_duration_summary_seconds" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("Describe should include the duration summary")
+	}
+}
+
+func TestProfileMemberScopeLabels(t *testing.T) {
+	instance := map[string]string{"instance": "db-1:27017", "replica_set": "rs0", "member": "db-1:27017"}
+
+	collector := NewProfileCollector(nil, zap.NewNop(), CollectorConfig{})
+	if values := collector.sourceLabelValues(instance, "app"); len(values) != 4 {
+		t.Errorf("Default scope should not add a member label, got %v", values)
+	}
+	if _, ok := collector.descriptors["profile_source_active"]; ok {
+		t.Error("Default scope should not export the source gauge")
+	}
+
+	config := CollectorConfig{
+		Collectors: map[string]interface{}{
+			"profile": map[string]interface{}{
+				"member_scope":        profileScopeSelf,
+				"duration_objectives": map[float64]float64{0.9: 0.01},
+			},
+		},
+	}
+	collector = NewProfileCollector(nil, zap.NewNop(), config)
+	values := collector.sourceLabelValues(instance, "app")
+	if len(values) != 5 || values[3] != "db-1:27017" || values[4] != "app" {
+		t.Errorf("Scoped collection should label the source member before the database, got %v", values)
+	}
+	if _, ok := collector.descriptors["profile_source_active"]; !ok {
+		t.Error("Scoped collection should export the source gauge")
+	}
+
+	// Label cardinality must match the descriptors, or observing panics
+	collector.observeDuration(bson.M{"millis": int32(250)}, append(values, "query", "orders")...)
+}
+
+func TestProfileDatabaseSelection(t *testing.T) {
+	collector := NewProfileCollector(nil, zap.NewNop(), CollectorConfig{})
+	if !collector.shouldSkipDatabase("admin") || collector.shouldSkipDatabase("orders") {
+		t.Error("Without a selection only system databases should be skipped")
+	}
+
+	config := CollectorConfig{
+		Collectors: map[string]interface{}{
+			"profile": map[string]interface{}{
+				"databases": []string{"orders", "tenant_[0-9]+", "admin", "("},
+			},
+		},
+	}
+	collector = NewProfileCollector(nil, zap.NewNop(), config)
+
+	for _, dbName := range []string{"orders", "tenant_42", "admin"} {
+		if collector.shouldSkipDatabase(dbName) {
+			t.Errorf("Database %q should be profiled when selected", dbName)
+		}
+	}
+	for _, dbName := range []string{"orders_archive", "tenant_x", "inventory", "local"} {
+		if !collector.shouldSkipDatabase(dbName) {
+			t.Errorf("Database %q should be skipped when not selected", dbName)
+		}
+	}
+}
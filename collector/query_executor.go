@@ -1,7 +1,6 @@
 package collector
 
 import (
-	"context"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -19,19 +18,19 @@ func NewQueryExecutorCollector(client *mongo.Client, logger *zap.Logger, config
 	labels := []string{"instance", "replica_set", "shard"}
 	descriptors := map[string]*prometheus.Desc{
 		"query_executor_total": prometheus.NewDesc(
-			"mongodb_metrics_query_executor_total",
+			config.metricName("mongodb_metrics_query_executor_total"),
 			"Total number of query executor operations",
 			labels,
 			nil,
 		),
 		"scanned_total": prometheus.NewDesc(
-			"mongodb_metrics_query_executor_scanned_total",
+			config.metricName("mongodb_metrics_query_executor_scanned_total"),
 			"Total number of documents scanned by query executor",
 			labels,
 			nil,
 		),
 		"scanned_objects_total": prometheus.NewDesc(
-			"mongodb_metrics_query_executor_scanned_objects_total",
+			config.metricName("mongodb_metrics_query_executor_scanned_objects_total"),
 			"Total number of objects scanned by query executor",
 			labels,
 			nil,
@@ -49,12 +48,12 @@ func (c *QueryExecutorCollector) Collect(ch chan<- prometheus.Metric) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+	ctx, done := c.collectContext("query_executor", 10*time.Second)
+	defer done()
 
 	var result bson.M
-	if err := c.client.Database("admin").RunCommand(ctx, bson.D{{"serverStatus", 1}}).Decode(&result); err != nil {
-		c.logger.Error("Failed to collect query executor metrics", zap.Error(err))
+	if err := c.runCommand(ctx, "query_executor", c.client.Database("admin"), serverStatusCommand("metrics"), &result); err != nil {
+		c.logCommandError("Failed to collect query executor metrics", err)
 		return
 	}
 
@@ -63,7 +62,7 @@ func (c *QueryExecutorCollector) Collect(ch chan<- prometheus.Metric) {
 	if metrics, ok := result["metrics"].(bson.M); ok {
 		if queryExecutor, ok := metrics["queryExecutor"].(bson.M); ok {
 			// Total queries
-			if total, ok := queryExecutor["scanned"].(int64); ok {
+			if total, ok := toInt64(queryExecutor["scanned"]); ok {
 				ch <- prometheus.MustNewConstMetric(
 					c.descriptors["query_executor_total"],
 					prometheus.CounterValue,
@@ -75,7 +74,7 @@ func (c *QueryExecutorCollector) Collect(ch chan<- prometheus.Metric) {
 			}
 
 			// Scanned documents
-			if scanned, ok := queryExecutor["scanned"].(int64); ok {
+			if scanned, ok := toInt64(queryExecutor["scanned"]); ok {
 				ch <- prometheus.MustNewConstMetric(
 					c.descriptors["scanned_total"],
 					prometheus.CounterValue,
@@ -87,7 +86,7 @@ func (c *QueryExecutorCollector) Collect(ch chan<- prometheus.Metric) {
 			}
 
 			// Scanned objects
-			if scannedObjects, ok := queryExecutor["scannedObjects"].(int64); ok {
+			if scannedObjects, ok := toInt64(queryExecutor["scannedObjects"]); ok {
 				ch <- prometheus.MustNewConstMetric(
 					c.descriptors["scanned_objects_total"],
 					prometheus.CounterValue,
@@ -146,34 +145,34 @@ func (c *QueryExecutorCollector) collectQueryExecutorMetrics(ch chan<- prometheu
 }
 
 func (c *QueryExecutorCollector) collectScannedMetrics(ch chan<- prometheus.Metric, queryExecutor bson.M, labels prometheus.Labels) {
-	if scanned, ok := queryExecutor["scanned"].(int64); ok {
+	if scanned, ok := toInt64(queryExecutor["scanned"]); ok {
 		ch <- prometheus.MustNewConstMetric(c.descriptors["metrics_query_executor_scanned_total"], prometheus.CounterValue, float64(scanned), labels["instance"], labels["replica_set"], labels["shard"])
 	}
 
-	if scannedObjects, ok := queryExecutor["scannedObjects"].(int64); ok {
+	if scannedObjects, ok := toInt64(queryExecutor["scannedObjects"]); ok {
 		ch <- prometheus.MustNewConstMetric(c.descriptors["metrics_query_executor_scanned_objects_total"], prometheus.CounterValue, float64(scannedObjects), labels["instance"], labels["replica_set"], labels["shard"])
 	}
 }
 
 func (c *QueryExecutorCollector) collectPlanCacheMetrics(ch chan<- prometheus.Metric, queryExecutor bson.M, labels prometheus.Labels) {
 	if planCache, ok := queryExecutor["planCache"].(bson.M); ok {
-		if hits, ok := planCache["hits"].(int64); ok {
+		if hits, ok := toInt64(planCache["hits"]); ok {
 			ch <- prometheus.MustNewConstMetric(c.descriptors["metrics_query_executor_plan_cache_hits_total"], prometheus.CounterValue, float64(hits), labels["instance"], labels["replica_set"], labels["shard"])
 		}
 
-		if misses, ok := planCache["misses"].(int64); ok {
+		if misses, ok := toInt64(planCache["misses"]); ok {
 			ch <- prometheus.MustNewConstMetric(c.descriptors["metrics_query_executor_plan_cache_misses_total"], prometheus.CounterValue, float64(misses), labels["instance"], labels["replica_set"], labels["shard"])
 		}
 
-		if evictions, ok := planCache["evictions"].(int64); ok {
+		if evictions, ok := toInt64(planCache["evictions"]); ok {
 			ch <- prometheus.MustNewConstMetric(c.descriptors["metrics_query_executor_plan_cache_evictions_total"], prometheus.CounterValue, float64(evictions), labels["instance"], labels["replica_set"], labels["shard"])
 		}
 
-		if entries, ok := planCache["entries"].(int64); ok {
+		if entries, ok := toInt64(planCache["entries"]); ok {
 			ch <- prometheus.MustNewConstMetric(c.descriptors["metrics_query_executor_plan_cache_entries"], prometheus.GaugeValue, float64(entries), labels["instance"], labels["replica_set"], labels["shard"])
 		}
 
-		if size, ok := planCache["size"].(int64); ok {
+		if size, ok := toInt64(planCache["size"]); ok {
 			ch <- prometheus.MustNewConstMetric(c.descriptors["metrics_query_executor_plan_cache_size_bytes"], prometheus.GaugeValue, float64(size), labels["instance"], labels["replica_set"], labels["shard"])
 		}
 	}
@@ -0,0 +1,64 @@
+package collector
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// readPreferenceMembers maps the readPreferenceCounters sections to the role
+// of the member that executed the operations
+var readPreferenceMembers = map[string]string{
+	"executedOnPrimary":   "primary",
+	"executedOnSecondary": "secondary",
+}
+
+// readPreferenceDescriptors describes the per read preference operation counters
+func readPreferenceDescriptors(config CollectorConfig) map[string]*prometheus.Desc {
+	return map[string]*prometheus.Desc{
+		"read_preference_operations_total": prometheus.NewDesc(
+			config.metricName("mongodb_read_preference_operations_total"),
+			"Operations executed on this member by the read preference mode they were sent with and by whether they came from clients (external) or other members (internal)",
+			[]string{"instance", "replica_set", "shard", "executed_on", "mode", "origin"},
+			nil,
+		),
+	}
+}
+
+// collectReadPreferenceCounters exports serverStatus readPreferenceCounters
+// on servers recent enough to report them. A secondary
+// whose secondary and secondaryPreferred counters stay flat is not receiving
+// the reads that clients were configured to send it
+func (c *ServerStatusCollector) collectReadPreferenceCounters(ch chan<- prometheus.Metric, result bson.M, instance map[string]string) {
+	counters, ok := result["readPreferenceCounters"].(bson.M)
+	if !ok {
+		return
+	}
+
+	for section, executedOn := range readPreferenceMembers {
+		modes, ok := counters[section].(bson.M)
+		if !ok {
+			continue
+		}
+		for mode, value := range modes {
+			origins, ok := value.(bson.M)
+			if !ok {
+				continue
+			}
+			for _, origin := range []string{"internal", "external"} {
+				if count := c.getNumericValue(origins[origin]); count != nil {
+					ch <- prometheus.MustNewConstMetric(
+						c.descriptors["read_preference_operations_total"],
+						prometheus.CounterValue,
+						*count,
+						instance["instance"],
+						instance["replica_set"],
+						instance["shard"],
+						executedOn,
+						mode,
+						origin,
+					)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ErrCollectorNotScheduled is returned when refreshing a collector that has no
+// interval and so runs on every scrape already
+var ErrCollectorNotScheduled = errors.New("collector runs on every scrape")
+
+// ErrShuttingDown is returned when a collector run is requested after shutdown has begun
+var ErrShuttingDown = errors.New("collector manager is shutting down")
+
+// collectorAliases maps the percona collector names an operator may use on the
+// admin API onto the collectors here
+var collectorAliases = map[string]string{
+	"indexstats":       "index_stats",
+	"collection_stats": "collstats",
+}
+
+// RefreshResult reports one forced collector run
+type RefreshResult struct {
+	Name     string  `json:"name"`
+	Series   int     `json:"series"`
+	Duration float64 `json:"duration_seconds"`
+	Error    string  `json:"error,omitempty"`
+}
+
+// RefreshCollectors runs the named scheduled collectors immediately and
+// replaces their cached metrics, so an operator can get fresh values without
+// waiting for the interval. No names refreshes every scheduled collector.
+// Names are all checked before anything runs
+func (cm *CollectorManager) RefreshCollectors(names []string) ([]RefreshResult, error) {
+	var targets []*scheduledCollector
+	if len(names) == 0 {
+		for _, collector := range cm.available {
+			if scheduled, ok := collector.(*scheduledCollector); ok && cm.isCollectorEnabled(scheduled.Name()) {
+				targets = append(targets, scheduled)
+			}
+		}
+	}
+	for _, name := range names {
+		if alias, ok := collectorAliases[name]; ok {
+			name = alias
+		}
+		collector := cm.findCollector(name)
+		if collector == nil {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownCollector, name)
+		}
+		if !cm.isCollectorEnabled(name) {
+			return nil, fmt.Errorf("%w: %s", ErrCollectorDisabledByConfig, name)
+		}
+		scheduled, ok := collector.(*scheduledCollector)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrCollectorNotScheduled, name)
+		}
+		targets = append(targets, scheduled)
+	}
+
+	results := make([]RefreshResult, len(targets))
+	var wg sync.WaitGroup
+	for i, scheduled := range targets {
+		wg.Add(1)
+		go func(i int, scheduled *scheduledCollector) {
+			defer wg.Done()
+			start := time.Now()
+			scheduled.expire()
+			series, err := cm.multiCollector.runNow(scheduled)
+			results[i] = RefreshResult{
+				Name:     scheduled.Name(),
+				Series:   series,
+				Duration: time.Since(start).Seconds(),
+			}
+			if err != nil {
+				results[i].Error = err.Error()
+			}
+		}(i, scheduled)
+	}
+	wg.Wait()
+	return results, nil
+}
+
+// runNow runs one collector outside a scrape, with the shutdown tracking,
+// watchdog, panic recovery and failure counting scrapes get. It returns how
+// many series the run produced
+func (mc *MultiCollector) runNow(c Collector) (int, error) {
+	mc.mu.Lock()
+	limiter := newSeriesLimiter(mc.maxSeriesPerMetric, mc.seriesLimits, mc.seriesDropped)
+	tracer := mc.tracer
+	lifetime := mc.lifetime
+	watchdog := mc.watchdog
+	mc.mu.Unlock()
+
+	if lifetime != nil && !lifetime.start() {
+		return 0, ErrShuttingDown
+	}
+
+	ctx := context.Background()
+	if tracer != nil {
+		var span trace.Span
+		ctx, span = tracer.startScrape()
+		defer span.End()
+	}
+
+	var errs []error
+	var errsMu sync.Mutex
+	reportError := func(err error) {
+		errsMu.Lock()
+		errs = append(errs, err)
+		errsMu.Unlock()
+	}
+
+	ch := make(chan prometheus.Metric)
+	counted := make(chan int)
+	go func() {
+		count := 0
+		for range ch {
+			count++
+		}
+		counted <- count
+	}()
+
+	mc.run(ctx, c, ch, limiter, tracer, lifetime, watchdog, reportError)
+	close(ch)
+	series := <-counted
+
+	errsMu.Lock()
+	defer errsMu.Unlock()
+	return series, errors.Join(errs...)
+}
@@ -0,0 +1,143 @@
+package collector
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// replMetricField maps one numeric field under serverStatus metrics.repl to a
+// descriptor; scale converts milliseconds to seconds where needed
+type replMetricField struct {
+	path      []string
+	descKey   string
+	valueType prometheus.ValueType
+	scale     float64
+}
+
+// replMetricFields are the secondary fetch and apply throughput counters
+var replMetricFields = []replMetricField{
+	{[]string{"apply", "batches", "num"}, "repl_apply_batches_total", prometheus.CounterValue, 1},
+	{[]string{"apply", "batches", "totalMillis"}, "repl_apply_batches_seconds_total", prometheus.CounterValue, 0.001},
+	{[]string{"apply", "ops"}, "repl_apply_ops_total", prometheus.CounterValue, 1},
+	{[]string{"network", "bytes"}, "repl_network_bytes_total", prometheus.CounterValue, 1},
+	{[]string{"network", "ops"}, "repl_network_ops_total", prometheus.CounterValue, 1},
+	{[]string{"network", "getmores", "num"}, "repl_network_getmores_total", prometheus.CounterValue, 1},
+	{[]string{"network", "getmores", "totalMillis"}, "repl_network_getmores_seconds_total", prometheus.CounterValue, 0.001},
+	{[]string{"network", "getmores", "numEmptyBatches"}, "repl_network_getmores_empty_batches_total", prometheus.CounterValue, 1},
+	{[]string{"network", "readersCreated"}, "repl_network_readers_created_total", prometheus.CounterValue, 1},
+}
+
+// replMetricsDescriptors describes the metrics.repl buffer, apply and network metrics
+func replMetricsDescriptors(config CollectorConfig) map[string]*prometheus.Desc {
+	labels := []string{"instance", "replica_set", "shard"}
+	bufferLabels := []string{"instance", "replica_set", "shard", "buffer"}
+
+	descriptors := map[string]*prometheus.Desc{
+		"repl_buffer_count": prometheus.NewDesc(
+			config.metricName("mongodb_repl_buffer_count"),
+			"Number of fetched oplog entries waiting in the replication buffer",
+			bufferLabels,
+			nil,
+		),
+		"repl_buffer_size_bytes": prometheus.NewDesc(
+			config.metricName("mongodb_repl_buffer_size_bytes"),
+			"Size of the fetched oplog entries waiting in the replication buffer",
+			bufferLabels,
+			nil,
+		),
+		"repl_buffer_max_size_bytes": prometheus.NewDesc(
+			config.metricName("mongodb_repl_buffer_max_size_bytes"),
+			"Maximum size of the replication buffer",
+			bufferLabels,
+			nil,
+		),
+	}
+
+	help := map[string]string{
+		"repl_apply_batches_total":                  "Oplog application batches applied by this secondary",
+		"repl_apply_batches_seconds_total":          "Time spent applying oplog batches",
+		"repl_apply_ops_total":                      "Oplog entries applied by this secondary",
+		"repl_network_bytes_total":                  "Bytes of oplog fetched from the sync source",
+		"repl_network_ops_total":                    "Oplog entries fetched from the sync source",
+		"repl_network_getmores_total":               "getMore requests sent to the sync source to fetch oplog",
+		"repl_network_getmores_seconds_total":       "Time spent waiting for oplog getMore responses",
+		"repl_network_getmores_empty_batches_total": "Oplog getMore responses that returned no entries",
+		"repl_network_readers_created_total":        "Oplog query processes created, one per sync source change or fetch restart",
+	}
+	for _, field := range replMetricFields {
+		descriptors[field.descKey] = prometheus.NewDesc(
+			config.metricName("mongodb_"+field.descKey),
+			help[field.descKey],
+			labels,
+			nil,
+		)
+	}
+	return descriptors
+}
+
+// replBuffers returns the replication buffers by name. MongoDB 7.0 split the
+// single buffer into a write buffer and an apply buffer; the older single
+// buffer is reported as "oplog"
+func replBuffers(repl bson.M) map[string]bson.M {
+	buffer, ok := repl["buffer"].(bson.M)
+	if !ok {
+		return nil
+	}
+	if _, legacy := buffer["count"]; legacy {
+		return map[string]bson.M{"oplog": buffer}
+	}
+
+	buffers := make(map[string]bson.M)
+	for name, value := range buffer {
+		if section, ok := value.(bson.M); ok {
+			buffers[name] = section
+		}
+	}
+	return buffers
+}
+
+// collectReplMetrics exports serverStatus metrics.repl, the fetch and apply
+// throughput of a secondary; primaries report the same fields, mostly at zero
+func (c *ServerStatusCollector) collectReplMetrics(ch chan<- prometheus.Metric, result bson.M, instance map[string]string) {
+	metrics, ok := result["metrics"].(bson.M)
+	if !ok {
+		return
+	}
+	repl, ok := metrics["repl"].(bson.M)
+	if !ok {
+		return
+	}
+
+	labels := []string{instance["instance"], instance["replica_set"], instance["shard"]}
+
+	for name, buffer := range replBuffers(repl) {
+		for key, descKey := range map[string]string{
+			"count":        "repl_buffer_count",
+			"sizeBytes":    "repl_buffer_size_bytes",
+			"maxSizeBytes": "repl_buffer_max_size_bytes",
+		} {
+			if value := c.getNumericValue(buffer[key]); value != nil {
+				ch <- prometheus.MustNewConstMetric(c.descriptors[descKey], prometheus.GaugeValue, *value, append(labels, name)...)
+			}
+		}
+	}
+
+	for _, field := range replMetricFields {
+		if value := c.getNumericValue(lookupPath(repl, field.path)); value != nil {
+			ch <- prometheus.MustNewConstMetric(c.descriptors[field.descKey], field.valueType, *value*field.scale, labels...)
+		}
+	}
+}
+
+// lookupPath follows nested documents and returns the value at path, or nil
+func lookupPath(doc bson.M, path []string) interface{} {
+	var value interface{} = doc
+	for _, key := range path {
+		section, ok := value.(bson.M)
+		if !ok {
+			return nil
+		}
+		value = section[key]
+	}
+	return value
+}
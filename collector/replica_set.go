@@ -2,6 +2,7 @@ package collector
 
 import (
 	"context"
+	"strconv"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -15,48 +16,78 @@ import (
 type ReplicaSetCollector struct {
 	*BaseCollector
 	descriptors map[string]*prometheus.Desc
+	oplogGrowth *oplogGrowthTracker
+	// sampleOplogTimestamps estimates oplog growth from the oldest entry when
+	// the used bytes cannot tell it, such as once the oplog is full
+	sampleOplogTimestamps bool
 }
 
 func NewReplicaSetCollector(client *mongo.Client, logger *zap.Logger, config CollectorConfig) *ReplicaSetCollector {
 	labels := []string{"instance", "replica_set", "shard"}
-	memberLabels := append(labels, "name", "state")
+	memberLabels := append(labels, "name", "state", "member_idx", "self")
 
 	descriptors := map[string]*prometheus.Desc{
 		"member_state": prometheus.NewDesc(
-			"mongodb_replset_member_state",
-			"State of the replica set member (1=Primary, 2=Secondary, 7=Arbiter)",
+			config.metricName("mongodb_replset_member_state"),
+			"State of the replica set member (0=Startup, 1=Primary, 2=Secondary, 3=Recovering, 5=Startup2, 6=Unknown, 7=Arbiter, 8=Down, 9=Rollback, 10=Removed)",
+			memberLabels,
+			nil,
+		),
+		"member_state_status": prometheus.NewDesc(
+			config.metricName("mongodb_replset_member_state_status"),
+			"Whether the replica set member is in the given state (1) or not (0), one series per known state",
 			memberLabels,
 			nil,
 		),
 		"member_health": prometheus.NewDesc(
-			"mongodb_replset_member_health",
+			config.metricName("mongodb_replset_member_health"),
 			"Health status of the replica set member (0=unhealthy, 1=healthy)",
 			memberLabels,
 			nil,
 		),
+		"member_replication_lag": prometheus.NewDesc(
+			config.metricName("mongodb_replset_member_replication_lag_seconds"),
+			"Seconds the secondary's last applied operation trails the primary's",
+			memberLabels,
+			nil,
+		),
+		"my_state": prometheus.NewDesc(
+			config.metricName("mongodb_replset_my_state"),
+			"Replica set state of the scraped node itself (same codes as mongodb_replset_member_state)",
+			labels,
+			nil,
+		),
 		"number_of_members": prometheus.NewDesc(
-			"mongodb_replset_number_of_members",
+			config.metricName("mongodb_replset_number_of_members"),
 			"Total number of members in the replica set",
 			labels,
 			nil,
 		),
 		"oplog_size_bytes": prometheus.NewDesc(
-			"mongodb_replset_oplog_size_bytes",
+			config.metricName("mongodb_replset_oplog_size_bytes"),
 			"Size of the oplog in bytes",
 			labels,
 			nil,
 		),
 		"oplog_head_timestamp": prometheus.NewDesc(
-			"mongodb_replset_oplog_head_timestamp",
+			config.metricName("mongodb_replset_oplog_head_timestamp"),
 			"Timestamp of the newest oplog entry",
 			labels,
 			nil,
 		),
+		"oplog_growth_bytes_per_hour": prometheus.NewDesc(
+			config.metricName("mongodb_replset_oplog_growth_bytes_per_hour"),
+			"Rate the oplog grows at in bytes per hour, from the change in its used bytes between scrapes",
+			labels,
+			nil,
+		),
 	}
 
 	return &ReplicaSetCollector{
-		BaseCollector: NewBaseCollector(client, logger, config),
-		descriptors:   descriptors,
+		BaseCollector:         NewBaseCollector(client, logger, config),
+		descriptors:           descriptors,
+		oplogGrowth:           newOplogGrowthTracker(),
+		sampleOplogTimestamps: getBoolOption(collectorOptions(config, "replica_set_status"), "sample_oplog_timestamps", false),
 	}
 }
 
@@ -65,23 +96,34 @@ func (c *ReplicaSetCollector) Collect(ch chan<- prometheus.Metric) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+	ctx, done := c.collectContext("replica_set_status", 10*time.Second)
+	defer done()
 
 	// Get replica set status
 	var replStatus bson.M
-	if err := c.client.Database("admin").RunCommand(ctx, bson.D{{"replSetGetStatus", 1}}).Decode(&replStatus); err != nil {
+	if err := c.runCommand(ctx, "replica_set_status", c.client.Database("admin"), bson.D{{"replSetGetStatus", 1}}, &replStatus); err != nil {
 		// If not a replica set, log at debug level and return
 		if err.Error() == "not running with --replSet" {
 			c.logger.Debug("Not running as replica set")
 			return
 		}
-		c.logger.Error("Failed to get replica set status", zap.Error(err))
+		c.logCommandError("Failed to get replica set status", err)
 		return
 	}
 
 	instance := c.getInstanceInfo(replStatus)
 
+	if myState, ok := toInt64(replStatus["myState"]); ok {
+		ch <- prometheus.MustNewConstMetric(
+			c.descriptors["my_state"],
+			prometheus.GaugeValue,
+			float64(myState),
+			instance["instance"],
+			instance["replica_set"],
+			instance["shard"],
+		)
+	}
+
 	// Number of members
 	if members, ok := replStatus["members"].(bson.A); ok {
 		ch <- prometheus.MustNewConstMetric(
@@ -93,12 +135,14 @@ func (c *ReplicaSetCollector) Collect(ch chan<- prometheus.Metric) {
 			instance["shard"],
 		)
 
+		lags := replicationLags(members)
+
 		// Member state and health
 		for _, m := range members {
 			if member, ok := m.(bson.M); ok {
 				name, ok1 := member["name"].(string)
-				state, ok2 := member["state"].(int32)
-				health, ok3 := member["health"].(int32)
+				state, ok2 := toInt64(member["state"])
+				health, ok3 := toInt64(member["health"])
 
 				if !ok1 || !ok2 || !ok3 {
 					c.logger.Warn("Invalid member data",
@@ -109,6 +153,8 @@ func (c *ReplicaSetCollector) Collect(ch chan<- prometheus.Metric) {
 					continue
 				}
 
+				memberIdx, self := memberIdentity(member)
+
 				ch <- prometheus.MustNewConstMetric(
 					c.descriptors["member_state"],
 					prometheus.GaugeValue,
@@ -118,8 +164,29 @@ func (c *ReplicaSetCollector) Collect(ch chan<- prometheus.Metric) {
 					instance["shard"],
 					name,
 					c.getStateString(float64(state)),
+					memberIdx,
+					self,
 				)
 
+				for _, known := range replicaSetStates {
+					inState := 0.0
+					if int64(known.code) == state {
+						inState = 1.0
+					}
+					ch <- prometheus.MustNewConstMetric(
+						c.descriptors["member_state_status"],
+						prometheus.GaugeValue,
+						inState,
+						instance["instance"],
+						instance["replica_set"],
+						instance["shard"],
+						name,
+						known.name,
+						memberIdx,
+						self,
+					)
+				}
+
 				ch <- prometheus.MustNewConstMetric(
 					c.descriptors["member_health"],
 					prometheus.GaugeValue,
@@ -129,7 +196,24 @@ func (c *ReplicaSetCollector) Collect(ch chan<- prometheus.Metric) {
 					instance["shard"],
 					name,
 					c.getStateString(float64(state)),
+					memberIdx,
+					self,
 				)
+
+				if lag, ok := lags[name]; ok {
+					ch <- prometheus.MustNewConstMetric(
+						c.descriptors["member_replication_lag"],
+						prometheus.GaugeValue,
+						lag,
+						instance["instance"],
+						instance["replica_set"],
+						instance["shard"],
+						name,
+						c.getStateString(float64(state)),
+						memberIdx,
+						self,
+					)
+				}
 			}
 		}
 	}
@@ -141,12 +225,13 @@ func (c *ReplicaSetCollector) Collect(ch chan<- prometheus.Metric) {
 func (c *ReplicaSetCollector) collectOplogMetrics(ctx context.Context, ch chan<- prometheus.Metric, instance map[string]string) {
 	// Get oplog size
 	var oplogStats bson.M
-	if err := c.client.Database("local").RunCommand(ctx, bson.D{{"collStats", "oplog.rs"}}).Decode(&oplogStats); err != nil {
+	if err := c.runCommand(ctx, "replica_set_status", c.client.Database("local"), bson.D{{"collStats", "oplog.rs"}}, &oplogStats); err != nil {
 		c.logger.Debug("Failed to get oplog stats", zap.Error(err))
 		return
 	}
 
-	if size, ok := oplogStats["size"].(int64); ok {
+	size, hasSize := toInt64(oplogStats["size"])
+	if hasSize {
 		ch <- prometheus.MustNewConstMetric(
 			c.descriptors["oplog_size_bytes"],
 			prometheus.GaugeValue,
@@ -160,16 +245,57 @@ func (c *ReplicaSetCollector) collectOplogMetrics(ctx context.Context, ch chan<-
 	// Get latest oplog entry timestamp
 	var latestOplog bson.M
 	opts := options.FindOne().SetSort(bson.D{{"$natural", -1}})
-	if err := c.client.Database("local").Collection("oplog.rs").FindOne(ctx, bson.M{}, opts).Decode(&latestOplog); err != nil {
+	opts.MaxTime = maxTime(ctx)
+	if err := c.findOne(ctx, "replica_set_status", c.client.Database("local").Collection("oplog.rs"), bson.M{}, &latestOplog, opts); err != nil {
 		c.logger.Debug("Failed to get latest oplog entry", zap.Error(err))
 		return
 	}
 
-	if ts, ok := latestOplog["ts"].(primitive.Timestamp); ok {
+	head, hasHead := latestOplog["ts"].(primitive.Timestamp)
+	if hasHead {
 		ch <- prometheus.MustNewConstMetric(
 			c.descriptors["oplog_head_timestamp"],
 			prometheus.GaugeValue,
-			float64(ts.T),
+			float64(head.T),
+			instance["instance"],
+			instance["replica_set"],
+			instance["shard"],
+		)
+	}
+
+	if hasSize {
+		maxSize, _ := toInt64(oplogStats["maxSize"])
+		c.collectOplogGrowth(ctx, ch, size, maxSize, head, hasHead, instance)
+	}
+}
+
+// collectOplogGrowth exports the oplog growth rate. Used bytes stop changing
+// once the oplog is full, so the rate then comes from sampling the oldest
+// entry if enabled, and is not exported otherwise
+func (c *ReplicaSetCollector) collectOplogGrowth(ctx context.Context, ch chan<- prometheus.Metric, used, maxSize int64, head primitive.Timestamp, hasHead bool, instance map[string]string) {
+	growth, ok := c.oplogGrowth.observe(instance["instance"], used, time.Now())
+	if oplogFull(used, maxSize) {
+		ok = false
+	}
+
+	if !ok && c.sampleOplogTimestamps && hasHead {
+		var oldestOplog bson.M
+		opts := options.FindOne().SetSort(bson.D{{"$natural", 1}}).SetProjection(bson.D{{"ts", 1}})
+		opts.MaxTime = maxTime(ctx)
+		if err := c.findOne(ctx, "replica_set_status", c.client.Database("local").Collection("oplog.rs"), bson.M{}, &oldestOplog, opts); err != nil {
+			c.logger.Debug("Failed to get oldest oplog entry", zap.Error(err))
+			return
+		}
+		if tail, hasTail := oldestOplog["ts"].(primitive.Timestamp); hasTail {
+			growth, ok = oplogWindowGrowth(used, head, tail)
+		}
+	}
+
+	if ok {
+		ch <- prometheus.MustNewConstMetric(
+			c.descriptors["oplog_growth_bytes_per_hour"],
+			prometheus.GaugeValue,
+			growth,
 			instance["instance"],
 			instance["replica_set"],
 			instance["shard"],
@@ -187,15 +313,93 @@ func (c *ReplicaSetCollector) Name() string {
 	return "replica_set_status"
 }
 
+// memberIdentity returns the member_idx and self label values of a replSetGetStatus member
+func memberIdentity(member bson.M) (string, string) {
+	memberIdx := "unknown"
+	if id, ok := toInt64(member["_id"]); ok {
+		memberIdx = strconv.FormatInt(id, 10)
+	}
+
+	self := "0"
+	if isSelf, ok := member["self"].(bool); ok && isSelf {
+		self = "1"
+	}
+
+	return memberIdx, self
+}
+
+// replicationLags returns, by member name, how many seconds each secondary's
+// optimeDate trails the primary's. Without a primary there is nothing to trail
+func replicationLags(members bson.A) map[string]float64 {
+	var primary time.Time
+	for _, m := range members {
+		member, ok := m.(bson.M)
+		if !ok {
+			continue
+		}
+		if state, ok := toInt64(member["state"]); ok && state == 1 {
+			primary, _ = optimeDate(member)
+		}
+	}
+	if primary.IsZero() {
+		return nil
+	}
+
+	lags := make(map[string]float64)
+	for _, m := range members {
+		member, ok := m.(bson.M)
+		if !ok {
+			continue
+		}
+		name, _ := member["name"].(string)
+		state, _ := toInt64(member["state"])
+		applied, ok := optimeDate(member)
+		if name == "" || state != 2 || !ok {
+			continue
+		}
+		// The primary's optime is read from its last heartbeat, so a
+		// secondary may briefly appear ahead of it
+		lag := primary.Sub(applied).Seconds()
+		if lag < 0 {
+			lag = 0
+		}
+		lags[name] = lag
+	}
+	return lags
+}
+
+func optimeDate(member bson.M) (time.Time, bool) {
+	switch date := member["optimeDate"].(type) {
+	case primitive.DateTime:
+		return date.Time(), true
+	case time.Time:
+		return date, true
+	}
+	return time.Time{}, false
+}
+
+// replicaSetStates lists every replica set member state in code order
+var replicaSetStates = []struct {
+	code int
+	name string
+}{
+	{0, "STARTUP"},
+	{1, "PRIMARY"},
+	{2, "SECONDARY"},
+	{3, "RECOVERING"},
+	{5, "STARTUP2"},
+	{6, "UNKNOWN"},
+	{7, "ARBITER"},
+	{8, "DOWN"},
+	{9, "ROLLBACK"},
+	{10, "REMOVED"},
+}
+
 func (c *ReplicaSetCollector) getStateString(state float64) string {
-	switch state {
-	case 1:
-		return "PRIMARY"
-	case 2:
-		return "SECONDARY"
-	case 7:
-		return "ARBITER"
-	default:
-		return "UNKNOWN"
+	for _, known := range replicaSetStates {
+		if float64(known.code) == state {
+			return known.name
+		}
 	}
+	return "UNKNOWN"
 }
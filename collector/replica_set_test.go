@@ -0,0 +1,102 @@
+package collector
+
+import (
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+func TestReplicaSetStateString(t *testing.T) {
+	collector := NewReplicaSetCollector(nil, zap.NewNop(), CollectorConfig{})
+
+	cases := map[float64]string{
+		0:  "STARTUP",
+		1:  "PRIMARY",
+		2:  "SECONDARY",
+		3:  "RECOVERING",
+		5:  "STARTUP2",
+		7:  "ARBITER",
+		8:  "DOWN",
+		9:  "ROLLBACK",
+		10: "REMOVED",
+		42: "UNKNOWN",
+	}
+
+	for state, expected := range cases {
+		if got := collector.getStateString(state); got != expected {
+			t.Errorf("State %v: expected %s, got %s", state, expected, got)
+		}
+	}
+}
+
+func TestMemberIdentity(t *testing.T) {
+	memberIdx, self := memberIdentity(bson.M{"_id": int32(2), "self": true})
+	if memberIdx != "2" || self != "1" {
+		t.Errorf("Expected member_idx=2 self=1, got member_idx=%s self=%s", memberIdx, self)
+	}
+
+	memberIdx, self = memberIdentity(bson.M{"name": "db-3:27017"})
+	if memberIdx != "unknown" || self != "0" {
+		t.Errorf("Expected member_idx=unknown self=0, got member_idx=%s self=%s", memberIdx, self)
+	}
+}
+
+func TestReplicationLags(t *testing.T) {
+	primary := time.Unix(1700000000, 0)
+	lags := replicationLags(bson.A{
+		bson.M{"name": "db-1:27017", "state": int32(1), "optimeDate": primitive.NewDateTimeFromTime(primary)},
+		bson.M{"name": "db-2:27017", "state": int32(2), "optimeDate": primitive.NewDateTimeFromTime(primary.Add(-12 * time.Second))},
+		bson.M{"name": "db-3:27017", "state": int32(2), "optimeDate": primitive.NewDateTimeFromTime(primary.Add(time.Second))},
+		bson.M{"name": "db-4:27017", "state": int32(7)},
+	})
+
+	expected := map[string]float64{"db-2:27017": 12, "db-3:27017": 0}
+	if len(lags) != len(expected) {
+		t.Fatalf("Expected lag for the secondaries only, got %v", lags)
+	}
+	for name, want := range expected {
+		if got := lags[name]; got != want {
+			t.Errorf("Expected %s lag %v, got %v", name, want, got)
+		}
+	}
+
+	if lags := replicationLags(bson.A{bson.M{"name": "db-2:27017", "state": int32(2)}}); lags != nil {
+		t.Errorf("Expected no lag without a primary, got %v", lags)
+	}
+}
+
+func TestOplogGrowthTracker(t *testing.T) {
+	tracker := newOplogGrowthTracker()
+	start := time.Unix(1700000000, 0)
+
+	if _, ok := tracker.observe("db-1:27017", 1000, start); ok {
+		t.Error("The first observation should have no growth rate")
+	}
+	growth, ok := tracker.observe("db-1:27017", 1000+3600, start.Add(30*time.Minute))
+	if !ok || growth != 7200 {
+		t.Errorf("Expected 7200 bytes per hour, got %v (ok=%v)", growth, ok)
+	}
+	if _, ok := tracker.observe("db-1:27017", 500, start.Add(time.Hour)); ok {
+		t.Error("A truncated oplog should have no growth rate")
+	}
+	if _, ok := tracker.observe("db-2:27017", 500, start.Add(time.Hour)); ok {
+		t.Error("Instances should be tracked separately")
+	}
+}
+
+func TestOplogWindowGrowth(t *testing.T) {
+	if !oplogFull(960, 1000) || oplogFull(900, 1000) || oplogFull(900, 0) {
+		t.Error("The oplog should count as full from 95% of its maximum size")
+	}
+
+	growth, ok := oplogWindowGrowth(1000, primitive.Timestamp{T: 1700007200}, primitive.Timestamp{T: 1700000000})
+	if !ok || growth != 500 {
+		t.Errorf("Expected 500 bytes per hour, got %v (ok=%v)", growth, ok)
+	}
+	if _, ok := oplogWindowGrowth(1000, primitive.Timestamp{T: 1700000000}, primitive.Timestamp{T: 1700000000}); ok {
+		t.Error("An oplog without a window should have no growth estimate")
+	}
+}
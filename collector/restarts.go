@@ -0,0 +1,108 @@
+package collector
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+// processStart is what the restart tracker remembers of an instance
+type processStart struct {
+	start    time.Time
+	seen     time.Time
+	uptime   time.Duration
+	restarts float64
+}
+
+// restartTracker counts server restarts by comparing each scrape's process
+// start time and uptime with the previous scrape's, so alerts need not guess
+// at resets of the uptime gauge
+type restartTracker struct {
+	mu        sync.Mutex
+	instances map[string]processStart
+}
+
+func newRestartTracker() *restartTracker {
+	return &restartTracker{instances: make(map[string]processStart)}
+}
+
+// observe records the instance's start time and uptime as seen at seen and
+// returns its restart count and whether this observation is a restart. A
+// process that started after the previous observation, or whose uptime went
+// down, is a new one; the second catches restarts faster than the slack
+func (t *restartTracker) observe(instance string, start, seen time.Time, uptime time.Duration) (float64, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	previous, ok := t.instances[instance]
+	restarted := ok && (start.After(previous.seen.Add(time.Second)) || uptime < previous.uptime)
+	current := processStart{start: start, seen: seen, uptime: uptime, restarts: previous.restarts}
+	if restarted {
+		current.restarts++
+	}
+	t.instances[instance] = current
+	return current.restarts, restarted
+}
+
+// restartDescriptors describes the restart detection metrics
+func restartDescriptors(config CollectorConfig) map[string]*prometheus.Desc {
+	labels := []string{"instance", "replica_set", "shard"}
+
+	return map[string]*prometheus.Desc{
+		"instance_restarts_total": prometheus.NewDesc(
+			config.metricName("mongodb_instance_restarts_total"),
+			"Server restarts observed by the exporter since it started",
+			labels,
+			nil,
+		),
+		"instance_start_time_seconds": prometheus.NewDesc(
+			config.metricName("mongodb_instance_start_time_seconds"),
+			"Unix time the server process started",
+			labels,
+			nil,
+		),
+	}
+}
+
+// processTimes returns when the server process started, when serverStatus was
+// taken and the uptime, preferring the server clock and millisecond uptime
+func processTimes(result bson.M) (start, seen time.Time, uptime time.Duration, ok bool) {
+	if millis, found := toFloat64(result["uptimeMillis"]); found {
+		uptime = time.Duration(millis) * time.Millisecond
+	} else if seconds, found := toFloat64(result["uptime"]); found {
+		uptime = time.Duration(seconds * float64(time.Second))
+	} else {
+		return time.Time{}, time.Time{}, 0, false
+	}
+
+	seen = time.Now()
+	if localTime, found := result["localTime"].(primitive.DateTime); found {
+		seen = localTime.Time()
+	}
+	return seen.Add(-uptime), seen, uptime, true
+}
+
+// collectRestarts exports the process start time and the restarts seen so
+// far. The cached startup options are dropped on a restart, as they may have changed
+func (c *ServerStatusCollector) collectRestarts(ch chan<- prometheus.Metric, result bson.M, instance map[string]string) {
+	start, seen, uptime, ok := processTimes(result)
+	if !ok {
+		return
+	}
+	labels := []string{instance["instance"], instance["replica_set"], instance["shard"]}
+
+	restarts, restarted := c.restarts.observe(instance["instance"], start, seen, uptime)
+	if restarted {
+		c.startupOptions = nil
+		c.logger.Info("Detected a server restart",
+			zap.String("instance", instance["instance"]),
+			zap.Time("started", start))
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.descriptors["instance_restarts_total"], prometheus.CounterValue, restarts, labels...)
+	ch <- prometheus.MustNewConstMetric(c.descriptors["instance_start_time_seconds"], prometheus.GaugeValue, float64(start.UnixMilli())/1000, labels...)
+}
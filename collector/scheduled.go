@@ -0,0 +1,115 @@
+package collector
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// scheduledCollector runs an expensive collector at most once per interval and
+// replays the metrics of the last runs to scrapes in between
+type scheduledCollector struct {
+	Collector
+	interval time.Duration
+	// staleAfter is how many consecutive runs may miss a series before it is evicted
+	staleAfter int
+
+	mu      sync.Mutex
+	lastRun time.Time
+	cached  map[string]*cachedSeries
+}
+
+// cachedSeries is the last value of a series and how many runs in a row have not reported it
+type cachedSeries struct {
+	metric prometheus.Metric
+	missed int
+}
+
+func newScheduledCollector(collector Collector, interval time.Duration, staleAfter int) *scheduledCollector {
+	if staleAfter < 1 {
+		staleAfter = 1
+	}
+	return &scheduledCollector{
+		Collector:  collector,
+		interval:   interval,
+		staleAfter: staleAfter,
+		cached:     make(map[string]*cachedSeries),
+	}
+}
+
+func (c *scheduledCollector) Collect(ch chan<- prometheus.Metric) {
+	// Holding the lock for the whole run keeps overlapping scrapes from
+	// starting a second run; they wait and then serve the fresh cache
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.lastRun.IsZero() && time.Since(c.lastRun) < c.interval {
+		for _, series := range c.cached {
+			ch <- series.metric
+		}
+		return
+	}
+
+	collected := make(chan prometheus.Metric)
+	done := make(chan struct{})
+	seen := make(map[string]bool)
+	go func() {
+		for metric := range collected {
+			key := seriesKey(metric)
+			seen[key] = true
+			c.cached[key] = &cachedSeries{metric: metric}
+			ch <- metric
+		}
+		close(done)
+	}()
+
+	c.Collector.Collect(collected)
+	close(collected)
+	<-done
+
+	// A series missing from one run may only mean a transient failure, so it is
+	// kept for a few runs; after that its database or collection is presumed gone
+	// and it is evicted rather than reported frozen at its last value
+	for key, series := range c.cached {
+		if seen[key] {
+			continue
+		}
+		series.missed++
+		if series.missed >= c.staleAfter {
+			delete(c.cached, key)
+			continue
+		}
+		ch <- series.metric
+	}
+
+	c.lastRun = time.Now()
+}
+
+// seriesKey identifies a series by its descriptor and label values
+func seriesKey(metric prometheus.Metric) string {
+	var key strings.Builder
+	key.WriteString(metric.Desc().String())
+
+	var m dto.Metric
+	if err := metric.Write(&m); err != nil {
+		return key.String()
+	}
+	for _, label := range m.GetLabel() {
+		key.WriteByte(0xff)
+		key.WriteString(label.GetName())
+		key.WriteByte('=')
+		key.WriteString(label.GetValue())
+	}
+	return key.String()
+}
+
+// expire makes the next run collect afresh, whatever the interval, so a
+// forced refresh serves fresh values from the next scrape on
+func (c *scheduledCollector) expire() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lastRun = time.Time{}
+}
@@ -0,0 +1,102 @@
+package collector
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// validatedCollectionsFilter keeps listCollections output to collections with a validator
+var validatedCollectionsFilter = bson.D{
+	{"type", "collection"},
+	{"options.validator", bson.D{{"$exists", true}}},
+}
+
+// collectionValidation is the schema validation setup of one collection
+type collectionValidation struct {
+	collection string
+	level      string
+	action     string
+}
+
+// listValidatedCollections returns the collections of db that define a validator
+func listValidatedCollections(ctx context.Context, db *mongo.Database, timeout time.Duration) ([]collectionValidation, error) {
+	timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	specs, err := db.ListCollectionSpecifications(timeoutCtx, validatedCollectionsFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	validations := make([]collectionValidation, 0, len(specs))
+	for _, spec := range specs {
+		validations = append(validations, parseValidation(spec))
+	}
+	return validations, nil
+}
+
+// parseValidation reads the validation level and action of a listCollections
+// entry. Both are omitted when left at their server defaults, strict and error
+func parseValidation(spec *mongo.CollectionSpecification) collectionValidation {
+	validation := collectionValidation{collection: spec.Name, level: "strict", action: "error"}
+	if spec.Options == nil {
+		return validation
+	}
+	if level, ok := spec.Options.Lookup("validationLevel").StringValueOK(); ok {
+		validation.level = level
+	}
+	if action, ok := spec.Options.Lookup("validationAction").StringValueOK(); ok {
+		validation.action = action
+	}
+	return validation
+}
+
+// schemaValidationDescriptors describes the validator count and info metrics
+func schemaValidationDescriptors(config CollectorConfig) map[string]*prometheus.Desc {
+	labels := []string{"instance", "replica_set", "shard", "database"}
+
+	return map[string]*prometheus.Desc{
+		"validated_collections": prometheus.NewDesc(
+			config.metricName("mongodb_collstats_validated_collections"),
+			"Number of monitored collections in the database that define a schema validator",
+			labels,
+			nil,
+		),
+		"validation_info": prometheus.NewDesc(
+			config.metricName("mongodb_collstats_validation_info"),
+			"Schema validation level and action of a collection with a validator, always 1",
+			append(labels, "collection", "level", "action"),
+			nil,
+		),
+	}
+}
+
+// collectSchemaValidation emits the validator setup of dbName's monitored collections
+func (c *CollStatsCollector) collectSchemaValidation(ctx context.Context, ch chan<- prometheus.Metric, dbName string, instance map[string]string) {
+	validations, err := listValidatedCollections(ctx, c.client.Database(dbName), 10*time.Second)
+	if err != nil {
+		c.logCommandError("Failed to list collection validators", err)
+		return
+	}
+
+	labels := []string{instance["instance"], instance["replica_set"], instance["shard"], dbName}
+	validated := 0
+	for _, validation := range validations {
+		if c.shouldSkipCollection(validation.collection) || !c.shouldMonitorCollection(dbName, validation.collection) {
+			continue
+		}
+		validated++
+		ch <- prometheus.MustNewConstMetric(
+			c.descriptors["validation_info"],
+			prometheus.GaugeValue,
+			1,
+			append(labels, validation.collection, validation.level, validation.action)...,
+		)
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.descriptors["validated_collections"], prometheus.GaugeValue, float64(validated), labels...)
+}
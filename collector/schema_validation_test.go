@@ -0,0 +1,33 @@
+package collector
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestParseValidation(t *testing.T) {
+	defaults, err := bson.Marshal(bson.D{{"validator", bson.D{{"$jsonSchema", bson.D{}}}}})
+	if err != nil {
+		t.Fatalf("Failed to marshal options: %v", err)
+	}
+	custom, err := bson.Marshal(bson.D{
+		{"validator", bson.D{{"$jsonSchema", bson.D{}}}},
+		{"validationLevel", "moderate"},
+		{"validationAction", "warn"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to marshal options: %v", err)
+	}
+
+	got := parseValidation(&mongo.CollectionSpecification{Name: "orders", Options: defaults})
+	if got.level != "strict" || got.action != "error" {
+		t.Errorf("Omitted level and action should default to strict and error, got %q and %q", got.level, got.action)
+	}
+
+	got = parseValidation(&mongo.CollectionSpecification{Name: "events", Options: custom})
+	if got.collection != "events" || got.level != "moderate" || got.action != "warn" {
+		t.Errorf("Explicit level and action should be read from options, got %+v", got)
+	}
+}
@@ -0,0 +1,44 @@
+package collector
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ErrUnknownScrapeProfile is returned when selecting a scrape profile that is not configured
+var ErrUnknownScrapeProfile = errors.New("unknown scrape profile")
+
+// profileCollector runs the collectors of one scrape profile through the
+// manager's MultiCollector, so runs share its failure counters, watchdog and
+// in-flight tracking with regular scrapes
+type profileCollector struct {
+	multi   *MultiCollector
+	include map[string]bool
+}
+
+// Describe sends nothing: the profile's collectors are described by the regular registration
+func (c *profileCollector) Describe(ch chan<- *prometheus.Desc) {}
+
+func (c *profileCollector) Collect(ch chan<- prometheus.Metric) {
+	c.multi.collect(ch, func(name string) bool {
+		return c.include[name]
+	})
+}
+
+// ScrapeProfileCollector returns a collector running only the collectors of
+// the named scrape profile. Profiles narrow what runs; collectors disabled in
+// configuration or at runtime stay off
+func (cm *CollectorManager) ScrapeProfileCollector(name string) (prometheus.Collector, error) {
+	collectors, ok := cm.config.ScrapeProfiles[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownScrapeProfile, name)
+	}
+
+	include := make(map[string]bool, len(collectors))
+	for _, collector := range collectors {
+		include[collector] = true
+	}
+	return &profileCollector{multi: cm.multiCollector, include: include}, nil
+}
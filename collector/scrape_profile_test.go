@@ -0,0 +1,44 @@
+package collector
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+func TestScrapeProfileCollector(t *testing.T) {
+	cm := NewCollectorManager(nil, zap.NewNop(), CollectorConfig{
+		DisabledMetrics: []string{"locks"},
+		ScrapeProfiles: map[string][]string{
+			"light": {"server_status", "locks"},
+		},
+	})
+	serverStatus := &countingCollector{MockCollector: MockCollector{name: "server_status"}}
+	locks := &countingCollector{MockCollector: MockCollector{name: "locks"}}
+	collStats := &countingCollector{MockCollector: MockCollector{name: "collstats"}}
+	cm.multiCollector.collectors = []Collector{serverStatus, locks, collStats}
+
+	profile, err := cm.ScrapeProfileCollector("light")
+	if err != nil {
+		t.Fatalf("ScrapeProfileCollector failed: %v", err)
+	}
+	ch := make(chan prometheus.Metric, 100)
+	profile.Collect(ch)
+	close(ch)
+
+	if serverStatus.runs != 1 {
+		t.Errorf("Collector in the profile should run once, ran %d times", serverStatus.runs)
+	}
+	if locks.runs != 0 {
+		t.Errorf("Collector disabled in configuration should not run, ran %d times", locks.runs)
+	}
+	if collStats.runs != 0 {
+		t.Errorf("Collector outside the profile should not run, ran %d times", collStats.runs)
+	}
+
+	if _, err := cm.ScrapeProfileCollector("deep"); !errors.Is(err, ErrUnknownScrapeProfile) {
+		t.Errorf("Expected ErrUnknownScrapeProfile, got %v", err)
+	}
+}
@@ -13,6 +13,9 @@ import (
 type ServerStatusCollector struct {
 	*BaseCollector
 	descriptors map[string]*prometheus.Desc
+	// startupOptions caches getCmdLineOpts, which only changes on restart
+	startupOptions bson.M
+	restarts       *restartTracker
 }
 
 func NewServerStatusCollector(client *mongo.Client, logger *zap.Logger, config CollectorConfig) *ServerStatusCollector {
@@ -20,64 +23,110 @@ func NewServerStatusCollector(client *mongo.Client, logger *zap.Logger, config C
 
 	descriptors := map[string]*prometheus.Desc{
 		"uptime_seconds": prometheus.NewDesc(
-			"mongodb_instance_uptime_seconds",
+			config.metricName("mongodb_instance_uptime_seconds"),
 			"The uptime of the MongoDB instance in seconds",
 			labels,
 			nil,
 		),
 		"connections": prometheus.NewDesc(
-			"mongodb_connections",
+			config.metricName("mongodb_connections"),
 			"The current connections metrics",
 			append(labels, "state"),
 			nil,
 		),
 		"memory": prometheus.NewDesc(
-			"mongodb_memory_bytes",
+			config.metricName("mongodb_memory_bytes"),
 			"The current memory usage in bytes",
 			append(labels, "type"),
 			nil,
 		),
 		"extra_info": prometheus.NewDesc(
-			"mongodb_extra_info",
+			config.metricName("mongodb_extra_info"),
 			"Extra information metrics",
 			append(labels, "type"),
 			nil,
 		),
 		"network_bytes_total": prometheus.NewDesc(
-			"mongodb_network_bytes_total",
+			config.metricName("mongodb_network_bytes_total"),
 			"Network traffic metrics",
 			append(labels, "direction"),
 			nil,
 		),
 		"op_counters_total": prometheus.NewDesc(
-			"mongodb_op_counters_total",
+			config.metricName("mongodb_op_counters_total"),
 			"Operation counters",
 			append(labels, "type"),
 			nil,
 		),
 		"metrics_document_total": prometheus.NewDesc(
-			"mongodb_metrics_document_total",
+			config.metricName("mongodb_metrics_document_total"),
 			"Document operation metrics",
 			append(labels, "type"),
 			nil,
 		),
 		"connections_metrics": prometheus.NewDesc(
-			"mongodb_connections_metrics",
+			config.metricName("mongodb_connections_metrics"),
 			"Connections metrics",
 			append(labels, "type"),
 			nil,
 		),
 		"page_faults_total": prometheus.NewDesc(
-			"mongodb_page_faults_total",
+			config.metricName("mongodb_page_faults_total"),
 			"Page fault statistics",
 			labels,
 			nil,
 		),
 	}
+	for key, desc := range storageWatchdogDescriptors(config) {
+		descriptors[key] = desc
+	}
+	for key, desc := range replMetricsDescriptors(config) {
+		descriptors[key] = desc
+	}
+	for key, desc := range readPreferenceDescriptors(config) {
+		descriptors[key] = desc
+	}
+	for key, desc := range mirroredReadsDescriptors(config) {
+		descriptors[key] = desc
+	}
+	for key, desc := range hedgingDescriptors(config) {
+		descriptors[key] = desc
+	}
+	for key, desc := range apiVersionsDescriptors(config) {
+		descriptors[key] = desc
+	}
+	for key, desc := range ferretDBDescriptors(config) {
+		descriptors[key] = desc
+	}
+	for key, desc := range perconaDescriptors(config) {
+		descriptors[key] = desc
+	}
+	for key, desc := range encryptionAtRestDescriptors(config) {
+		descriptors[key] = desc
+	}
+	for key, desc := range ldapDescriptors(config) {
+		descriptors[key] = desc
+	}
+	for key, desc := range instanceInfoDescriptors(config) {
+		descriptors[key] = desc
+	}
+	for key, desc := range restartDescriptors(config) {
+		descriptors[key] = desc
+	}
+	for key, desc := range clockSkewDescriptors(config) {
+		descriptors[key] = desc
+	}
+	for key, desc := range journalDescriptors(config) {
+		descriptors[key] = desc
+	}
+	for key, desc := range globalLockDescriptors(config) {
+		descriptors[key] = desc
+	}
 
 	return &ServerStatusCollector{
 		BaseCollector: NewBaseCollector(client, logger, config),
 		descriptors:   descriptors,
+		restarts:      newRestartTracker(),
 	}
 }
 
@@ -86,17 +135,25 @@ func (c *ServerStatusCollector) Collect(ch chan<- prometheus.Metric) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+	ctx, done := c.collectContext("server_status", 10*time.Second)
+	defer done()
+
+	sections := []string{"connections", "dur", "extra_info", "globalLock", "mem", "metrics", "network", "opcounters", "security", "storageEngine"}
+	if c.supports(ctx, "mirroredReads") {
+		sections = append(sections, "mirroredReads")
+	}
 
 	var result bson.M
-	err := c.client.Database("admin").RunCommand(ctx, bson.D{{"serverStatus", 1}}).Decode(&result)
+	sent := time.Now()
+	err := c.runCommand(ctx, "server_status", c.client.Database("admin"), serverStatusCommand(sections...), &result)
 	if err != nil {
-		c.logger.Error("Failed to get server status", zap.Error(err))
+		c.logCommandError("Failed to get server status", err)
 		return
 	}
+	received := time.Now()
 
 	c.collectMetrics(ctx, ch, result)
+	c.collectClockSkew(ch, result, sent, received)
 }
 
 func (c *ServerStatusCollector) Describe(ch chan<- *prometheus.Desc) {
@@ -113,7 +170,7 @@ func (c *ServerStatusCollector) collectMetrics(ctx context.Context, ch chan<- pr
 	instance := c.getInstanceInfo(result)
 
 	// Uptime with validation
-	if uptime, ok := result["uptime"].(float64); ok && uptime >= 0 {
+	if uptime, ok := toFloat64(result["uptime"]); ok && uptime >= 0 {
 		ch <- prometheus.MustNewConstMetric(
 			c.descriptors["uptime_seconds"],
 			prometheus.GaugeValue,
@@ -277,4 +334,21 @@ func (c *ServerStatusCollector) collectMetrics(ctx context.Context, ch chan<- pr
 			}
 		}
 	}
+
+	// First, so the startup options read below are refreshed after a restart
+	c.collectRestarts(ch, result, instance)
+	c.collectStorageWatchdog(ch, result, instance)
+	c.collectReplMetrics(ch, result, instance)
+	c.collectReadPreferenceCounters(ch, result, instance)
+	c.collectMirroredReads(ch, result, instance)
+	c.collectHedgingMetrics(ch, result, instance)
+	c.collectAPIVersions(ch, result, instance)
+	c.collectFerretDB(ch, result, instance)
+	c.collectPercona(ctx, ch, result, instance)
+	c.collectEncryptionAtRest(ctx, ch, result, instance)
+	c.collectLDAP(ch, result, instance)
+	c.collectInstanceInfo(ctx, ch, result, instance)
+	c.collectJournal(ch, result, instance)
+	c.collectGlobalLock(ch, result, instance)
+	c.collectFilesystemUsage(ctx, ch, instance)
 }
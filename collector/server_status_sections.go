@@ -0,0 +1,58 @@
+package collector
+
+import "go.mongodb.org/mongo-driver/bson"
+
+// serverStatusSections are the serverStatus sections returned by default that
+// collectors may read. Sections not listed here are small or opt-in
+var serverStatusSections = []string{
+	"asserts",
+	"connections",
+	"dur",
+	"electionMetrics",
+	"extra_info",
+	"flowControl",
+	"globalLock",
+	"locks",
+	"logicalSessionRecordCache",
+	"mem",
+	"metrics",
+	"network",
+	"opLatencies",
+	"opcounters",
+	"opcountersRepl",
+	"security",
+	"storageEngine",
+	"tcmalloc",
+	"transactions",
+	"wiredTiger",
+}
+
+// optInServerStatusSections are only returned when explicitly requested
+var optInServerStatusSections = []string{
+	"mirroredReads",
+}
+
+// serverStatusCommand builds a serverStatus command that turns off every known
+// section except the given ones, so large documents such as wiredTiger and
+// tcmalloc are only built and decoded by the collectors that use them. repl is
+// always kept because it provides the replica_set label. Requested opt-in
+// sections are turned on
+func serverStatusCommand(sections ...string) bson.D {
+	keep := make(map[string]bool, len(sections))
+	for _, section := range sections {
+		keep[section] = true
+	}
+
+	cmd := bson.D{{"serverStatus", 1}}
+	for _, section := range serverStatusSections {
+		if !keep[section] {
+			cmd = append(cmd, bson.E{Key: section, Value: 0})
+		}
+	}
+	for _, section := range optInServerStatusSections {
+		if keep[section] {
+			cmd = append(cmd, bson.E{Key: section, Value: 1})
+		}
+	}
+	return cmd
+}
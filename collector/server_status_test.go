@@ -3,9 +3,12 @@ package collector
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.uber.org/zap"
 )
 
@@ -140,3 +143,594 @@ func TestServerStatusCollectorWithMockData(t *testing.T) {
 		t.Error("Should collect metrics from mock data")
 	}
 }
+
+func TestServerStatusCommand(t *testing.T) {
+	cmd := serverStatusCommand("locks", "metrics")
+	if cmd[0].Key != "serverStatus" {
+		t.Fatalf("serverStatus should be the command name, got %q", cmd[0].Key)
+	}
+
+	excluded := make(map[string]bool)
+	for _, elem := range cmd[1:] {
+		if elem.Value != 0 {
+			t.Errorf("Section %q should only be listed to exclude it", elem.Key)
+		}
+		excluded[elem.Key] = true
+	}
+
+	if excluded["locks"] || excluded["metrics"] || excluded["repl"] {
+		t.Error("Requested sections and repl should be returned")
+	}
+	if !excluded["wiredTiger"] || !excluded["tcmalloc"] {
+		t.Error("Unused large sections should be excluded")
+	}
+
+	cmd = serverStatusCommand("mirroredReads")
+	if last := cmd[len(cmd)-1]; last.Key != "mirroredReads" || last.Value != 1 {
+		t.Errorf("Requested opt-in sections should be turned on, got %v", last)
+	}
+}
+
+func TestStorageWatchdogMetrics(t *testing.T) {
+	collector := NewServerStatusCollector(nil, zap.NewNop(), CollectorConfig{})
+	instance := map[string]string{"instance": "db-1:27017", "replica_set": "rs0", "shard": ""}
+
+	ch := make(chan prometheus.Metric, 10)
+	collector.collectStorageWatchdog(ch, bson.M{
+		"watchdog": bson.M{
+			"checkGeneration":   int64(42),
+			"monitorGeneration": int64(7),
+			"monitorPeriod":     int32(60),
+		},
+		"diskSpaceMonitor": bson.M{
+			"availableBytes": int64(1 << 30),
+			"enabled":        true,
+		},
+	}, instance)
+	close(ch)
+
+	names := make(map[string]int)
+	for metric := range ch {
+		names[descName(metric.Desc())]++
+	}
+
+	for _, name := range []string{
+		"mongodb_storage_watchdog_checks_total",
+		"mongodb_storage_watchdog_monitor_runs_total",
+		"mongodb_storage_watchdog_period_seconds",
+	} {
+		if names[name] != 1 {
+			t.Errorf("Expected %s from the watchdog section", name)
+		}
+	}
+	if names["mongodb_storage_disk_space_monitor"] != 1 {
+		t.Errorf("Only numeric diskSpaceMonitor fields should be exported, got %d", names["mongodb_storage_disk_space_monitor"])
+	}
+
+	ch = make(chan prometheus.Metric, 10)
+	collector.collectStorageWatchdog(ch, bson.M{}, instance)
+	close(ch)
+	if len(ch) != 0 {
+		t.Error("Servers without a watchdog should not report watchdog metrics")
+	}
+}
+
+func TestReplMetrics(t *testing.T) {
+	collector := NewServerStatusCollector(nil, zap.NewNop(), CollectorConfig{})
+	instance := map[string]string{"instance": "db-2:27017", "replica_set": "rs0", "shard": ""}
+
+	ch := make(chan prometheus.Metric, 30)
+	collector.collectReplMetrics(ch, bson.M{"metrics": bson.M{"repl": bson.M{
+		"buffer": bson.M{
+			"apply": bson.M{"count": int64(3), "sizeBytes": int64(300), "maxSizeBytes": int64(1 << 20)},
+			"write": bson.M{"count": int64(1), "sizeBytes": int64(100), "maxSizeBytes": int64(1 << 20)},
+		},
+		"apply": bson.M{"batches": bson.M{"num": int32(10), "totalMillis": int32(2500)}, "ops": int64(400)},
+		"network": bson.M{
+			"bytes":    int64(8192),
+			"getmores": bson.M{"num": int64(12), "totalMillis": int64(900), "numEmptyBatches": int64(2)},
+		},
+	}}}, instance)
+	close(ch)
+
+	names := make(map[string]int)
+	for metric := range ch {
+		names[descName(metric.Desc())]++
+	}
+
+	if names["mongodb_repl_buffer_count"] != 2 {
+		t.Errorf("Split 7.0 buffers should each be reported, got %d", names["mongodb_repl_buffer_count"])
+	}
+	for _, name := range []string{
+		"mongodb_repl_apply_batches_total",
+		"mongodb_repl_apply_batches_seconds_total",
+		"mongodb_repl_apply_ops_total",
+		"mongodb_repl_network_bytes_total",
+		"mongodb_repl_network_getmores_empty_batches_total",
+	} {
+		if names[name] != 1 {
+			t.Errorf("Expected %s from metrics.repl", name)
+		}
+	}
+
+	legacy := replBuffers(bson.M{"buffer": bson.M{"count": int64(0), "sizeBytes": int64(0)}})
+	if _, ok := legacy["oplog"]; !ok || len(legacy) != 1 {
+		t.Errorf("Pre-7.0 single buffer should be reported as oplog, got %v", legacy)
+	}
+}
+
+func TestReadPreferenceCounters(t *testing.T) {
+	collector := NewServerStatusCollector(nil, zap.NewNop(), CollectorConfig{})
+	instance := map[string]string{"instance": "db-2:27017", "replica_set": "rs0", "shard": ""}
+
+	ch := make(chan prometheus.Metric, 20)
+	collector.collectReadPreferenceCounters(ch, bson.M{"readPreferenceCounters": bson.M{
+		"executedOnPrimary": bson.M{
+			"primary": bson.M{"internal": int64(5), "external": int64(100)},
+		},
+		"executedOnSecondary": bson.M{
+			"secondaryPreferred": bson.M{"internal": int64(0), "external": int64(40)},
+			"tagged":             bson.M{"internal": int64(0), "external": int64(3)},
+		},
+	}}, instance)
+	close(ch)
+
+	if len(ch) != 6 {
+		t.Errorf("Every mode should report internal and external counts, got %d series", len(ch))
+	}
+}
+
+func TestMirroredReads(t *testing.T) {
+	collector := NewServerStatusCollector(nil, zap.NewNop(), CollectorConfig{})
+	instance := map[string]string{"instance": "db-1:27017", "replica_set": "rs0", "shard": ""}
+
+	ch := make(chan prometheus.Metric, 10)
+	collector.collectMirroredReads(ch, bson.M{"mirroredReads": bson.M{
+		"seen": int64(1000),
+		"sent": int64(10),
+	}}, instance)
+	close(ch)
+
+	names := make(map[string]int)
+	for metric := range ch {
+		names[descName(metric.Desc())]++
+	}
+	if names["mongodb_mirrored_reads_seen_total"] != 1 || names["mongodb_mirrored_reads_sent_total"] != 1 || len(names) != 2 {
+		t.Errorf("Only the fields reported by the server should be exported, got %v", names)
+	}
+}
+
+func TestHedgingMetrics(t *testing.T) {
+	collector := NewServerStatusCollector(nil, zap.NewNop(), CollectorConfig{})
+	instance := map[string]string{"instance": "mongos-1:27017", "replica_set": "unknown", "shard": ""}
+
+	ch := make(chan prometheus.Metric, 10)
+	collector.collectHedgingMetrics(ch, bson.M{"hedgingMetrics": bson.M{
+		"numTotalOperations":                int64(500),
+		"numTotalHedgedOperations":          int64(120),
+		"numAdvantageouslyHedgedOperations": int64(30),
+	}}, instance)
+	close(ch)
+	if len(ch) != 3 {
+		t.Errorf("Expected the three hedging counters, got %d", len(ch))
+	}
+
+	ch = make(chan prometheus.Metric, 10)
+	collector.collectHedgingMetrics(ch, bson.M{}, instance)
+	close(ch)
+	if len(ch) != 0 {
+		t.Error("mongod without hedgingMetrics should not report hedging counters")
+	}
+}
+
+func TestAPIVersions(t *testing.T) {
+	collector := NewServerStatusCollector(nil, zap.NewNop(), CollectorConfig{})
+	instance := map[string]string{"instance": "db-1:27017", "replica_set": "rs0", "shard": ""}
+
+	ch := make(chan prometheus.Metric, 10)
+	collector.collectAPIVersions(ch, bson.M{"apiVersions": bson.M{
+		"orders-service": bson.A{"1"},
+		"reporting":      bson.A{"default", "1"},
+		"legacy-batch":   bson.A{"default"},
+	}}, instance)
+	close(ch)
+
+	names := make(map[string]int)
+	for metric := range ch {
+		names[descName(metric.Desc())]++
+	}
+	if names["mongodb_api_version_info"] != 4 {
+		t.Errorf("Each application and version pair should be reported, got %d", names["mongodb_api_version_info"])
+	}
+	if names["mongodb_api_version_apps"] != 2 {
+		t.Errorf("Application counts should be reported per version, got %d", names["mongodb_api_version_apps"])
+	}
+
+	usage := apiVersionUsage(bson.M{"apiVersions": bson.M{"reporting": bson.A{"default", "1"}}})
+	if len(usage["reporting"]) != 2 {
+		t.Errorf("All versions of an application should be kept, got %v", usage)
+	}
+	if apiVersionUsage(bson.M{}) != nil {
+		t.Error("Servers without apiVersions should report no usage")
+	}
+}
+
+func TestFerretDB(t *testing.T) {
+	caps := newServerCapabilities(zap.NewNop())
+	collector := NewServerStatusCollector(nil, zap.NewNop(), CollectorConfig{capabilities: caps})
+	instance := map[string]string{"instance": "ferretdb:27017", "replica_set": "unknown", "shard": "unknown"}
+	result := bson.M{"ferretdb": bson.M{"pool": bson.M{"open": int32(4), "idle": int64(2)}, "backend": "postgresql"}}
+
+	ch := make(chan prometheus.Metric, 10)
+	collector.collectFerretDB(ch, result, instance)
+	close(ch)
+	if len(ch) != 0 {
+		t.Error("FerretDB metrics should not be exported for other servers")
+	}
+
+	caps.ferretDB = "v1.24.0"
+	caps.restrictFlavor(flavorFerretDB)
+
+	ch = make(chan prometheus.Metric, 10)
+	collector.collectFerretDB(ch, result, instance)
+	close(ch)
+
+	names := make(map[string]int)
+	for metric := range ch {
+		names[descName(metric.Desc())]++
+	}
+	if names["mongodb_ferretdb_info"] != 1 {
+		t.Errorf("FerretDB version should be reported, got %d", names["mongodb_ferretdb_info"])
+	}
+	if names["mongodb_ferretdb_status"] != 2 {
+		t.Errorf("Numeric fields of the ferretdb section should be reported, got %d", names["mongodb_ferretdb_status"])
+	}
+
+	if collector.isMetricEnabled("replica_set_status") || !collector.isMetricEnabled("server_status") {
+		t.Error("Only collectors FerretDB supports should stay enabled")
+	}
+}
+
+func TestPercona(t *testing.T) {
+	caps := newServerCapabilities(zap.NewNop())
+	collector := NewServerStatusCollector(nil, zap.NewNop(), CollectorConfig{capabilities: caps})
+	instance := map[string]string{"instance": "psmdb:27017", "replica_set": "rs0", "shard": "unknown"}
+	result := bson.M{"inMemory": bson.M{"cache": bson.M{
+		"bytes currently in the cache":     int64(1 << 20),
+		"tracked dirty bytes in the cache": int64(4096),
+		"maximum bytes configured":         int64(1 << 30),
+	}}}
+
+	ch := make(chan prometheus.Metric, 10)
+	collector.collectPercona(context.Background(), ch, result, instance)
+	close(ch)
+	if len(ch) != 0 {
+		t.Error("Percona metrics should not be exported for other builds")
+	}
+
+	caps.psmdb = "6.0.9-7"
+	collector.startupOptions = bson.M{
+		"operationProfiling": bson.M{"rateLimit": int32(100)},
+		"auditLog":           bson.M{"destination": "file", "format": "JSON"},
+	}
+
+	ch = make(chan prometheus.Metric, 10)
+	collector.collectPercona(context.Background(), ch, result, instance)
+	close(ch)
+
+	names := make(map[string]int)
+	for metric := range ch {
+		names[descName(metric.Desc())]++
+	}
+	expected := map[string]int{
+		"mongodb_psmdb_info":                 1,
+		"mongodb_psmdb_inmemory_cache_bytes": 3,
+		"mongodb_psmdb_profiler_rate_limit":  1,
+		"mongodb_psmdb_audit_log_info":       1,
+	}
+	for name, count := range expected {
+		if names[name] != count {
+			t.Errorf("Expected %d %s series, got %d", count, name, names[name])
+		}
+	}
+}
+
+func TestEncryptionAtRest(t *testing.T) {
+	collector := NewServerStatusCollector(nil, zap.NewNop(), CollectorConfig{})
+	collector.startupOptions = bson.M{"security": bson.M{
+		"enableEncryption": true,
+		"kmip":             bson.M{"serverName": "kmip.example.net"},
+	}}
+	instance := map[string]string{"instance": "db-1:27017", "replica_set": "rs0", "shard": "unknown"}
+	rotated := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	result := bson.M{"encryptionAtRest": bson.M{
+		"kmip": bson.M{"lastKeyRotation": primitive.NewDateTimeFromTime(rotated)},
+	}}
+
+	ch := make(chan prometheus.Metric, 10)
+	collector.collectEncryptionAtRest(context.Background(), ch, result, instance)
+	close(ch)
+
+	names := make(map[string]int)
+	for metric := range ch {
+		names[descName(metric.Desc())]++
+	}
+	expected := map[string]int{
+		"mongodb_encryption_at_rest_enabled":           1,
+		"mongodb_encryption_at_rest_info":              1,
+		"mongodb_encryption_at_rest_timestamp_seconds": 1,
+	}
+	for name, count := range expected {
+		if names[name] != count {
+			t.Errorf("Expected %d %s series, got %d", count, name, names[name])
+		}
+	}
+
+	if got := encryptionKeyManagement(bson.M{"enableEncryption": true}); got != "keyfile" {
+		t.Errorf("Encryption without KMIP or Vault should use a keyfile, got %q", got)
+	}
+	if got := encryptionKeyManagement(bson.M{}); got != "" {
+		t.Errorf("Disabled encryption should have no key management, got %q", got)
+	}
+}
+
+func TestLDAP(t *testing.T) {
+	collector := NewServerStatusCollector(nil, zap.NewNop(), CollectorConfig{})
+	instance := map[string]string{"instance": "db-1:27017", "replica_set": "rs0", "shard": "unknown"}
+	result := bson.M{
+		"ldapOperations": bson.M{
+			"numConnectionsInUse":     int64(3),
+			"numConnectionsAvailable": int64(7),
+			"bind":                    bson.M{"numOp": int64(120), "totalTime": int64(5400)},
+		},
+		"security": bson.M{"authentication": bson.M{"mechanisms": bson.M{
+			"PLAIN":         bson.M{"authenticate": bson.M{"received": int64(120), "successful": int64(110)}},
+			"SCRAM-SHA-256": bson.M{"authenticate": bson.M{"received": int64(40), "successful": int64(40)}},
+		}}},
+	}
+
+	ch := make(chan prometheus.Metric, 20)
+	collector.collectLDAP(ch, result, instance)
+	close(ch)
+
+	names := make(map[string]int)
+	for metric := range ch {
+		names[descName(metric.Desc())]++
+	}
+	expected := map[string]int{
+		"mongodb_ldap_stat":                      4,
+		"mongodb_authentication_attempts_total":  2,
+		"mongodb_authentication_successes_total": 2,
+	}
+	for name, count := range expected {
+		if names[name] != count {
+			t.Errorf("Expected %d %s series, got %d", count, name, names[name])
+		}
+	}
+}
+
+func TestInstanceInfo(t *testing.T) {
+	caps := newServerCapabilities(zap.NewNop())
+	caps.enterprise = true
+	collector := NewServerStatusCollector(nil, zap.NewNop(), CollectorConfig{capabilities: caps})
+	instance := map[string]string{"instance": "mongo:27017", "replica_set": "rs0", "shard": "unknown"}
+	result := bson.M{
+		"version":       "7.0.5",
+		"storageEngine": bson.M{"name": "wiredTiger"},
+	}
+
+	ch := make(chan prometheus.Metric, 1)
+	collector.emitInstanceInfo(ch, result, ClusterRoleShardServer, instance)
+	close(ch)
+
+	var m dto.Metric
+	if err := (<-ch).Write(&m); err != nil {
+		t.Fatalf("Failed to read metric: %v", err)
+	}
+	labels := make(map[string]string)
+	for _, pair := range m.GetLabel() {
+		labels[pair.GetName()] = pair.GetValue()
+	}
+	expected := map[string]string{
+		"version":        "7.0.5",
+		"edition":        "enterprise",
+		"storage_engine": "wiredTiger",
+		"replica_set":    "rs0",
+		"cluster_role":   "shardsvr",
+	}
+	for name, value := range expected {
+		if labels[name] != value {
+			t.Errorf("Expected %s=%q, got %q", name, value, labels[name])
+		}
+	}
+
+	// A detected Percona build takes precedence over the modules list
+	caps.psmdb = "7.0.5-3"
+	if got := caps.edition(); got != "percona" {
+		t.Errorf("Expected percona edition, got %s", got)
+	}
+}
+
+func TestInstanceInfoClusterRoleLabel(t *testing.T) {
+	registerer := func() prometheus.Registerer {
+		return prometheus.WrapRegistererWith(prometheus.Labels{"cluster_role": ClusterRoleReplicaMember}, prometheus.NewRegistry())
+	}
+
+	// The const label clashes with the variable one unless the collector leaves it off
+	if err := registerer().Register(NewServerStatusCollector(nil, zap.NewNop(), CollectorConfig{})); err == nil {
+		t.Error("Expected registering with both cluster_role labels to fail")
+	}
+	collector := NewServerStatusCollector(nil, zap.NewNop(), CollectorConfig{ClusterRoleLabel: true})
+	if err := registerer().Register(collector); err != nil {
+		t.Fatalf("Failed to register with the cluster_role const label: %v", err)
+	}
+
+	ch := make(chan prometheus.Metric, 1)
+	collector.emitInstanceInfo(ch, bson.M{"version": "7.0.5"}, "", map[string]string{"instance": "mongo:27017"})
+	close(ch)
+
+	var m dto.Metric
+	if err := (<-ch).Write(&m); err != nil {
+		t.Fatalf("Failed to read metric: %v", err)
+	}
+	for _, pair := range m.GetLabel() {
+		if pair.GetName() == "cluster_role" {
+			t.Errorf("Expected no cluster_role label, got %q", pair.GetValue())
+		}
+	}
+}
+
+func TestRestartTracker(t *testing.T) {
+	tracker := newRestartTracker()
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	observe := func(start time.Time, seen time.Duration) (float64, bool) {
+		at := base.Add(seen)
+		return tracker.observe("mongo:27017", start, at, at.Sub(start))
+	}
+
+	if restarts, restarted := observe(base, time.Hour); restarts != 0 || restarted {
+		t.Errorf("First observation is not a restart, got %v restarts", restarts)
+	}
+	// Jitter in the derived start time is not a restart
+	if restarts, restarted := observe(base.Add(3*time.Millisecond), 2*time.Hour); restarts != 0 || restarted {
+		t.Errorf("Same process should not count as restarted, got %v restarts", restarts)
+	}
+	// A process started after the last observation is a new one, even with a longer uptime
+	if restarts, restarted := observe(base.Add(2*time.Hour+time.Minute), 5*time.Hour); restarts != 1 || !restarted {
+		t.Errorf("Expected 1 restart, got %v", restarts)
+	}
+	// Uptime going down catches a restart right after the last observation
+	if restarts, restarted := observe(base.Add(5*time.Hour), 5*time.Hour+time.Second); restarts != 2 || !restarted {
+		t.Errorf("Expected 2 restarts, got %v", restarts)
+	}
+}
+
+func TestProcessTimes(t *testing.T) {
+	localTime := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	result := bson.M{
+		"uptime":       float64(3600),
+		"uptimeMillis": int64(3600500),
+		"localTime":    primitive.NewDateTimeFromTime(localTime),
+	}
+
+	start, seen, uptime, ok := processTimes(result)
+	if !ok {
+		t.Fatal("Expected process times to be found")
+	}
+	if !seen.Equal(localTime) {
+		t.Errorf("Expected the server clock to be used, got %v", seen)
+	}
+	if uptime != 3600500*time.Millisecond {
+		t.Errorf("Expected millisecond uptime, got %v", uptime)
+	}
+	if want := localTime.Add(-uptime); !start.Equal(want) {
+		t.Errorf("Expected start %v, got %v", want, start)
+	}
+
+	if _, _, _, ok := processTimes(bson.M{}); ok {
+		t.Error("Expected no process times without an uptime")
+	}
+}
+
+func TestClockSkew(t *testing.T) {
+	sent := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	received := sent.Add(200 * time.Millisecond)
+	result := bson.M{"localTime": primitive.NewDateTimeFromTime(sent.Add(2100 * time.Millisecond))}
+
+	skew, ok := clockSkew(result, sent, received)
+	if !ok {
+		t.Fatal("Expected clock skew from localTime")
+	}
+	if skew != 2*time.Second {
+		t.Errorf("Expected 2s skew measured against the round trip midpoint, got %v", skew)
+	}
+
+	if _, ok := clockSkew(bson.M{}, sent, received); ok {
+		t.Error("Expected no clock skew without localTime")
+	}
+}
+
+func TestJournal(t *testing.T) {
+	collector := NewServerStatusCollector(nil, zap.NewNop(), CollectorConfig{})
+	instance := map[string]string{"instance": "legacy:27017", "replica_set": "rs0", "shard": "unknown"}
+
+	ch := make(chan prometheus.Metric, 20)
+	collector.collectJournal(ch, bson.M{}, instance)
+	if len(ch) != 0 {
+		t.Error("Servers without the dur section should export no journaling metrics")
+	}
+
+	result := bson.M{"dur": bson.M{
+		"commits":            int32(30),
+		"journaledMB":        float64(0.5),
+		"writeToDataFilesMB": float64(0.25),
+		"compression":        float64(0.8),
+		"commitsInWriteLock": int32(2),
+		"earlyCommits":       int32(0),
+		"timeMs": bson.M{
+			"dt":             int32(3005),
+			"prepLogBuffer":  int32(1),
+			"writeToJournal": int32(12),
+		},
+	}}
+	collector.collectJournal(ch, result, instance)
+	close(ch)
+
+	names := make(map[string]int)
+	for metric := range ch {
+		names[descName(metric.Desc())]++
+	}
+	expected := map[string]int{
+		"mongodb_dur_commits":                       1,
+		"mongodb_dur_journaled_megabytes":           1,
+		"mongodb_dur_write_to_data_files_megabytes": 1,
+		"mongodb_dur_commits_in_write_lock":         1,
+		"mongodb_dur_early_commits":                 1,
+		"mongodb_dur_time_milliseconds":             3,
+	}
+	for name, count := range expected {
+		if names[name] != count {
+			t.Errorf("Expected %d %s series, got %d", count, name, names[name])
+		}
+	}
+}
+
+func TestGlobalLock(t *testing.T) {
+	collector := NewServerStatusCollector(nil, zap.NewNop(), CollectorConfig{})
+	instance := map[string]string{"instance": "localhost:27017", "replica_set": "rs0", "shard": "unknown"}
+
+	result := bson.M{"globalLock": bson.M{
+		"totalTime":     int64(2500000),
+		"currentQueue":  bson.M{"total": int32(3), "readers": int32(1), "writers": int32(2)},
+		"activeClients": bson.M{"total": int32(5), "readers": int32(4), "writers": int32(1)},
+	}}
+	ch := make(chan prometheus.Metric, 10)
+	collector.collectGlobalLock(ch, result, instance)
+	close(ch)
+
+	names := make(map[string]int)
+	for metric := range ch {
+		name := descName(metric.Desc())
+		names[name]++
+		if name == "mongodb_global_lock_total_time_seconds" {
+			var m dto.Metric
+			if err := metric.Write(&m); err != nil {
+				t.Fatal(err)
+			}
+			if got := m.GetCounter().GetValue(); got != 2.5 {
+				t.Errorf("Expected total time 2.5s, got %v", got)
+			}
+		}
+	}
+	expected := map[string]int{
+		"mongodb_global_lock_total_time_seconds": 1,
+		"mongodb_global_lock_current_queue":      3,
+		"mongodb_global_lock_active_clients":     3,
+	}
+	for name, count := range expected {
+		if names[name] != count {
+			t.Errorf("Expected %d %s series, got %d", count, name, names[name])
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package collector
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Client states reported by the $currentOp breakdown
+const (
+	clientStateActive        = "active"
+	clientStateIdle          = "idle"
+	clientStateInTransaction = "in_transaction"
+)
+
+// sessionStatePipeline lists every connection and idle session and groups them
+// server-side, so only one document per state and appName is returned
+var sessionStatePipeline = []bson.D{
+	{{"$currentOp", bson.D{
+		{"allUsers", true},
+		{"idleConnections", true},
+		{"idleSessions", true},
+	}}},
+	{{"$match", bson.D{{"type", bson.D{{"$in", bson.A{"op", "idleSession"}}}}}}},
+	{{"$group", bson.D{
+		{"_id", bson.D{
+			{"type", "$type"},
+			{"active", "$active"},
+			{"appName", "$appName"},
+			{"session", bson.D{{"$ne", bson.A{bson.D{{"$type", "$lsid"}}, "missing"}}}},
+			{"transaction", bson.D{{"$ne", bson.A{bson.D{{"$type", "$transaction"}}, "missing"}}}},
+		}},
+		{"count", bson.D{{"$sum", 1}}},
+	}}},
+}
+
+// clientGroup is one $group result of sessionStatePipeline
+type clientGroup struct {
+	ID struct {
+		Type        string `bson:"type"`
+		Active      bool   `bson:"active"`
+		AppName     string `bson:"appName"`
+		Session     bool   `bson:"session"`
+		Transaction bool   `bson:"transaction"`
+	} `bson:"_id"`
+	Count int64 `bson:"count"`
+}
+
+// state classifies the group; running work is active even inside a transaction
+func (g clientGroup) state() string {
+	switch {
+	case g.ID.Active:
+		return clientStateActive
+	case g.ID.Transaction:
+		return clientStateInTransaction
+	default:
+		return clientStateIdle
+	}
+}
+
+// clientStateKey identifies a state and appName series
+type clientStateKey struct {
+	state   string
+	appName string
+}
+
+// countClientStates splits the groups into connection and session counts.
+// Every "op" entry is a client connection; entries with a logical session id,
+// and idle sessions that hold an open transaction, are counted as sessions
+func countClientStates(groups []clientGroup) (connections, sessions map[clientStateKey]int64) {
+	connections = make(map[clientStateKey]int64)
+	sessions = make(map[clientStateKey]int64)
+
+	for _, g := range groups {
+		key := clientStateKey{state: g.state(), appName: g.ID.AppName}
+		if g.ID.Type == "op" {
+			connections[key] += g.Count
+		}
+		if g.ID.Session || g.ID.Type == "idleSession" {
+			sessions[key] += g.Count
+		}
+	}
+	return connections, sessions
+}
+
+// collectSessionStates exports connection and session counts by state and appName
+func (c *ConnectionPoolCollector) collectSessionStates(ctx context.Context, ch chan<- prometheus.Metric, instance map[string]string) {
+	cursor, err := c.aggregate(ctx, "connection_pool", c.client.Database("admin"), sessionStatePipeline, &options.AggregateOptions{MaxTime: maxTime(ctx)})
+	if err != nil {
+		c.logCommandError("Failed to run $currentOp for session states", err)
+		return
+	}
+
+	var groups []clientGroup
+	if err := cursor.All(ctx, &groups); err != nil {
+		c.logCommandError("Failed to read $currentOp session states", err)
+		return
+	}
+
+	connections, sessions := countClientStates(groups)
+	for descKey, counts := range map[string]map[clientStateKey]int64{
+		"currentop_connections": connections,
+		"currentop_sessions":    sessions,
+	} {
+		for key, count := range counts {
+			ch <- prometheus.MustNewConstMetric(
+				c.descriptors[descKey],
+				prometheus.GaugeValue,
+				float64(count),
+				instance["instance"],
+				instance["replica_set"],
+				instance["shard"],
+				key.state,
+				key.appName,
+			)
+		}
+	}
+}
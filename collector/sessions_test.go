@@ -0,0 +1,45 @@
+package collector
+
+import "testing"
+
+func newClientGroup(opType, appName string, active, session, transaction bool, count int64) clientGroup {
+	var g clientGroup
+	g.ID.Type = opType
+	g.ID.AppName = appName
+	g.ID.Active = active
+	g.ID.Session = session
+	g.ID.Transaction = transaction
+	g.Count = count
+	return g
+}
+
+func TestCountClientStates(t *testing.T) {
+	groups := []clientGroup{
+		newClientGroup("op", "api", true, true, false, 3),
+		newClientGroup("op", "api", false, false, false, 40),
+		newClientGroup("op", "api", true, true, true, 1),
+		newClientGroup("idleSession", "worker", false, true, true, 2),
+	}
+
+	connections, sessions := countClientStates(groups)
+
+	if got := connections[clientStateKey{clientStateActive, "api"}]; got != 4 {
+		t.Errorf("Running operations should count as active connections, got %d", got)
+	}
+	if got := connections[clientStateKey{clientStateIdle, "api"}]; got != 40 {
+		t.Errorf("Idle connections should be counted, got %d", got)
+	}
+	if _, ok := connections[clientStateKey{clientStateInTransaction, "worker"}]; ok {
+		t.Error("Idle sessions should not be counted as connections")
+	}
+
+	if got := sessions[clientStateKey{clientStateInTransaction, "worker"}]; got != 2 {
+		t.Errorf("Idle sessions holding a transaction should be in_transaction, got %d", got)
+	}
+	if got := sessions[clientStateKey{clientStateActive, "api"}]; got != 4 {
+		t.Errorf("Operations with a session should count as active sessions, got %d", got)
+	}
+	if _, ok := sessions[clientStateKey{clientStateIdle, "api"}]; ok {
+		t.Error("Connections without a session should not be counted as sessions")
+	}
+}
@@ -0,0 +1,300 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// ShardKeyDistributionCollector reports how evenly chunks and documents of the
+// configured sharded collections are spread across shards, and how concentrated
+// their shard key values are, so hot shards show up before the balancer falls behind
+type ShardKeyDistributionCollector struct {
+	*BaseCollector
+	descriptors map[string]*prometheus.Desc
+	collections []namespace
+	sampleSize  int
+}
+
+func NewShardKeyDistributionCollector(client *mongo.Client, logger *zap.Logger, config CollectorConfig) *ShardKeyDistributionCollector {
+	labels := []string{"instance", "replica_set", "shard", "database", "collection"}
+	shardLabels := []string{"instance", "replica_set", "shard", "database", "collection", "shard_name"}
+	basisLabels := []string{"instance", "replica_set", "shard", "database", "collection", "basis"}
+
+	descriptors := map[string]*prometheus.Desc{
+		"shard_key_chunks": prometheus.NewDesc(
+			config.metricName("mongodb_shard_key_chunks"),
+			"Number of chunks of the collection on each shard",
+			shardLabels,
+			nil,
+		),
+		"shard_key_documents": prometheus.NewDesc(
+			config.metricName("mongodb_shard_key_documents"),
+			"Number of documents of the collection on each shard",
+			shardLabels,
+			nil,
+		),
+		"shard_key_imbalance_ratio": prometheus.NewDesc(
+			config.metricName("mongodb_shard_key_imbalance_ratio"),
+			"Largest per-shard share divided by the mean share, by basis (chunks or documents); 1 is perfectly balanced",
+			basisLabels,
+			nil,
+		),
+		"shard_key_hot_value_ratio": prometheus.NewDesc(
+			config.metricName("mongodb_shard_key_hot_value_ratio"),
+			"Fraction of sampled documents sharing the most frequent shard key value",
+			labels,
+			nil,
+		),
+	}
+
+	options := collectorOptions(config, "shard_key_distribution")
+	sampleSize := getIntOption(options, "sample_size", 0)
+	if sampleSize <= 0 {
+		sampleSize = defaultSelectivitySampleSize
+	}
+
+	var collections []namespace
+	for _, name := range getStringSliceOption(options, "collections") {
+		dbName, collName, ok := strings.Cut(name, ".")
+		if !ok || dbName == "" || collName == "" {
+			logger.Warn("Ignoring shard key distribution namespace without a database", zap.String("namespace", name))
+			continue
+		}
+		collections = append(collections, namespace{Database: dbName, Collection: collName})
+	}
+
+	return &ShardKeyDistributionCollector{
+		BaseCollector: NewBaseCollector(client, logger, config),
+		descriptors:   descriptors,
+		collections:   collections,
+		sampleSize:    sampleSize,
+	}
+}
+
+func (c *ShardKeyDistributionCollector) Collect(ch chan<- prometheus.Metric) {
+	if !c.isMetricExplicitlyEnabled("shard_key_distribution") || len(c.collections) == 0 {
+		return
+	}
+
+	ctx, done := c.collectContext("shard_key_distribution", 30*time.Second)
+	defer done()
+
+	// Chunk metadata is only reachable through mongos
+	var isMaster bson.M
+	if err := c.runCommand(ctx, "shard_key_distribution", c.client.Database("admin"), bson.D{{"isMaster", 1}}, &isMaster); err != nil {
+		c.logCommandError("Failed to run isMaster command", err)
+		return
+	}
+	if msg, _ := isMaster["msg"].(string); msg != "isdbgrid" {
+		c.logger.Debug("Not a mongos instance, skipping shard key distribution")
+		return
+	}
+
+	shards, err := c.listShards(ctx)
+	if err != nil {
+		c.logCommandError("Failed to list shards", err)
+		return
+	}
+
+	instance := c.getInstanceInfo(isMaster)
+	for _, ns := range c.collections {
+		c.collectDistribution(ctx, ch, ns, shards, instance)
+	}
+}
+
+// listShards returns the names of every shard in the cluster
+func (c *ShardKeyDistributionCollector) listShards(ctx context.Context) ([]string, error) {
+	cursor, err := c.find(ctx, "shard_key_distribution", c.client.Database("config").Collection("shards"), bson.D{},
+		&options.FindOptions{Projection: bson.D{{"_id", 1}}, MaxTime: maxTime(ctx)})
+	if err != nil {
+		return nil, err
+	}
+
+	var docs []struct {
+		ID string `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+
+	shards := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		shards = append(shards, doc.ID)
+	}
+	return shards, nil
+}
+
+// shardedCollection is the config.collections entry of a sharded collection
+type shardedCollection struct {
+	Key     bson.D      `bson:"key"`
+	UUID    interface{} `bson:"uuid"`
+	Dropped bool        `bson:"dropped"`
+}
+
+func (c *ShardKeyDistributionCollector) collectDistribution(ctx context.Context, ch chan<- prometheus.Metric, ns namespace, shards []string, instance map[string]string) {
+	var meta shardedCollection
+	err := c.findOne(ctx, "shard_key_distribution", c.client.Database("config").Collection("collections"), bson.D{{"_id", ns.String()}}, &meta,
+		&options.FindOneOptions{MaxTime: maxTime(ctx)})
+	if err != nil || meta.Dropped || len(meta.Key) == 0 {
+		c.logger.Debug("Collection is not sharded, skipping shard key distribution",
+			zap.String("namespace", ns.String()),
+			zap.Error(err))
+		return
+	}
+
+	labels := []string{instance["instance"], instance["replica_set"], instance["shard"], ns.Database, ns.Collection}
+
+	if chunks, err := c.chunksPerShard(ctx, ns, meta.UUID); err != nil {
+		c.logCommandError("Failed to count chunks per shard", err)
+	} else {
+		c.emitPerShard(ch, "shard_key_chunks", "chunks", withAllShards(chunks, shards), labels)
+	}
+
+	if documents, err := c.documentsPerShard(ctx, ns); err != nil {
+		c.logCommandError("Failed to count documents per shard", err)
+	} else {
+		c.emitPerShard(ch, "shard_key_documents", "documents", withAllShards(documents, shards), labels)
+	}
+
+	if ratio, ok := c.hotValueRatio(ctx, ns, meta.Key); ok {
+		ch <- prometheus.MustNewConstMetric(c.descriptors["shard_key_hot_value_ratio"], prometheus.GaugeValue, ratio, labels...)
+	}
+}
+
+// chunksPerShard counts the collection's chunks by shard. Chunks reference
+// their collection by ns before MongoDB 5.0 and by uuid after, so both match
+func (c *ShardKeyDistributionCollector) chunksPerShard(ctx context.Context, ns namespace, uuid interface{}) (map[string]float64, error) {
+	match := bson.A{bson.D{{"ns", ns.String()}}}
+	if uuid != nil {
+		match = append(match, bson.D{{"uuid", uuid}})
+	}
+	pipeline := []bson.D{
+		{{"$match", bson.D{{"$or", match}}}},
+		{{"$group", bson.D{{"_id", "$shard"}, {"n", bson.D{{"$sum", 1}}}}}},
+	}
+	return c.countByShard(ctx, c.client.Database("config").Collection("chunks"), pipeline)
+}
+
+// documentsPerShard reads each shard's document count from collection metadata
+func (c *ShardKeyDistributionCollector) documentsPerShard(ctx context.Context, ns namespace) (map[string]float64, error) {
+	pipeline := []bson.D{
+		{{"$collStats", bson.D{{"count", bson.D{}}}}},
+		{{"$project", bson.D{{"_id", "$shard"}, {"n", "$count"}}}},
+	}
+	return c.countByShard(ctx, c.client.Database(ns.Database).Collection(ns.Collection), pipeline)
+}
+
+// countByShard runs a pipeline producing {_id: shard, n: count} documents
+func (c *ShardKeyDistributionCollector) countByShard(ctx context.Context, coll *mongo.Collection, pipeline []bson.D) (map[string]float64, error) {
+	cursor, err := c.aggregate(ctx, "shard_key_distribution", coll, pipeline, &options.AggregateOptions{MaxTime: maxTime(ctx)})
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []bson.M
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]float64, len(rows))
+	for _, row := range rows {
+		shardName, ok := row["_id"].(string)
+		if !ok {
+			continue
+		}
+		if n, ok := toFloat64(row["n"]); ok {
+			counts[shardName] += n
+		}
+	}
+	return counts, nil
+}
+
+// hotValueRatio samples the collection and returns the share of the sample
+// held by its most frequent shard key value
+func (c *ShardKeyDistributionCollector) hotValueRatio(ctx context.Context, ns namespace, shardKey bson.D) (float64, bool) {
+	key := bson.D{}
+	for i, field := range shardKey {
+		key = append(key, bson.E{Key: fmt.Sprintf("k%d", i), Value: "$" + field.Key})
+	}
+	pipeline := []bson.D{
+		{{"$sample", bson.D{{"size", c.sampleSize}}}},
+		{{"$facet", bson.D{
+			{"sampled", bson.A{bson.D{{"$count", "n"}}}},
+			{"top", bson.A{
+				bson.D{{"$group", bson.D{{"_id", key}, {"n", bson.D{{"$sum", 1}}}}}},
+				bson.D{{"$sort", bson.D{{"n", -1}}}},
+				bson.D{{"$limit", 1}},
+			}},
+		}}},
+	}
+
+	cursor, err := c.aggregate(ctx, "shard_key_distribution", c.client.Database(ns.Database).Collection(ns.Collection), pipeline, &options.AggregateOptions{MaxTime: maxTime(ctx)})
+	if err != nil {
+		c.logCommandError("Failed to sample shard key values", err)
+		return 0, false
+	}
+	var results []bson.M
+	if err := cursor.All(ctx, &results); err != nil || len(results) == 0 {
+		return 0, false
+	}
+
+	sampled := facetCount(results[0], "sampled")
+	if sampled == 0 {
+		return 0, false
+	}
+	return float64(facetCount(results[0], "top")) / float64(sampled), true
+}
+
+// emitPerShard exports per-shard counts and their imbalance ratio
+func (c *ShardKeyDistributionCollector) emitPerShard(ch chan<- prometheus.Metric, descKey, basis string, counts map[string]float64, labels []string) {
+	for shardName, count := range counts {
+		ch <- prometheus.MustNewConstMetric(c.descriptors[descKey], prometheus.GaugeValue, count, append(labels, shardName)...)
+	}
+	if ratio, ok := imbalanceRatio(counts); ok {
+		ch <- prometheus.MustNewConstMetric(c.descriptors["shard_key_imbalance_ratio"], prometheus.GaugeValue, ratio, append(labels, basis)...)
+	}
+}
+
+// withAllShards adds a zero count for every shard holding none of the
+// collection, so an empty shard lowers the mean instead of being ignored
+func withAllShards(counts map[string]float64, shards []string) map[string]float64 {
+	for _, shardName := range shards {
+		if _, ok := counts[shardName]; !ok {
+			counts[shardName] = 0
+		}
+	}
+	return counts
+}
+
+// imbalanceRatio returns the largest count divided by the mean count across shards
+func imbalanceRatio(counts map[string]float64) (float64, bool) {
+	var total, largest float64
+	for _, count := range counts {
+		total += count
+		if count > largest {
+			largest = count
+		}
+	}
+	if total == 0 {
+		return 0, false
+	}
+	return largest / (total / float64(len(counts))), true
+}
+
+func (c *ShardKeyDistributionCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range c.descriptors {
+		ch <- desc
+	}
+}
+
+func (c *ShardKeyDistributionCollector) Name() string {
+	return "shard_key_distribution"
+}
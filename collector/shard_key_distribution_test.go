@@ -0,0 +1,41 @@
+package collector
+
+import (
+	"math"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestImbalanceRatio(t *testing.T) {
+	counts := withAllShards(map[string]float64{"shard0": 300, "shard1": 100}, []string{"shard0", "shard1", "shard2"})
+	if counts["shard2"] != 0 {
+		t.Error("Shards holding none of the collection should be counted as zero")
+	}
+
+	ratio, ok := imbalanceRatio(counts)
+	if !ok || math.Abs(ratio-2.25) > 1e-9 {
+		t.Errorf("Expected 300 / mean(300, 100, 0) = 2.25, got %v", ratio)
+	}
+
+	if ratio, _ := imbalanceRatio(map[string]float64{"shard0": 50, "shard1": 50}); ratio != 1 {
+		t.Errorf("Evenly spread collections should have a ratio of 1, got %v", ratio)
+	}
+	if _, ok := imbalanceRatio(map[string]float64{"shard0": 0}); ok {
+		t.Error("Empty collections should not report a ratio")
+	}
+}
+
+func TestShardKeyDistributionCollections(t *testing.T) {
+	config := CollectorConfig{
+		Collectors: map[string]interface{}{
+			"shard_key_distribution": map[string]interface{}{
+				"collections": []string{"app.orders", "events"},
+			},
+		},
+	}
+	collector := NewShardKeyDistributionCollector(nil, zap.NewNop(), config)
+	if len(collector.collections) != 1 || collector.collections[0].String() != "app.orders" {
+		t.Errorf("Only database.collection namespaces should be analyzed, got %v", collector.collections)
+	}
+}
@@ -2,17 +2,23 @@ package collector
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.uber.org/zap"
 )
 
 type ShardingCollector struct {
 	*BaseCollector
 	descriptors map[string]*prometheus.Desc
+	changelog   *changelogCounters
+	// mongosFreshness is how recent a mongos ping must be to count the router as active
+	mongosFreshness time.Duration
 }
 
 func NewShardingCollector(client *mongo.Client, logger *zap.Logger, config CollectorConfig) *ShardingCollector {
@@ -22,73 +28,163 @@ func NewShardingCollector(client *mongo.Client, logger *zap.Logger, config Colle
 
 	descriptors := map[string]*prometheus.Desc{
 		"mongos_up": prometheus.NewDesc(
-			"mongodb_mongos_up",
+			config.metricName("mongodb_mongos_up"),
 			"Whether the mongos instance is up",
 			labels,
 			nil,
 		),
 		"shards_total": prometheus.NewDesc(
-			"mongodb_shards_total",
+			config.metricName("mongodb_shards_total"),
 			"Total number of shards in the cluster",
 			labels,
 			nil,
 		),
 		"shard_chunks_total": prometheus.NewDesc(
-			"mongodb_shard_chunks_total",
+			config.metricName("mongodb_shard_chunks_total"),
 			"Total number of chunks per shard",
 			chunkLabels,
 			nil,
 		),
 		"balancer_enabled": prometheus.NewDesc(
-			"mongodb_balancer_enabled",
+			config.metricName("mongodb_balancer_enabled"),
 			"Whether the balancer is enabled (1) or disabled (0)",
 			labels,
 			nil,
 		),
 		"balancer_running": prometheus.NewDesc(
-			"mongodb_balancer_running",
+			config.metricName("mongodb_balancer_running"),
 			"Whether the balancer is currently running (1) or not (0)",
 			labels,
 			nil,
 		),
 		"balancer_migrations_total": prometheus.NewDesc(
-			"mongodb_balancer_migrations_total",
+			config.metricName("mongodb_balancer_migrations_total"),
 			"Total number of chunk migrations",
 			append(labels, "type"),
 			nil,
 		),
 		"shard_databases_total": prometheus.NewDesc(
-			"mongodb_shard_databases_total",
+			config.metricName("mongodb_shard_databases_total"),
 			"Number of databases on each shard",
 			shardLabels,
 			nil,
 		),
 		"shard_collections_total": prometheus.NewDesc(
-			"mongodb_shard_collections_total",
+			config.metricName("mongodb_shard_collections_total"),
 			"Number of sharded collections per shard",
 			shardLabels,
 			nil,
 		),
 		"sharded_collections_total": prometheus.NewDesc(
-			"mongodb_sharded_collections_total",
+			config.metricName("mongodb_sharded_collections_total"),
 			"Total number of sharded collections in the cluster",
 			labels,
 			nil,
 		),
+		"migrations_in_progress": prometheus.NewDesc(
+			config.metricName("mongodb_sharding_migrations_in_progress"),
+			"Number of chunk migrations currently registered in config.migrations",
+			labels,
+			nil,
+		),
+		"active_migrations": prometheus.NewDesc(
+			config.metricName("mongodb_sharding_active_migrations"),
+			"Number of chunk migrations currently running by namespace, source and destination shard",
+			append(labels, "database", "collection", "from_shard", "to_shard"),
+			nil,
+		),
+		"mongos_instances_total": prometheus.NewDesc(
+			config.metricName("mongodb_mongos_instances_total"),
+			"Number of mongos routers in config.mongos that pinged within the freshness window",
+			labels,
+			nil,
+		),
+		"mongos_last_ping_age_seconds": prometheus.NewDesc(
+			config.metricName("mongodb_mongos_last_ping_age_seconds"),
+			"Seconds since each mongos router registered in config.mongos last pinged",
+			append(labels, "mongos"),
+			nil,
+		),
+		"shard_draining": prometheus.NewDesc(
+			config.metricName("mongodb_shard_draining"),
+			"Whether the shard is being removed and drained of its chunks (1) or not (0)",
+			append(labels, "shard_name"),
+			nil,
+		),
+		"shard_draining_remaining_chunks": prometheus.NewDesc(
+			config.metricName("mongodb_shard_draining_remaining_chunks"),
+			"Number of chunks still on a draining shard",
+			append(labels, "shard_name"),
+			nil,
+		),
+		"chunk_size_bytes": prometheus.NewDesc(
+			config.metricName("mongodb_sharding_chunk_size_bytes"),
+			"Maximum chunk size the balancer splits and migrates by, from config.settings or the server default",
+			labels,
+			nil,
+		),
+		"chunk_size_configured": prometheus.NewDesc(
+			config.metricName("mongodb_sharding_chunk_size_configured"),
+			"Whether the chunk size is set explicitly in config.settings (1) or left at the server default (0)",
+			labels,
+			nil,
+		),
+		"balancer_last_round_duration_seconds": prometheus.NewDesc(
+			config.metricName("mongodb_balancer_last_round_duration_seconds"),
+			"Execution time of the most recent balancer round from config.actionlog",
+			labels,
+			nil,
+		),
+		"balancer_last_round_timestamp_seconds": prometheus.NewDesc(
+			config.metricName("mongodb_balancer_last_round_timestamp_seconds"),
+			"Unix time the most recent balancer round was logged",
+			labels,
+			nil,
+		),
+		"balancer_last_round_chunks_moved": prometheus.NewDesc(
+			config.metricName("mongodb_balancer_last_round_chunks_moved"),
+			"Number of chunks moved in the most recent balancer round",
+			labels,
+			nil,
+		),
+		"balancer_last_round_candidate_chunks": prometheus.NewDesc(
+			config.metricName("mongodb_balancer_last_round_candidate_chunks"),
+			"Number of chunks the most recent balancer round selected for migration",
+			labels,
+			nil,
+		),
+		"balancer_recent_rounds": prometheus.NewDesc(
+			config.metricName("mongodb_balancer_recent_rounds"),
+			"Number of recent balancer rounds read from config.actionlog",
+			labels,
+			nil,
+		),
+		"balancer_recent_rounds_failed": prometheus.NewDesc(
+			config.metricName("mongodb_balancer_recent_rounds_failed"),
+			"Number of recent balancer rounds that reported an error",
+			labels,
+			nil,
+		),
 		"chunk_migrations_failed_total": prometheus.NewDesc(
-			"mongodb_chunk_migrations_failed_total",
+			config.metricName("mongodb_chunk_migrations_failed_total"),
 			"Total number of failed chunk migrations",
 			labels,
 			nil,
 		),
+		"chunk_merges_total": prometheus.NewDesc(
+			config.metricName("mongodb_chunk_merges_total"),
+			"Total number of chunk merges",
+			labels,
+			nil,
+		),
 		"chunk_splits_total": prometheus.NewDesc(
-			"mongodb_chunk_splits_total",
+			config.metricName("mongodb_chunk_splits_total"),
 			"Total number of chunk splits",
 			labels,
 			nil,
 		),
 		"orphaned_documents": prometheus.NewDesc(
-			"mongodb_orphaned_documents",
+			config.metricName("mongodb_orphaned_documents"),
 			"Number of orphaned documents per shard",
 			shardLabels,
 			nil,
@@ -96,8 +192,10 @@ func NewShardingCollector(client *mongo.Client, logger *zap.Logger, config Colle
 	}
 
 	return &ShardingCollector{
-		BaseCollector: NewBaseCollector(client, logger, config),
-		descriptors:   descriptors,
+		BaseCollector:   NewBaseCollector(client, logger, config),
+		descriptors:     descriptors,
+		changelog:       newChangelogCounters(),
+		mongosFreshness: getDurationOption(collectorOptions(config, "sharding"), "mongos_ping_freshness", time.Minute),
 	}
 }
 
@@ -106,12 +204,12 @@ func (c *ShardingCollector) Collect(ch chan<- prometheus.Metric) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
-	defer cancel()
+	ctx, done := c.collectContext("sharding", 15*time.Second)
+	defer done()
 
 	// Check if this is a mongos instance
 	var isMaster bson.M
-	err := c.client.Database("admin").RunCommand(ctx, bson.D{{"isMaster", 1}}).Decode(&isMaster)
+	err := c.runCommand(ctx, "sharding", c.client.Database("admin"), bson.D{{"isMaster", 1}}, &isMaster)
 	if err != nil {
 		c.logger.Error("Failed to run isMaster command", zap.Error(err))
 		return
@@ -147,6 +245,12 @@ func (c *ShardingCollector) collectShardingMetrics(ctx context.Context, ch chan<
 	// Get balancer status
 	c.collectBalancerStatus(ctx, ch, instance)
 
+	// Get the configured chunk size
+	c.collectChunkSize(ctx, ch, instance)
+
+	// Get the routers registered in the cluster
+	c.collectMongosInstances(ctx, ch, instance)
+
 	// Get chunk distribution
 	c.collectChunkDistribution(ctx, ch, instance)
 
@@ -155,20 +259,46 @@ func (c *ShardingCollector) collectShardingMetrics(ctx context.Context, ch chan<
 
 	// Get migration statistics
 	c.collectMigrationStats(ctx, ch, instance)
+
+	// Get migrations running right now
+	c.collectActiveMigrations(ctx, ch, instance)
+
+	// Get the outcome of recent balancer rounds
+	c.collectBalancerRounds(ctx, ch, instance)
 }
 
 func (c *ShardingCollector) collectShardInfo(ctx context.Context, ch chan<- prometheus.Metric, instance map[string]string) {
 	// List shards
-	cursor, err := c.client.Database("config").Collection("shards").Find(ctx, bson.D{})
+	cursor, err := c.find(ctx, "sharding", c.client.Database("config").Collection("shards"), bson.D{}, &options.FindOptions{MaxTime: maxTime(ctx)})
 	if err != nil {
 		c.logger.Error("Failed to query config.shards", zap.Error(err))
 		return
 	}
 	defer cursor.Close(ctx)
 
-	var shards []bson.M
-	if err := cursor.All(ctx, &shards); err != nil {
-		c.logger.Error("Failed to decode shards", zap.Error(err))
+	shardCount := 0
+	for cursor.Next(ctx) {
+		var shard struct {
+			ID       string `bson:"_id"`
+			Host     string `bson:"host"`
+			Draining bool   `bson:"draining"`
+		}
+		shardCount++
+
+		if err := cursor.Decode(&shard); err != nil || shard.ID == "" || shard.Host == "" {
+			c.logger.Warn("Invalid shard data", zap.String("shard", cursor.Current.String()))
+			continue
+		}
+
+		// Count databases per shard
+		c.countDatabasesPerShard(ctx, ch, instance, shard.ID, shard.Host)
+
+		// Track shard removals to completion
+		c.collectDrainingStatus(ctx, ch, instance, shard.ID, shard.Draining)
+	}
+
+	if err := cursor.Err(); err != nil {
+		c.logger.Error("Failed to iterate shards", zap.Error(err))
 		return
 	}
 
@@ -176,33 +306,43 @@ func (c *ShardingCollector) collectShardInfo(ctx context.Context, ch chan<- prom
 	ch <- prometheus.MustNewConstMetric(
 		c.descriptors["shards_total"],
 		prometheus.GaugeValue,
-		float64(len(shards)),
+		float64(shardCount),
 		instance["instance"],
 		instance["replica_set"],
 		instance["shard"],
 	)
+}
 
-	// Per-shard metrics
-	for _, shard := range shards {
-		shardName, ok1 := shard["_id"].(string)
-		shardHost, ok2 := shard["host"].(string)
+// collectDrainingStatus reports whether a shard is draining and, while it is,
+// how many chunks removeShard still has to migrate off it
+func (c *ShardingCollector) collectDrainingStatus(ctx context.Context, ch chan<- prometheus.Metric, instance map[string]string, shardName string, draining bool) {
+	labels := []string{instance["instance"], instance["replica_set"], instance["shard"], shardName}
 
-		if !ok1 || !ok2 {
-			c.logger.Warn("Invalid shard data", zap.Any("shard", shard))
-			continue
-		}
+	drainingValue := 0.0
+	if draining {
+		drainingValue = 1.0
+	}
+	ch <- prometheus.MustNewConstMetric(c.descriptors["shard_draining"], prometheus.GaugeValue, drainingValue, labels...)
 
-		// Count databases per shard
-		c.countDatabasesPerShard(ctx, ch, instance, shardName, shardHost)
+	if !draining {
+		return
+	}
+
+	chunks, err := c.countDocuments(ctx, "sharding", c.client.Database("config").Collection("chunks"), bson.D{{"shard", shardName}},
+		&options.CountOptions{MaxTime: maxTime(ctx)})
+	if err != nil {
+		c.logger.Debug("Failed to count chunks on draining shard", zap.String("shard", shardName), zap.Error(err))
+		return
 	}
+	ch <- prometheus.MustNewConstMetric(c.descriptors["shard_draining_remaining_chunks"], prometheus.GaugeValue, float64(chunks), labels...)
 }
 
 func (c *ShardingCollector) collectBalancerStatus(ctx context.Context, ch chan<- prometheus.Metric, instance map[string]string) {
 	// Check balancer status
 	var balancerStatus bson.M
-	err := c.client.Database("admin").RunCommand(ctx, bson.D{{"balancerStatus", 1}}).Decode(&balancerStatus)
+	err := c.runCommand(ctx, "sharding", c.client.Database("admin"), bson.D{{"balancerStatus", 1}}, &balancerStatus)
 	if err != nil {
-		c.logger.Error("Failed to get balancer status", zap.Error(err))
+		c.logCommandError("Failed to get balancer status", err)
 		return
 	}
 
@@ -239,32 +379,162 @@ func (c *ShardingCollector) collectBalancerStatus(ctx context.Context, ch chan<-
 	}
 }
 
-func (c *ShardingCollector) collectChunkDistribution(ctx context.Context, ch chan<- prometheus.Metric, instance map[string]string) {
-	// Get chunk distribution from config.chunks
-	pipeline := []bson.D{
+// collectMongosInstances counts the routers that pinged recently and reports
+// every registered router's ping age. Routers that were shut down stay in
+// config.mongos, so a growing age spots a dead router still in the topology
+func (c *ShardingCollector) collectMongosInstances(ctx context.Context, ch chan<- prometheus.Metric, instance map[string]string) {
+	cursor, err := c.find(ctx, "sharding", c.client.Database("config").Collection("mongos"), bson.D{},
+		&options.FindOptions{Projection: bson.D{{"_id", 1}, {"ping", 1}}, MaxTime: maxTime(ctx)})
+	if err != nil {
+		c.logger.Debug("Failed to query config.mongos", zap.Error(err))
+		return
+	}
+
+	var routers []struct {
+		ID   string    `bson:"_id"`
+		Ping time.Time `bson:"ping"`
+	}
+	if err := cursor.All(ctx, &routers); err != nil {
+		c.logger.Debug("Failed to decode config.mongos entries", zap.Error(err))
+		return
+	}
+
+	labels := []string{instance["instance"], instance["replica_set"], instance["shard"]}
+	now := time.Now()
+	active := 0
+	for _, router := range routers {
+		age := now.Sub(router.Ping)
+		if age <= c.mongosFreshness {
+			active++
+		}
+		ch <- prometheus.MustNewConstMetric(
+			c.descriptors["mongos_last_ping_age_seconds"],
+			prometheus.GaugeValue,
+			age.Seconds(),
+			append(labels, router.ID)...,
+		)
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.descriptors["mongos_instances_total"], prometheus.GaugeValue, float64(active), labels...)
+}
+
+// defaultChunkSizeMB is the chunk size used when config.settings has no
+// chunksize document; MongoDB 6.0 doubled it from 64MB
+func defaultChunkSizeMB(majorVersion int) float64 {
+	if majorVersion >= 6 {
+		return 128
+	}
+	return 64
+}
+
+func (c *ShardingCollector) collectChunkSize(ctx context.Context, ch chan<- prometheus.Metric, instance map[string]string) {
+	var setting bson.M
+	err := c.findOne(ctx, "sharding", c.client.Database("config").Collection("settings"), bson.D{{"_id", "chunksize"}}, &setting,
+		&options.FindOneOptions{MaxTime: maxTime(ctx)})
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		c.logger.Debug("Failed to read chunk size setting", zap.Error(err))
+		return
+	}
+
+	// The setting is stored in megabytes
+	sizeMB, configured := toFloat64(setting["value"])
+	if !configured {
+		majorVersion, err := c.serverMajorVersion(ctx)
+		if err != nil {
+			c.logger.Debug("Failed to determine server version for the default chunk size", zap.Error(err))
+			return
+		}
+		sizeMB = defaultChunkSizeMB(majorVersion)
+	}
+
+	configuredValue := 0.0
+	if configured {
+		configuredValue = 1.0
+	}
+	labels := []string{instance["instance"], instance["replica_set"], instance["shard"]}
+	ch <- prometheus.MustNewConstMetric(c.descriptors["chunk_size_bytes"], prometheus.GaugeValue, sizeMB*1024*1024, labels...)
+	ch <- prometheus.MustNewConstMetric(c.descriptors["chunk_size_configured"], prometheus.GaugeValue, configuredValue, labels...)
+}
+
+// chunkPipeline groups config.chunks by namespace and shard. From MongoDB 5.0
+// chunks reference their collection by UUID instead of ns, so the namespace is
+// resolved from config.collections; ns is still used for chunks that carry it
+func chunkPipeline(majorVersion int) []bson.D {
+	if majorVersion < 5 {
+		return []bson.D{
+			{{"$group", bson.D{
+				{"_id", bson.D{
+					{"ns", "$ns"},
+					{"shard", "$shard"},
+				}},
+				{"count", bson.D{{"$sum", 1}}},
+			}}},
+		}
+	}
+
+	return []bson.D{
 		{{"$group", bson.D{
 			{"_id", bson.D{
 				{"ns", "$ns"},
+				{"uuid", "$uuid"},
 				{"shard", "$shard"},
 			}},
 			{"count", bson.D{{"$sum", 1}}},
 		}}},
+		{{"$lookup", bson.D{
+			{"from", "collections"},
+			{"localField", "_id.uuid"},
+			{"foreignField", "uuid"},
+			{"as", "collection"},
+		}}},
+		{{"$project", bson.D{
+			{"_id", bson.D{
+				{"ns", bson.D{{"$ifNull", bson.A{"$_id.ns", bson.D{{"$first", "$collection._id"}}}}}},
+				{"shard", "$_id.shard"},
+			}},
+			{"count", 1},
+		}}},
 	}
+}
 
-	cursor, err := c.client.Database("config").Collection("chunks").Aggregate(ctx, pipeline)
+// serverMajorVersion returns the major version reported by buildInfo
+func (c *ShardingCollector) serverMajorVersion(ctx context.Context) (int, error) {
+	var buildInfo bson.M
+	if err := c.runCommand(ctx, "sharding", c.client.Database("admin"), bson.D{{"buildInfo", 1}}, &buildInfo); err != nil {
+		return 0, err
+	}
+	version, _ := buildInfo["version"].(string)
+	major, ok := parseMajorVersion(version)
+	if !ok {
+		return 0, fmt.Errorf("unrecognized server version %q", version)
+	}
+	return major, nil
+}
+
+func (c *ShardingCollector) collectChunkDistribution(ctx context.Context, ch chan<- prometheus.Metric, instance map[string]string) {
+	// Get chunk distribution from config.chunks
+	majorVersion, err := c.serverMajorVersion(ctx)
+	if err != nil {
+		// The pre-5.0 schema is the safest guess; it reports nothing rather
+		// than misattributing chunks on newer clusters
+		c.logger.Debug("Failed to determine server version for chunk metadata", zap.Error(err))
+	}
+	pipeline := chunkPipeline(majorVersion)
+
+	cursor, err := c.aggregate(ctx, "sharding", c.client.Database("config").Collection("chunks"), pipeline, &options.AggregateOptions{MaxTime: maxTime(ctx)})
 	if err != nil {
 		c.logger.Error("Failed to aggregate chunks", zap.Error(err))
 		return
 	}
 	defer cursor.Close(ctx)
 
-	var results []bson.M
-	if err := cursor.All(ctx, &results); err != nil {
-		c.logger.Error("Failed to decode chunk distribution", zap.Error(err))
-		return
-	}
+	for cursor.Next(ctx) {
+		var result bson.M
+		if err := cursor.Decode(&result); err != nil {
+			c.logger.Debug("Failed to decode chunk distribution entry", zap.Error(err))
+			continue
+		}
 
-	for _, result := range results {
 		id, ok := result["_id"].(bson.M)
 		if !ok {
 			continue
@@ -272,7 +542,7 @@ func (c *ShardingCollector) collectChunkDistribution(ctx context.Context, ch cha
 
 		ns, ok1 := id["ns"].(string)
 		shardName, ok2 := id["shard"].(string)
-		count, ok3 := result["count"].(int32)
+		count, ok3 := toInt64(result["count"])
 
 		if !ok1 || !ok2 || !ok3 {
 			continue
@@ -293,99 +563,191 @@ func (c *ShardingCollector) collectChunkDistribution(ctx context.Context, ch cha
 			shardName,
 		)
 	}
+
+	if err := cursor.Err(); err != nil {
+		c.logger.Error("Chunk distribution scan stopped early", zap.Error(err))
+	}
 }
 
 func (c *ShardingCollector) collectDatabaseShardDistribution(ctx context.Context, ch chan<- prometheus.Metric, instance map[string]string) {
-	// Count sharded collections
-	cursor, err := c.client.Database("config").Collection("collections").Find(ctx, bson.D{})
+	// Count sharded collections server-side instead of fetching every document
+	collections, err := c.countDocuments(ctx, "sharding", c.client.Database("config").Collection("collections"), bson.D{}, &options.CountOptions{MaxTime: maxTime(ctx)})
 	if err != nil {
-		c.logger.Error("Failed to query config.collections", zap.Error(err))
-		return
-	}
-	defer cursor.Close(ctx)
-
-	var collections []bson.M
-	if err := cursor.All(ctx, &collections); err != nil {
-		c.logger.Error("Failed to decode collections", zap.Error(err))
+		c.logger.Error("Failed to count config.collections", zap.Error(err))
 		return
 	}
 
 	ch <- prometheus.MustNewConstMetric(
 		c.descriptors["sharded_collections_total"],
 		prometheus.GaugeValue,
-		float64(len(collections)),
+		float64(collections),
 		instance["instance"],
 		instance["replica_set"],
 		instance["shard"],
 	)
 }
 
-func (c *ShardingCollector) collectMigrationStats(ctx context.Context, ch chan<- prometheus.Metric, instance map[string]string) {
-	// Get migration history from config.changelog
-	pipeline := []bson.D{
-		{{"$match", bson.D{
-			{"what", bson.D{{"$in", []string{"moveChunk.from", "moveChunk.to", "moveChunk.commit"}}}},
-		}}},
-		{{"$group", bson.D{
-			{"_id", "$what"},
-			{"count", bson.D{{"$sum", 1}}},
-		}}},
-	}
+// migrationEvents are the changelog events reported by balancer_migrations_total
+var migrationEvents = []string{"moveChunk.from", "moveChunk.to", "moveChunk.commit"}
 
-	cursor, err := c.client.Database("config").Collection("changelog").Aggregate(ctx, pipeline)
+func (c *ShardingCollector) collectMigrationStats(ctx context.Context, ch chan<- prometheus.Metric, instance map[string]string) {
+	counts, err := c.changelog.refresh(ctx, c.BaseCollector, c.client.Database("config").Collection("changelog"))
 	if err != nil {
 		c.logger.Debug("Failed to query config.changelog", zap.Error(err))
-		return // This collection might not exist in older versions
+		return
 	}
-	defer cursor.Close(ctx)
 
-	var results []bson.M
-	if err := cursor.All(ctx, &results); err != nil {
-		c.logger.Error("Failed to decode migration stats", zap.Error(err))
-		return
+	labels := []string{instance["instance"], instance["replica_set"], instance["shard"]}
+	for _, migType := range migrationEvents {
+		ch <- prometheus.MustNewConstMetric(
+			c.descriptors["balancer_migrations_total"],
+			prometheus.CounterValue,
+			counts[migType],
+			append(labels, migType)...,
+		)
 	}
 
-	for _, result := range results {
-		migType, ok1 := result["_id"].(string)
-		count, ok2 := result["count"].(int32)
+	for descKey, value := range map[string]float64{
+		"chunk_migrations_failed_total": counts["moveChunk.error"],
+		"chunk_splits_total":            counts["split"] + counts["multi-split"],
+		"chunk_merges_total":            counts["merge"],
+	} {
+		ch <- prometheus.MustNewConstMetric(c.descriptors[descKey], prometheus.CounterValue, value, labels...)
+	}
+}
 
-		if !ok1 || !ok2 {
-			continue
-		}
+// activeMigrationsPipeline groups the config.migrations entries, one per
+// running chunk migration, by namespace and shard pair
+var activeMigrationsPipeline = []bson.D{
+	{{"$group", bson.D{
+		{"_id", bson.D{{"ns", "$ns"}, {"from", "$fromShard"}, {"to", "$toShard"}}},
+		{"count", bson.D{{"$sum", 1}}},
+	}}},
+}
+
+func (c *ShardingCollector) collectActiveMigrations(ctx context.Context, ch chan<- prometheus.Metric, instance map[string]string) {
+	cursor, err := c.aggregate(ctx, "sharding", c.client.Database("config").Collection("migrations"), activeMigrationsPipeline, &options.AggregateOptions{MaxTime: maxTime(ctx)})
+	if err != nil {
+		c.logger.Debug("Failed to query config.migrations", zap.Error(err))
+		return
+	}
 
+	var groups []struct {
+		ID struct {
+			NS   string `bson:"ns"`
+			From string `bson:"from"`
+			To   string `bson:"to"`
+		} `bson:"_id"`
+		Count int64 `bson:"count"`
+	}
+	if err := cursor.All(ctx, &groups); err != nil {
+		c.logger.Debug("Failed to decode config.migrations entries", zap.Error(err))
+		return
+	}
+
+	var total int64
+	for _, group := range groups {
+		total += group.Count
+		database, collection := parseNamespace(group.ID.NS)
 		ch <- prometheus.MustNewConstMetric(
-			c.descriptors["balancer_migrations_total"],
-			prometheus.CounterValue,
-			float64(count),
+			c.descriptors["active_migrations"],
+			prometheus.GaugeValue,
+			float64(group.Count),
 			instance["instance"],
 			instance["replica_set"],
 			instance["shard"],
-			migType,
+			database,
+			collection,
+			group.ID.From,
+			group.ID.To,
 		)
 	}
+
+	ch <- prometheus.MustNewConstMetric(
+		c.descriptors["migrations_in_progress"],
+		prometheus.GaugeValue,
+		float64(total),
+		instance["instance"],
+		instance["replica_set"],
+		instance["shard"],
+	)
 }
 
-func (c *ShardingCollector) countDatabasesPerShard(ctx context.Context, ch chan<- prometheus.Metric, instance map[string]string, shardName, shardHost string) {
-	// Count databases on this shard
-	cursor, err := c.client.Database("config").Collection("databases").Find(ctx, bson.D{
-		{"primary", shardName},
-	})
+// recentBalancerRounds is how many of the latest balancer rounds are inspected for errors
+const recentBalancerRounds = 10
+
+// balancerRound is a "balancer.round" entry of config.actionlog
+type balancerRound struct {
+	Time    time.Time `bson:"time"`
+	Details struct {
+		ExecutionTimeMillis float64 `bson:"executionTimeMillis"`
+		// The server spells this field "errorOccured"
+		ErrorOccurred   bool    `bson:"errorOccured"`
+		CandidateChunks float64 `bson:"candidateChunks"`
+		ChunksMoved     float64 `bson:"chunksMoved"`
+	} `bson:"details"`
+}
+
+// failedRounds counts the rounds that reported an error
+func failedRounds(rounds []balancerRound) int {
+	failed := 0
+	for _, round := range rounds {
+		if round.Details.ErrorOccurred {
+			failed++
+		}
+	}
+	return failed
+}
+
+func (c *ShardingCollector) collectBalancerRounds(ctx context.Context, ch chan<- prometheus.Metric, instance map[string]string) {
+	findOptions := options.Find().
+		SetSort(bson.D{{"time", -1}}).
+		SetLimit(recentBalancerRounds)
+	findOptions.MaxTime = maxTime(ctx)
+
+	cursor, err := c.find(ctx, "sharding", c.client.Database("config").Collection("actionlog"), bson.D{{"what", "balancer.round"}}, findOptions)
 	if err != nil {
-		c.logger.Error("Failed to query config.databases", zap.Error(err))
+		c.logger.Debug("Failed to query config.actionlog", zap.Error(err))
+		return
+	}
+
+	var rounds []balancerRound
+	if err := cursor.All(ctx, &rounds); err != nil {
+		c.logger.Debug("Failed to decode config.actionlog entries", zap.Error(err))
+		return
+	}
+	if len(rounds) == 0 {
 		return
 	}
-	defer cursor.Close(ctx)
 
-	var databases []bson.M
-	if err := cursor.All(ctx, &databases); err != nil {
-		c.logger.Error("Failed to decode databases", zap.Error(err))
+	labels := []string{instance["instance"], instance["replica_set"], instance["shard"]}
+	last := rounds[0]
+	for descKey, value := range map[string]float64{
+		"balancer_last_round_duration_seconds":  last.Details.ExecutionTimeMillis / 1000,
+		"balancer_last_round_timestamp_seconds": float64(last.Time.Unix()),
+		"balancer_last_round_chunks_moved":      last.Details.ChunksMoved,
+		"balancer_last_round_candidate_chunks":  last.Details.CandidateChunks,
+		"balancer_recent_rounds":                float64(len(rounds)),
+		"balancer_recent_rounds_failed":         float64(failedRounds(rounds)),
+	} {
+		ch <- prometheus.MustNewConstMetric(c.descriptors[descKey], prometheus.GaugeValue, value, labels...)
+	}
+}
+
+func (c *ShardingCollector) countDatabasesPerShard(ctx context.Context, ch chan<- prometheus.Metric, instance map[string]string, shardName, shardHost string) {
+	// Count databases on this shard
+	databases, err := c.countDocuments(ctx, "sharding", c.client.Database("config").Collection("databases"), bson.D{
+		{"primary", shardName},
+	}, &options.CountOptions{MaxTime: maxTime(ctx)})
+	if err != nil {
+		c.logger.Error("Failed to count config.databases", zap.Error(err))
 		return
 	}
 
 	ch <- prometheus.MustNewConstMetric(
 		c.descriptors["shard_databases_total"],
 		prometheus.GaugeValue,
-		float64(len(databases)),
+		float64(databases),
 		instance["instance"],
 		instance["replica_set"],
 		instance["shard"],
@@ -0,0 +1,63 @@
+package collector
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestChunkPipeline(t *testing.T) {
+	if pipeline := chunkPipeline(4); len(pipeline) != 1 {
+		t.Errorf("Pre-5.0 chunks should be grouped by ns directly, got %d stages", len(pipeline))
+	}
+
+	pipeline := chunkPipeline(7)
+	if len(pipeline) != 3 || pipeline[1][0].Key != "$lookup" {
+		t.Fatalf("5.0+ chunks should resolve their namespace from config.collections, got %v", pipeline)
+	}
+	lookup := pipeline[1][0].Value.(bson.D)
+	if lookup[0].Value != "collections" || lookup[1].Value != "_id.uuid" {
+		t.Errorf("Lookup should join chunk UUIDs against config.collections, got %v", lookup)
+	}
+}
+
+func TestActiveMigrationsPipelineGroupsByShardPair(t *testing.T) {
+	group, ok := activeMigrationsPipeline[0][0].Value.(bson.D)
+	if activeMigrationsPipeline[0][0].Key != "$group" || !ok {
+		t.Fatal("Active migrations should be grouped server-side")
+	}
+	id, ok := group[0].Value.(bson.D)
+	if !ok || len(id) != 3 || id[0].Value != "$ns" || id[1].Value != "$fromShard" || id[2].Value != "$toShard" {
+		t.Errorf("Migrations should be grouped by namespace and shard pair, got %v", group[0].Value)
+	}
+}
+
+func TestFailedRounds(t *testing.T) {
+	raw, err := bson.Marshal(bson.D{
+		{"what", "balancer.round"},
+		{"details", bson.D{{"executionTimeMillis", 120}, {"errorOccured", true}, {"chunksMoved", 0}}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to marshal round: %v", err)
+	}
+	var failed balancerRound
+	if err := bson.Unmarshal(raw, &failed); err != nil {
+		t.Fatalf("Failed to decode round: %v", err)
+	}
+
+	if !failed.Details.ErrorOccurred || failed.Details.ExecutionTimeMillis != 120 {
+		t.Errorf("Round details should be decoded from the server's field names, got %+v", failed.Details)
+	}
+	if got := failedRounds([]balancerRound{failed, {}, failed}); got != 2 {
+		t.Errorf("Two of three rounds should count as failed, got %d", got)
+	}
+}
+
+func TestDefaultChunkSizeMB(t *testing.T) {
+	if got := defaultChunkSizeMB(5); got != 64 {
+		t.Errorf("Pre-6.0 default chunk size should be 64MB, got %v", got)
+	}
+	if got := defaultChunkSizeMB(7); got != 128 {
+		t.Errorf("6.0+ default chunk size should be 128MB, got %v", got)
+	}
+}
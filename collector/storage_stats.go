@@ -1,9 +1,9 @@
 package collector
 
 import (
-	"context"
 	"time"
 
+	"github.com/jimohabdol/mongodb-exporter/database"
 	"github.com/prometheus/client_golang/prometheus"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -21,43 +21,43 @@ func NewStorageStatsCollector(client *mongo.Client, logger *zap.Logger, config C
 
 	descriptors := map[string]*prometheus.Desc{
 		"database_size_bytes": prometheus.NewDesc(
-			"mongodb_database_size_bytes",
+			config.metricName("mongodb_database_size_bytes"),
 			"Total size of the database in bytes",
 			labels,
 			nil,
 		),
 		"collection_size_bytes": prometheus.NewDesc(
-			"mongodb_collection_size_bytes",
+			config.metricName("mongodb_collection_size_bytes"),
 			"Total size of the collection in bytes",
 			collectionLabels,
 			nil,
 		),
 		"collection_storage_size_bytes": prometheus.NewDesc(
-			"mongodb_collection_storage_size_bytes",
+			config.metricName("mongodb_collection_storage_size_bytes"),
 			"Total storage size of the collection in bytes",
 			collectionLabels,
 			nil,
 		),
 		"collection_avg_obj_size_bytes": prometheus.NewDesc(
-			"mongodb_collection_avg_obj_size_bytes",
+			config.metricName("mongodb_collection_avg_obj_size_bytes"),
 			"Average object size in the collection in bytes",
 			collectionLabels,
 			nil,
 		),
 		"collection_count": prometheus.NewDesc(
-			"mongodb_collection_count",
+			config.metricName("mongodb_collection_count"),
 			"Number of documents in the collection",
 			collectionLabels,
 			nil,
 		),
 		"collection_index_size_bytes": prometheus.NewDesc(
-			"mongodb_collection_index_size_bytes",
+			config.metricName("mongodb_collection_index_size_bytes"),
 			"Total size of all indexes in the collection",
 			collectionLabels,
 			nil,
 		),
 		"collection_capped": prometheus.NewDesc(
-			"mongodb_collection_capped",
+			config.metricName("mongodb_collection_capped"),
 			"Whether the collection is capped (1) or not (0)",
 			collectionLabels,
 			nil,
@@ -75,17 +75,22 @@ func (c *StorageStatsCollector) Collect(ch chan<- prometheus.Metric) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+	ctx, done := c.collectContext("storage_stats", 10*time.Second)
+	defer done()
 
 	// Get list of databases
-	databases, err := c.client.ListDatabaseNames(ctx, bson.D{})
+	databases, err := c.listDatabases(ctx, 10*time.Second)
 	if err != nil {
 		c.logger.Error("Failed to list databases", zap.Error(err))
 		return
 	}
 
-	instance := c.getInstanceInfo(bson.M{})
+	// dbStats carries no host or replica set, so the labels come from hello
+	hello, err := database.Hello(ctx, c.adminCommand("storage_stats"))
+	if err != nil {
+		c.logCommandError("Failed to run hello", err)
+	}
+	instance := c.getInstanceInfo(helloInstance(hello))
 
 	for _, dbName := range databases {
 		// Skip admin and local databases
@@ -95,7 +100,7 @@ func (c *StorageStatsCollector) Collect(ch chan<- prometheus.Metric) {
 
 		// Get database stats
 		var dbStats bson.M
-		if err := c.client.Database(dbName).RunCommand(ctx, bson.D{{"dbStats", 1}}).Decode(&dbStats); err != nil {
+		if err := c.runCommand(ctx, "storage_stats", c.client.Database(dbName), bson.D{{"dbStats", 1}}, &dbStats); err != nil {
 			c.logger.Error("Failed to get database stats",
 				zap.String("database", dbName),
 				zap.Error(err))
@@ -103,7 +108,7 @@ func (c *StorageStatsCollector) Collect(ch chan<- prometheus.Metric) {
 		}
 
 		// Database size
-		if dataSize, ok := dbStats["dataSize"].(int64); ok {
+		if dataSize, ok := toInt64(dbStats["dataSize"]); ok {
 			ch <- prometheus.MustNewConstMetric(
 				c.descriptors["database_size_bytes"],
 				prometheus.GaugeValue,
@@ -117,7 +122,7 @@ func (c *StorageStatsCollector) Collect(ch chan<- prometheus.Metric) {
 
 		// Get collections
 		db := c.client.Database(dbName)
-		collections, err := db.ListCollectionNames(ctx, bson.D{})
+		collections, err := c.listCollections(ctx, dbName, 10*time.Second)
 		if err != nil {
 			c.logger.Error("Failed to list collections",
 				zap.String("database", dbName),
@@ -127,7 +132,7 @@ func (c *StorageStatsCollector) Collect(ch chan<- prometheus.Metric) {
 
 		for _, collName := range collections {
 			var collStats bson.M
-			if err := db.RunCommand(ctx, bson.D{{"collStats", collName}}).Decode(&collStats); err != nil {
+			if err := c.runCommand(ctx, "storage_stats", db, bson.D{{"collStats", collName}}, &collStats); err != nil {
 				c.logger.Error("Failed to get collection stats",
 					zap.String("database", dbName),
 					zap.String("collection", collName),
@@ -145,7 +150,7 @@ func (c *StorageStatsCollector) Collect(ch chan<- prometheus.Metric) {
 			}
 
 			for statName, metricName := range metrics {
-				if value, ok := collStats[statName].(int64); ok {
+				if value, ok := toInt64(collStats[statName]); ok {
 					if desc, ok := c.descriptors[metricName]; ok {
 						ch <- prometheus.MustNewConstMetric(
 							desc,
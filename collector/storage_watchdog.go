@@ -0,0 +1,107 @@
+package collector
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// storageWatchdogDescriptors describes the storage watchdog and disk space
+// metrics that warn before mongod aborts because its storage stopped
+// responding or its disk filled up
+func storageWatchdogDescriptors(config CollectorConfig) map[string]*prometheus.Desc {
+	labels := []string{"instance", "replica_set", "shard"}
+
+	return map[string]*prometheus.Desc{
+		"storage_watchdog_checks_total": prometheus.NewDesc(
+			config.metricName("mongodb_storage_watchdog_checks_total"),
+			"Storage watchdog checks completed; a counter that stops increasing means storage is hung and mongod will abort",
+			labels,
+			nil,
+		),
+		"storage_watchdog_monitor_runs_total": prometheus.NewDesc(
+			config.metricName("mongodb_storage_watchdog_monitor_runs_total"),
+			"Storage watchdog monitor runs",
+			labels,
+			nil,
+		),
+		"storage_watchdog_period_seconds": prometheus.NewDesc(
+			config.metricName("mongodb_storage_watchdog_period_seconds"),
+			"Configured storage watchdog period (watchdogPeriodSeconds)",
+			labels,
+			nil,
+		),
+		"disk_space_monitor": prometheus.NewDesc(
+			config.metricName("mongodb_storage_disk_space_monitor"),
+			"Numeric fields of the serverStatus diskSpaceMonitor section",
+			append(append([]string{}, labels...), "field"),
+			nil,
+		),
+		"filesystem_used_bytes": prometheus.NewDesc(
+			config.metricName("mongodb_storage_filesystem_used_bytes"),
+			"Space used on the filesystem holding the data directory",
+			labels,
+			nil,
+		),
+		"filesystem_size_bytes": prometheus.NewDesc(
+			config.metricName("mongodb_storage_filesystem_size_bytes"),
+			"Total size of the filesystem holding the data directory",
+			labels,
+			nil,
+		),
+	}
+}
+
+// collectStorageWatchdog exports the serverStatus watchdog section, present on
+// MongoDB Enterprise and Percona Server with watchdogPeriodSeconds set, and the
+// diskSpaceMonitor section where the server reports one
+func (c *ServerStatusCollector) collectStorageWatchdog(ch chan<- prometheus.Metric, result bson.M, instance map[string]string) {
+	labels := []string{instance["instance"], instance["replica_set"], instance["shard"]}
+
+	if watchdog, ok := result["watchdog"].(bson.M); ok {
+		fields := []struct {
+			key       string
+			descKey   string
+			valueType prometheus.ValueType
+		}{
+			{"checkGeneration", "storage_watchdog_checks_total", prometheus.CounterValue},
+			{"monitorGeneration", "storage_watchdog_monitor_runs_total", prometheus.CounterValue},
+			{"monitorPeriod", "storage_watchdog_period_seconds", prometheus.GaugeValue},
+		}
+		for _, field := range fields {
+			if value := c.getNumericValue(watchdog[field.key]); value != nil {
+				ch <- prometheus.MustNewConstMetric(c.descriptors[field.descKey], field.valueType, *value, labels...)
+			}
+		}
+	}
+
+	if monitor, ok := result["diskSpaceMonitor"].(bson.M); ok {
+		for field, raw := range monitor {
+			if value := c.getNumericValue(raw); value != nil {
+				ch <- prometheus.MustNewConstMetric(c.descriptors["disk_space_monitor"], prometheus.GaugeValue, *value,
+					append(labels, field)...)
+			}
+		}
+	}
+}
+
+// collectFilesystemUsage exports the usage of the filesystem holding the data
+// directory, which every server reports through dbStats
+func (c *ServerStatusCollector) collectFilesystemUsage(ctx context.Context, ch chan<- prometheus.Metric, instance map[string]string) {
+	labels := []string{instance["instance"], instance["replica_set"], instance["shard"]}
+
+	// fsUsedSize and fsTotalSize describe the whole filesystem, so the small
+	// admin database is enough to read them
+	var dbStats bson.M
+	if err := c.runCommand(ctx, "server_status", c.client.Database("admin"), bson.D{{"dbStats", 1}}, &dbStats); err != nil {
+		c.logCommandError("Failed to get filesystem usage", err)
+		return
+	}
+	if used := c.getNumericValue(dbStats["fsUsedSize"]); used != nil {
+		ch <- prometheus.MustNewConstMetric(c.descriptors["filesystem_used_bytes"], prometheus.GaugeValue, *used, labels...)
+	}
+	if total := c.getNumericValue(dbStats["fsTotalSize"]); total != nil {
+		ch <- prometheus.MustNewConstMetric(c.descriptors["filesystem_size_bytes"], prometheus.GaugeValue, *total, labels...)
+	}
+}
@@ -0,0 +1,113 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// timeoutHistorySize is the number of recent runs considered per collector
+	timeoutHistorySize = 20
+	// timeoutHeadroom multiplies the slowest recent run to leave room for variance
+	timeoutHeadroom = 2
+)
+
+// timeoutTuner derives per-collector deadlines from their recent run durations,
+// clamped to [min, max], so slow clusters are not cut off by fixed timeouts
+// while fast clusters still fail quickly
+type timeoutTuner struct {
+	min time.Duration
+	max time.Duration
+
+	mu      sync.Mutex
+	history map[string][]time.Duration
+}
+
+func newTimeoutTuner(min, max time.Duration) *timeoutTuner {
+	return &timeoutTuner{
+		min:     min,
+		max:     max,
+		history: make(map[string][]time.Duration),
+	}
+}
+
+// Timeout returns the deadline for the named collector, or fallback (clamped) until it has history
+func (t *timeoutTuner) Timeout(name string, fallback time.Duration) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	history := t.history[name]
+	if len(history) == 0 {
+		return t.clamp(fallback)
+	}
+
+	var slowest time.Duration
+	for _, d := range history {
+		if d > slowest {
+			slowest = d
+		}
+	}
+
+	return t.clamp(slowest * timeoutHeadroom)
+}
+
+// Observe records how long a run of the named collector took
+func (t *timeoutTuner) Observe(name string, duration time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	history := append(t.history[name], duration)
+	if len(history) > timeoutHistorySize {
+		history = history[len(history)-timeoutHistorySize:]
+	}
+	t.history[name] = history
+}
+
+func (t *timeoutTuner) clamp(d time.Duration) time.Duration {
+	if d < t.min {
+		return t.min
+	}
+	if d > t.max {
+		return t.max
+	}
+	return d
+}
+
+// collectContext returns the context a collector run should use and a func that
+// ends the run. With adaptive timeouts enabled, the deadline comes from the
+// collector's recent durations and the run's duration is recorded on completion.
+// With tracing enabled, the context carries the span of the collector's run.
+// Under a collector manager, the context is cancelled when the manager shuts down.
+func (bc *BaseCollector) collectContext(name string, fallback time.Duration) (context.Context, func()) {
+	parent := context.Background()
+	if bc.config.lifetime != nil {
+		parent = bc.config.lifetime.ctx
+	}
+	if bc.config.tracer != nil {
+		parent = trace.ContextWithSpan(parent, trace.SpanFromContext(bc.config.tracer.parent(name)))
+	}
+
+	tuner := bc.config.timeouts
+	if tuner == nil {
+		return context.WithTimeout(parent, fallback)
+	}
+
+	timeout := tuner.Timeout(name, fallback)
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	start := time.Now()
+
+	return ctx, func() {
+		elapsed := time.Since(start)
+		// A run cut off by its deadline only shows the timeout was too short;
+		// recording it lets the next deadline grow instead of staying pinned
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			elapsed = timeout
+		}
+		cancel()
+		tuner.Observe(name, elapsed)
+	}
+}
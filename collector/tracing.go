@@ -0,0 +1,72 @@
+package collector
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/jimohabdol/mongodb-exporter/collector"
+
+// scrapeTracer records a span per scrape with a child span per collector run.
+// Collectors take their context from the span of their current run, so the
+// MongoDB command spans of the instrumented client nest beneath it.
+type scrapeTracer struct {
+	tracer trace.Tracer
+
+	mu      sync.Mutex
+	parents map[string]context.Context
+}
+
+func newScrapeTracer() *scrapeTracer {
+	return &scrapeTracer{
+		tracer:  otel.Tracer(tracerName),
+		parents: make(map[string]context.Context),
+	}
+}
+
+// startScrape starts the span covering one collection of every collector
+func (t *scrapeTracer) startScrape() (context.Context, trace.Span) {
+	return t.tracer.Start(context.Background(), "scrape")
+}
+
+// startCollector starts the span for one collector run and returns the func
+// that ends it, marking the span failed when the run failed
+func (t *scrapeTracer) startCollector(ctx context.Context, name string) func(received int, err error) {
+	ctx, span := t.tracer.Start(ctx, "collect "+name, trace.WithAttributes(attribute.String("collector", name)))
+
+	t.mu.Lock()
+	t.parents[name] = ctx
+	t.mu.Unlock()
+
+	return func(received int, err error) {
+		t.mu.Lock()
+		if t.parents[name] == ctx {
+			delete(t.parents, name)
+		}
+		t.mu.Unlock()
+
+		span.SetAttributes(attribute.Int("metrics", received))
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
+
+// parent returns the context of the collector's current run, or a background
+// context when the collector runs outside a traced scrape
+func (t *scrapeTracer) parent(name string) context.Context {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if ctx, ok := t.parents[name]; ok {
+		return ctx
+	}
+	return context.Background()
+}
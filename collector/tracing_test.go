@@ -0,0 +1,58 @@
+package collector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+type tracedCollector struct {
+	MockCollector
+	tracer *scrapeTracer
+	seen   trace.SpanContext
+}
+
+func (c *tracedCollector) Collect(ch chan<- prometheus.Metric) {
+	c.seen = trace.SpanContextFromContext(c.tracer.parent(c.name))
+	c.MockCollector.Collect(ch)
+}
+
+func TestMultiCollectorTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	tracer := &scrapeTracer{tracer: provider.Tracer(tracerName), parents: make(map[string]context.Context)}
+
+	traced := &tracedCollector{MockCollector: MockCollector{name: "server_status"}, tracer: tracer}
+	mc := NewMultiCollector(zap.NewNop())
+	mc.tracer = tracer
+	mc.AddCollector(traced)
+
+	ch := make(chan prometheus.Metric, 10)
+	mc.Collect(ch)
+	close(ch)
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("Expected scrape and collector spans, got %d", len(spans))
+	}
+
+	collectSpan, scrapeSpan := spans[0], spans[1]
+	if scrapeSpan.Name() != "scrape" || collectSpan.Name() != "collect server_status" {
+		t.Fatalf("Unexpected span names %q and %q", scrapeSpan.Name(), collectSpan.Name())
+	}
+	if collectSpan.Parent().SpanID() != scrapeSpan.SpanContext().SpanID() {
+		t.Error("Collector span should be a child of the scrape span")
+	}
+	if traced.seen.SpanID() != collectSpan.SpanContext().SpanID() {
+		t.Error("Collector context should carry its run's span so command spans nest beneath it")
+	}
+
+	if trace.SpanContextFromContext(tracer.parent("server_status")).IsValid() {
+		t.Error("Collector context should not carry a span outside a scrape")
+	}
+}
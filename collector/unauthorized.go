@@ -0,0 +1,126 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/jimohabdol/mongodb-exporter/database"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// unauthorizedThreshold is how many consecutive Unauthorized failures block a command
+const unauthorizedThreshold = 3
+
+// errCommandBlocked is returned instead of running a command that keeps failing with Unauthorized
+var errCommandBlocked = errors.New("command skipped after repeated Unauthorized errors")
+
+type commandKey struct {
+	collector string
+	command   string
+}
+
+// unauthorizedTracker stops collectors from retrying commands the user lacks
+// privileges for, and exports the blocked commands as a metric instead of
+// logging the same failure on every scrape
+type unauthorizedTracker struct {
+	logger *zap.Logger
+
+	mu       sync.Mutex
+	failures map[commandKey]int
+	blocked  *prometheus.GaugeVec
+}
+
+func newUnauthorizedTracker(logger *zap.Logger) *unauthorizedTracker {
+	return &unauthorizedTracker{
+		logger:   logger,
+		failures: make(map[commandKey]int),
+		blocked: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "mongodb_exporter_collector_unauthorized",
+			Help: "Whether a collector command is skipped because it repeatedly failed with Unauthorized (1 = skipped)",
+		}, []string{"collector", "command"}),
+	}
+}
+
+// isBlocked reports whether the command has hit the Unauthorized threshold
+func (t *unauthorizedTracker) isBlocked(collector, command string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.failures[commandKey{collector, command}] >= unauthorizedThreshold
+}
+
+// observe records the outcome of a command run; any non-Unauthorized result resets its count
+func (t *unauthorizedTracker) observe(collector, command string, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := commandKey{collector, command}
+	if !isUnauthorized(err) {
+		delete(t.failures, key)
+		return
+	}
+
+	t.failures[key]++
+	if t.failures[key] == unauthorizedThreshold {
+		t.logger.Warn("Skipping command after repeated Unauthorized errors; grant the missing privilege and re-enable the collector or restart",
+			zap.String("collector", collector),
+			zap.String("command", command),
+			zap.Error(err))
+		t.blocked.WithLabelValues(collector, command).Set(1)
+	}
+}
+
+// reset unblocks every command of a collector, e.g. after privileges were granted
+func (t *unauthorizedTracker) reset(collector string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for key := range t.failures {
+		if key.collector == collector {
+			delete(t.failures, key)
+			t.blocked.DeleteLabelValues(key.collector, key.command)
+		}
+	}
+}
+
+// runCommand runs command on db unless the server lacks it or it is blocked for the collector,
+// tracking Unauthorized failures.
+// The command is bounded server-side by maxTimeMS from ctx's deadline.
+func (bc *BaseCollector) runCommand(ctx context.Context, collector string, db *mongo.Database, command bson.D, result interface{}) error {
+	command = withMaxTime(ctx, command)
+	name := command[0].Key
+	if err := bc.gate(ctx, collector, name, ""); err != nil {
+		return err
+	}
+
+	tracker := bc.config.unauthorized
+	if tracker == nil {
+		return db.RunCommand(ctx, command).Decode(result)
+	}
+	if tracker.isBlocked(collector, name) {
+		return errCommandBlocked
+	}
+
+	err := db.RunCommand(ctx, command).Decode(result)
+	tracker.observe(collector, name, err)
+	return err
+}
+
+// adminCommand runs commands on the admin database through runCommand on behalf of collector
+func (bc *BaseCollector) adminCommand(collector string) database.AdminCommand {
+	return func(ctx context.Context, command bson.D, result interface{}) error {
+		return bc.runCommand(ctx, collector, bc.client.Database("admin"), command, result)
+	}
+}
+
+// logCommandError logs a failed command, at debug level once the command is being skipped
+func (bc *BaseCollector) logCommandError(msg string, err error) {
+	if errors.Is(err, errCommandBlocked) || errors.Is(err, errCommandUnsupported) {
+		bc.logger.Debug(msg, zap.Error(err))
+		return
+	}
+	bc.logger.Error(msg, zap.Error(err))
+}
@@ -0,0 +1,50 @@
+package collector
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+func TestUnauthorizedTracker(t *testing.T) {
+	tracker := newUnauthorizedTracker(zap.NewNop())
+	unauthorized := mongo.CommandError{Code: 13, Name: "Unauthorized"}
+
+	for i := 0; i < unauthorizedThreshold-1; i++ {
+		tracker.observe("sharding", "balancerStatus", unauthorized)
+	}
+	if tracker.isBlocked("sharding", "balancerStatus") {
+		t.Error("Command should not be blocked before reaching the threshold")
+	}
+
+	// Other errors and successes reset the count
+	tracker.observe("sharding", "balancerStatus", errors.New("connection reset"))
+	tracker.observe("sharding", "balancerStatus", unauthorized)
+	if tracker.isBlocked("sharding", "balancerStatus") {
+		t.Error("Non-Unauthorized errors should reset the failure count")
+	}
+
+	for i := 0; i < unauthorizedThreshold; i++ {
+		tracker.observe("sharding", "balancerStatus", unauthorized)
+	}
+	if !tracker.isBlocked("sharding", "balancerStatus") {
+		t.Error("Command should be blocked after consecutive Unauthorized errors")
+	}
+	if tracker.isBlocked("cursors", "balancerStatus") {
+		t.Error("Blocking should be per collector")
+	}
+	if got := testutil.ToFloat64(tracker.blocked.WithLabelValues("sharding", "balancerStatus")); got != 1 {
+		t.Errorf("Expected unauthorized gauge 1, got %v", got)
+	}
+
+	tracker.reset("sharding")
+	if tracker.isBlocked("sharding", "balancerStatus") {
+		t.Error("Reset should unblock the collector's commands")
+	}
+	if got := testutil.CollectAndCount(tracker.blocked); got != 0 {
+		t.Errorf("Expected no unauthorized series after reset, got %d", got)
+	}
+}
@@ -0,0 +1,89 @@
+package collector
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// defaultUnusedIndexLookback is how long an index must go without accesses
+// before its size counts as reclaimable
+const defaultUnusedIndexLookback = 7 * 24 * time.Hour
+
+// indexUsage is one $indexStats result
+type indexUsage struct {
+	Name     string `bson:"name"`
+	Accesses struct {
+		Ops   int64     `bson:"ops"`
+		Since time.Time `bson:"since"`
+	} `bson:"accesses"`
+}
+
+// unusedIndexSizes returns the sizes of indexes with no accesses for at least
+// lookback. $indexStats counters restart with the server, so an index only
+// qualifies once its counter has been running for the whole lookback. Through
+// mongos each shard reports its own counter; an index counts as unused only if
+// every shard agrees. The _id index cannot be dropped and is never reported
+func unusedIndexSizes(usage []indexUsage, sizes bson.M, lookback time.Duration, now time.Time) map[string]float64 {
+	ops := make(map[string]int64, len(usage))
+	since := make(map[string]time.Time, len(usage))
+	for _, u := range usage {
+		ops[u.Name] += u.Accesses.Ops
+		if u.Accesses.Since.After(since[u.Name]) {
+			since[u.Name] = u.Accesses.Since
+		}
+	}
+
+	unused := make(map[string]float64)
+	for name, counted := range ops {
+		if name == "_id_" || counted > 0 || now.Sub(since[name]) < lookback {
+			continue
+		}
+		if size, ok := toInt64(sizes[name]); ok {
+			unused[name] = float64(size)
+		}
+	}
+	return unused
+}
+
+// collectUnusedIndexSizes joins $indexStats usage with the index sizes from
+// collStats and reports the bytes held by indexes nothing has used
+func (c *IndexStatsCollector) collectUnusedIndexSizes(ctx context.Context, ch chan<- prometheus.Metric, ns namespace, stats bson.M, instance map[string]string) {
+	sizes, ok := stats["indexSizes"].(bson.M)
+	if !ok {
+		return
+	}
+
+	pipeline := []bson.D{
+		{{"$indexStats", bson.D{}}},
+		{{"$project", bson.D{{"name", 1}, {"accesses", 1}}}},
+	}
+	cursor, err := c.aggregate(ctx, "index_stats", c.client.Database(ns.Database).Collection(ns.Collection), pipeline, &options.AggregateOptions{MaxTime: maxTime(ctx)})
+	if err != nil {
+		c.logCommandError("Failed to run $indexStats", err)
+		return
+	}
+
+	var usage []indexUsage
+	if err := cursor.All(ctx, &usage); err != nil {
+		c.logCommandError("Failed to read $indexStats", err)
+		return
+	}
+
+	for indexName, size := range unusedIndexSizes(usage, sizes, c.unusedLookback, time.Now()) {
+		ch <- prometheus.MustNewConstMetric(
+			c.descriptors["index_unused_size_bytes"],
+			prometheus.GaugeValue,
+			size,
+			instance["instance"],
+			instance["replica_set"],
+			instance["shard"],
+			ns.Database,
+			ns.Collection,
+			indexName,
+		)
+	}
+}
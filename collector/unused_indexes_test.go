@@ -0,0 +1,47 @@
+package collector
+
+import (
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func newIndexUsage(name string, ops int64, since time.Time) indexUsage {
+	u := indexUsage{Name: name}
+	u.Accesses.Ops = ops
+	u.Accesses.Since = since
+	return u
+}
+
+func TestUnusedIndexSizes(t *testing.T) {
+	now := time.Now()
+	lookback := 7 * 24 * time.Hour
+	longAgo := now.Add(-30 * 24 * time.Hour)
+
+	sizes := bson.M{
+		"_id_":        int64(4096),
+		"status_1":    int64(8192),
+		"created_at":  int32(1024),
+		"email_1":     int64(2048),
+		"sharded_key": int64(512),
+	}
+	usage := []indexUsage{
+		newIndexUsage("_id_", 0, longAgo),
+		newIndexUsage("status_1", 0, longAgo),
+		newIndexUsage("created_at", 0, now.Add(-time.Hour)),
+		newIndexUsage("email_1", 5, longAgo),
+		// Reported by two shards; one of them used it
+		newIndexUsage("sharded_key", 0, longAgo),
+		newIndexUsage("sharded_key", 3, longAgo),
+	}
+
+	unused := unusedIndexSizes(usage, sizes, lookback, now)
+	if len(unused) != 1 || unused["status_1"] != 8192 {
+		t.Errorf("Only status_1 should be reclaimable, got %v", unused)
+	}
+
+	if unused := unusedIndexSizes(usage, sizes, 0, now); unused["created_at"] != 1024 {
+		t.Errorf("Without a lookback every index with zero accesses should count, got %v", unused)
+	}
+}
@@ -0,0 +1,57 @@
+package collector
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// runGate relays one collector run's metrics to the scrape until the run is
+// abandoned by the watchdog, after which the scrape may already have returned
+// and its channel must not be written to
+type runGate struct {
+	out chan<- prometheus.Metric
+
+	mu        sync.Mutex
+	abandoned bool
+	finished  bool
+}
+
+func (g *runGate) send(metric prometheus.Metric) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if !g.abandoned {
+		g.out <- metric
+	}
+}
+
+// abandon reports whether the run was abandoned, which fails if it already
+// finished. mark runs on abandonment, before the run can finish
+func (g *runGate) abandon(mark func()) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.finished {
+		return false
+	}
+	g.abandoned = true
+	mark()
+	return true
+}
+
+// finish reports whether the run finished before the watchdog abandoned it
+func (g *runGate) finish() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.abandoned {
+		return false
+	}
+	g.finished = true
+	return true
+}
+
+func newAbandonedCounter() *prometheus.CounterVec {
+	return prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "mongodb_exporter_collector_abandoned_total",
+		Help: "Collector runs abandoned because they exceeded the watchdog deadline",
+	}, []string{"collector"})
+}
@@ -1,7 +1,6 @@
 package collector
 
 import (
-	"context"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -21,49 +20,49 @@ func NewWiredTigerCollector(client *mongo.Client, logger *zap.Logger, config Col
 
 	descriptors := map[string]*prometheus.Desc{
 		"cache_max_bytes": prometheus.NewDesc(
-			"mongodb_wiredtiger_cache_max_bytes",
+			config.metricName("mongodb_wiredtiger_cache_max_bytes"),
 			"Maximum bytes configured for cache",
 			labels,
 			nil,
 		),
 		"cache_used_bytes": prometheus.NewDesc(
-			"mongodb_wiredtiger_cache_used_bytes",
+			config.metricName("mongodb_wiredtiger_cache_used_bytes"),
 			"Bytes currently in cache",
 			labels,
 			nil,
 		),
 		"cache_dirty_bytes": prometheus.NewDesc(
-			"mongodb_wiredtiger_cache_dirty_bytes",
+			config.metricName("mongodb_wiredtiger_cache_dirty_bytes"),
 			"Bytes currently dirty in cache",
 			labels,
 			nil,
 		),
 		"cache_pages": prometheus.NewDesc(
-			"mongodb_wiredtiger_cache_pages",
+			config.metricName("mongodb_wiredtiger_cache_pages"),
 			"Number of pages by state",
 			cacheLabels,
 			nil,
 		),
 		"cache_evicted_total": prometheus.NewDesc(
-			"mongodb_wiredtiger_cache_evicted_total",
+			config.metricName("mongodb_wiredtiger_cache_evicted_total"),
 			"Pages evicted from cache",
 			append(labels, "mode"),
 			nil,
 		),
 		"io_total": prometheus.NewDesc(
-			"mongodb_wiredtiger_io_total",
+			config.metricName("mongodb_wiredtiger_io_total"),
 			"Number of I/O operations",
 			append(labels, "type"),
 			nil,
 		),
 		"scan_total": prometheus.NewDesc(
-			"mongodb_wiredtiger_scan_total",
+			config.metricName("mongodb_wiredtiger_scan_total"),
 			"Scan operations",
 			append(labels, "type"),
 			nil,
 		),
 		"block_operations_total": prometheus.NewDesc(
-			"mongodb_wiredtiger_block_operations_total",
+			config.metricName("mongodb_wiredtiger_block_operations_total"),
 			"Block operations",
 			append(labels, "type"),
 			nil,
@@ -81,12 +80,12 @@ func (c *WiredTigerCollector) Collect(ch chan<- prometheus.Metric) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+	ctx, done := c.collectContext("wiredtiger", 10*time.Second)
+	defer done()
 
 	var result bson.M
-	if err := c.client.Database("admin").RunCommand(ctx, bson.D{{"serverStatus", 1}}).Decode(&result); err != nil {
-		c.logger.Error("Failed to collect WiredTiger metrics", zap.Error(err))
+	if err := c.runCommand(ctx, "wiredtiger", c.client.Database("admin"), serverStatusCommand("wiredTiger"), &result); err != nil {
+		c.logCommandError("Failed to collect WiredTiger metrics", err)
 		return
 	}
 
@@ -102,7 +101,7 @@ func (c *WiredTigerCollector) Collect(ch chan<- prometheus.Metric) {
 func (c *WiredTigerCollector) collectCacheMetrics(ch chan<- prometheus.Metric, wt bson.M, instance map[string]string) {
 	if cache, ok := wt["cache"].(bson.M); ok {
 		// Maximum configured cache size
-		if maxBytes, ok := cache["maximum bytes configured"].(int64); ok {
+		if maxBytes, ok := toInt64(cache["maximum bytes configured"]); ok {
 			if desc, ok := c.descriptors["cache_max_bytes"]; ok {
 				ch <- prometheus.MustNewConstMetric(
 					desc,
@@ -116,12 +115,12 @@ func (c *WiredTigerCollector) collectCacheMetrics(ch chan<- prometheus.Metric, w
 		}
 
 		// Current cache usage
-		if bytesInCache, ok := cache["bytes currently in the cache"].(int64); ok {
+		if bytesInCache := c.getSignedValue(cache["bytes currently in the cache"]); validateSignedMetricValue(bytesInCache) {
 			if desc, ok := c.descriptors["cache_used_bytes"]; ok {
 				ch <- prometheus.MustNewConstMetric(
 					desc,
 					prometheus.GaugeValue,
-					float64(bytesInCache),
+					*bytesInCache,
 					instance["instance"],
 					instance["replica_set"],
 					instance["shard"],
@@ -130,12 +129,12 @@ func (c *WiredTigerCollector) collectCacheMetrics(ch chan<- prometheus.Metric, w
 		}
 
 		// Dirty bytes in cache
-		if dirtyBytes, ok := cache["tracked dirty bytes in the cache"].(int64); ok {
+		if dirtyBytes := c.getSignedValue(cache["tracked dirty bytes in the cache"]); validateSignedMetricValue(dirtyBytes) {
 			if desc, ok := c.descriptors["cache_dirty_bytes"]; ok {
 				ch <- prometheus.MustNewConstMetric(
 					desc,
 					prometheus.GaugeValue,
-					float64(dirtyBytes),
+					*dirtyBytes,
 					instance["instance"],
 					instance["replica_set"],
 					instance["shard"],
@@ -148,12 +147,13 @@ func (c *WiredTigerCollector) collectCacheMetrics(ch chan<- prometheus.Metric, w
 			"pages currently held in the cache": "total",
 			"tracked dirty pages in the cache":  "dirty",
 			"pages read into cache":             "read",
+			"pages requested from the cache":    "requested",
 			"pages written from cache":          "written",
 		}
 
 		if desc, ok := c.descriptors["cache_pages"]; ok {
 			for metric, label := range pageStates {
-				if value, ok := cache[metric].(int64); ok {
+				if value, ok := toInt64(cache[metric]); ok {
 					ch <- prometheus.MustNewConstMetric(
 						desc,
 						prometheus.GaugeValue,
@@ -175,7 +175,7 @@ func (c *WiredTigerCollector) collectCacheMetrics(ch chan<- prometheus.Metric, w
 
 		if desc, ok := c.descriptors["cache_evicted_total"]; ok {
 			for metric, label := range evictionTypes {
-				if value, ok := cache[metric].(int64); ok {
+				if value, ok := toInt64(cache[metric]); ok {
 					ch <- prometheus.MustNewConstMetric(
 						desc,
 						prometheus.CounterValue,
@@ -203,7 +203,7 @@ func (c *WiredTigerCollector) collectBlockManagerMetrics(ch chan<- prometheus.Me
 
 		if desc, ok := c.descriptors["block_operations_total"]; ok {
 			for metric, label := range blockOps {
-				if value, ok := blockManager[metric].(int64); ok {
+				if value, ok := toInt64(blockManager[metric]); ok {
 					ch <- prometheus.MustNewConstMetric(
 						desc,
 						prometheus.CounterValue,
@@ -224,7 +224,7 @@ func (c *WiredTigerCollector) collectConcurrentTransactionsMetrics(ch chan<- pro
 		if desc, ok := c.descriptors["io_total"]; ok {
 			for txType, metrics := range concurrentTransactions {
 				if metricsMap, ok := metrics.(bson.M); ok {
-					if available, ok := metricsMap["available"].(int64); ok {
+					if available, ok := toInt64(metricsMap["available"]); ok {
 						ch <- prometheus.MustNewConstMetric(
 							desc,
 							prometheus.GaugeValue,
@@ -235,7 +235,7 @@ func (c *WiredTigerCollector) collectConcurrentTransactionsMetrics(ch chan<- pro
 							txType+"_available",
 						)
 					}
-					if out, ok := metricsMap["out"].(int64); ok {
+					if out, ok := toInt64(metricsMap["out"]); ok {
 						ch <- prometheus.MustNewConstMetric(
 							desc,
 							prometheus.GaugeValue,
@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -16,6 +17,8 @@ type Config struct {
 	Metrics    MetricsConfig    `yaml:"metrics"`
 	Logging    LoggingConfig    `yaml:"logging"`
 	Collectors CollectorsConfig `yaml:"collectors"`
+	EMF        EMFConfig        `yaml:"emf"`
+	Tracing    TracingConfig    `yaml:"tracing"`
 }
 
 type MongoDBConfig struct {
@@ -35,6 +38,9 @@ type MongoDBConfig struct {
 	MaxPoolSize            uint64        `yaml:"max_pool_size" env:"MONGO_MAX_POOL_SIZE"`
 	MinPoolSize            uint64        `yaml:"min_pool_size" env:"MONGO_MIN_POOL_SIZE"`
 	MaxIdleTime            time.Duration `yaml:"max_idle_time" env:"MONGO_MAX_IDLE_TIME"`
+	// TargetFlavor is the server implementation: mongodb, documentdb for Amazon DocumentDB or ferretdb.
+	// FerretDB is also detected from buildInfo
+	TargetFlavor string `yaml:"target_flavor" env:"MONGO_TARGET_FLAVOR"`
 }
 
 type ServerConfig struct {
@@ -42,19 +48,138 @@ type ServerConfig struct {
 	ReadTimeout  time.Duration `yaml:"read_timeout" env:"SERVER_READ_TIMEOUT"`
 	WriteTimeout time.Duration `yaml:"write_timeout" env:"SERVER_WRITE_TIMEOUT"`
 	IdleTimeout  time.Duration `yaml:"idle_timeout" env:"SERVER_IDLE_TIMEOUT"`
+	AdminToken   string        `yaml:"admin_token" env:"SERVER_ADMIN_TOKEN"`
+	EnablePprof  bool          `yaml:"enable_pprof" env:"SERVER_ENABLE_PPROF"`
+
+	MaxConcurrentScrapes int           `yaml:"max_concurrent_scrapes" env:"SERVER_MAX_CONCURRENT_SCRAPES"`
+	ScrapeQueueTimeout   time.Duration `yaml:"scrape_queue_timeout" env:"SERVER_SCRAPE_QUEUE_TIMEOUT"`
+	RateLimit            float64       `yaml:"rate_limit" env:"SERVER_RATE_LIMIT"`
+	RateLimitBurst       int           `yaml:"rate_limit_burst" env:"SERVER_RATE_LIMIT_BURST"`
+	CoalesceScrapes      bool          `yaml:"coalesce_scrapes" env:"SERVER_COALESCE_SCRAPES"`
+
+	LandingPage      bool              `yaml:"landing_page" env:"SERVER_LANDING_PAGE"`
+	LandingPageTitle string            `yaml:"landing_page_title" env:"SERVER_LANDING_PAGE_TITLE"`
+	LandingPageLinks []LandingPageLink `yaml:"landing_page_links"`
+
+	EnableCommandAudit bool `yaml:"enable_command_audit" env:"SERVER_ENABLE_COMMAND_AUDIT"`
+	CommandAuditSize   int  `yaml:"command_audit_size" env:"SERVER_COMMAND_AUDIT_SIZE"`
+}
+
+// LandingPageLink is an extra link shown on the landing page, e.g. a runbook or dashboard
+type LandingPageLink struct {
+	Name        string `yaml:"name"`
+	URL         string `yaml:"url"`
+	Description string `yaml:"description"`
 }
 
 type MetricsConfig struct {
 	CollectionInterval time.Duration     `yaml:"collection_interval" env:"METRICS_COLLECTION_INTERVAL"`
+	Preset             string            `yaml:"preset" env:"METRICS_PRESET"`
 	EnabledMetrics     []string          `yaml:"enabled_metrics" env:"METRICS_ENABLED"`
 	DisabledMetrics    []string          `yaml:"disabled_metrics" env:"METRICS_DISABLED"`
 	CustomLabels       map[string]string `yaml:"custom_labels" env:"METRICS_CUSTOM_LABELS"`
+	ClusterRoleLabel   bool              `yaml:"cluster_role_label" env:"METRICS_CLUSTER_ROLE_LABEL"`
+	ClusterName        string            `yaml:"cluster_name" env:"METRICS_CLUSTER_NAME"`
+	Environment        string            `yaml:"environment" env:"METRICS_ENVIRONMENT"`
+	Namespace          string            `yaml:"namespace" env:"METRICS_NAMESPACE"`
+	InstanceLabel      string            `yaml:"instance_label" env:"METRICS_INSTANCE_LABEL"`
+	StripInstancePort  bool              `yaml:"strip_instance_port" env:"METRICS_STRIP_INSTANCE_PORT"`
+	RuntimeMetrics     bool              `yaml:"runtime_metrics" env:"METRICS_RUNTIME"`
+	MaxSeriesPerMetric int               `yaml:"max_series_per_metric" env:"METRICS_MAX_SERIES_PER_METRIC"`
+	SeriesLimits       map[string]int    `yaml:"series_limits"`
+	NamespaceCacheTTL  time.Duration     `yaml:"namespace_cache_ttl" env:"METRICS_NAMESPACE_CACHE_TTL"`
+	Parallelism        int               `yaml:"collection_parallelism" env:"METRICS_COLLECTION_PARALLELISM"`
+	AdaptiveTimeouts   bool              `yaml:"adaptive_timeouts" env:"METRICS_ADAPTIVE_TIMEOUTS"`
+	TimeoutMin         time.Duration     `yaml:"timeout_min" env:"METRICS_TIMEOUT_MIN"`
+	TimeoutMax         time.Duration     `yaml:"timeout_max" env:"METRICS_TIMEOUT_MAX"`
+	CollectorWatchdog  time.Duration     `yaml:"collector_watchdog" env:"METRICS_COLLECTOR_WATCHDOG"`
+	StaleAfterRuns     int               `yaml:"stale_after_runs" env:"METRICS_STALE_AFTER_RUNS"`
+
+	LatencySummaries LatencySummariesConfig `yaml:"latency_summaries"`
+
+	// OpenMetrics lets scrapers negotiate the OpenMetrics format, which carries exemplars
+	OpenMetrics bool `yaml:"openmetrics" env:"METRICS_OPENMETRICS"`
+
+	// DerivedMetrics adds health ratios computed from the collected series, such as cache fill
+	DerivedMetrics bool `yaml:"derived_metrics" env:"METRICS_DERIVED"`
+
+	// ScrapeProfiles are the collector sets selectable with /metrics?profile=<name>;
+	// light, standard and deep are predefined and can be overridden
+	ScrapeProfiles map[string][]string `yaml:"scrape_profiles"`
+}
+
+// Latency metrics that can be exported as summaries
+const (
+	LatencyCommandDuration = "command_duration"
+	LatencyPingRTT         = "ping_rtt"
+	LatencyProfileDuration = "profile_duration"
+)
+
+// LatencySummariesConfig exports selected latency metrics as summaries with
+// quantiles, for users whose tooling cannot work with histograms
+type LatencySummariesConfig struct {
+	// Metrics lists the latency metrics exported as summaries: command_duration, ping_rtt, profile_duration
+	Metrics []string `yaml:"metrics" env:"METRICS_LATENCY_SUMMARIES"`
+	// Objectives maps each quantile to its allowed absolute error (empty = median, p90 and p99)
+	Objectives map[float64]float64 `yaml:"objectives"`
+	// MaxAge is how long an observation counts towards the quantiles
+	MaxAge time.Duration `yaml:"max_age" env:"METRICS_LATENCY_SUMMARY_MAX_AGE"`
+	// ReplaceHistograms drops the histograms of the selected metrics instead of exporting both
+	ReplaceHistograms bool `yaml:"replace_histograms" env:"METRICS_LATENCY_SUMMARIES_REPLACE"`
+}
+
+// QuantileObjectives returns the configured objectives, or the median, p90 and p99 when none are set.
+// The default is not set in setDefaults because YAML would merge configured quantiles into it.
+func (c LatencySummariesConfig) QuantileObjectives() map[float64]float64 {
+	if len(c.Objectives) > 0 {
+		return c.Objectives
+	}
+	return map[float64]float64{0.5: 0.05, 0.9: 0.01, 0.99: 0.001}
+}
+
+// Includes reports whether metric is exported as a summary
+func (c LatencySummariesConfig) Includes(metric string) bool {
+	for _, name := range c.Metrics {
+		if name == metric {
+			return true
+		}
+	}
+	return false
 }
 
 type LoggingConfig struct {
 	Level      string `yaml:"level" env:"LOG_LEVEL"`
 	Format     string `yaml:"format" env:"LOG_FORMAT"`
 	OutputPath string `yaml:"output_path" env:"LOG_OUTPUT_PATH"`
+
+	// MaxSizeMB rotates the output file once it grows past this size (0 = no size limit)
+	MaxSizeMB int `yaml:"max_size_mb" env:"LOG_MAX_SIZE_MB"`
+	// MaxAge rotates the output file once it has been written to for this long (0 = no age limit)
+	MaxAge time.Duration `yaml:"max_age" env:"LOG_MAX_AGE"`
+	// MaxBackups is how many rotated files are kept (0 = keep all)
+	MaxBackups int `yaml:"max_backups" env:"LOG_MAX_BACKUPS"`
+	// Compress gzips rotated files
+	Compress bool `yaml:"compress" env:"LOG_COMPRESS"`
+}
+
+type EMFConfig struct {
+	Enabled        bool          `yaml:"enabled" env:"EMF_ENABLED"`
+	Namespace      string        `yaml:"namespace" env:"EMF_NAMESPACE"`
+	Interval       time.Duration `yaml:"interval" env:"EMF_INTERVAL"`
+	OutputPath     string        `yaml:"output_path" env:"EMF_OUTPUT_PATH"`
+	MetricFamilies []string      `yaml:"metric_families" env:"EMF_METRIC_FAMILIES"`
+	// Dimensions are the labels that become CloudWatch dimensions (empty = every label)
+	Dimensions []string `yaml:"dimensions" env:"EMF_DIMENSIONS"`
+}
+
+// TracingConfig exports OpenTelemetry spans for scrapes and MongoDB commands over OTLP/HTTP
+type TracingConfig struct {
+	Enabled bool `yaml:"enabled" env:"TRACING_ENABLED"`
+	// Endpoint is the OTLP/HTTP collector address (host:port); empty uses OTEL_EXPORTER_OTLP_ENDPOINT or localhost:4318
+	Endpoint    string  `yaml:"endpoint" env:"TRACING_ENDPOINT"`
+	Insecure    bool    `yaml:"insecure" env:"TRACING_INSECURE"`
+	ServiceName string  `yaml:"service_name" env:"TRACING_SERVICE_NAME"`
+	SampleRatio float64 `yaml:"sample_ratio" env:"TRACING_SAMPLE_RATIO"`
 }
 
 type CollectorsConfig struct {
@@ -63,32 +188,222 @@ type CollectorsConfig struct {
 	Sharding       ShardingConfig       `yaml:"sharding"`
 	IndexStats     IndexStatsConfig     `yaml:"index_stats"`
 	ConnectionPool ConnectionPoolConfig `yaml:"connection_pool"`
+	// IndexSelectivity configures the opt-in sampling collector estimating index selectivity
+	IndexSelectivity IndexSelectivityConfig `yaml:"index_selectivity"`
+	// ShardKeyDistribution configures the opt-in per-collection shard balance collector
+	ShardKeyDistribution ShardKeyDistributionConfig `yaml:"shard_key_distribution"`
+	// DBHash configures the opt-in replica set data consistency check
+	DBHash DBHashConfig `yaml:"dbhash"`
+	// Atlas configures the opt-in collector reading the Atlas Administration API
+	Atlas AtlasConfig `yaml:"atlas"`
+	// OpsManager configures the opt-in collector reading the Ops Manager or Cloud Manager API
+	OpsManager OpsManagerConfig `yaml:"opsmanager"`
+	// Ping configures the probe measuring ping round trips on every scrape
+	Ping PingConfig `yaml:"ping"`
+	// Canary configures the opt-in collector writing and reading back a document
+	Canary CanaryConfig `yaml:"canary"`
+	// ReplicaSet configures the replica set status and oplog collector
+	ReplicaSet ReplicaSetConfig `yaml:"replica_set_status"`
+}
+
+// Intervals returns the per-collector run intervals keyed by collector name
+func (c CollectorsConfig) Intervals() map[string]time.Duration {
+	return map[string]time.Duration{
+		"collstats":              c.CollStats.Interval,
+		"profile":                c.Profile.Interval,
+		"sharding":               c.Sharding.Interval,
+		"index_stats":            c.IndexStats.Interval,
+		"connection_pool":        c.ConnectionPool.Interval,
+		"index_selectivity":      c.IndexSelectivity.Interval,
+		"shard_key_distribution": c.ShardKeyDistribution.Interval,
+		"dbhash":                 c.DBHash.Interval,
+		"atlas":                  c.Atlas.Interval,
+		"opsmanager":             c.OpsManager.Interval,
+		"canary":                 c.Canary.Interval,
+	}
 }
 
 type CollStatsConfig struct {
-	MonitoredCollections []string `yaml:"monitored_collections"`
+	MonitoredCollections []string      `yaml:"monitored_collections"`
+	TopNBySize           int           `yaml:"top_n_by_size"`
+	TopNByActivity       int           `yaml:"top_n_by_activity"`
+	Interval             time.Duration `yaml:"interval"`
+	// WiredTigerDetail adds per-collection reconciliation, btree and cache eviction statistics
+	WiredTigerDetail bool `yaml:"wiredtiger_detail"`
+	// CountMode is "estimated" (collStats metadata) or "exact" (countDocuments)
+	CountMode string `yaml:"count_mode"`
+	// ExactCountCollections limits exact counting to these database.collection names
+	ExactCountCollections []string `yaml:"exact_count_collections"`
 }
 
 type ProfileConfig struct {
-	SlowOperationThreshold string `yaml:"slow_operation_threshold"`
-	MaxEntriesPerCycle     int    `yaml:"max_entries_per_cycle"`
+	SlowOperationThreshold string        `yaml:"slow_operation_threshold"`
+	MaxEntriesPerCycle     int           `yaml:"max_entries_per_cycle"`
+	Interval               time.Duration `yaml:"interval"`
+	MaxTrackedOperations   int           `yaml:"max_tracked_operations"`
+	MaxPlanSummaries       int           `yaml:"max_plan_summaries"`
+	MaxLockTypes           int           `yaml:"max_lock_types"`
+	// DurationHistogram exports per-operation durations as a histogram with query exemplars
+	DurationHistogram bool `yaml:"duration_histogram"`
+	// MemberScope limits which replica set member profiles are read from: all, primary or self
+	MemberScope string `yaml:"member_scope"`
+	// Databases restricts profiling to these names or regular expressions
+	Databases []string `yaml:"databases"`
 }
 
 type ShardingConfig struct {
-	CollectChunkDistribution bool `yaml:"collect_chunk_distribution"`
-	CollectMigrationHistory  bool `yaml:"collect_migration_history"`
+	CollectChunkDistribution bool          `yaml:"collect_chunk_distribution"`
+	CollectMigrationHistory  bool          `yaml:"collect_migration_history"`
+	Interval                 time.Duration `yaml:"interval"`
+	// MongosPingFreshness is how recent a config.mongos ping must be for the router to count as active
+	MongosPingFreshness time.Duration `yaml:"mongos_ping_freshness"`
 }
 
 type IndexStatsConfig struct {
-	CollectUsageStats       bool `yaml:"collect_usage_stats"`
-	MaxIndexesPerCollection int  `yaml:"max_indexes_per_collection"`
+	CollectUsageStats       bool          `yaml:"collect_usage_stats"`
+	MaxIndexesPerCollection int           `yaml:"max_indexes_per_collection"`
+	Interval                time.Duration `yaml:"interval"`
+	// UnusedLookback is how long an index must go unaccessed to count as unused
+	UnusedLookback time.Duration `yaml:"unused_lookback"`
+}
+
+type IndexSelectivityConfig struct {
+	// SampleSize is the number of documents sampled per collection
+	SampleSize int `yaml:"sample_size"`
+	// Collections limits sampling to these "database.collection" namespaces (empty = all)
+	Collections []string      `yaml:"collections"`
+	Interval    time.Duration `yaml:"interval"`
+}
+
+type ShardKeyDistributionConfig struct {
+	// Collections are the "database.collection" sharded namespaces to analyze
+	Collections []string `yaml:"collections"`
+	// SampleSize is the number of documents sampled for shard key hotness
+	SampleSize int           `yaml:"sample_size"`
+	Interval   time.Duration `yaml:"interval"`
+}
+
+type DBHashConfig struct {
+	// Databases are compared with dbHash across replica set members
+	Databases []string `yaml:"databases"`
+	// Confirmations is how many consecutive checks must disagree before a mismatch is reported
+	Confirmations int           `yaml:"confirmations"`
+	Interval      time.Duration `yaml:"interval"`
+}
+
+// AtlasConfig reads hardware measurements and open alerts of an Atlas project
+// with a programmatic API key that has the Project Read Only role
+type AtlasConfig struct {
+	PublicKey  string `yaml:"public_key" env:"ATLAS_PUBLIC_KEY"`
+	PrivateKey string `yaml:"private_key" env:"ATLAS_PRIVATE_KEY"`
+	ProjectID  string `yaml:"project_id" env:"ATLAS_PROJECT_ID"`
+	// Cluster limits processes and alerts to one cluster of the project (empty = every cluster)
+	Cluster string `yaml:"cluster" env:"ATLAS_CLUSTER"`
+	// BaseURL overrides the Atlas Administration API host
+	BaseURL             string        `yaml:"base_url"`
+	ProcessMeasurements []string      `yaml:"process_measurements"`
+	DiskMeasurements    []string      `yaml:"disk_measurements"`
+	Interval            time.Duration `yaml:"interval"`
+}
+
+// OpsManagerConfig reads automation and backup health of an Ops Manager or
+// Cloud Manager project with a programmatic API key
+type OpsManagerConfig struct {
+	// BaseURL is the Ops Manager address, or https://cloud.mongodb.com for Cloud Manager
+	BaseURL    string `yaml:"base_url" env:"OPS_MANAGER_URL"`
+	PublicKey  string `yaml:"public_key" env:"OPS_MANAGER_PUBLIC_KEY"`
+	PrivateKey string `yaml:"private_key" env:"OPS_MANAGER_PRIVATE_KEY"`
+	ProjectID  string `yaml:"project_id" env:"OPS_MANAGER_PROJECT_ID"`
+	// Clusters limits backup metrics and alerts to these cluster names (empty = every cluster)
+	Clusters []string      `yaml:"clusters"`
+	Interval time.Duration `yaml:"interval"`
+}
+
+type PingConfig struct {
+	// Count is how many pings are sent one after another per scrape
+	Count int `yaml:"count"`
+}
+
+// CanaryConfig sets where the canary collector writes its documents; the
+// exporter user needs readWrite on that database
+type CanaryConfig struct {
+	Database   string        `yaml:"database"`
+	Collection string        `yaml:"collection"`
+	Interval   time.Duration `yaml:"interval"`
+}
+
+type ReplicaSetConfig struct {
+	// SampleOplogTimestamps estimates oplog growth from the oldest and newest
+	// entries when the change in used bytes cannot, such as once the oplog is full
+	SampleOplogTimestamps bool `yaml:"sample_oplog_timestamps"`
 }
 
 type ConnectionPoolConfig struct {
-	CollectPerHostMetrics    bool `yaml:"collect_per_host_metrics"`
-	AnalyzeCurrentOperations bool `yaml:"analyze_current_operations"`
+	CollectPerHostMetrics    bool          `yaml:"collect_per_host_metrics"`
+	AnalyzeCurrentOperations bool          `yaml:"analyze_current_operations"`
+	Interval                 time.Duration `yaml:"interval"`
+	// SessionStates counts connections and sessions by state and appName with $currentOp
+	SessionStates bool `yaml:"session_states"`
+	// ClientMetadata counts connections by appName and driver with $currentOp
+	ClientMetadata bool `yaml:"client_metadata"`
+	// MaxClientGroups caps the appName and driver combinations; the rest are reported as "other"
+	MaxClientGroups int `yaml:"max_client_groups"`
+}
+
+// metricPresets are the curated collector sets selectable with metrics.preset
+var metricPresets = map[string][]string{
+	"minimal": {
+		"server_status",
+		"replica_set_status",
+	},
+	"default": {
+		"server_status",
+		"replica_set_status",
+		"sharding",
+		"wiredtiger",
+		"locks",
+		"storage_stats",
+		"query_executor",
+		"cursors",
+		"connection_pool",
+		"compatibility",
+		"maintenance",
+		"ping",
+	},
+	"full": {
+		"server_status",
+		"replica_set_status",
+		"sharding",
+		"wiredtiger",
+		"locks",
+		"index_stats",
+		"storage_stats",
+		"query_executor",
+		"collstats",
+		"cursors",
+		"profile",
+		"connection_pool",
+		"compatibility",
+		"index_selectivity",
+		"shard_key_distribution",
+		"dbhash",
+		"maintenance",
+		"atlas",
+		"opsmanager",
+		"ping",
+	},
 }
 
+// defaultScrapeProfiles are the scrape profiles available without configuration,
+// mapped to the presets with the same collectors
+var defaultScrapeProfiles = map[string]string{
+	"light":    "minimal",
+	"standard": "default",
+	"deep":     "full",
+}
+
+var metricNamespacePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
 func LoadConfig(configPath string) (*Config, error) {
 	config := &Config{}
 
@@ -108,6 +423,9 @@ func LoadConfig(configPath string) (*Config, error) {
 		return nil, fmt.Errorf("invalid configuration: %w", err)
 	}
 
+	applyPreset(config)
+	applyScrapeProfiles(config)
+
 	return config, nil
 }
 
@@ -121,16 +439,55 @@ func setDefaults(config *Config) {
 	config.MongoDB.MaxPoolSize = 100
 	config.MongoDB.MinPoolSize = 5
 	config.MongoDB.MaxIdleTime = 30 * time.Minute
+	config.MongoDB.TargetFlavor = "mongodb"
 
 	config.Server.Port = "8080"
 	config.Server.ReadTimeout = 30 * time.Second
 	config.Server.WriteTimeout = 30 * time.Second
 	config.Server.IdleTimeout = 60 * time.Second
+	config.Server.RateLimitBurst = 5
+	config.Server.CoalesceScrapes = true
+	config.Server.LandingPage = true
+	config.Server.CommandAuditSize = 1000
 
 	config.Metrics.CollectionInterval = 15 * time.Second
+	config.Metrics.Parallelism = 4
+	config.Metrics.Namespace = "mongodb"
+	config.Metrics.TimeoutMin = 2 * time.Second
+	config.Metrics.TimeoutMax = 60 * time.Second
+	config.Metrics.CollectorWatchdog = 90 * time.Second
+	config.Metrics.StaleAfterRuns = 2
+	config.Metrics.DerivedMetrics = true
+	config.Metrics.LatencySummaries.MaxAge = 10 * time.Minute
+
+	config.Collectors.CollStats.CountMode = "estimated"
+	config.Collectors.Sharding.MongosPingFreshness = time.Minute
+	config.Collectors.IndexStats.UnusedLookback = 7 * 24 * time.Hour
+	config.Collectors.IndexSelectivity.SampleSize = 1000
+	config.Collectors.IndexSelectivity.Interval = time.Hour
+	config.Collectors.ShardKeyDistribution.SampleSize = 1000
+	config.Collectors.ShardKeyDistribution.Interval = 10 * time.Minute
+	config.Collectors.DBHash.Confirmations = 2
+	config.Collectors.DBHash.Interval = 6 * time.Hour
+	config.Collectors.ConnectionPool.MaxClientGroups = 50
+	config.Collectors.Atlas.BaseURL = "https://cloud.mongodb.com"
+	config.Collectors.Atlas.Interval = time.Minute
+	config.Collectors.OpsManager.Interval = 5 * time.Minute
+	config.Collectors.Ping.Count = 3
+	config.Collectors.Canary.Database = "mongodb_exporter"
+	config.Collectors.Canary.Collection = "canary"
 
 	config.Logging.Level = "info"
 	config.Logging.Format = "json"
+	config.Logging.MaxSizeMB = 100
+	config.Logging.MaxBackups = 5
+
+	config.EMF.Namespace = "MongoDB"
+	config.EMF.Interval = 60 * time.Second
+	config.EMF.OutputPath = "stdout"
+
+	config.Tracing.ServiceName = "mongodb-exporter"
+	config.Tracing.SampleRatio = 1
 }
 
 func loadFromFile(config *Config, configPath string) error {
@@ -209,6 +566,9 @@ func loadFromEnv(config *Config) error {
 			config.MongoDB.MaxIdleTime = timeout
 		}
 	}
+	if targetFlavor := os.Getenv("MONGO_TARGET_FLAVOR"); targetFlavor != "" {
+		config.MongoDB.TargetFlavor = targetFlavor
+	}
 
 	if port := os.Getenv("SERVER_PORT"); port != "" {
 		config.Server.Port = port
@@ -228,6 +588,57 @@ func loadFromEnv(config *Config) error {
 			config.Server.IdleTimeout = timeout
 		}
 	}
+	if adminToken := os.Getenv("SERVER_ADMIN_TOKEN"); adminToken != "" {
+		config.Server.AdminToken = adminToken
+	}
+	if enablePprof := os.Getenv("SERVER_ENABLE_PPROF"); enablePprof != "" {
+		if enabled, err := strconv.ParseBool(enablePprof); err == nil {
+			config.Server.EnablePprof = enabled
+		}
+	}
+	if enableAudit := os.Getenv("SERVER_ENABLE_COMMAND_AUDIT"); enableAudit != "" {
+		if enabled, err := strconv.ParseBool(enableAudit); err == nil {
+			config.Server.EnableCommandAudit = enabled
+		}
+	}
+	if auditSize := os.Getenv("SERVER_COMMAND_AUDIT_SIZE"); auditSize != "" {
+		if size, err := strconv.Atoi(auditSize); err == nil {
+			config.Server.CommandAuditSize = size
+		}
+	}
+	if maxConcurrentScrapes := os.Getenv("SERVER_MAX_CONCURRENT_SCRAPES"); maxConcurrentScrapes != "" {
+		if max, err := strconv.Atoi(maxConcurrentScrapes); err == nil {
+			config.Server.MaxConcurrentScrapes = max
+		}
+	}
+	if scrapeQueueTimeout := os.Getenv("SERVER_SCRAPE_QUEUE_TIMEOUT"); scrapeQueueTimeout != "" {
+		if timeout, err := time.ParseDuration(scrapeQueueTimeout); err == nil {
+			config.Server.ScrapeQueueTimeout = timeout
+		}
+	}
+	if rateLimit := os.Getenv("SERVER_RATE_LIMIT"); rateLimit != "" {
+		if limit, err := strconv.ParseFloat(rateLimit, 64); err == nil {
+			config.Server.RateLimit = limit
+		}
+	}
+	if rateLimitBurst := os.Getenv("SERVER_RATE_LIMIT_BURST"); rateLimitBurst != "" {
+		if burst, err := strconv.Atoi(rateLimitBurst); err == nil {
+			config.Server.RateLimitBurst = burst
+		}
+	}
+	if coalesceScrapes := os.Getenv("SERVER_COALESCE_SCRAPES"); coalesceScrapes != "" {
+		if enabled, err := strconv.ParseBool(coalesceScrapes); err == nil {
+			config.Server.CoalesceScrapes = enabled
+		}
+	}
+	if landingPage := os.Getenv("SERVER_LANDING_PAGE"); landingPage != "" {
+		if enabled, err := strconv.ParseBool(landingPage); err == nil {
+			config.Server.LandingPage = enabled
+		}
+	}
+	if landingPageTitle := os.Getenv("SERVER_LANDING_PAGE_TITLE"); landingPageTitle != "" {
+		config.Server.LandingPageTitle = landingPageTitle
+	}
 
 	if collectionInterval := os.Getenv("METRICS_COLLECTION_INTERVAL"); collectionInterval != "" {
 		if interval, err := time.ParseDuration(collectionInterval); err == nil {
@@ -237,9 +648,102 @@ func loadFromEnv(config *Config) error {
 	if enabledMetrics := os.Getenv("METRICS_ENABLED"); enabledMetrics != "" {
 		config.Metrics.EnabledMetrics = strings.Split(enabledMetrics, ",")
 	}
+	if preset := os.Getenv("METRICS_PRESET"); preset != "" {
+		config.Metrics.Preset = preset
+	}
 	if disabledMetrics := os.Getenv("METRICS_DISABLED"); disabledMetrics != "" {
 		config.Metrics.DisabledMetrics = strings.Split(disabledMetrics, ",")
 	}
+	if maxSeries := os.Getenv("METRICS_MAX_SERIES_PER_METRIC"); maxSeries != "" {
+		if max, err := strconv.Atoi(maxSeries); err == nil {
+			config.Metrics.MaxSeriesPerMetric = max
+		}
+	}
+	if adaptive := os.Getenv("METRICS_ADAPTIVE_TIMEOUTS"); adaptive != "" {
+		if enabled, err := strconv.ParseBool(adaptive); err == nil {
+			config.Metrics.AdaptiveTimeouts = enabled
+		}
+	}
+	if timeoutMin := os.Getenv("METRICS_TIMEOUT_MIN"); timeoutMin != "" {
+		if timeout, err := time.ParseDuration(timeoutMin); err == nil {
+			config.Metrics.TimeoutMin = timeout
+		}
+	}
+	if timeoutMax := os.Getenv("METRICS_TIMEOUT_MAX"); timeoutMax != "" {
+		if timeout, err := time.ParseDuration(timeoutMax); err == nil {
+			config.Metrics.TimeoutMax = timeout
+		}
+	}
+	if watchdog := os.Getenv("METRICS_COLLECTOR_WATCHDOG"); watchdog != "" {
+		if timeout, err := time.ParseDuration(watchdog); err == nil {
+			config.Metrics.CollectorWatchdog = timeout
+		}
+	}
+	if staleAfter := os.Getenv("METRICS_STALE_AFTER_RUNS"); staleAfter != "" {
+		if runs, err := strconv.Atoi(staleAfter); err == nil {
+			config.Metrics.StaleAfterRuns = runs
+		}
+	}
+	if summaries := os.Getenv("METRICS_LATENCY_SUMMARIES"); summaries != "" {
+		config.Metrics.LatencySummaries.Metrics = strings.Split(summaries, ",")
+	}
+	if maxAge := os.Getenv("METRICS_LATENCY_SUMMARY_MAX_AGE"); maxAge != "" {
+		if age, err := time.ParseDuration(maxAge); err == nil {
+			config.Metrics.LatencySummaries.MaxAge = age
+		}
+	}
+	if replace := os.Getenv("METRICS_LATENCY_SUMMARIES_REPLACE"); replace != "" {
+		if enabled, err := strconv.ParseBool(replace); err == nil {
+			config.Metrics.LatencySummaries.ReplaceHistograms = enabled
+		}
+	}
+	if openMetrics := os.Getenv("METRICS_OPENMETRICS"); openMetrics != "" {
+		if enabled, err := strconv.ParseBool(openMetrics); err == nil {
+			config.Metrics.OpenMetrics = enabled
+		}
+	}
+	if parallelism := os.Getenv("METRICS_COLLECTION_PARALLELISM"); parallelism != "" {
+		if workers, err := strconv.Atoi(parallelism); err == nil {
+			config.Metrics.Parallelism = workers
+		}
+	}
+	if cacheTTL := os.Getenv("METRICS_NAMESPACE_CACHE_TTL"); cacheTTL != "" {
+		if ttl, err := time.ParseDuration(cacheTTL); err == nil {
+			config.Metrics.NamespaceCacheTTL = ttl
+		}
+	}
+	if clusterName := os.Getenv("METRICS_CLUSTER_NAME"); clusterName != "" {
+		config.Metrics.ClusterName = clusterName
+	}
+	if environment := os.Getenv("METRICS_ENVIRONMENT"); environment != "" {
+		config.Metrics.Environment = environment
+	}
+	if namespace := os.Getenv("METRICS_NAMESPACE"); namespace != "" {
+		config.Metrics.Namespace = namespace
+	}
+	if instanceLabel := os.Getenv("METRICS_INSTANCE_LABEL"); instanceLabel != "" {
+		config.Metrics.InstanceLabel = instanceLabel
+	}
+	if stripPort := os.Getenv("METRICS_STRIP_INSTANCE_PORT"); stripPort != "" {
+		if enabled, err := strconv.ParseBool(stripPort); err == nil {
+			config.Metrics.StripInstancePort = enabled
+		}
+	}
+	if roleLabel := os.Getenv("METRICS_CLUSTER_ROLE_LABEL"); roleLabel != "" {
+		if enabled, err := strconv.ParseBool(roleLabel); err == nil {
+			config.Metrics.ClusterRoleLabel = enabled
+		}
+	}
+	if runtimeMetrics := os.Getenv("METRICS_RUNTIME"); runtimeMetrics != "" {
+		if enabled, err := strconv.ParseBool(runtimeMetrics); err == nil {
+			config.Metrics.RuntimeMetrics = enabled
+		}
+	}
+	if derivedMetrics := os.Getenv("METRICS_DERIVED"); derivedMetrics != "" {
+		if enabled, err := strconv.ParseBool(derivedMetrics); err == nil {
+			config.Metrics.DerivedMetrics = enabled
+		}
+	}
 
 	if level := os.Getenv("LOG_LEVEL"); level != "" {
 		config.Logging.Level = level
@@ -250,6 +754,96 @@ func loadFromEnv(config *Config) error {
 	if outputPath := os.Getenv("LOG_OUTPUT_PATH"); outputPath != "" {
 		config.Logging.OutputPath = outputPath
 	}
+	if maxSize := os.Getenv("LOG_MAX_SIZE_MB"); maxSize != "" {
+		if size, err := strconv.Atoi(maxSize); err == nil {
+			config.Logging.MaxSizeMB = size
+		}
+	}
+	if maxAge := os.Getenv("LOG_MAX_AGE"); maxAge != "" {
+		if age, err := time.ParseDuration(maxAge); err == nil {
+			config.Logging.MaxAge = age
+		}
+	}
+	if maxBackups := os.Getenv("LOG_MAX_BACKUPS"); maxBackups != "" {
+		if backups, err := strconv.Atoi(maxBackups); err == nil {
+			config.Logging.MaxBackups = backups
+		}
+	}
+	if compress := os.Getenv("LOG_COMPRESS"); compress != "" {
+		if enabled, err := strconv.ParseBool(compress); err == nil {
+			config.Logging.Compress = enabled
+		}
+	}
+
+	if emfEnabled := os.Getenv("EMF_ENABLED"); emfEnabled != "" {
+		if enabled, err := strconv.ParseBool(emfEnabled); err == nil {
+			config.EMF.Enabled = enabled
+		}
+	}
+	if emfNamespace := os.Getenv("EMF_NAMESPACE"); emfNamespace != "" {
+		config.EMF.Namespace = emfNamespace
+	}
+	if emfInterval := os.Getenv("EMF_INTERVAL"); emfInterval != "" {
+		if interval, err := time.ParseDuration(emfInterval); err == nil {
+			config.EMF.Interval = interval
+		}
+	}
+	if emfOutputPath := os.Getenv("EMF_OUTPUT_PATH"); emfOutputPath != "" {
+		config.EMF.OutputPath = emfOutputPath
+	}
+	if emfMetricFamilies := os.Getenv("EMF_METRIC_FAMILIES"); emfMetricFamilies != "" {
+		config.EMF.MetricFamilies = strings.Split(emfMetricFamilies, ",")
+	}
+	if emfDimensions := os.Getenv("EMF_DIMENSIONS"); emfDimensions != "" {
+		config.EMF.Dimensions = strings.Split(emfDimensions, ",")
+	}
+
+	if tracingEnabled := os.Getenv("TRACING_ENABLED"); tracingEnabled != "" {
+		if enabled, err := strconv.ParseBool(tracingEnabled); err == nil {
+			config.Tracing.Enabled = enabled
+		}
+	}
+	if atlasPublicKey := os.Getenv("ATLAS_PUBLIC_KEY"); atlasPublicKey != "" {
+		config.Collectors.Atlas.PublicKey = atlasPublicKey
+	}
+	if atlasPrivateKey := os.Getenv("ATLAS_PRIVATE_KEY"); atlasPrivateKey != "" {
+		config.Collectors.Atlas.PrivateKey = atlasPrivateKey
+	}
+	if atlasProjectID := os.Getenv("ATLAS_PROJECT_ID"); atlasProjectID != "" {
+		config.Collectors.Atlas.ProjectID = atlasProjectID
+	}
+	if atlasCluster := os.Getenv("ATLAS_CLUSTER"); atlasCluster != "" {
+		config.Collectors.Atlas.Cluster = atlasCluster
+	}
+	if opsManagerURL := os.Getenv("OPS_MANAGER_URL"); opsManagerURL != "" {
+		config.Collectors.OpsManager.BaseURL = opsManagerURL
+	}
+	if opsManagerPublicKey := os.Getenv("OPS_MANAGER_PUBLIC_KEY"); opsManagerPublicKey != "" {
+		config.Collectors.OpsManager.PublicKey = opsManagerPublicKey
+	}
+	if opsManagerPrivateKey := os.Getenv("OPS_MANAGER_PRIVATE_KEY"); opsManagerPrivateKey != "" {
+		config.Collectors.OpsManager.PrivateKey = opsManagerPrivateKey
+	}
+	if opsManagerProjectID := os.Getenv("OPS_MANAGER_PROJECT_ID"); opsManagerProjectID != "" {
+		config.Collectors.OpsManager.ProjectID = opsManagerProjectID
+	}
+
+	if tracingEndpoint := os.Getenv("TRACING_ENDPOINT"); tracingEndpoint != "" {
+		config.Tracing.Endpoint = tracingEndpoint
+	}
+	if tracingInsecure := os.Getenv("TRACING_INSECURE"); tracingInsecure != "" {
+		if insecure, err := strconv.ParseBool(tracingInsecure); err == nil {
+			config.Tracing.Insecure = insecure
+		}
+	}
+	if serviceName := os.Getenv("TRACING_SERVICE_NAME"); serviceName != "" {
+		config.Tracing.ServiceName = serviceName
+	}
+	if sampleRatio := os.Getenv("TRACING_SAMPLE_RATIO"); sampleRatio != "" {
+		if ratio, err := strconv.ParseFloat(sampleRatio, 64); err == nil {
+			config.Tracing.SampleRatio = ratio
+		}
+	}
 
 	return nil
 }
@@ -271,6 +865,12 @@ func validateConfig(config *Config) error {
 		return fmt.Errorf("max pool size cannot be less than min pool size")
 	}
 
+	switch config.MongoDB.TargetFlavor {
+	case "", "mongodb", "documentdb", "ferretdb":
+	default:
+		return fmt.Errorf("target flavor must be mongodb, documentdb or ferretdb")
+	}
+
 	if config.Server.Port == "" {
 		return fmt.Errorf("server port is required")
 	}
@@ -287,9 +887,243 @@ func validateConfig(config *Config) error {
 		return fmt.Errorf("idle timeout must be positive")
 	}
 
+	if config.Server.MaxConcurrentScrapes < 0 {
+		return fmt.Errorf("max concurrent scrapes cannot be negative")
+	}
+
+	if config.Server.RateLimit < 0 {
+		return fmt.Errorf("rate limit cannot be negative")
+	}
+
+	if config.Server.CommandAuditSize < 0 {
+		return fmt.Errorf("command audit size cannot be negative")
+	}
+
 	if config.Metrics.CollectionInterval <= 0 {
 		return fmt.Errorf("collection interval must be positive")
 	}
 
+	if config.Metrics.Preset != "" {
+		if _, ok := metricPresets[config.Metrics.Preset]; !ok {
+			return fmt.Errorf("unknown metrics preset %q (expected minimal, default or full)", config.Metrics.Preset)
+		}
+	}
+
+	for name, collectors := range config.Metrics.ScrapeProfiles {
+		if name == "" {
+			return fmt.Errorf("scrape profile name cannot be empty")
+		}
+		if len(collectors) == 0 {
+			return fmt.Errorf("scrape profile %q lists no collectors", name)
+		}
+	}
+
+	if config.Metrics.AdaptiveTimeouts {
+		if config.Metrics.TimeoutMin <= 0 || config.Metrics.TimeoutMax <= 0 {
+			return fmt.Errorf("adaptive timeout bounds must be positive")
+		}
+		if config.Metrics.TimeoutMin > config.Metrics.TimeoutMax {
+			return fmt.Errorf("timeout min cannot be greater than timeout max")
+		}
+		if config.Metrics.CollectorWatchdog > 0 && config.Metrics.CollectorWatchdog <= config.Metrics.TimeoutMax {
+			return fmt.Errorf("collector watchdog must be greater than timeout max")
+		}
+	}
+
+	if config.Metrics.CollectorWatchdog < 0 {
+		return fmt.Errorf("collector watchdog cannot be negative")
+	}
+
+	if config.Metrics.Parallelism < 0 {
+		return fmt.Errorf("collection parallelism cannot be negative")
+	}
+
+	if config.Metrics.NamespaceCacheTTL < 0 {
+		return fmt.Errorf("namespace cache TTL cannot be negative")
+	}
+
+	firstClassLabels := map[string]bool{
+		"cluster_name": config.Metrics.ClusterName != "",
+		"environment":  config.Metrics.Environment != "",
+		"cluster_role": config.Metrics.ClusterRoleLabel,
+	}
+	for label, enabled := range firstClassLabels {
+		if _, ok := config.Metrics.CustomLabels[label]; ok && enabled {
+			return fmt.Errorf("custom label %q conflicts with a first-class metrics label option", label)
+		}
+	}
+
+	if config.Metrics.Namespace != "" && !metricNamespacePattern.MatchString(config.Metrics.Namespace) {
+		return fmt.Errorf("metrics namespace %q is not a valid Prometheus metric name prefix", config.Metrics.Namespace)
+	}
+
+	if config.Metrics.MaxSeriesPerMetric < 0 {
+		return fmt.Errorf("max series per metric cannot be negative")
+	}
+
+	for name, interval := range config.Collectors.Intervals() {
+		if interval < 0 {
+			return fmt.Errorf("%s collector interval cannot be negative", name)
+		}
+	}
+
+	switch config.Collectors.Profile.MemberScope {
+	case "", "all":
+	case "primary", "self":
+		// Without a direct connection reads are routed to whichever member the
+		// driver selects, so the scope would not describe the member reporting
+		uri := strings.ToLower(config.MongoDB.URI)
+		if !strings.Contains(uri, "directconnection=true") && !strings.Contains(uri, "connect=direct") {
+			return fmt.Errorf("profile member scope %q requires directConnection=true in the MongoDB URI", config.Collectors.Profile.MemberScope)
+		}
+	default:
+		return fmt.Errorf("unknown profile member scope %q (expected all, primary or self)", config.Collectors.Profile.MemberScope)
+	}
+
+	for _, pattern := range config.Collectors.Profile.Databases {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid profile database pattern %q: %w", pattern, err)
+		}
+	}
+
+	if config.Collectors.Sharding.MongosPingFreshness < 0 {
+		return fmt.Errorf("mongos ping freshness cannot be negative")
+	}
+
+	switch config.Collectors.CollStats.CountMode {
+	case "", "estimated", "exact":
+	default:
+		return fmt.Errorf("unknown collstats count mode %q (expected estimated or exact)", config.Collectors.CollStats.CountMode)
+	}
+
+	if config.Collectors.ConnectionPool.MaxClientGroups < 0 {
+		return fmt.Errorf("connection pool max client groups cannot be negative")
+	}
+
+	if config.Collectors.IndexStats.UnusedLookback < 0 {
+		return fmt.Errorf("index stats unused lookback cannot be negative")
+	}
+
+	if config.Collectors.IndexSelectivity.SampleSize < 0 {
+		return fmt.Errorf("index selectivity sample size cannot be negative")
+	}
+
+	if config.Collectors.ShardKeyDistribution.SampleSize < 0 {
+		return fmt.Errorf("shard key distribution sample size cannot be negative")
+	}
+	for _, name := range config.Collectors.ShardKeyDistribution.Collections {
+		if dbName, collName, ok := strings.Cut(name, "."); !ok || dbName == "" || collName == "" {
+			return fmt.Errorf("shard key distribution collection %q must be in database.collection form", name)
+		}
+	}
+
+	if config.Collectors.DBHash.Confirmations < 0 {
+		return fmt.Errorf("dbhash confirmations cannot be negative")
+	}
+	if config.Collectors.Ping.Count < 1 {
+		return fmt.Errorf("ping count must be at least 1")
+	}
+	if canary := config.Collectors.Canary; canary.Database == "admin" || canary.Database == "local" || canary.Database == "config" {
+		return fmt.Errorf("canary database cannot be the %s database", canary.Database)
+	}
+	if atlas := config.Collectors.Atlas; atlas.PublicKey != "" || atlas.PrivateKey != "" || atlas.ProjectID != "" {
+		if atlas.PublicKey == "" || atlas.PrivateKey == "" || atlas.ProjectID == "" {
+			return fmt.Errorf("atlas collector requires public_key, private_key and project_id together")
+		}
+	}
+	if atlas := config.Collectors.Atlas; atlas.BaseURL != "" && !strings.HasPrefix(atlas.BaseURL, "https://") && !strings.HasPrefix(atlas.BaseURL, "http://") {
+		return fmt.Errorf("atlas base URL %q must start with https:// or http://", atlas.BaseURL)
+	}
+
+	if om := config.Collectors.OpsManager; om.BaseURL != "" || om.PublicKey != "" || om.PrivateKey != "" || om.ProjectID != "" {
+		if om.BaseURL == "" || om.PublicKey == "" || om.PrivateKey == "" || om.ProjectID == "" {
+			return fmt.Errorf("opsmanager collector requires base_url, public_key, private_key and project_id together")
+		}
+		if !strings.HasPrefix(om.BaseURL, "https://") && !strings.HasPrefix(om.BaseURL, "http://") {
+			return fmt.Errorf("opsmanager base URL %q must start with https:// or http://", om.BaseURL)
+		}
+	}
+
+	for _, dbName := range config.Collectors.DBHash.Databases {
+		// local holds each member's own oplog and replica set state, so it always differs
+		if dbName == "" || dbName == "local" {
+			return fmt.Errorf("dbhash database %q cannot be compared across members", dbName)
+		}
+	}
+
+	if config.Metrics.StaleAfterRuns < 0 {
+		return fmt.Errorf("stale after runs cannot be negative")
+	}
+
+	for _, metric := range config.Metrics.LatencySummaries.Metrics {
+		switch metric {
+		case LatencyCommandDuration, LatencyPingRTT, LatencyProfileDuration:
+		default:
+			return fmt.Errorf("unknown latency summary metric %q (expected command_duration, ping_rtt or profile_duration)", metric)
+		}
+	}
+	if len(config.Metrics.LatencySummaries.Metrics) > 0 {
+		for quantile, allowedError := range config.Metrics.LatencySummaries.Objectives {
+			if quantile <= 0 || quantile >= 1 || allowedError < 0 || allowedError >= 1 {
+				return fmt.Errorf("latency summary objective %v: %v is invalid; quantiles and errors must be between 0 and 1", quantile, allowedError)
+			}
+		}
+		if config.Metrics.LatencySummaries.MaxAge <= 0 {
+			return fmt.Errorf("latency summary max age must be positive")
+		}
+	}
+
+	if config.Logging.MaxSizeMB < 0 || config.Logging.MaxAge < 0 || config.Logging.MaxBackups < 0 {
+		return fmt.Errorf("log rotation limits cannot be negative")
+	}
+
+	if config.EMF.Enabled {
+		if config.EMF.Namespace == "" {
+			return fmt.Errorf("EMF namespace is required when EMF output is enabled")
+		}
+		if config.EMF.Interval <= 0 {
+			return fmt.Errorf("EMF interval must be positive")
+		}
+		if len(config.EMF.Dimensions) > 30 {
+			return fmt.Errorf("EMF dimensions are limited to 30, got %d", len(config.EMF.Dimensions))
+		}
+	}
+
+	if config.Tracing.Enabled && (config.Tracing.SampleRatio < 0 || config.Tracing.SampleRatio > 1) {
+		return fmt.Errorf("tracing sample ratio must be between 0 and 1")
+	}
+
 	return nil
 }
+
+// applyPreset merges the selected preset's collectors into EnabledMetrics;
+// disabled_metrics still takes precedence over anything the preset enables
+func applyPreset(config *Config) {
+	preset, ok := metricPresets[config.Metrics.Preset]
+	if !ok {
+		return
+	}
+
+	enabled := make(map[string]bool, len(config.Metrics.EnabledMetrics))
+	for _, name := range config.Metrics.EnabledMetrics {
+		enabled[name] = true
+	}
+	for _, name := range preset {
+		if !enabled[name] {
+			config.Metrics.EnabledMetrics = append(config.Metrics.EnabledMetrics, name)
+			enabled[name] = true
+		}
+	}
+}
+
+// applyScrapeProfiles adds the predefined scrape profiles not overridden in the configuration
+func applyScrapeProfiles(config *Config) {
+	if config.Metrics.ScrapeProfiles == nil {
+		config.Metrics.ScrapeProfiles = make(map[string][]string, len(defaultScrapeProfiles))
+	}
+	for name, preset := range defaultScrapeProfiles {
+		if _, ok := config.Metrics.ScrapeProfiles[name]; !ok {
+			config.Metrics.ScrapeProfiles[name] = metricPresets[preset]
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package config
+
+import (
+	"flag"
+	"sort"
+	"strings"
+)
+
+// perconaCollectors maps percona/mongodb_exporter collector names onto the
+// collectors they correspond to here, so existing --collector.* flags keep working
+var perconaCollectors = map[string][]string{
+	"diagnosticdata":   {"server_status", "wiredtiger", "locks", "query_executor", "cursors"},
+	"replicasetstatus": {"replica_set_status"},
+	"dbstats":          {"storage_stats"},
+	"indexstats":       {"index_stats"},
+	"collstats":        {"collstats"},
+	"profile":          {"profile"},
+	"shards":           {"sharding"},
+	"currentopmetrics": {"connection_pool"},
+}
+
+// nativeCollectors are the collector names accepted by enabled_metrics
+var nativeCollectors = []string{
+	"server_status",
+	"replica_set_status",
+	"sharding",
+	"wiredtiger",
+	"locks",
+	"index_stats",
+	"storage_stats",
+	"query_executor",
+	"collstats",
+	"cursors",
+	"profile",
+	"connection_pool",
+	"compatibility",
+	"index_selectivity",
+	"shard_key_distribution",
+	"dbhash",
+	"maintenance",
+	"atlas",
+	"opsmanager",
+	"ping",
+	"canary",
+}
+
+// optInCollectors only run when enabled_metrics names them, so an empty list
+// leaves them off. Keep in sync with the collector package
+var optInCollectors = map[string]bool{
+	"atlas":                  true,
+	"canary":                 true,
+	"dbhash":                 true,
+	"index_selectivity":      true,
+	"opsmanager":             true,
+	"shard_key_distribution": true,
+}
+
+// CollectorFlags holds the Percona-style --collect-all, --collector.<name> and
+// --no-collector.<name> switches registered on a flag set
+type CollectorFlags struct {
+	fs         *flag.FlagSet
+	collectAll *bool
+	enable     map[string]*bool
+	disable    map[string]*bool
+}
+
+// RegisterCollectorFlags adds the collector switches to fs; call Apply after fs is parsed
+func RegisterCollectorFlags(fs *flag.FlagSet) *CollectorFlags {
+	cf := &CollectorFlags{
+		fs:         fs,
+		collectAll: fs.Bool("collect-all", false, "Enable all collectors"),
+		enable:     make(map[string]*bool),
+		disable:    make(map[string]*bool),
+	}
+
+	names := make([]string, 0, len(perconaCollectors)+len(nativeCollectors))
+	for name := range perconaCollectors {
+		names = append(names, name)
+	}
+	for _, name := range nativeCollectors {
+		if _, ok := perconaCollectors[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		target := "the " + name + " collector"
+		if collectors := collectorsFor(name); len(collectors) != 1 || collectors[0] != name {
+			target = strings.Join(collectors, ", ") + " (percona " + name + ")"
+		}
+		cf.enable[name] = fs.Bool("collector."+name, false, "Enable "+target)
+		cf.disable[name] = fs.Bool("no-collector."+name, false, "Disable "+target)
+	}
+
+	return cf
+}
+
+// Apply folds the switches given on the command line into metrics, overriding
+// enabled_metrics/disabled_metrics from the file and environment
+func (cf *CollectorFlags) Apply(metrics *MetricsConfig) {
+	set := make(map[string]bool)
+	cf.fs.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+
+	if set["collect-all"] && *cf.collectAll {
+		// An empty enabled list would leave the opt-in collectors off
+		metrics.EnabledMetrics = append([]string(nil), nativeCollectors...)
+	}
+
+	for name, value := range cf.enable {
+		if !set["collector."+name] {
+			continue
+		}
+		if *value {
+			enableCollectors(metrics, collectorsFor(name))
+		} else {
+			disableCollectors(metrics, collectorsFor(name))
+		}
+	}
+
+	for name, value := range cf.disable {
+		if set["no-collector."+name] && *value {
+			disableCollectors(metrics, collectorsFor(name))
+		}
+	}
+}
+
+func collectorsFor(name string) []string {
+	if collectors, ok := perconaCollectors[name]; ok {
+		return collectors
+	}
+	return []string{name}
+}
+
+func enableCollectors(metrics *MetricsConfig, names []string) {
+	for _, name := range names {
+		metrics.DisabledMetrics = removeString(metrics.DisabledMetrics, name)
+		if len(metrics.EnabledMetrics) == 0 {
+			// With an empty enabled list every collector but the opt-in ones
+			// already runs; naming one of those means listing the defaults too
+			if !optInCollectors[name] {
+				continue
+			}
+			metrics.EnabledMetrics = defaultCollectors()
+		}
+		if !containsString(metrics.EnabledMetrics, name) {
+			metrics.EnabledMetrics = append(metrics.EnabledMetrics, name)
+		}
+	}
+}
+
+// defaultCollectors lists the collectors an empty enabled list runs
+func defaultCollectors() []string {
+	var names []string
+	for _, name := range nativeCollectors {
+		if !optInCollectors[name] {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func disableCollectors(metrics *MetricsConfig, names []string) {
+	for _, name := range names {
+		if !containsString(metrics.DisabledMetrics, name) {
+			metrics.DisabledMetrics = append(metrics.DisabledMetrics, name)
+		}
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func removeString(values []string, value string) []string {
+	result := values[:0]
+	for _, v := range values {
+		if v != value {
+			result = append(result, v)
+		}
+	}
+	return result
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"flag"
+	"testing"
+)
+
+func TestCollectorFlags(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	cf := RegisterCollectorFlags(fs)
+
+	if err := fs.Parse([]string{"--collector.collstats", "--collector.indexstats", "--no-collector.profile"}); err != nil {
+		t.Fatalf("Failed to parse flags: %v", err)
+	}
+
+	metrics := MetricsConfig{
+		EnabledMetrics:  []string{"server_status"},
+		DisabledMetrics: []string{"collstats"},
+	}
+	cf.Apply(&metrics)
+
+	for _, name := range []string{"server_status", "collstats", "index_stats"} {
+		if !containsString(metrics.EnabledMetrics, name) {
+			t.Errorf("Expected %s to be enabled, got %v", name, metrics.EnabledMetrics)
+		}
+	}
+	if containsString(metrics.DisabledMetrics, "collstats") {
+		t.Error("--collector.collstats should remove collstats from disabled metrics")
+	}
+	if !containsString(metrics.DisabledMetrics, "profile") {
+		t.Error("--no-collector.profile should disable profile")
+	}
+}
+
+func TestCollectAllFlag(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	cf := RegisterCollectorFlags(fs)
+
+	if err := fs.Parse([]string{"--collect-all"}); err != nil {
+		t.Fatalf("Failed to parse flags: %v", err)
+	}
+
+	metrics := MetricsConfig{EnabledMetrics: []string{"server_status"}}
+	cf.Apply(&metrics)
+
+	for _, name := range nativeCollectors {
+		if !containsString(metrics.EnabledMetrics, name) {
+			t.Errorf("--collect-all should enable %s, got %v", name, metrics.EnabledMetrics)
+		}
+	}
+}
+
+func TestOptInCollectorFlag(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	cf := RegisterCollectorFlags(fs)
+
+	if err := fs.Parse([]string{"--collector.dbhash", "--collector.collstats"}); err != nil {
+		t.Fatalf("Failed to parse flags: %v", err)
+	}
+
+	var metrics MetricsConfig
+	cf.Apply(&metrics)
+
+	for _, name := range []string{"dbhash", "server_status", "collstats", "ping"} {
+		if !containsString(metrics.EnabledMetrics, name) {
+			t.Errorf("Expected %s to be enabled, got %v", name, metrics.EnabledMetrics)
+		}
+	}
+	for _, name := range []string{"canary", "atlas"} {
+		if containsString(metrics.EnabledMetrics, name) {
+			t.Errorf("Opt-in collector %s should stay off, got %v", name, metrics.EnabledMetrics)
+		}
+	}
+}
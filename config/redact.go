@@ -0,0 +1,74 @@
+package config
+
+import "strings"
+
+const redactedValue = "REDACTED"
+
+// uriSecretOptions are connection string options whose values are credentials
+var uriSecretOptions = map[string]bool{
+	"password":                        true,
+	"authmechanismproperties":         true,
+	"tlscertificatekeyfilepassword":   true,
+	"sslclientcertificatekeypassword": true,
+	"sslpemkeypassword":               true,
+}
+
+// Redacted returns a copy of the configuration safe to expose, with the MongoDB
+// password, any credentials embedded in the URI, the admin token and the Atlas
+// and Ops Manager private keys masked
+func (c Config) Redacted() Config {
+	redacted := c
+
+	redacted.MongoDB.URI = RedactURI(c.MongoDB.URI)
+	if c.MongoDB.Password != "" {
+		redacted.MongoDB.Password = redactedValue
+	}
+	if c.Server.AdminToken != "" {
+		redacted.Server.AdminToken = redactedValue
+	}
+	if c.Collectors.Atlas.PrivateKey != "" {
+		redacted.Collectors.Atlas.PrivateKey = redactedValue
+	}
+	if c.Collectors.OpsManager.PrivateKey != "" {
+		redacted.Collectors.OpsManager.PrivateKey = redactedValue
+	}
+
+	return redacted
+}
+
+// RedactURI masks the password in a MongoDB connection string and the values of
+// options carrying secrets, keeping the rest readable for logs
+func RedactURI(uri string) string {
+	schemeEnd := strings.Index(uri, "://")
+	if schemeEnd < 0 {
+		return uri
+	}
+	prefix, rest := uri[:schemeEnd+3], uri[schemeEnd+3:]
+
+	// The authority ends at the first path or query separator
+	authority, tail := rest, ""
+	if i := strings.IndexAny(rest, "/?"); i >= 0 {
+		authority, tail = rest[:i], rest[i:]
+	}
+
+	if at := strings.LastIndex(authority, "@"); at >= 0 {
+		userinfo, hosts := authority[:at], authority[at+1:]
+		if colon := strings.Index(userinfo, ":"); colon >= 0 {
+			userinfo = userinfo[:colon+1] + redactedValue
+		}
+		authority = userinfo + "@" + hosts
+	}
+
+	if q := strings.Index(tail, "?"); q >= 0 {
+		options := strings.Split(tail[q+1:], "&")
+		for i, option := range options {
+			key, _, found := strings.Cut(option, "=")
+			if found && uriSecretOptions[strings.ToLower(key)] {
+				options[i] = key + "=" + redactedValue
+			}
+		}
+		tail = tail[:q+1] + strings.Join(options, "&")
+	}
+
+	return prefix + authority + tail
+}
@@ -0,0 +1,119 @@
+package database
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+// CommandRecord is one MongoDB command issued by the exporter
+type CommandRecord struct {
+	Time            time.Time `json:"time"`
+	Command         string    `json:"command"`
+	Database        string    `json:"database"`
+	DurationSeconds float64   `json:"duration_seconds"`
+	ReplyBytes      int       `json:"reply_bytes"`
+	Error           string    `json:"error,omitempty"`
+}
+
+// CommandAudit records every command the exporter sends, keeping the most
+// recent ones for inspection and aggregating all of them as metrics, so the
+// load the exporter puts on MongoDB can be shown to DBAs
+type CommandAudit struct {
+	mu      sync.Mutex
+	records []CommandRecord
+	next    int
+	full    bool
+
+	commands   *prometheus.CounterVec
+	seconds    *prometheus.CounterVec
+	replyBytes *prometheus.CounterVec
+}
+
+// NewCommandAudit keeps the last size commands
+func NewCommandAudit(size int) *CommandAudit {
+	labels := []string{"command", "database"}
+	return &CommandAudit{
+		records: make([]CommandRecord, size),
+		commands: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "mongodb_exporter_audited_commands_total",
+			Help: "MongoDB commands issued by the exporter",
+		}, append(labels, "result")),
+		seconds: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "mongodb_exporter_audited_command_seconds_total",
+			Help: "Total time MongoDB spent on commands issued by the exporter",
+		}, labels),
+		replyBytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "mongodb_exporter_audited_command_reply_bytes_total",
+			Help: "Total size of replies to commands issued by the exporter",
+		}, labels),
+	}
+}
+
+// monitor returns the driver command monitor feeding the audit
+func (a *CommandAudit) monitor() *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Succeeded: func(_ context.Context, evt *event.CommandSucceededEvent) {
+			a.record(evt.CommandFinishedEvent, len(evt.Reply), "")
+		},
+		Failed: func(_ context.Context, evt *event.CommandFailedEvent) {
+			a.record(evt.CommandFinishedEvent, 0, evt.Failure)
+		},
+	}
+}
+
+func (a *CommandAudit) record(evt event.CommandFinishedEvent, replyBytes int, failure string) {
+	result := "success"
+	if failure != "" {
+		result = "error"
+	}
+	a.commands.WithLabelValues(evt.CommandName, evt.DatabaseName, result).Inc()
+	a.seconds.WithLabelValues(evt.CommandName, evt.DatabaseName).Add(evt.Duration.Seconds())
+	a.replyBytes.WithLabelValues(evt.CommandName, evt.DatabaseName).Add(float64(replyBytes))
+
+	if len(a.records) == 0 {
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.records[a.next] = CommandRecord{
+		Time:            time.Now().Add(-evt.Duration),
+		Command:         evt.CommandName,
+		Database:        evt.DatabaseName,
+		DurationSeconds: evt.Duration.Seconds(),
+		ReplyBytes:      replyBytes,
+		Error:           failure,
+	}
+	a.next = (a.next + 1) % len(a.records)
+	if a.next == 0 {
+		a.full = true
+	}
+}
+
+// Recent returns the retained commands, oldest first
+func (a *CommandAudit) Recent() []CommandRecord {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if !a.full {
+		return append([]CommandRecord(nil), a.records[:a.next]...)
+	}
+	return append(append([]CommandRecord(nil), a.records[a.next:]...), a.records[:a.next]...)
+}
+
+func (a *CommandAudit) Describe(ch chan<- *prometheus.Desc) {
+	a.commands.Describe(ch)
+	a.seconds.Describe(ch)
+	a.replyBytes.Describe(ch)
+}
+
+func (a *CommandAudit) Collect(ch chan<- prometheus.Metric) {
+	a.commands.Collect(ch)
+	a.seconds.Collect(ch)
+	a.replyBytes.Collect(ch)
+}
@@ -0,0 +1,48 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+func finishedEvent(command string, duration time.Duration) event.CommandFinishedEvent {
+	return event.CommandFinishedEvent{CommandName: command, DatabaseName: "admin", Duration: duration}
+}
+
+func TestCommandAudit(t *testing.T) {
+	audit := NewCommandAudit(2)
+	monitor := combineCommandMonitors(audit.monitor(), &event.CommandMonitor{})
+	ctx := context.Background()
+
+	monitor.Succeeded(ctx, &event.CommandSucceededEvent{CommandFinishedEvent: finishedEvent("serverStatus", time.Second), Reply: make([]byte, 100)})
+	monitor.Succeeded(ctx, &event.CommandSucceededEvent{CommandFinishedEvent: finishedEvent("serverStatus", time.Second), Reply: make([]byte, 50)})
+	monitor.Failed(ctx, &event.CommandFailedEvent{CommandFinishedEvent: finishedEvent("replSetGetStatus", time.Millisecond), Failure: "not running with --replSet"})
+
+	recent := audit.Recent()
+	if len(recent) != 2 {
+		t.Fatalf("Expected the last 2 commands to be kept, got %d", len(recent))
+	}
+	if recent[0].Command != "serverStatus" || recent[0].ReplyBytes != 50 {
+		t.Errorf("Expected the second serverStatus first, got %+v", recent[0])
+	}
+	if recent[1].Command != "replSetGetStatus" || recent[1].Error == "" {
+		t.Errorf("Expected the failed replSetGetStatus last, got %+v", recent[1])
+	}
+
+	if got := testutil.ToFloat64(audit.commands.WithLabelValues("serverStatus", "admin", "success")); got != 2 {
+		t.Errorf("Expected 2 audited serverStatus commands, got %v", got)
+	}
+	if got := testutil.ToFloat64(audit.replyBytes.WithLabelValues("serverStatus", "admin")); got != 150 {
+		t.Errorf("Expected 150 reply bytes, got %v", got)
+	}
+	if got := testutil.ToFloat64(audit.seconds.WithLabelValues("serverStatus", "admin")); got != 2 {
+		t.Errorf("Expected 2 seconds of serverStatus, got %v", got)
+	}
+	if got := testutil.ToFloat64(audit.commands.WithLabelValues("replSetGetStatus", "admin", "error")); got != 1 {
+		t.Errorf("Expected 1 failed replSetGetStatus, got %v", got)
+	}
+}
@@ -0,0 +1,43 @@
+package database
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+// commandMetrics times every command the exporter runs, showing which ones drive scrape time
+type commandMetrics struct {
+	duration *latencyMetric
+}
+
+func newCommandMetrics() *commandMetrics {
+	return &commandMetrics{
+		duration: newLatencyMetric(
+			"mongodb_exporter_command_duration_seconds",
+			"Duration of MongoDB commands run by the exporter",
+			[]float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+			[]string{"command"},
+		),
+	}
+}
+
+func (c *commandMetrics) monitor() *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Succeeded: func(_ context.Context, evt *event.CommandSucceededEvent) {
+			c.duration.observe(evt.Duration.Seconds(), evt.CommandName)
+		},
+		Failed: func(_ context.Context, evt *event.CommandFailedEvent) {
+			c.duration.observe(evt.Duration.Seconds(), evt.CommandName)
+		},
+	}
+}
+
+func (c *commandMetrics) Describe(ch chan<- *prometheus.Desc) {
+	c.duration.Describe(ch)
+}
+
+func (c *commandMetrics) Collect(ch chan<- prometheus.Metric) {
+	c.duration.Collect(ch)
+}
@@ -0,0 +1,46 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+func TestCommandMetrics(t *testing.T) {
+	commands := newCommandMetrics()
+	monitor := commands.monitor()
+	ctx := context.Background()
+
+	monitor.Succeeded(ctx, &event.CommandSucceededEvent{CommandFinishedEvent: finishedEvent("serverStatus", 20*time.Millisecond)})
+	monitor.Succeeded(ctx, &event.CommandSucceededEvent{CommandFinishedEvent: finishedEvent("collStats", 3*time.Second)})
+	monitor.Failed(ctx, &event.CommandFailedEvent{CommandFinishedEvent: finishedEvent("collStats", time.Second)})
+
+	registry := prometheus.NewPedanticRegistry()
+	registry.MustRegister(commands)
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Failed to gather command metrics: %v", err)
+	}
+	if len(families) != 1 || families[0].GetName() != "mongodb_exporter_command_duration_seconds" {
+		t.Fatalf("Expected only the command duration histogram, got %v", families)
+	}
+
+	expected := map[string]struct {
+		count uint64
+		sum   float64
+	}{
+		"serverStatus": {1, 0.02},
+		"collStats":    {2, 4},
+	}
+	for _, metric := range families[0].GetMetric() {
+		command := metric.GetLabel()[0].GetValue()
+		histogram := metric.GetHistogram()
+		if want := expected[command]; histogram.GetSampleCount() != want.count || histogram.GetSampleSum() != want.sum {
+			t.Errorf("%s: expected count %d and sum %v, got %d and %v",
+				command, want.count, want.sum, histogram.GetSampleCount(), histogram.GetSampleSum())
+		}
+	}
+}
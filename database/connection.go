@@ -6,30 +6,178 @@ import (
 	"crypto/x509"
 	"fmt"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/jimohabdol/mongodb-exporter/config"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo"
 	"go.uber.org/zap"
 )
 
+// Topology types reported by ServerInfo
+const (
+	TopologySharded    = "sharded"
+	TopologyReplicaSet = "replica_set"
+	TopologyStandalone = "standalone"
+)
+
+// ServerInfo describes the MongoDB deployment the exporter is connected to
+type ServerInfo struct {
+	Version      string `json:"version"`
+	TopologyType string `json:"topology_type"`
+	ReplicaSet   string `json:"replica_set,omitempty"`
+}
+
 type ConnectionManager struct {
-	client *mongo.Client
-	logger *zap.Logger
-	config *config.MongoDBConfig
+	client   *mongo.Client
+	logger   *zap.Logger
+	config   *config.MongoDBConfig
+	tracing  bool
+	audit    *CommandAudit
+	pool     *poolMetrics
+	commands *commandMetrics
+
+	membersMu sync.Mutex
+	members   map[string]*mongo.Client
 }
 
 func NewConnectionManager(cfg *config.MongoDBConfig, logger *zap.Logger) *ConnectionManager {
 	return &ConnectionManager{
-		logger: logger,
-		config: cfg,
+		logger:   logger,
+		config:   cfg,
+		pool:     newPoolMetrics(),
+		commands: newCommandMetrics(),
+	}
+}
+
+// EnableTracing records an OpenTelemetry span for every command sent by the
+// client; it must be called before Connect
+func (cm *ConnectionManager) EnableTracing() {
+	cm.tracing = true
+}
+
+// EnableCommandAudit records every command sent by the client, keeping the
+// last size of them; it must be called before Connect
+func (cm *ConnectionManager) EnableCommandAudit(size int) {
+	cm.audit = NewCommandAudit(size)
+}
+
+// EnableCommandSummaries also exports command durations as a summary with
+// quantiles; it must be called before Connect
+func (cm *ConnectionManager) EnableCommandSummaries(opts SummaryOptions) {
+	cm.commands.duration.enableSummary(opts)
+}
+
+// PoolMetrics exports the behavior of the client's connection pool
+func (cm *ConnectionManager) PoolMetrics() prometheus.Collector {
+	if cm.pool == nil {
+		return nil
 	}
+	return cm.pool
+}
+
+// CommandMetrics exports the duration of every command the client runs
+func (cm *ConnectionManager) CommandMetrics() prometheus.Collector {
+	if cm.commands == nil {
+		return nil
+	}
+	return cm.commands
+}
+
+// CommandAudit returns the command audit, or nil when auditing is disabled
+func (cm *ConnectionManager) CommandAudit() *CommandAudit {
+	return cm.audit
 }
 
 func (cm *ConnectionManager) Connect(ctx context.Context) error {
-	opts := options.Client().ApplyURI(cm.config.URI)
+	opts, err := cm.clientOptions()
+	if err != nil {
+		return err
+	}
+
+	client, err := mongo.Connect(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("failed to connect to MongoDB: %w", err)
+	}
+
+	if err := client.Ping(ctx, nil); err != nil {
+		return fmt.Errorf("failed to ping MongoDB: %w", err)
+	}
+
+	cm.client = client
+	cm.logger.Info("Successfully connected to MongoDB",
+		zap.String("uri", config.RedactURI(cm.config.URI)),
+		zap.String("database", cm.config.Database))
+
+	return nil
+}
+
+// MemberClient returns a client connected directly to one replica set member,
+// given as host:port, with the same credentials, TLS and monitoring as the main
+// client. Clients are created on first use and closed by Disconnect
+func (cm *ConnectionManager) MemberClient(ctx context.Context, host string) (*mongo.Client, error) {
+	cm.membersMu.Lock()
+	defer cm.membersMu.Unlock()
+
+	if client, ok := cm.members[host]; ok {
+		return client, nil
+	}
+
+	opts, err := cm.memberOptions(host)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := mongo.Connect(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to member %s: %w", host, err)
+	}
+
+	if cm.members == nil {
+		cm.members = make(map[string]*mongo.Client)
+	}
+	cm.members[host] = client
+	return client, nil
+}
+
+// clientOptions builds the driver options of the main client
+func (cm *ConnectionManager) clientOptions() (*options.ClientOptions, error) {
+	return cm.applyOptions(options.Client().ApplyURI(cm.config.URI))
+}
 
+// memberOptions builds the driver options of a client connected directly to
+// host. A direct connection cannot use a mongodb+srv URI, so rather than the
+// URI itself only what it resolved to carries over: credentials, TLS, the
+// app name and compressors
+func (cm *ConnectionManager) memberOptions(host string) (*options.ClientOptions, error) {
+	uri := options.Client().ApplyURI(cm.config.URI)
+	if err := uri.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid MongoDB URI: %w", err)
+	}
+
+	opts := options.Client().SetHosts([]string{host}).SetDirect(true)
+	if uri.Auth != nil {
+		opts.SetAuth(*uri.Auth)
+	}
+	if uri.TLSConfig != nil {
+		opts.SetTLSConfig(uri.TLSConfig)
+	}
+	if uri.AppName != nil {
+		opts.SetAppName(*uri.AppName)
+	}
+	if uri.Compressors != nil {
+		opts.SetCompressors(uri.Compressors)
+	}
+	return cm.applyOptions(opts)
+}
+
+// applyOptions adds the settings shared by the main and member clients to opts
+func (cm *ConnectionManager) applyOptions(opts *options.ClientOptions) (*options.ClientOptions, error) {
 	opts.SetConnectTimeout(cm.config.ConnectionTimeout)
 	opts.SetServerSelectionTimeout(cm.config.ServerSelectionTimeout)
 
@@ -62,29 +210,33 @@ func (cm *ConnectionManager) Connect(ctx context.Context) error {
 		opts.SetAuth(credential)
 	}
 
+	if cm.pool != nil {
+		opts.SetPoolMonitor(cm.pool.monitor())
+	}
+
+	var monitors []*event.CommandMonitor
+	if cm.commands != nil {
+		monitors = append(monitors, cm.commands.monitor())
+	}
+	if cm.tracing {
+		monitors = append(monitors, otelmongo.NewMonitor())
+	}
+	if cm.audit != nil {
+		monitors = append(monitors, cm.audit.monitor())
+	}
+	if len(monitors) > 0 {
+		opts.SetMonitor(combineCommandMonitors(monitors...))
+	}
+
 	if cm.config.TLSEnabled {
 		tlsConfig, err := cm.buildTLSConfig()
 		if err != nil {
-			return fmt.Errorf("failed to build TLS config: %w", err)
+			return nil, fmt.Errorf("failed to build TLS config: %w", err)
 		}
 		opts.SetTLSConfig(tlsConfig)
 	}
 
-	client, err := mongo.Connect(ctx, opts)
-	if err != nil {
-		return fmt.Errorf("failed to connect to MongoDB: %w", err)
-	}
-
-	if err := client.Ping(ctx, nil); err != nil {
-		return fmt.Errorf("failed to ping MongoDB: %w", err)
-	}
-
-	cm.client = client
-	cm.logger.Info("Successfully connected to MongoDB",
-		zap.String("uri", cm.config.URI),
-		zap.String("database", cm.config.Database))
-
-	return nil
+	return opts, nil
 }
 
 func (cm *ConnectionManager) buildTLSConfig() (*tls.Config, error) {
@@ -123,6 +275,15 @@ func (cm *ConnectionManager) GetClient() *mongo.Client {
 }
 
 func (cm *ConnectionManager) Disconnect(ctx context.Context) error {
+	cm.membersMu.Lock()
+	for host, client := range cm.members {
+		if err := client.Disconnect(ctx); err != nil {
+			cm.logger.Warn("Failed to disconnect from replica set member", zap.String("host", host), zap.Error(err))
+		}
+	}
+	cm.members = nil
+	cm.membersMu.Unlock()
+
 	if cm.client != nil {
 		if err := cm.client.Disconnect(ctx); err != nil {
 			cm.logger.Error("Failed to disconnect from MongoDB", zap.Error(err))
@@ -150,3 +311,36 @@ func (cm *ConnectionManager) GetDatabase() *mongo.Database {
 	}
 	return cm.client.Database(cm.config.Database)
 }
+
+// ServerInfo reports the server version and topology from buildInfo and hello
+func (cm *ConnectionManager) ServerInfo(ctx context.Context) (*ServerInfo, error) {
+	if cm.client == nil {
+		return nil, fmt.Errorf("MongoDB client is nil")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	admin := cm.client.Database("admin")
+
+	var buildInfo bson.M
+	if err := admin.RunCommand(ctx, bson.D{{Key: "buildInfo", Value: 1}}).Decode(&buildInfo); err != nil {
+		return nil, fmt.Errorf("failed to run buildInfo: %w", err)
+	}
+
+	hello, err := Hello(ctx, RunAdminCommand(cm.client))
+	if err != nil {
+		return nil, fmt.Errorf("failed to run hello: %w", err)
+	}
+
+	info := &ServerInfo{TopologyType: TopologyStandalone}
+	info.Version, _ = buildInfo["version"].(string)
+	if msg, _ := hello["msg"].(string); msg == "isdbgrid" {
+		info.TopologyType = TopologySharded
+	} else if setName, ok := hello["setName"].(string); ok {
+		info.TopologyType = TopologyReplicaSet
+		info.ReplicaSet = setName
+	}
+
+	return info, nil
+}
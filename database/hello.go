@@ -0,0 +1,31 @@
+package database
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// AdminCommand runs one command against the admin database and decodes the reply into result
+type AdminCommand func(ctx context.Context, command bson.D, result interface{}) error
+
+// RunAdminCommand returns an AdminCommand running commands directly on client
+func RunAdminCommand(client *mongo.Client) AdminCommand {
+	return func(ctx context.Context, command bson.D, result interface{}) error {
+		return client.Database("admin").RunCommand(ctx, command).Decode(result)
+	}
+}
+
+// Hello runs hello, falling back to isMaster on servers older than 4.4.2,
+// which only understand the latter
+func Hello(ctx context.Context, run AdminCommand) (bson.M, error) {
+	var hello bson.M
+	if err := run(ctx, bson.D{{Key: "hello", Value: 1}}, &hello); err != nil {
+		hello = nil
+		if err := run(ctx, bson.D{{Key: "isMaster", Value: 1}}, &hello); err != nil {
+			return nil, err
+		}
+	}
+	return hello, nil
+}
@@ -0,0 +1,88 @@
+package database
+
+import (
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// SummaryOptions configures the summary a latency metric is also exported as,
+// for users whose tooling cannot compute quantiles from histograms
+type SummaryOptions struct {
+	// Objectives maps each quantile to its allowed absolute error
+	Objectives map[float64]float64
+	// MaxAge is how long an observation counts towards the quantiles
+	MaxAge time.Duration
+	// ReplaceHistogram drops the histogram so only the summary is exported
+	ReplaceHistogram bool
+}
+
+// latencyMetric is a latency exported as a histogram, a summary with quantiles, or both
+type latencyMetric struct {
+	histogram *prometheus.HistogramVec
+	summary   *prometheus.SummaryVec
+
+	name   string
+	help   string
+	labels []string
+}
+
+func newLatencyMetric(name, help string, buckets []float64, labels []string) *latencyMetric {
+	return &latencyMetric{
+		histogram: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    name,
+			Help:    help,
+			Buckets: buckets,
+		}, labels),
+		name:   name,
+		help:   help,
+		labels: labels,
+	}
+}
+
+// summaryName keeps the summary apart from the histogram so both can be exported,
+// e.g. mongodb_exporter_command_duration_seconds becomes mongodb_exporter_command_duration_summary_seconds
+func summaryName(name string) string {
+	return strings.TrimSuffix(name, "_seconds") + "_summary_seconds"
+}
+
+// enableSummary adds the summary; it must be called before anything is observed
+func (l *latencyMetric) enableSummary(opts SummaryOptions) {
+	l.summary = prometheus.NewSummaryVec(prometheus.SummaryOpts{
+		Name:       summaryName(l.name),
+		Help:       l.help,
+		Objectives: opts.Objectives,
+		MaxAge:     opts.MaxAge,
+	}, l.labels)
+	if opts.ReplaceHistogram {
+		l.histogram = nil
+	}
+}
+
+func (l *latencyMetric) observe(seconds float64, labels ...string) {
+	if l.histogram != nil {
+		l.histogram.WithLabelValues(labels...).Observe(seconds)
+	}
+	if l.summary != nil {
+		l.summary.WithLabelValues(labels...).Observe(seconds)
+	}
+}
+
+func (l *latencyMetric) Describe(ch chan<- *prometheus.Desc) {
+	if l.histogram != nil {
+		l.histogram.Describe(ch)
+	}
+	if l.summary != nil {
+		l.summary.Describe(ch)
+	}
+}
+
+func (l *latencyMetric) Collect(ch chan<- prometheus.Metric) {
+	if l.histogram != nil {
+		l.histogram.Collect(ch)
+	}
+	if l.summary != nil {
+		l.summary.Collect(ch)
+	}
+}
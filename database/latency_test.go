@@ -0,0 +1,57 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func TestLatencyMetricSummary(t *testing.T) {
+	opts := SummaryOptions{Objectives: map[float64]float64{0.5: 0.05, 0.99: 0.001}, MaxAge: time.Minute}
+
+	both := newCommandMetrics()
+	both.duration.enableSummary(opts)
+	both.duration.observe(0.002, "ping")
+	both.duration.observe(0.004, "ping")
+
+	families := gather(t, both)
+	if len(families) != 2 {
+		t.Fatalf("Expected the histogram and the summary, got %v", families)
+	}
+	summary := families["mongodb_exporter_command_duration_summary_seconds"]
+	if summary == nil {
+		t.Fatalf("Summary should be exported next to the histogram, got %v", families)
+	}
+	if got := summary.GetMetric()[0].GetSummary(); got.GetSampleCount() != 2 || len(got.GetQuantile()) != 2 {
+		t.Errorf("Expected 2 samples over 2 quantiles, got %v", got)
+	}
+
+	opts.ReplaceHistogram = true
+	replaced := newCommandMetrics()
+	replaced.duration.enableSummary(opts)
+	replaced.duration.observe(0.01, "serverStatus")
+
+	families = gather(t, replaced)
+	if len(families) != 1 || families["mongodb_exporter_command_duration_summary_seconds"] == nil {
+		t.Errorf("Only the summary should be exported when replacing the histogram, got %v", families)
+	}
+}
+
+func gather(t *testing.T, collector prometheus.Collector) map[string]*dto.MetricFamily {
+	t.Helper()
+
+	registry := prometheus.NewPedanticRegistry()
+	registry.MustRegister(collector)
+	gathered, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Failed to gather metrics: %v", err)
+	}
+
+	families := make(map[string]*dto.MetricFamily)
+	for _, family := range gathered {
+		families[family.GetName()] = family
+	}
+	return families
+}
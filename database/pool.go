@@ -0,0 +1,119 @@
+package database
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+// poolMetrics exports the behavior of the exporter's own driver connection pool
+type poolMetrics struct {
+	checkouts         *prometheus.CounterVec
+	checkoutFailures  *prometheus.CounterVec
+	checkoutWait      *prometheus.HistogramVec
+	connectionsOpened *prometheus.CounterVec
+	connectionsClosed *prometheus.CounterVec
+	inUse             *prometheus.GaugeVec
+
+	// Pool events carry no request ID, so checkout starts are matched to their
+	// outcome in order; the driver also serves waiting checkouts in order
+	mu      sync.Mutex
+	waiting map[string][]time.Time
+	now     func() time.Time
+}
+
+func newPoolMetrics() *poolMetrics {
+	return &poolMetrics{
+		checkouts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "mongodb_exporter_pool_checkouts_total",
+			Help: "Connections checked out of the exporter's driver pool",
+		}, []string{"address"}),
+		checkoutFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "mongodb_exporter_pool_checkout_failures_total",
+			Help: "Failed connection checkouts from the exporter's driver pool",
+		}, []string{"address", "reason"}),
+		checkoutWait: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "mongodb_exporter_pool_checkout_wait_seconds",
+			Help:    "Time spent waiting to check a connection out of the exporter's driver pool",
+			Buckets: []float64{0.0005, 0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1, 5, 10},
+		}, []string{"address"}),
+		connectionsOpened: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "mongodb_exporter_pool_connections_created_total",
+			Help: "Connections created by the exporter's driver pool",
+		}, []string{"address"}),
+		connectionsClosed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "mongodb_exporter_pool_connections_closed_total",
+			Help: "Connections closed by the exporter's driver pool",
+		}, []string{"address", "reason"}),
+		inUse: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "mongodb_exporter_pool_connections_in_use",
+			Help: "Connections currently checked out of the exporter's driver pool",
+		}, []string{"address"}),
+		waiting: make(map[string][]time.Time),
+		now:     time.Now,
+	}
+}
+
+func (p *poolMetrics) monitor() *event.PoolMonitor {
+	return &event.PoolMonitor{Event: p.observe}
+}
+
+func (p *poolMetrics) observe(evt *event.PoolEvent) {
+	switch evt.Type {
+	case event.GetStarted:
+		p.mu.Lock()
+		p.waiting[evt.Address] = append(p.waiting[evt.Address], p.now())
+		p.mu.Unlock()
+	case event.GetSucceeded:
+		p.observeWait(evt.Address)
+		p.checkouts.WithLabelValues(evt.Address).Inc()
+		p.inUse.WithLabelValues(evt.Address).Inc()
+	case event.GetFailed:
+		p.observeWait(evt.Address)
+		p.checkoutFailures.WithLabelValues(evt.Address, evt.Reason).Inc()
+	case event.ConnectionReturned:
+		p.inUse.WithLabelValues(evt.Address).Dec()
+	case event.ConnectionCreated:
+		p.connectionsOpened.WithLabelValues(evt.Address).Inc()
+	case event.ConnectionClosed:
+		p.connectionsClosed.WithLabelValues(evt.Address, evt.Reason).Inc()
+	}
+}
+
+func (p *poolMetrics) observeWait(address string) {
+	p.mu.Lock()
+	queue := p.waiting[address]
+	if len(queue) == 0 {
+		p.mu.Unlock()
+		return
+	}
+	started := queue[0]
+	if len(queue) == 1 {
+		delete(p.waiting, address)
+	} else {
+		p.waiting[address] = queue[1:]
+	}
+	p.mu.Unlock()
+
+	p.checkoutWait.WithLabelValues(address).Observe(p.now().Sub(started).Seconds())
+}
+
+func (p *poolMetrics) Describe(ch chan<- *prometheus.Desc) {
+	p.checkouts.Describe(ch)
+	p.checkoutFailures.Describe(ch)
+	p.checkoutWait.Describe(ch)
+	p.connectionsOpened.Describe(ch)
+	p.connectionsClosed.Describe(ch)
+	p.inUse.Describe(ch)
+}
+
+func (p *poolMetrics) Collect(ch chan<- prometheus.Metric) {
+	p.checkouts.Collect(ch)
+	p.checkoutFailures.Collect(ch)
+	p.checkoutWait.Collect(ch)
+	p.connectionsOpened.Collect(ch)
+	p.connectionsClosed.Collect(ch)
+	p.inUse.Collect(ch)
+}
@@ -0,0 +1,67 @@
+package database
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+func TestPoolMetrics(t *testing.T) {
+	pool := newPoolMetrics()
+	clock := time.Now()
+	pool.now = func() time.Time { return clock }
+	monitor := pool.monitor()
+	address := "db1:27017"
+
+	monitor.Event(&event.PoolEvent{Type: event.ConnectionCreated, Address: address})
+	monitor.Event(&event.PoolEvent{Type: event.GetStarted, Address: address})
+	monitor.Event(&event.PoolEvent{Type: event.GetStarted, Address: address})
+	clock = clock.Add(2 * time.Second)
+	monitor.Event(&event.PoolEvent{Type: event.GetSucceeded, Address: address})
+	monitor.Event(&event.PoolEvent{Type: event.GetFailed, Address: address, Reason: event.ReasonTimedOut})
+	monitor.Event(&event.PoolEvent{Type: event.ConnectionReturned, Address: address})
+	monitor.Event(&event.PoolEvent{Type: event.ConnectionClosed, Address: address, Reason: event.ReasonIdle})
+
+	if got := testutil.ToFloat64(pool.checkouts.WithLabelValues(address)); got != 1 {
+		t.Errorf("Expected 1 checkout, got %v", got)
+	}
+	if got := testutil.ToFloat64(pool.checkoutFailures.WithLabelValues(address, event.ReasonTimedOut)); got != 1 {
+		t.Errorf("Expected 1 checkout failure, got %v", got)
+	}
+	if got := testutil.ToFloat64(pool.connectionsOpened.WithLabelValues(address)); got != 1 {
+		t.Errorf("Expected 1 created connection, got %v", got)
+	}
+	if got := testutil.ToFloat64(pool.connectionsClosed.WithLabelValues(address, event.ReasonIdle)); got != 1 {
+		t.Errorf("Expected 1 closed connection, got %v", got)
+	}
+	if got := testutil.ToFloat64(pool.inUse.WithLabelValues(address)); got != 0 {
+		t.Errorf("Expected no connections in use after check-in, got %v", got)
+	}
+	if len(pool.waiting) != 0 {
+		t.Error("Every checkout start should be matched to its outcome")
+	}
+
+	expected := `
+# HELP mongodb_exporter_pool_checkout_wait_seconds Time spent waiting to check a connection out of the exporter's driver pool
+# TYPE mongodb_exporter_pool_checkout_wait_seconds histogram
+mongodb_exporter_pool_checkout_wait_seconds_bucket{address="db1:27017",le="0.0005"} 0
+mongodb_exporter_pool_checkout_wait_seconds_bucket{address="db1:27017",le="0.001"} 0
+mongodb_exporter_pool_checkout_wait_seconds_bucket{address="db1:27017",le="0.005"} 0
+mongodb_exporter_pool_checkout_wait_seconds_bucket{address="db1:27017",le="0.01"} 0
+mongodb_exporter_pool_checkout_wait_seconds_bucket{address="db1:27017",le="0.05"} 0
+mongodb_exporter_pool_checkout_wait_seconds_bucket{address="db1:27017",le="0.1"} 0
+mongodb_exporter_pool_checkout_wait_seconds_bucket{address="db1:27017",le="0.5"} 0
+mongodb_exporter_pool_checkout_wait_seconds_bucket{address="db1:27017",le="1"} 0
+mongodb_exporter_pool_checkout_wait_seconds_bucket{address="db1:27017",le="5"} 2
+mongodb_exporter_pool_checkout_wait_seconds_bucket{address="db1:27017",le="10"} 2
+mongodb_exporter_pool_checkout_wait_seconds_bucket{address="db1:27017",le="+Inf"} 2
+mongodb_exporter_pool_checkout_wait_seconds_sum{address="db1:27017"} 4
+mongodb_exporter_pool_checkout_wait_seconds_count{address="db1:27017"} 2
+`
+	if err := testutil.CollectAndCompare(pool.checkoutWait, strings.NewReader(expected)); err != nil {
+		t.Errorf("Unexpected checkout wait histogram: %v", err)
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/jimohabdol/mongodb-exporter/config"
+)
+
+// runHealthcheck probes the local readiness endpoint and returns the process exit
+// code, so container health checks do not need curl or wget in the image
+func runHealthcheck(args []string) int {
+	fs := flag.NewFlagSet("healthcheck", flag.ExitOnError)
+	configPath := fs.String("config", "", "Path to configuration file, used to find the server port")
+	url := fs.String("url", "", "Readiness URL to probe (default http://localhost:<server.port>/-/ready)")
+	timeout := fs.Duration("timeout", 5*time.Second, "Timeout for the readiness request")
+	fs.Parse(args)
+
+	target := *url
+	if target == "" {
+		cfg, err := config.LoadConfig(*configPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+			return 1
+		}
+		target = "http://localhost:" + cfg.Server.Port + "/-/ready"
+	}
+
+	client := &http.Client{Timeout: *timeout}
+	resp, err := client.Get(target)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Health check failed: %v\n", err)
+		return 1
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		fmt.Fprintf(os.Stderr, "Health check failed: %s returned %s\n", target, resp.Status)
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,218 @@
+package logging
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// backupTimeFormat sorts lexically in rotation order
+const backupTimeFormat = "20060102T150405.000"
+
+// RotationOptions bounds how large and how old the active log file may get
+type RotationOptions struct {
+	// MaxSize rotates the file once a write would take it past this many bytes (0 = no size limit)
+	MaxSize int64
+	// MaxAge rotates the file once it has been open this long (0 = no age limit)
+	MaxAge time.Duration
+	// MaxBackups is how many rotated files are kept (0 = keep all)
+	MaxBackups int
+	// Compress gzips rotated files
+	Compress bool
+}
+
+// RotatingFile is an io.Writer appending to a file that is renamed to
+// <path>.<timestamp> and replaced with a new one when it gets too large or old
+type RotatingFile struct {
+	path    string
+	options RotationOptions
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+
+	// compressions tracks in-flight gzip of rotated files so Close can wait for them;
+	// housekeeping runs them one at a time so pruning never sees a half-compressed backup
+	compressions sync.WaitGroup
+	housekeeping sync.Mutex
+
+	now func() time.Time
+}
+
+// NewRotatingFile opens path for appending, creating it if needed
+func NewRotatingFile(path string, options RotationOptions) (*RotatingFile, error) {
+	rf := &RotatingFile{
+		path:    path,
+		options: options,
+		now:     time.Now,
+	}
+	if err := rf.open(); err != nil {
+		return nil, err
+	}
+	return rf, nil
+}
+
+func (rf *RotatingFile) open() error {
+	file, err := os.OpenFile(rf.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat log file: %w", err)
+	}
+
+	rf.file = file
+	rf.size = info.Size()
+	rf.openedAt = rf.now()
+	return nil
+}
+
+// Write appends p to the active file, rotating first if p would exceed the limits
+func (rf *RotatingFile) Write(p []byte) (int, error) {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+
+	if rf.shouldRotate(int64(len(p))) {
+		if err := rf.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := rf.file.Write(p)
+	rf.size += int64(n)
+	return n, err
+}
+
+func (rf *RotatingFile) shouldRotate(incoming int64) bool {
+	// An empty file is never rotated, so entries larger than MaxSize still get written
+	if rf.size == 0 {
+		return false
+	}
+	if rf.options.MaxSize > 0 && rf.size+incoming > rf.options.MaxSize {
+		return true
+	}
+	return rf.options.MaxAge > 0 && rf.now().Sub(rf.openedAt) >= rf.options.MaxAge
+}
+
+func (rf *RotatingFile) rotate() error {
+	if err := rf.file.Close(); err != nil {
+		return fmt.Errorf("failed to close log file: %w", err)
+	}
+
+	backup := rf.path + "." + rf.now().Format(backupTimeFormat)
+	if err := os.Rename(rf.path, backup); err != nil {
+		return fmt.Errorf("failed to rotate log file: %w", err)
+	}
+
+	if err := rf.open(); err != nil {
+		return err
+	}
+
+	// Compressing a large file inline would stall every logging goroutine
+	rf.compressions.Add(1)
+	go func() {
+		defer rf.compressions.Done()
+		rf.housekeeping.Lock()
+		defer rf.housekeeping.Unlock()
+
+		if rf.options.Compress {
+			if err := compressFile(backup); err != nil {
+				fmt.Fprintf(os.Stderr, "failed to compress rotated log file %s: %v\n", backup, err)
+			}
+		}
+		rf.pruneBackups()
+	}()
+	return nil
+}
+
+// pruneBackups removes the oldest rotated files beyond MaxBackups
+func (rf *RotatingFile) pruneBackups() {
+	if rf.options.MaxBackups <= 0 {
+		return
+	}
+
+	backups, err := rf.backups()
+	if err != nil || len(backups) <= rf.options.MaxBackups {
+		return
+	}
+
+	for _, backup := range backups[:len(backups)-rf.options.MaxBackups] {
+		os.Remove(backup)
+	}
+}
+
+// backups lists rotated files, oldest first
+func (rf *RotatingFile) backups() ([]string, error) {
+	matches, err := filepath.Glob(rf.path + ".*")
+	if err != nil {
+		return nil, err
+	}
+
+	backups := matches[:0]
+	for _, match := range matches {
+		stamp := match[len(rf.path)+1:]
+		if len(stamp) >= len(backupTimeFormat) {
+			if _, err := time.Parse(backupTimeFormat, stamp[:len(backupTimeFormat)]); err == nil {
+				backups = append(backups, match)
+			}
+		}
+	}
+	sort.Strings(backups)
+	return backups, nil
+}
+
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(dst)
+	if _, err := io.Copy(gz, src); err != nil {
+		dst.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		dst.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(path + ".gz")
+		return err
+	}
+
+	return os.Remove(path)
+}
+
+// Sync flushes the active file to disk
+func (rf *RotatingFile) Sync() error {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+	return rf.file.Sync()
+}
+
+// Close closes the active file after any pending compression finishes
+func (rf *RotatingFile) Close() error {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+
+	rf.compressions.Wait()
+	return rf.file.Close()
+}
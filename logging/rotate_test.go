@@ -0,0 +1,110 @@
+package logging
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRotatingFileRotatesBySize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "exporter.log")
+	rf, err := NewRotatingFile(path, RotationOptions{MaxSize: 10})
+	if err != nil {
+		t.Fatalf("Failed to open rotating file: %v", err)
+	}
+
+	clock := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	rf.now = func() time.Time { return clock }
+
+	rf.Write([]byte("12345678\n"))
+	clock = clock.Add(time.Second)
+	rf.Write([]byte("abcdefgh\n"))
+	if err := rf.Close(); err != nil {
+		t.Fatalf("Failed to close rotating file: %v", err)
+	}
+
+	active, _ := os.ReadFile(path)
+	if string(active) != "abcdefgh\n" {
+		t.Errorf("Active file should only hold the latest entry, got %q", active)
+	}
+
+	backup, err := os.ReadFile(path + ".20260101T120001.000")
+	if err != nil {
+		t.Fatalf("Rotated file should exist: %v", err)
+	}
+	if string(backup) != "12345678\n" {
+		t.Errorf("Rotated file should hold the earlier entry, got %q", backup)
+	}
+}
+
+func TestRotatingFileRotatesByAge(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "exporter.log")
+	rf, err := NewRotatingFile(path, RotationOptions{MaxAge: time.Hour})
+	if err != nil {
+		t.Fatalf("Failed to open rotating file: %v", err)
+	}
+
+	clock := time.Now()
+	rf.now = func() time.Time { return clock }
+	rf.openedAt = clock
+
+	rf.Write([]byte("first\n"))
+	rf.Write([]byte("second\n"))
+	clock = clock.Add(time.Hour)
+	rf.Write([]byte("third\n"))
+	rf.Close()
+
+	backups, _ := rf.backups()
+	if len(backups) != 1 {
+		t.Fatalf("Expected 1 rotated file, got %d", len(backups))
+	}
+	active, _ := os.ReadFile(path)
+	if string(active) != "third\n" {
+		t.Errorf("Active file should start over after max age, got %q", active)
+	}
+}
+
+func TestRotatingFilePrunesAndCompressesBackups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "exporter.log")
+	rf, err := NewRotatingFile(path, RotationOptions{MaxSize: 1, MaxBackups: 2, Compress: true})
+	if err != nil {
+		t.Fatalf("Failed to open rotating file: %v", err)
+	}
+
+	clock := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	rf.now = func() time.Time { return clock }
+
+	for _, entry := range []string{"a", "b", "c", "d", "e"} {
+		clock = clock.Add(time.Second)
+		rf.Write([]byte(entry))
+		// Let each rotation's housekeeping finish so pruning order is deterministic
+		rf.compressions.Wait()
+	}
+	rf.Close()
+
+	backups, _ := rf.backups()
+	if len(backups) != 2 {
+		t.Fatalf("Expected 2 rotated files to be kept, got %v", backups)
+	}
+
+	for i, expected := range []string{"c", "d"} {
+		if !strings.HasSuffix(backups[i], ".gz") {
+			t.Errorf("Rotated file %s should be compressed", backups[i])
+			continue
+		}
+		file, _ := os.Open(backups[i])
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			t.Fatalf("Failed to read compressed backup: %v", err)
+		}
+		content, _ := io.ReadAll(gz)
+		file.Close()
+		if string(content) != expected {
+			t.Errorf("Expected backup %d to hold %q, got %q", i, expected, content)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+//go:build !windows
+
+package main
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// handleLogLevelSignals switches to debug logging on SIGUSR1 and back to the
+// configured level on SIGUSR2
+func handleLogLevelSignals(level zap.AtomicLevel, logger *zap.Logger) {
+	configured := level.Level()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGUSR1, syscall.SIGUSR2)
+
+	go func() {
+		for sig := range signals {
+			target := configured
+			if sig == syscall.SIGUSR1 {
+				target = zapcore.DebugLevel
+			}
+
+			previous := level.Level()
+			level.SetLevel(target)
+			logger.Warn("Log level changed via signal",
+				zap.String("signal", sig.String()),
+				zap.Stringer("from", previous),
+				zap.Stringer("to", target))
+		}
+	}()
+}
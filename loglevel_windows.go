@@ -0,0 +1,8 @@
+//go:build windows
+
+package main
+
+import "go.uber.org/zap"
+
+// handleLogLevelSignals is a no-op: Windows has no SIGUSR1/SIGUSR2, use PUT /admin/loglevel instead
+func handleLogLevelSignals(level zap.AtomicLevel, logger *zap.Logger) {}
@@ -6,12 +6,15 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"runtime"
 	"syscall"
 	"time"
 
 	"github.com/jimohabdol/mongodb-exporter/config"
 	"github.com/jimohabdol/mongodb-exporter/database"
+	"github.com/jimohabdol/mongodb-exporter/logging"
 	"github.com/jimohabdol/mongodb-exporter/server"
+	"github.com/jimohabdol/mongodb-exporter/tracing"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -23,16 +26,33 @@ var (
 )
 
 func main() {
+	args := os.Args[1:]
+	scrapeOnce := false
+	if len(args) > 0 {
+		switch args[0] {
+		case "healthcheck":
+			os.Exit(runHealthcheck(args[1:]))
+		case "check-permissions":
+			os.Exit(runCheckPermissions(args[1:]))
+		case "scrape":
+			scrapeOnce = true
+			args = args[1:]
+		}
+	}
+
 	var (
 		configPath  = flag.String("config", "", "Path to configuration file")
 		showVersion = flag.Bool("version", false, "Show version information")
+		dryRun      = flag.Bool("dry-run", false, "Collect metrics once, print them to stdout and exit")
 	)
-	flag.Parse()
+	collectorFlags := config.RegisterCollectorFlags(flag.CommandLine)
+	flag.CommandLine.Parse(args)
 
 	if *showVersion {
 		fmt.Printf("MongoDB Exporter v%s\n", version)
 		fmt.Printf("Build Time: %s\n", buildTime)
 		fmt.Printf("Git Commit: %s\n", gitCommit)
+		fmt.Printf("Go Version: %s\n", runtime.Version())
 		os.Exit(0)
 	}
 
@@ -41,8 +61,13 @@ func main() {
 		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
 		os.Exit(1)
 	}
+	collectorFlags.Apply(&cfg.Metrics)
+
+	if scrapeOnce || *dryRun {
+		os.Exit(runScrape(cfg))
+	}
 
-	logger, err := setupLogger(cfg.Logging)
+	logger, logLevel, err := setupLogger(cfg.Logging)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to setup logger: %v\n", err)
 		os.Exit(1)
@@ -62,22 +87,63 @@ func main() {
 
 	connManager := database.NewConnectionManager(&cfg.MongoDB, logger)
 
+	shutdownTracing := func(context.Context) error { return nil }
+	if cfg.Tracing.Enabled {
+		shutdownTracing, err = tracing.Setup(ctx, cfg.Tracing, version)
+		if err != nil {
+			logger.Fatal("Failed to set up tracing", zap.Error(err))
+		}
+		connManager.EnableTracing()
+		logger.Info("OpenTelemetry tracing enabled",
+			zap.String("endpoint", cfg.Tracing.Endpoint),
+			zap.Float64("sample_ratio", cfg.Tracing.SampleRatio))
+	}
+
+	if cfg.Server.EnableCommandAudit {
+		connManager.EnableCommandAudit(cfg.Server.CommandAuditSize)
+	}
+
+	summaries := cfg.Metrics.LatencySummaries
+	summaryOptions := database.SummaryOptions{
+		Objectives:       summaries.QuantileObjectives(),
+		MaxAge:           summaries.MaxAge,
+		ReplaceHistogram: summaries.ReplaceHistograms,
+	}
+	if summaries.Includes(config.LatencyCommandDuration) {
+		connManager.EnableCommandSummaries(summaryOptions)
+	}
+
 	if err := connManager.Connect(ctx); err != nil {
 		logger.Fatal("Failed to connect to MongoDB", zap.Error(err))
 	}
 
 	srv := server.NewServer(cfg, logger, connManager)
+	srv.SetBuildInfo(server.BuildInfo{
+		Version:   version,
+		GitCommit: gitCommit,
+		BuildTime: buildTime,
+	})
+	srv.SetLogLevel(logLevel)
+	handleLogLevelSignals(logLevel, logger)
 	if err := srv.Start(ctx); err != nil {
 		logger.Fatal("Failed to start server", zap.Error(err))
 	}
 
 	logger.Info("MongoDB Exporter started successfully",
 		zap.String("port", cfg.Server.Port),
-		zap.String("mongodb_uri", cfg.MongoDB.URI))
+		zap.String("mongodb_uri", config.RedactURI(cfg.MongoDB.URI)))
+
+	if err := server.NotifySystemd("READY=1"); err != nil {
+		logger.Warn("Failed to send readiness notification to systemd", zap.Error(err))
+	}
 
 	<-sigChan
 	logger.Info("Received shutdown signal, starting graceful shutdown")
 
+	if err := server.NotifySystemd("STOPPING=1"); err != nil {
+		logger.Warn("Failed to send stopping notification to systemd", zap.Error(err))
+	}
+
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer shutdownCancel()
 
@@ -89,13 +155,18 @@ func main() {
 		logger.Error("Failed to disconnect from MongoDB", zap.Error(err))
 	}
 
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		logger.Error("Failed to flush traces", zap.Error(err))
+	}
+
 	logger.Info("MongoDB Exporter shutdown complete")
 }
 
-func setupLogger(cfg config.LoggingConfig) (*zap.Logger, error) {
+// setupLogger builds the logger along with the level that controls it at runtime
+func setupLogger(cfg config.LoggingConfig) (*zap.Logger, zap.AtomicLevel, error) {
 	var level zapcore.Level
 	if err := level.UnmarshalText([]byte(cfg.Level)); err != nil {
-		return nil, fmt.Errorf("invalid log level: %w", err)
+		return nil, zap.AtomicLevel{}, fmt.Errorf("invalid log level: %w", err)
 	}
 
 	config := zap.NewProductionConfig()
@@ -114,5 +185,45 @@ func setupLogger(cfg config.LoggingConfig) (*zap.Logger, error) {
 		config.Encoding = "json"
 	}
 
-	return config.Build()
+	var logger *zap.Logger
+	var err error
+	if isLogFile(cfg.OutputPath) && (cfg.MaxSizeMB > 0 || cfg.MaxAge > 0) {
+		logger, err = buildRotatingLogger(config, cfg)
+	} else {
+		logger, err = config.Build()
+	}
+	return logger, config.Level, err
+}
+
+// isLogFile reports whether an output path names a file rather than a standard stream
+func isLogFile(path string) bool {
+	return path != "" && path != "stdout" && path != "stderr"
+}
+
+// buildRotatingLogger builds the logger from config but writes through a
+// rotating file instead of letting the log file grow forever
+func buildRotatingLogger(zapConfig zap.Config, cfg config.LoggingConfig) (*zap.Logger, error) {
+	file, err := logging.NewRotatingFile(cfg.OutputPath, logging.RotationOptions{
+		MaxSize:    int64(cfg.MaxSizeMB) * 1024 * 1024,
+		MaxAge:     cfg.MaxAge,
+		MaxBackups: cfg.MaxBackups,
+		Compress:   cfg.Compress,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var encoder zapcore.Encoder
+	if zapConfig.Encoding == "console" {
+		encoder = zapcore.NewConsoleEncoder(zapConfig.EncoderConfig)
+	} else {
+		encoder = zapcore.NewJSONEncoder(zapConfig.EncoderConfig)
+	}
+
+	// Build still applies caller, stacktrace and error output options; only the core is swapped
+	zapConfig.OutputPaths = nil
+	return zapConfig.Build(zap.WrapCore(func(zapcore.Core) zapcore.Core {
+		core := zapcore.NewCore(encoder, zapcore.AddSync(file), zapConfig.Level)
+		return zapcore.NewSamplerWithOptions(core, time.Second, zapConfig.Sampling.Initial, zapConfig.Sampling.Thereafter)
+	}))
 }
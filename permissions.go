@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jimohabdol/mongodb-exporter/collector"
+	"github.com/jimohabdol/mongodb-exporter/config"
+	"github.com/jimohabdol/mongodb-exporter/database"
+	"go.uber.org/zap"
+)
+
+// runCheckPermissions runs the commands each enabled collector depends on and
+// reports which collectors will fail for lack of privileges; it exits 1 if any will
+func runCheckPermissions(args []string) int {
+	fs := flag.NewFlagSet("check-permissions", flag.ExitOnError)
+	configPath := fs.String("config", "", "Path to configuration file")
+	collectorFlags := config.RegisterCollectorFlags(fs)
+	fs.Parse(args)
+
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+		return 1
+	}
+	collectorFlags.Apply(&cfg.Metrics)
+
+	ctx := context.Background()
+	connManager := database.NewConnectionManager(&cfg.MongoDB, zap.NewNop())
+	if err := connManager.Connect(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to connect to MongoDB: %v\n", err)
+		return 1
+	}
+	defer connManager.Disconnect(ctx)
+	client := connManager.GetClient()
+
+	if privileges, err := collector.CheckConnectionPrivileges(ctx, client); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	} else {
+		fmt.Printf("Authenticated as: %s\n", joinOrNone(privileges.Users))
+		fmt.Printf("Roles: %s\n\n", joinOrNone(privileges.Roles))
+	}
+
+	checks := collector.CheckPermissions(ctx, client, zap.NewNop(), collector.CollectorConfig{
+		EnabledMetrics:  cfg.Metrics.EnabledMetrics,
+		DisabledMetrics: cfg.Metrics.DisabledMetrics,
+		Collectors: map[string]interface{}{
+			"canary": map[string]interface{}{
+				"database":   cfg.Collectors.Canary.Database,
+				"collection": cfg.Collectors.Canary.Collection,
+			},
+		},
+	})
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "COLLECTOR\tCOMMAND\tRESULT")
+	var failing []string
+	missing := make(map[string]bool)
+	for _, check := range checks {
+		result := "ok"
+		switch {
+		case check.Unauthorized:
+			result = "UNAUTHORIZED - requires " + check.Hint
+			if !missing[check.Collector] {
+				missing[check.Collector] = true
+				failing = append(failing, check.Collector)
+			}
+		case check.Err != nil:
+			// Usually the command does not apply to this deployment type
+			result = "error: " + check.Err.Error()
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", check.Collector, check.Command, result)
+	}
+	w.Flush()
+
+	if len(failing) > 0 {
+		fmt.Printf("\nCollectors that will fail due to missing privileges: %s\n", strings.Join(failing, ", "))
+		return 1
+	}
+
+	fmt.Println("\nAll enabled collectors have the privileges they need.")
+	return 0
+}
+
+func joinOrNone(values []string) string {
+	if len(values) == 0 {
+		return "(none)"
+	}
+	return strings.Join(values, ", ")
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/jimohabdol/mongodb-exporter/config"
+	"github.com/jimohabdol/mongodb-exporter/database"
+	"github.com/jimohabdol/mongodb-exporter/server"
+	"go.uber.org/zap"
+)
+
+// runScrape connects, runs every enabled collector once and prints the metrics
+// to stdout, returning the process exit code
+func runScrape(cfg *config.Config) int {
+	// Keep stdout clean for the exposition text
+	if cfg.Logging.OutputPath == "" || cfg.Logging.OutputPath == "stdout" {
+		cfg.Logging.OutputPath = "stderr"
+	}
+
+	logger, _, err := setupLogger(cfg.Logging)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to setup logger: %v\n", err)
+		return 1
+	}
+	defer logger.Sync()
+
+	ctx := context.Background()
+
+	connManager := database.NewConnectionManager(&cfg.MongoDB, logger)
+	if err := connManager.Connect(ctx); err != nil {
+		logger.Error("Failed to connect to MongoDB", zap.Error(err))
+		return 1
+	}
+	defer connManager.Disconnect(ctx)
+
+	srv := server.NewServer(cfg, logger, connManager)
+	if err := srv.WriteMetrics(ctx, os.Stdout); err != nil {
+		logger.Error("Failed to scrape metrics", zap.Error(err))
+		return 1
+	}
+
+	return 0
+}
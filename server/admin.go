@@ -0,0 +1,117 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/jimohabdol/mongodb-exporter/collector"
+	"go.uber.org/zap"
+)
+
+const adminCollectorsPath = "/admin/collectors"
+
+// registerAdminHandlers exposes runtime collector management, log level control and the effective configuration behind admin auth
+func (s *Server) registerAdminHandlers(mux *http.ServeMux) {
+	mux.Handle("/config", s.requireAdminAuth(http.HandlerFunc(s.configHandler)))
+	mux.Handle(adminCollectorsPath, s.requireAdminAuth(http.HandlerFunc(s.listCollectorsHandler)))
+	mux.Handle(adminCollectorsPath+"/", s.requireAdminAuth(http.HandlerFunc(s.toggleCollectorHandler)))
+	mux.Handle("/admin/loglevel", s.requireAdminAuth(http.HandlerFunc(s.logLevelHandler)))
+	mux.Handle("/admin/scrape", s.requireAdminAuth(s.limitConcurrentScrapes(http.HandlerFunc(s.forceScrapeHandler))))
+}
+
+func (s *Server) listCollectorsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s.collectorManager.Collectors())
+}
+
+// toggleCollectorHandler serves POST /admin/collectors/{name}/enable|disable
+func (s *Server) toggleCollectorHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, adminCollectorsPath+"/"), "/")
+	if len(parts) != 2 || parts[0] == "" {
+		http.NotFound(w, r)
+		return
+	}
+	name, action := parts[0], parts[1]
+
+	var err error
+	switch action {
+	case "enable":
+		err = s.collectorManager.EnableCollector(name)
+	case "disable":
+		err = s.collectorManager.DisableCollector(name)
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	switch {
+	case errors.Is(err, collector.ErrUnknownCollector):
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case errors.Is(err, collector.ErrCollectorDisabledByConfig):
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	s.logger.Info("Collector toggled via admin API",
+		zap.String("collector", name),
+		zap.String("action", action),
+		zap.String("remote_addr", r.RemoteAddr))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s.collectorManager.Collectors())
+}
+
+// forceScrapeHandler serves POST /admin/scrape?collectors=collstats,indexstats,
+// running scheduled collectors now instead of at their next interval
+func (s *Server) forceScrapeHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var names []string
+	for _, name := range strings.Split(r.URL.Query().Get("collectors"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	results, err := s.collectorManager.RefreshCollectors(names)
+	switch {
+	case errors.Is(err, collector.ErrUnknownCollector):
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case errors.Is(err, collector.ErrCollectorDisabledByConfig), errors.Is(err, collector.ErrCollectorNotScheduled):
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	s.logger.Info("Collectors refreshed via admin API",
+		zap.Strings("collectors", names),
+		zap.String("remote_addr", r.RemoteAddr))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(results)
+}
@@ -0,0 +1,18 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// commandAuditHandler serves the most recent MongoDB commands issued by the exporter
+func (s *Server) commandAuditHandler(w http.ResponseWriter, r *http.Request) {
+	audit := s.connectionManager.CommandAudit()
+	if audit == nil {
+		http.Error(w, "Command audit is not enabled", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(audit.Recent())
+}
@@ -0,0 +1,33 @@
+package server
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// requireAdminAuth guards operational endpoints with the configured admin token.
+// Requests are rejected outright when no token is configured.
+func (s *Server) requireAdminAuth(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := s.config.Server.AdminToken
+		if token == "" {
+			http.Error(w, "Admin endpoints are disabled: no admin token configured", http.StatusForbidden)
+			return
+		}
+
+		provided := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			s.logger.Warn("Rejected unauthorized admin request",
+				zap.String("path", r.URL.Path),
+				zap.String("remote_addr", r.RemoteAddr))
+			w.Header().Set("WWW-Authenticate", `Bearer realm="mongodb-exporter"`)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		handler.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,234 @@
+package server
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"go.uber.org/zap"
+)
+
+// clusterAggregation derives one cluster-level series family from a family
+// gathered per instance, so dashboards need no cross-shard PromQL
+type clusterAggregation struct {
+	name      string
+	help      string
+	source    string
+	by        []string
+	valueType prometheus.ValueType
+	// reduce combines the values of the source series sharing the by labels
+	reduce func(values []float64) float64
+	// group, when set, first reduces the source series per these labels, e.g.
+	// one value per replica set member however many members observed it
+	group       []string
+	groupReduce func(values []float64) float64
+}
+
+func sumValues(values []float64) float64 {
+	total := 0.0
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
+
+func maxValue(values []float64) float64 {
+	highest := values[0]
+	for _, v := range values[1:] {
+		if v > highest {
+			highest = v
+		}
+	}
+	return highest
+}
+
+func minValue(values []float64) float64 {
+	lowest := values[0]
+	for _, v := range values[1:] {
+		if v < lowest {
+			lowest = v
+		}
+	}
+	return lowest
+}
+
+func countZero(values []float64) float64 {
+	count := 0.0
+	for _, v := range values {
+		if v == 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// clusterAggregations are the series served on /metrics/cluster; names are
+// relative to the metric namespace
+var clusterAggregations = []clusterAggregation{
+	{
+		name:      "_cluster_op_counters_total",
+		help:      "Operations by type summed over every instance the exporter collects from",
+		source:    "_op_counters_total",
+		by:        []string{"type"},
+		valueType: prometheus.CounterValue,
+		reduce:    sumValues,
+	},
+	{
+		name:      "_cluster_data_size_bytes",
+		help:      "Size of every database summed over the replica sets and shards the exporter collects from",
+		source:    "_database_size_bytes",
+		valueType: prometheus.GaugeValue,
+		// Members of a replica set hold copies of the same data, so each database counts once per replica set
+		group:       []string{"replica_set", "shard", "database"},
+		groupReduce: maxValue,
+		reduce:      sumValues,
+	},
+	{
+		name:      "_cluster_max_replication_lag_seconds",
+		help:      "Largest replication lag of any secondary the exporter collects from",
+		source:    "_replset_member_replication_lag_seconds",
+		valueType: prometheus.GaugeValue,
+		reduce:    maxValue,
+	},
+	{
+		name:        "_cluster_unhealthy_members",
+		help:        "Number of replica set members any observer reports as unhealthy",
+		source:      "_replset_member_health",
+		valueType:   prometheus.GaugeValue,
+		group:       []string{"replica_set", "name"},
+		groupReduce: minValue,
+		reduce:      countZero,
+	},
+}
+
+// lastGather records the families of the last completed gather, so
+// /metrics/cluster can aggregate them without a collection run of its own
+type lastGather struct {
+	gatherer prometheus.Gatherer
+
+	mu       sync.Mutex
+	families []*dto.MetricFamily
+}
+
+func (g *lastGather) Gather() ([]*dto.MetricFamily, error) {
+	families, err := g.gatherer.Gather()
+
+	g.mu.Lock()
+	g.families = families
+	g.mu.Unlock()
+	return families, err
+}
+
+// last serves the recorded families, none until the first gather completes
+func (g *lastGather) last() prometheus.Gatherer {
+	return prometheus.GathererFunc(func() ([]*dto.MetricFamily, error) {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		return g.families, nil
+	})
+}
+
+// clusterCollector serves clusterAggregations computed from a gather of the
+// exporter's regular metrics
+type clusterCollector struct {
+	gatherer  prometheus.Gatherer
+	namespace string
+	logger    *zap.Logger
+}
+
+func newClusterCollector(gatherer prometheus.Gatherer, namespace string, logger *zap.Logger) *clusterCollector {
+	if namespace == "" {
+		namespace = "mongodb"
+	}
+	return &clusterCollector{gatherer: gatherer, namespace: namespace, logger: logger}
+}
+
+// Describe sends nothing: the aggregated families depend on what was gathered
+func (c *clusterCollector) Describe(ch chan<- *prometheus.Desc) {}
+
+func (c *clusterCollector) Collect(ch chan<- prometheus.Metric) {
+	families, err := c.gatherer.Gather()
+	if err != nil {
+		// Gather returns what it could collect alongside the error
+		c.logger.Warn("Partial metrics for cluster aggregation", zap.Error(err))
+	}
+
+	byName := make(map[string]*dto.MetricFamily, len(families))
+	for _, family := range families {
+		byName[family.GetName()] = family
+	}
+
+	for _, aggregation := range clusterAggregations {
+		family, ok := byName[c.namespace+aggregation.source]
+		if !ok {
+			continue
+		}
+		desc := prometheus.NewDesc(c.namespace+aggregation.name, aggregation.help, aggregation.by, nil)
+		for _, series := range aggregation.apply(family) {
+			ch <- prometheus.MustNewConstMetric(desc, aggregation.valueType, series.value, series.labels...)
+		}
+	}
+}
+
+type aggregatedSeries struct {
+	labels []string
+	value  float64
+}
+
+// apply reduces the samples of family to one series per distinct by labels
+func (a clusterAggregation) apply(family *dto.MetricFamily) []aggregatedSeries {
+	samples := make([]aggregatedSeries, 0, len(family.GetMetric()))
+	for _, metric := range family.GetMetric() {
+		value, ok := emfValue(family.GetType(), metric)
+		if !ok {
+			continue
+		}
+		samples = append(samples, aggregatedSeries{labels: labelValues(metric, append(a.group, a.by...)), value: value})
+	}
+
+	if a.group != nil {
+		samples = reduceSeries(samples, a.groupReduce)
+		for i := range samples {
+			samples[i].labels = samples[i].labels[len(a.group):]
+		}
+	}
+	return reduceSeries(samples, a.reduce)
+}
+
+// reduceSeries combines the values of samples with identical labels
+func reduceSeries(samples []aggregatedSeries, reduce func([]float64) float64) []aggregatedSeries {
+	groups := make(map[string][]float64)
+	labels := make(map[string][]string)
+	for _, sample := range samples {
+		key := strings.Join(sample.labels, "\xff")
+		groups[key] = append(groups[key], sample.value)
+		labels[key] = sample.labels
+	}
+
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	reduced := make([]aggregatedSeries, 0, len(keys))
+	for _, key := range keys {
+		reduced = append(reduced, aggregatedSeries{labels: labels[key], value: reduce(groups[key])})
+	}
+	return reduced
+}
+
+func labelValues(metric *dto.Metric, names []string) []string {
+	values := make([]string, len(names))
+	for i, name := range names {
+		for _, pair := range metric.GetLabel() {
+			if pair.GetName() == name {
+				values[i] = pair.GetValue()
+				break
+			}
+		}
+	}
+	return values
+}
@@ -0,0 +1,100 @@
+package server
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.uber.org/zap"
+)
+
+func TestClusterCollector(t *testing.T) {
+	registry := prometheus.NewRegistry()
+
+	opcounters := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "mongodb_op_counters_total",
+		Help: "test",
+	}, []string{"instance", "shard", "type"})
+	opcounters.WithLabelValues("shard-a:27017", "a", "insert").Add(10)
+	opcounters.WithLabelValues("shard-b:27017", "b", "insert").Add(5)
+	opcounters.WithLabelValues("shard-b:27017", "b", "query").Add(7)
+	registry.MustRegister(opcounters)
+
+	health := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "mongodb_replset_member_health",
+		Help: "test",
+	}, []string{"instance", "replica_set", "name"})
+	// Both observers see db-2 down; only db-1 sees db-3 down
+	health.WithLabelValues("db-1:27017", "rs0", "db-2:27017").Set(0)
+	health.WithLabelValues("db-3:27017", "rs0", "db-2:27017").Set(0)
+	health.WithLabelValues("db-1:27017", "rs0", "db-3:27017").Set(0)
+	health.WithLabelValues("db-3:27017", "rs0", "db-3:27017").Set(1)
+	health.WithLabelValues("db-1:27017", "rs0", "db-1:27017").Set(1)
+	registry.MustRegister(health)
+
+	sizes := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "mongodb_database_size_bytes",
+		Help: "test",
+	}, []string{"instance", "replica_set", "shard", "database"})
+	// Members of a replica set report the same database once between them
+	sizes.WithLabelValues("shard-a-1:27017", "rs-a", "a", "orders").Set(100)
+	sizes.WithLabelValues("shard-a-2:27017", "rs-a", "a", "orders").Set(98)
+	sizes.WithLabelValues("shard-b-1:27017", "rs-b", "b", "orders").Set(50)
+	sizes.WithLabelValues("shard-b-1:27017", "rs-b", "b", "users").Set(20)
+	registry.MustRegister(sizes)
+
+	lag := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "mongodb_replset_member_replication_lag_seconds",
+		Help: "test",
+	}, []string{"instance", "replica_set", "name"})
+	lag.WithLabelValues("shard-a-1:27017", "rs-a", "shard-a-2:27017").Set(3)
+	lag.WithLabelValues("shard-b-1:27017", "rs-b", "shard-b-2:27017").Set(12)
+	registry.MustRegister(lag)
+
+	cluster := newClusterCollector(registry, "", zap.NewNop())
+	expected := `
+# HELP mongodb_cluster_data_size_bytes Size of every database summed over the replica sets and shards the exporter collects from
+# TYPE mongodb_cluster_data_size_bytes gauge
+mongodb_cluster_data_size_bytes 170
+# HELP mongodb_cluster_max_replication_lag_seconds Largest replication lag of any secondary the exporter collects from
+# TYPE mongodb_cluster_max_replication_lag_seconds gauge
+mongodb_cluster_max_replication_lag_seconds 12
+# HELP mongodb_cluster_op_counters_total Operations by type summed over every instance the exporter collects from
+# TYPE mongodb_cluster_op_counters_total counter
+mongodb_cluster_op_counters_total{type="insert"} 15
+mongodb_cluster_op_counters_total{type="query"} 7
+# HELP mongodb_cluster_unhealthy_members Number of replica set members any observer reports as unhealthy
+# TYPE mongodb_cluster_unhealthy_members gauge
+mongodb_cluster_unhealthy_members 2
+`
+	if err := testutil.CollectAndCompare(cluster, strings.NewReader(expected)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestClusterCollectorServesLastGather(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	gathers := 0
+	counted := prometheus.NewGaugeFunc(prometheus.GaugeOpts{Name: "mongodb_database_size_bytes", Help: "test"}, func() float64 {
+		gathers++
+		return 42
+	})
+	registry.MustRegister(counted)
+
+	scraped := &lastGather{gatherer: registry}
+	cluster := newClusterCollector(scraped.last(), "", zap.NewNop())
+	if got := testutil.CollectAndCount(cluster); got != 0 {
+		t.Errorf("Expected no aggregates before the first scrape, got %d", got)
+	}
+
+	if _, err := scraped.Gather(); err != nil {
+		t.Fatal(err)
+	}
+	if got := testutil.ToFloat64(cluster); got != 42 {
+		t.Errorf("Expected the aggregate of the last scrape, got %v", got)
+	}
+	if gathers != 1 {
+		t.Errorf("Cluster aggregation should not collect on its own, collected %d times", gathers)
+	}
+}
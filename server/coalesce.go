@@ -0,0 +1,57 @@
+package server
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// coalescingGatherer shares a single in-flight Gather between concurrent callers,
+// so simultaneous scrapes (e.g. an HA Prometheus pair) trigger one MongoDB collection run
+type coalescingGatherer struct {
+	gatherer  prometheus.Gatherer
+	coalesced prometheus.Counter
+
+	mu       sync.Mutex
+	inflight *gatherCall
+}
+
+type gatherCall struct {
+	done     chan struct{}
+	families []*dto.MetricFamily
+	err      error
+}
+
+func newCoalescingGatherer(gatherer prometheus.Gatherer) *coalescingGatherer {
+	return &coalescingGatherer{
+		gatherer: gatherer,
+		coalesced: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "mongodb_exporter_scrapes_coalesced_total",
+			Help: "Total number of scrapes served from a collection run started by a concurrent scrape",
+		}),
+	}
+}
+
+func (g *coalescingGatherer) Gather() ([]*dto.MetricFamily, error) {
+	g.mu.Lock()
+	if call := g.inflight; call != nil {
+		g.mu.Unlock()
+		g.coalesced.Inc()
+		<-call.done
+		return call.families, call.err
+	}
+
+	call := &gatherCall{done: make(chan struct{})}
+	g.inflight = call
+	g.mu.Unlock()
+
+	call.families, call.err = g.gatherer.Gather()
+
+	g.mu.Lock()
+	g.inflight = nil
+	g.mu.Unlock()
+	close(call.done)
+
+	return call.families, call.err
+}
@@ -0,0 +1,21 @@
+package server
+
+import (
+	"net/http"
+
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+// configHandler serves the effective configuration, secrets redacted, in the config file's YAML format
+func (s *Server) configHandler(w http.ResponseWriter, r *http.Request) {
+	data, err := yaml.Marshal(s.config.Redacted())
+	if err != nil {
+		s.logger.Error("Failed to encode configuration", zap.Error(err))
+		http.Error(w, "Failed to encode configuration", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/yaml")
+	w.Write(data)
+}
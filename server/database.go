@@ -0,0 +1,48 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/jimohabdol/mongodb-exporter/collector"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.uber.org/zap"
+)
+
+const databaseMetricsPath = "/metrics/db/"
+
+// databaseMetricsHandler serves GET /metrics/db/{database}: the per-namespace
+// collectors run on demand for that database only
+func (s *Server) databaseMetricsHandler(w http.ResponseWriter, r *http.Request) {
+	database := strings.TrimPrefix(r.URL.Path, databaseMetricsPath)
+	if database == "" || strings.ContainsAny(database, "/\\. \"$") {
+		http.NotFound(w, r)
+		return
+	}
+
+	multi, err := s.collectorManager.DatabaseCollector(r.Context(), database)
+	switch {
+	case errors.Is(err, collector.ErrUnknownDatabase):
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case err != nil:
+		s.logger.Error("Failed to prepare database metrics", zap.String("database", database), zap.Error(err))
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	registry := prometheus.NewRegistry()
+	registerer := prometheus.Registerer(registry)
+	if len(s.collectorLabels) > 0 {
+		registerer = prometheus.WrapRegistererWith(s.collectorLabels, registry)
+	}
+	if err := registerer.Register(multi); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	promhttp.HandlerFor(registry, promhttp.HandlerOpts{
+		EnableOpenMetrics: s.config.Metrics.OpenMetrics,
+	}).ServeHTTP(w, r)
+}
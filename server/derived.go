@@ -0,0 +1,305 @@
+package server
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// derivedSelectorLabels distinguish the inputs of a derived ratio within one
+// source family; every other label identifies the instance the ratio is for
+var derivedSelectorLabels = map[string]bool{"state": true, "type": true}
+
+// derivedInput is one source series a derived ratio reads, relative to the
+// metric namespace, with the value of its selector label if it has one
+type derivedInput struct {
+	source   string
+	selector string
+}
+
+// derivedRatio is a health ratio computed in the exporter from series the
+// collectors export, so operators need not write the division in PromQL
+type derivedRatio struct {
+	name string
+	help string
+	// variants are the values of the type label, one series each; nil exports one series without it
+	variants []string
+	compute  func(in derivedInputs, variant string) (float64, bool)
+}
+
+// derivedRatios are the gauges added to /metrics; names are relative to the metric namespace
+var derivedRatios = []derivedRatio{
+	{
+		name: "_wiredtiger_cache_fill_ratio",
+		help: "Bytes in the WiredTiger cache divided by its configured maximum",
+		compute: func(in derivedInputs, _ string) (float64, bool) {
+			return in.ratio(derivedInput{source: "_wiredtiger_cache_used_bytes"}, derivedInput{source: "_wiredtiger_cache_max_bytes"})
+		},
+	},
+	{
+		name: "_wiredtiger_cache_dirty_ratio",
+		help: "Dirty bytes in the WiredTiger cache divided by its configured maximum",
+		compute: func(in derivedInputs, _ string) (float64, bool) {
+			return in.ratio(derivedInput{source: "_wiredtiger_cache_dirty_bytes"}, derivedInput{source: "_wiredtiger_cache_max_bytes"})
+		},
+	},
+	{
+		name: "_wiredtiger_cache_hit_ratio",
+		help: "Share of WiredTiger cache page requests served without reading from disk, over the last interval with requests",
+		compute: func(in derivedInputs, _ string) (float64, bool) {
+			deltas, ok := in.deltas(
+				derivedInput{source: "_wiredtiger_cache_pages", selector: "read"},
+				derivedInput{source: "_wiredtiger_cache_pages", selector: "requested"},
+			)
+			if !ok || deltas[1] <= 0 || deltas[0] > deltas[1] {
+				return 0, false
+			}
+			return 1 - deltas[0]/deltas[1], true
+		},
+	},
+	{
+		name: "_connections_used_percent",
+		help: "Current incoming connections as a percentage of the connections the server accepts",
+		compute: func(in derivedInputs, _ string) (float64, bool) {
+			current, ok := in.value(derivedInput{source: "_connections", selector: "current"})
+			if !ok {
+				return 0, false
+			}
+			available, ok := in.value(derivedInput{source: "_connections", selector: "available"})
+			if !ok || current+available <= 0 {
+				return 0, false
+			}
+			return current / (current + available) * 100, true
+		},
+	},
+	{
+		name:     "_wiredtiger_ticket_utilization_ratio",
+		help:     "WiredTiger read or write tickets in use divided by all tickets of that type",
+		variants: []string{"read", "write"},
+		compute: func(in derivedInputs, variant string) (float64, bool) {
+			used, ok := in.value(derivedInput{source: "_wiredtiger_io_total", selector: variant + "_used"})
+			if !ok {
+				return 0, false
+			}
+			available, ok := in.value(derivedInput{source: "_wiredtiger_io_total", selector: variant + "_available"})
+			if !ok || used+available <= 0 {
+				return 0, false
+			}
+			return used / (used + available), true
+		},
+	},
+}
+
+// derivedSources are the source families any derived ratio reads
+var derivedSources = map[string]bool{
+	"_wiredtiger_cache_used_bytes":  true,
+	"_wiredtiger_cache_max_bytes":   true,
+	"_wiredtiger_cache_dirty_bytes": true,
+	"_wiredtiger_cache_pages":       true,
+	"_connections":                  true,
+	"_wiredtiger_io_total":          true,
+}
+
+// counterWindow holds the two latest distinct samples of cumulative source
+// series read together, so their changes cover the same interval
+type counterWindow struct {
+	previous []float64
+	current  []float64
+}
+
+// derivedGatherer adds derivedRatios to what the wrapped gatherer returns.
+// The ratios are computed from that same gather, so they cost no extra MongoDB commands
+type derivedGatherer struct {
+	gatherer  prometheus.Gatherer
+	namespace string
+
+	mu       sync.Mutex
+	counters map[string]*counterWindow
+}
+
+func newDerivedGatherer(gatherer prometheus.Gatherer, namespace string) *derivedGatherer {
+	if namespace == "" {
+		namespace = "mongodb"
+	}
+	return &derivedGatherer{gatherer: gatherer, namespace: namespace, counters: make(map[string]*counterWindow)}
+}
+
+func (g *derivedGatherer) Gather() ([]*dto.MetricFamily, error) {
+	families, err := g.gatherer.Gather()
+
+	registry := prometheus.NewRegistry()
+	if registerErr := registry.Register(&derivedCollector{gatherer: g, groups: g.groupInputs(families)}); registerErr != nil {
+		return families, err
+	}
+	derived, derivedErr := registry.Gather()
+	if derivedErr != nil || len(derived) == 0 {
+		return families, err
+	}
+
+	// The wrapped gatherer may share its result between concurrent scrapes, so it is not appended to
+	merged := make([]*dto.MetricFamily, 0, len(families)+len(derived))
+	merged = append(merged, families...)
+	merged = append(merged, derived...)
+	sort.Slice(merged, func(i, j int) bool { return merged[i].GetName() < merged[j].GetName() })
+	return merged, err
+}
+
+// derivedGroup is the source values of one instance
+type derivedGroup struct {
+	key    string
+	names  []string
+	values []string
+	inputs map[derivedInput]float64
+}
+
+// groupInputs collects the source series by the labels identifying their instance
+func (g *derivedGatherer) groupInputs(families []*dto.MetricFamily) []*derivedGroup {
+	groups := make(map[string]*derivedGroup)
+	for _, family := range families {
+		source := strings.TrimPrefix(family.GetName(), g.namespace)
+		if source == family.GetName() || !derivedSources[source] {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			value, ok := emfValue(family.GetType(), metric)
+			if !ok {
+				continue
+			}
+
+			input := derivedInput{source: source}
+			var names, values, pairs []string
+			for _, pair := range metric.GetLabel() {
+				if derivedSelectorLabels[pair.GetName()] {
+					input.selector = pair.GetValue()
+					continue
+				}
+				names = append(names, pair.GetName())
+				values = append(values, pair.GetValue())
+				pairs = append(pairs, pair.GetName()+"="+pair.GetValue())
+			}
+
+			key := strings.Join(pairs, "\xff")
+			group, ok := groups[key]
+			if !ok {
+				group = &derivedGroup{key: key, names: names, values: values, inputs: make(map[derivedInput]float64)}
+				groups[key] = group
+			}
+			group.inputs[input] = value
+		}
+	}
+
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	sorted := make([]*derivedGroup, 0, len(keys))
+	for _, key := range keys {
+		sorted = append(sorted, groups[key])
+	}
+	return sorted
+}
+
+// observeCounters records cumulative values and returns their changes over
+// the last interval in which any of them changed. Scrapes sharing one gather
+// see the same values, so they get the same changes rather than none
+func (g *derivedGatherer) observeCounters(key string, values []float64) ([]float64, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	window, ok := g.counters[key]
+	if !ok {
+		g.counters[key] = &counterWindow{current: values}
+		return nil, false
+	}
+	for i, value := range values {
+		if value != window.current[i] {
+			window.previous, window.current = window.current, values
+			break
+		}
+	}
+	if window.previous == nil {
+		return nil, false
+	}
+
+	deltas := make([]float64, len(values))
+	for i := range deltas {
+		deltas[i] = window.current[i] - window.previous[i]
+		if deltas[i] < 0 {
+			// The server restarted and its counters reset
+			return nil, false
+		}
+	}
+	return deltas, true
+}
+
+// derivedInputs gives a derived ratio the source values of one instance
+type derivedInputs struct {
+	gatherer *derivedGatherer
+	group    *derivedGroup
+}
+
+func (in derivedInputs) value(input derivedInput) (float64, bool) {
+	value, ok := in.group.inputs[input]
+	return value, ok
+}
+
+func (in derivedInputs) ratio(numerator, denominator derivedInput) (float64, bool) {
+	top, ok := in.value(numerator)
+	if !ok {
+		return 0, false
+	}
+	bottom, ok := in.value(denominator)
+	if !ok || bottom <= 0 {
+		return 0, false
+	}
+	return top / bottom, true
+}
+
+// deltas returns the changes of cumulative inputs over the same interval
+func (in derivedInputs) deltas(inputs ...derivedInput) ([]float64, bool) {
+	values := make([]float64, len(inputs))
+	key := in.group.key
+	for i, input := range inputs {
+		value, ok := in.value(input)
+		if !ok {
+			return nil, false
+		}
+		values[i] = value
+		key += "\xff" + input.source + "=" + input.selector
+	}
+	return in.gatherer.observeCounters(key, values)
+}
+
+// derivedCollector emits the derived ratios of one gather
+type derivedCollector struct {
+	gatherer *derivedGatherer
+	groups   []*derivedGroup
+}
+
+// Describe sends nothing: which instances have ratios depends on what was gathered
+func (c *derivedCollector) Describe(ch chan<- *prometheus.Desc) {}
+
+func (c *derivedCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, group := range c.groups {
+		in := derivedInputs{gatherer: c.gatherer, group: group}
+		for _, ratio := range derivedRatios {
+			if ratio.variants == nil {
+				if value, ok := ratio.compute(in, ""); ok {
+					desc := prometheus.NewDesc(c.gatherer.namespace+ratio.name, ratio.help, group.names, nil)
+					ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, value, group.values...)
+				}
+				continue
+			}
+			desc := prometheus.NewDesc(c.gatherer.namespace+ratio.name, ratio.help, append(group.names[:len(group.names):len(group.names)], "type"), nil)
+			for _, variant := range ratio.variants {
+				if value, ok := ratio.compute(in, variant); ok {
+					ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, value, append(group.values[:len(group.values):len(group.values)], variant)...)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func TestDerivedGatherer(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	labels := []string{"instance", "replica_set", "shard"}
+
+	cacheMax := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "mongodb_wiredtiger_cache_max_bytes", Help: "test"}, labels)
+	cacheUsed := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "mongodb_wiredtiger_cache_used_bytes", Help: "test"}, labels)
+	cacheDirty := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "mongodb_wiredtiger_cache_dirty_bytes", Help: "test"}, labels)
+	cachePages := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "mongodb_wiredtiger_cache_pages", Help: "test"}, append(labels, "type"))
+	connections := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "mongodb_connections", Help: "test"}, append(labels, "state"))
+	tickets := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "mongodb_wiredtiger_io_total", Help: "test"}, append(labels, "type"))
+	registry.MustRegister(cacheMax, cacheUsed, cacheDirty, cachePages, connections, tickets)
+
+	cacheMax.WithLabelValues("db-1:27017", "rs0", "unknown").Set(1000)
+	cacheUsed.WithLabelValues("db-1:27017", "rs0", "unknown").Set(800)
+	cacheDirty.WithLabelValues("db-1:27017", "rs0", "unknown").Set(50)
+	cachePages.WithLabelValues("db-1:27017", "rs0", "unknown", "read").Set(100)
+	cachePages.WithLabelValues("db-1:27017", "rs0", "unknown", "requested").Set(1000)
+	connections.WithLabelValues("db-1:27017", "rs0", "unknown", "current").Set(25)
+	connections.WithLabelValues("db-1:27017", "rs0", "unknown", "available").Set(75)
+	tickets.WithLabelValues("db-1:27017", "rs0", "unknown", "read_used").Set(32)
+	tickets.WithLabelValues("db-1:27017", "rs0", "unknown", "read_available").Set(96)
+	// No write tickets reported: that variant is left out
+
+	gatherer := newDerivedGatherer(registry, "")
+	values := derivedValues(t, gatherer)
+	expected := map[string]float64{
+		"mongodb_wiredtiger_cache_fill_ratio":                    0.8,
+		"mongodb_wiredtiger_cache_dirty_ratio":                   0.05,
+		"mongodb_connections_used_percent":                       25,
+		"mongodb_wiredtiger_ticket_utilization_ratio{type=read}": 0.25,
+	}
+	for name, value := range expected {
+		if got, ok := values[name]; !ok || got != value {
+			t.Errorf("Expected %s = %v, got %v (present=%v)", name, value, got, ok)
+		}
+	}
+	if _, ok := values["mongodb_wiredtiger_cache_hit_ratio"]; ok {
+		t.Error("The cache hit ratio needs two gathers")
+	}
+	if len(values) != len(expected) {
+		t.Errorf("Expected %d derived series, got %v", len(expected), values)
+	}
+
+	cachePages.WithLabelValues("db-1:27017", "rs0", "unknown", "read").Set(150)
+	cachePages.WithLabelValues("db-1:27017", "rs0", "unknown", "requested").Set(1500)
+	for i := 0; i < 2; i++ {
+		// A repeated gather of unchanged counters keeps the last interval's ratio
+		if got := derivedValues(t, gatherer)["mongodb_wiredtiger_cache_hit_ratio"]; got != 0.9 {
+			t.Errorf("Gather %d: expected cache hit ratio 0.9, got %v", i, got)
+		}
+	}
+}
+
+func TestDerivedGathererNamespace(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	connections := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "acme_connections", Help: "test"}, []string{"instance", "state"})
+	connections.WithLabelValues("db-1:27017", "current").Set(1)
+	connections.WithLabelValues("db-1:27017", "available").Set(3)
+	registry.MustRegister(connections)
+
+	values := derivedValues(t, newDerivedGatherer(registry, "acme"))
+	if got := values["acme_connections_used_percent"]; got != 25 {
+		t.Errorf("Expected acme_connections_used_percent 25, got %v", got)
+	}
+}
+
+// derivedValues gathers and returns the derived gauges by name, with the type label if set
+func derivedValues(t *testing.T, gatherer prometheus.Gatherer) map[string]float64 {
+	t.Helper()
+	families, err := gatherer.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values := make(map[string]float64)
+	for _, family := range families {
+		if family.GetType() != dto.MetricType_GAUGE || !isDerived(family.GetName()) {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			name := family.GetName()
+			for _, pair := range metric.GetLabel() {
+				if pair.GetName() == "type" {
+					name += "{type=" + pair.GetValue() + "}"
+				}
+			}
+			values[name] = metric.GetGauge().GetValue()
+		}
+	}
+	return values
+}
+
+func isDerived(name string) bool {
+	for _, ratio := range derivedRatios {
+		if len(name) > len(ratio.name) && name[len(name)-len(ratio.name):] == ratio.name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,276 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"go.uber.org/zap"
+)
+
+// maxEMFDimensions is the CloudWatch limit on dimensions per metric
+const maxEMFDimensions = 30
+
+// maxEMFMetrics is the CloudWatch limit on metrics per directive
+const maxEMFMetrics = 100
+
+// EMFSink periodically gathers metrics from a registry and writes them
+// as CloudWatch Embedded Metric Format documents, one JSON line per label set
+type EMFSink struct {
+	gatherer  prometheus.Gatherer
+	writer    io.Writer
+	closer    io.Closer
+	namespace string
+	interval  time.Duration
+	families  map[string]bool
+	// dimensions are the labels used as dimensions (nil = every label)
+	dimensions []string
+	logger     *zap.Logger
+}
+
+func NewEMFSink(gatherer prometheus.Gatherer, namespace string, interval time.Duration, outputPath string, families, dimensions []string, logger *zap.Logger) (*EMFSink, error) {
+	sink := &EMFSink{
+		gatherer:  gatherer,
+		namespace: namespace,
+		interval:  interval,
+		families:  make(map[string]bool),
+		logger:    logger,
+	}
+
+	for _, family := range families {
+		if family = strings.TrimSpace(family); family != "" {
+			sink.families[family] = true
+		}
+	}
+	for _, dimension := range dimensions {
+		if dimension = strings.TrimSpace(dimension); dimension != "" {
+			sink.dimensions = append(sink.dimensions, dimension)
+		}
+	}
+
+	switch outputPath {
+	case "", "stdout":
+		sink.writer = os.Stdout
+	case "stderr":
+		sink.writer = os.Stderr
+	default:
+		file, err := os.OpenFile(outputPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open EMF output file: %w", err)
+		}
+		sink.writer = file
+		sink.closer = file
+	}
+
+	return sink, nil
+}
+
+// Run writes EMF documents every interval until the context is cancelled
+func (s *EMFSink) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	if s.closer != nil {
+		defer s.closer.Close()
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Flush(time.Now()); err != nil {
+				s.logger.Error("Failed to write EMF metrics", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Flush gathers the selected metric families and writes them to the output
+func (s *EMFSink) Flush(timestamp time.Time) error {
+	families, err := s.gatherer.Gather()
+	if err != nil && len(families) == 0 {
+		return fmt.Errorf("failed to gather metrics: %w", err)
+	}
+
+	documents := s.buildDocuments(families, timestamp)
+
+	encoder := json.NewEncoder(s.writer)
+	for _, document := range documents {
+		if err := encoder.Encode(document); err != nil {
+			return fmt.Errorf("failed to encode EMF document: %w", err)
+		}
+	}
+
+	return nil
+}
+
+type emfMetric struct {
+	Name string `json:"Name"`
+	Unit string `json:"Unit,omitempty"`
+}
+
+type emfDirective struct {
+	Namespace  string      `json:"Namespace"`
+	Dimensions [][]string  `json:"Dimensions"`
+	Metrics    []emfMetric `json:"Metrics"`
+}
+
+type emfMetadata struct {
+	Timestamp         int64          `json:"Timestamp"`
+	CloudWatchMetrics []emfDirective `json:"CloudWatchMetrics"`
+}
+
+// buildDocuments groups samples sharing the same label set into a single EMF document
+func (s *EMFSink) buildDocuments(families []*dto.MetricFamily, timestamp time.Time) []map[string]interface{} {
+	type group struct {
+		labels     []*dto.LabelPair
+		values     map[string]float64
+		metricKeys []string
+	}
+
+	groups := make(map[string]*group)
+	var order []string
+
+	for _, family := range families {
+		name := family.GetName()
+		if len(s.families) > 0 && !s.families[name] {
+			continue
+		}
+
+		for _, metric := range family.GetMetric() {
+			value, ok := emfValue(family.GetType(), metric)
+			if !ok {
+				continue
+			}
+
+			labels := metric.GetLabel()
+			key := emfGroupKey(labels)
+
+			g, exists := groups[key]
+			if !exists {
+				g = &group{labels: labels, values: make(map[string]float64)}
+				groups[key] = g
+				order = append(order, key)
+			}
+
+			if _, seen := g.values[name]; !seen {
+				g.metricKeys = append(g.metricKeys, name)
+			}
+			g.values[name] = value
+		}
+	}
+
+	documents := make([]map[string]interface{}, 0, len(order))
+	for _, key := range order {
+		g := groups[key]
+
+		document := make(map[string]interface{})
+		for _, label := range g.labels {
+			document[label.GetName()] = label.GetValue()
+		}
+		dimensions := s.dimensionSet(g.labels)
+
+		metrics := make([]emfMetric, 0, len(g.metricKeys))
+		for _, name := range g.metricKeys {
+			document[name] = g.values[name]
+			metrics = append(metrics, emfMetric{Name: name, Unit: emfUnit(name)})
+		}
+
+		// A directive lists at most maxEMFMetrics; the rest go in further
+		// directives of the same document
+		var directives []emfDirective
+		for start := 0; start < len(metrics); start += maxEMFMetrics {
+			end := start + maxEMFMetrics
+			if end > len(metrics) {
+				end = len(metrics)
+			}
+			directives = append(directives, emfDirective{
+				Namespace:  s.namespace,
+				Dimensions: [][]string{dimensions},
+				Metrics:    metrics[start:end],
+			})
+		}
+
+		document["_aws"] = emfMetadata{
+			Timestamp:         timestamp.UnixMilli(),
+			CloudWatchMetrics: directives,
+		}
+
+		documents = append(documents, document)
+	}
+
+	return documents
+}
+
+// dimensionSet returns the configured dimensions the series has, or up to
+// maxEMFDimensions of its labels when none are configured. Labels left out
+// are still written to the document, where CloudWatch Logs can query them
+func (s *EMFSink) dimensionSet(labels []*dto.LabelPair) []string {
+	dimensions := make([]string, 0, len(labels))
+	if len(s.dimensions) == 0 {
+		for _, label := range labels {
+			if len(dimensions) < maxEMFDimensions {
+				dimensions = append(dimensions, label.GetName())
+			}
+		}
+		return dimensions
+	}
+
+	for _, dimension := range s.dimensions {
+		for _, label := range labels {
+			if label.GetName() == dimension {
+				dimensions = append(dimensions, dimension)
+				break
+			}
+		}
+	}
+	return dimensions
+}
+
+func emfValue(metricType dto.MetricType, metric *dto.Metric) (float64, bool) {
+	switch metricType {
+	case dto.MetricType_GAUGE:
+		return metric.GetGauge().GetValue(), true
+	case dto.MetricType_COUNTER:
+		return metric.GetCounter().GetValue(), true
+	case dto.MetricType_UNTYPED:
+		return metric.GetUntyped().GetValue(), true
+	default:
+		// Histograms and summaries have no single-value EMF representation
+		return 0, false
+	}
+}
+
+func emfGroupKey(labels []*dto.LabelPair) string {
+	pairs := make([]string, 0, len(labels))
+	for _, label := range labels {
+		pairs = append(pairs, label.GetName()+"="+label.GetValue())
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func emfUnit(name string) string {
+	switch {
+	case strings.HasSuffix(name, "_bytes") || strings.HasSuffix(name, "_bytes_total"):
+		return "Bytes"
+	case strings.HasSuffix(name, "_seconds") || strings.HasSuffix(name, "_seconds_total"):
+		return "Seconds"
+	case strings.HasSuffix(name, "_microseconds") || strings.HasSuffix(name, "_microseconds_total"):
+		return "Microseconds"
+	case strings.HasSuffix(name, "_milliseconds"):
+		return "Milliseconds"
+	case strings.HasSuffix(name, "_total"):
+		return "Count"
+	default:
+		return "None"
+	}
+}
@@ -0,0 +1,115 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+func TestEMFSinkFlush(t *testing.T) {
+	registry := prometheus.NewRegistry()
+
+	connections := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "mongodb_connections",
+		Help: "test",
+	}, []string{"instance", "state"})
+	connections.WithLabelValues("db-01:27017", "current").Set(42)
+	registry.MustRegister(connections)
+
+	ignored := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "mongodb_ignored",
+		Help: "test",
+	})
+	registry.MustRegister(ignored)
+
+	var buf bytes.Buffer
+	sink := &EMFSink{
+		gatherer:  registry,
+		writer:    &buf,
+		namespace: "MongoDB",
+		interval:  time.Minute,
+		families:  map[string]bool{"mongodb_connections": true},
+		logger:    zap.NewNop(),
+	}
+
+	if err := sink.Flush(time.Unix(1700000000, 0)); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("Expected 1 EMF document, got %d", len(lines))
+	}
+
+	var document map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &document); err != nil {
+		t.Fatalf("EMF document should be valid JSON: %v", err)
+	}
+
+	if document["mongodb_connections"] != 42.0 {
+		t.Errorf("Expected metric value 42, got %v", document["mongodb_connections"])
+	}
+
+	if document["instance"] != "db-01:27017" {
+		t.Errorf("Expected instance dimension, got %v", document["instance"])
+	}
+
+	if _, ok := document["mongodb_ignored"]; ok {
+		t.Error("Unselected metric families should not be written")
+	}
+
+	aws, ok := document["_aws"].(map[string]interface{})
+	if !ok {
+		t.Fatal("EMF document should contain _aws metadata")
+	}
+
+	if aws["Timestamp"] != 1700000000000.0 {
+		t.Errorf("Expected timestamp in milliseconds, got %v", aws["Timestamp"])
+	}
+}
+
+func TestEMFDirectiveLimits(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	for i := 0; i < 150; i++ {
+		gauge := prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        fmt.Sprintf("mongodb_metric_%03d", i),
+			Help:        "test",
+			ConstLabels: prometheus.Labels{"instance": "db-01:27017", "cluster": "prod"},
+		})
+		registry.MustRegister(gauge)
+	}
+
+	sink := &EMFSink{
+		gatherer:   registry,
+		namespace:  "MongoDB",
+		dimensions: []string{"cluster", "replica_set"},
+		logger:     zap.NewNop(),
+	}
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	documents := sink.buildDocuments(families, time.Unix(1700000000, 0))
+	if len(documents) != 1 {
+		t.Fatalf("Expected 1 EMF document, got %d", len(documents))
+	}
+
+	directives := documents[0]["_aws"].(emfMetadata).CloudWatchMetrics
+	if len(directives) != 2 || len(directives[0].Metrics) != 100 || len(directives[1].Metrics) != 50 {
+		t.Fatalf("Expected the 150 metrics split into directives of 100 and 50, got %d directives", len(directives))
+	}
+	for _, directive := range directives {
+		if len(directive.Dimensions) != 1 || strings.Join(directive.Dimensions[0], ",") != "cluster" {
+			t.Errorf("Expected only the configured dimensions the series has, got %v", directive.Dimensions)
+		}
+	}
+	if documents[0]["instance"] != "db-01:27017" {
+		t.Errorf("Labels that are not dimensions should still be written, got %v", documents[0]["instance"])
+	}
+}
@@ -0,0 +1,98 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/jimohabdol/mongodb-exporter/collector"
+	"github.com/jimohabdol/mongodb-exporter/database"
+	"go.uber.org/zap"
+)
+
+// healthReport is the JSON body served by /health
+type healthReport struct {
+	Status     string                      `json:"status"`
+	Connection connectionHealth            `json:"connection"`
+	Server     *database.ServerInfo        `json:"server,omitempty"`
+	Collectors []collector.CollectorStatus `json:"collectors"`
+	LastError  *healthError                `json:"last_error,omitempty"`
+}
+
+type connectionHealth struct {
+	State string `json:"state"`
+	Error string `json:"error,omitempty"`
+}
+
+type healthError struct {
+	Source  string    `json:"source"`
+	Message string    `json:"message"`
+	Time    time.Time `json:"time"`
+}
+
+// healthHandler reports connection state, deployment details and per-collector
+// scrape outcomes in one document for use during incidents
+func (s *Server) healthHandler(w http.ResponseWriter, r *http.Request) {
+	report := healthReport{
+		Status:     "healthy",
+		Connection: connectionHealth{State: "connected"},
+		Collectors: make([]collector.CollectorStatus, 0),
+	}
+	statusCode := http.StatusOK
+
+	if err := s.connectionManager.HealthCheck(r.Context()); err != nil {
+		s.logger.Error("Health check failed", zap.Error(err))
+		report.Status = "unhealthy"
+		report.Connection = connectionHealth{State: "disconnected", Error: err.Error()}
+		report.LastError = &healthError{Source: "connection", Message: err.Error(), Time: time.Now()}
+		statusCode = http.StatusServiceUnavailable
+	} else if info, err := s.connectionManager.ServerInfo(r.Context()); err != nil {
+		s.logger.Warn("Failed to read server info for health report", zap.Error(err))
+	} else {
+		report.Server = info
+	}
+
+	for _, status := range s.collectorManager.Collectors() {
+		if !status.Configured {
+			continue
+		}
+		report.Collectors = append(report.Collectors, status)
+
+		if report.LastError != nil && report.LastError.Source == "connection" {
+			continue
+		}
+		if status.LastErrorAt != nil && (report.LastError == nil || status.LastErrorAt.After(report.LastError.Time)) {
+			report.LastError = &healthError{Source: status.Name, Message: status.LastError, Time: *status.LastErrorAt}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(report)
+}
+
+// livenessHandler only reports that the process is serving HTTP; MongoDB
+// outages must not get the exporter restarted
+func (s *Server) livenessHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"status":"healthy"}`))
+}
+
+// readinessHandler reports whether MongoDB is reachable and collectors are initialized
+func (s *Server) readinessHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.collectorManager.Initialized() {
+		http.Error(w, "Collectors not initialized", http.StatusServiceUnavailable)
+		return
+	}
+
+	if err := s.connectionManager.HealthCheck(r.Context()); err != nil {
+		s.logger.Warn("Readiness check failed", zap.Error(err))
+		http.Error(w, "MongoDB unreachable", http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"status":"ready"}`))
+}
@@ -0,0 +1,50 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/jimohabdol/mongodb-exporter/collector"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// constLabels returns the labels attached to every MongoDB series the exporter collects
+func (s *Server) constLabels(ctx context.Context) prometheus.Labels {
+	labels := prometheus.Labels{}
+
+	staticLabels := map[string]string{
+		"cluster_name": s.config.Metrics.ClusterName,
+		"environment":  s.config.Metrics.Environment,
+	}
+	for name, value := range staticLabels {
+		if value == "" {
+			continue
+		}
+
+		resolved, err := resolveLabelValue(ctx, value)
+		if err != nil {
+			s.logger.Warn("Failed to resolve label value",
+				zap.String("label", name),
+				zap.String("value", value),
+				zap.Error(err))
+		}
+		if resolved != "" {
+			labels[name] = resolved
+		}
+	}
+
+	if s.config.Metrics.ClusterRoleLabel {
+		detectCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+
+		role, err := collector.DetectClusterRole(detectCtx, s.connectionManager.GetClient())
+		if err != nil {
+			s.logger.Warn("Failed to detect cluster role", zap.Error(err))
+		}
+		s.logger.Info("Detected cluster role", zap.String("cluster_role", role))
+		labels["cluster_role"] = role
+	}
+
+	return labels
+}
@@ -0,0 +1,87 @@
+package server
+
+import (
+	"html/template"
+	"net/http"
+
+	"github.com/jimohabdol/mongodb-exporter/config"
+	"go.uber.org/zap"
+)
+
+var landingPageTemplate = template.Must(template.New("landing").Parse(`<!DOCTYPE html>
+<html>
+<head>
+    <title>{{.Title}}</title>
+    <style>
+        body { font-family: Arial, sans-serif; margin: 40px; }
+        .container { max-width: 800px; margin: 0 auto; }
+        h1 { color: #333; }
+        .endpoint { background: #f5f5f5; padding: 10px; margin: 10px 0; border-radius: 5px; }
+        .endpoint h3 { margin: 0 0 10px 0; color: #666; }
+        .endpoint p { margin: 5px 0; }
+        a { color: #007cba; text-decoration: none; }
+        a:hover { text-decoration: underline; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <h1>{{.Title}}</h1>
+        <p>A Prometheus exporter for MongoDB metrics.</p>
+        
+        <div class="endpoint">
+            <h3>Available Endpoints:</h3>
+            <p><strong>Metrics:</strong> <a href="/metrics">/metrics</a> - Prometheus metrics</p>
+            <p><strong>Cluster metrics:</strong> <a href="/metrics/cluster">/metrics/cluster</a> - Cluster-level aggregates</p>
+            <p><strong>Database metrics:</strong> /metrics/db/{database} - Per-namespace metrics of one database</p>
+            <p><strong>Health:</strong> <a href="/health">/health</a> - Health check endpoint</p>
+            <p><strong>Liveness:</strong> <a href="/-/healthy">/-/healthy</a> - Process is alive</p>
+            <p><strong>Readiness:</strong> <a href="/-/ready">/-/ready</a> - MongoDB reachable and collectors initialized</p>
+            <p><strong>Version:</strong> <a href="/version">/version</a> - Exporter build information</p>
+        </div>
+        {{if .Links}}
+        <div class="endpoint">
+            <h3>Links:</h3>
+            {{range .Links}}<p><a href="{{.URL}}">{{.Name}}</a>{{if .Description}} - {{.Description}}{{end}}</p>
+            {{end}}
+        </div>
+        {{end}}
+        <div class="endpoint">
+            <h3>Usage:</h3>
+            <p>Add this exporter to your Prometheus configuration:</p>
+            <pre>scrape_configs:
+  - job_name: 'mongodb'
+    static_configs:
+      - targets: ['localhost:{{.Port}}']</pre>
+        </div>
+    </div>
+</body>
+</html>`))
+
+type landingPageData struct {
+	Title string
+	Port  string
+	Links []config.LandingPageLink
+}
+
+// rootHandler serves the informational landing page; it is not registered when server.landing_page is false
+func (s *Server) rootHandler(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+
+	data := landingPageData{
+		Title: s.config.Server.LandingPageTitle,
+		Port:  s.config.Server.Port,
+		Links: s.config.Server.LandingPageLinks,
+	}
+	if data.Title == "" {
+		data.Title = "MongoDB Exporter"
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	w.WriteHeader(http.StatusOK)
+	if err := landingPageTemplate.Execute(w, data); err != nil {
+		s.logger.Error("Failed to render landing page", zap.Error(err))
+	}
+}
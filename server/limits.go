@@ -0,0 +1,182 @@
+package server
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// scrapeLimiter bounds the number of scrape requests collecting at the same time.
+// Excess requests wait up to queueTimeout for a slot, or are rejected immediately
+// when queueTimeout is zero.
+type scrapeLimiter struct {
+	slots        chan struct{}
+	queueTimeout time.Duration
+}
+
+func newScrapeLimiter(maxConcurrent int, queueTimeout time.Duration) *scrapeLimiter {
+	return &scrapeLimiter{
+		slots:        make(chan struct{}, maxConcurrent),
+		queueTimeout: queueTimeout,
+	}
+}
+
+func (l *scrapeLimiter) acquire(r *http.Request) bool {
+	select {
+	case l.slots <- struct{}{}:
+		return true
+	default:
+	}
+
+	if l.queueTimeout <= 0 {
+		return false
+	}
+
+	timer := time.NewTimer(l.queueTimeout)
+	defer timer.Stop()
+
+	select {
+	case l.slots <- struct{}{}:
+		return true
+	case <-timer.C:
+		return false
+	case <-r.Context().Done():
+		return false
+	}
+}
+
+func (l *scrapeLimiter) release() {
+	<-l.slots
+}
+
+// tokenBucket is a minimal token bucket refilled continuously at rate tokens per second
+type tokenBucket struct {
+	tokens   float64
+	lastSeen time.Time
+}
+
+// ipRateLimiter applies an independent token bucket to each client IP
+type ipRateLimiter struct {
+	mu      sync.Mutex
+	rate    float64
+	burst   float64
+	buckets map[string]*tokenBucket
+	lastGC  time.Time
+}
+
+func newIPRateLimiter(rate float64, burst int) *ipRateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &ipRateLimiter{
+		rate:    rate,
+		burst:   float64(burst),
+		buckets: make(map[string]*tokenBucket),
+		lastGC:  time.Now(),
+	}
+}
+
+func (l *ipRateLimiter) allow(ip string, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.evictIdle(now)
+
+	bucket, ok := l.buckets[ip]
+	if !ok {
+		bucket = &tokenBucket{tokens: l.burst, lastSeen: now}
+		l.buckets[ip] = bucket
+	}
+
+	bucket.tokens += now.Sub(bucket.lastSeen).Seconds() * l.rate
+	if bucket.tokens > l.burst {
+		bucket.tokens = l.burst
+	}
+	bucket.lastSeen = now
+
+	if bucket.tokens < 1 {
+		return false
+	}
+
+	bucket.tokens--
+	return true
+}
+
+// evictIdle drops buckets that have refilled completely so the map cannot grow unbounded
+func (l *ipRateLimiter) evictIdle(now time.Time) {
+	if now.Sub(l.lastGC) < time.Minute {
+		return
+	}
+	l.lastGC = now
+
+	refill := time.Duration(l.burst / l.rate * float64(time.Second))
+	for ip, bucket := range l.buckets {
+		if now.Sub(bucket.lastSeen) > refill {
+			delete(l.buckets, ip)
+		}
+	}
+}
+
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func newRejectedRequestsCounter() *prometheus.CounterVec {
+	return prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "mongodb_exporter_requests_rejected_total",
+		Help: "Total number of HTTP requests rejected by the exporter's concurrency or rate limits",
+	}, []string{"reason"})
+}
+
+// limitConcurrentScrapes makes handler take a slot of the server's scrape
+// limiter, which every scraping endpoint shares
+func (s *Server) limitConcurrentScrapes(handler http.Handler) http.Handler {
+	limiter := s.scrapeLimiter
+	if limiter == nil {
+		return handler
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !limiter.acquire(r) {
+			s.rejectedRequests.WithLabelValues("max_concurrent_scrapes").Inc()
+			s.logger.Warn("Rejected scrape: too many concurrent scrapes",
+				zap.String("remote_addr", r.RemoteAddr),
+				zap.Int("max_concurrent_scrapes", s.config.Server.MaxConcurrentScrapes))
+			http.Error(w, "Too many concurrent scrapes", http.StatusServiceUnavailable)
+			return
+		}
+		defer limiter.release()
+
+		handler.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) rateLimit(handler http.Handler) http.Handler {
+	if s.config.Server.RateLimit <= 0 {
+		return handler
+	}
+
+	limiter := newIPRateLimiter(s.config.Server.RateLimit, s.config.Server.RateLimitBurst)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !limiter.allow(clientIP(r), time.Now()) {
+			s.rejectedRequests.WithLabelValues("rate_limit").Inc()
+			s.logger.Warn("Rejected request: rate limit exceeded",
+				zap.String("remote_addr", r.RemoteAddr),
+				zap.String("path", r.URL.Path))
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, "Rate limit exceeded", http.StatusTooManyRequests)
+			return
+		}
+
+		handler.ServeHTTP(w, r)
+	})
+}
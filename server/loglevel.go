@@ -0,0 +1,58 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type logLevelPayload struct {
+	Level string `json:"level"`
+}
+
+// SetLogLevel lets /admin/loglevel change the level of the running logger
+func (s *Server) SetLogLevel(level zap.AtomicLevel) {
+	s.logLevel = &level
+}
+
+// logLevelHandler serves GET and PUT /admin/loglevel, e.g. to enable debug
+// logging during an incident without a restart
+func (s *Server) logLevelHandler(w http.ResponseWriter, r *http.Request) {
+	if s.logLevel == nil {
+		http.Error(w, "Runtime log level changes are not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		var payload logLevelPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			http.Error(w, "Invalid request body, expected {\"level\": \"debug\"}", http.StatusBadRequest)
+			return
+		}
+
+		var level zapcore.Level
+		if err := level.UnmarshalText([]byte(payload.Level)); err != nil {
+			http.Error(w, "Invalid log level: "+payload.Level, http.StatusBadRequest)
+			return
+		}
+
+		previous := s.logLevel.Level()
+		s.logLevel.SetLevel(level)
+		// Logged at warn so the change is visible whatever the new level is
+		s.logger.Warn("Log level changed via admin API",
+			zap.Stringer("from", previous),
+			zap.Stringer("to", level),
+			zap.String("remote_addr", r.RemoteAddr))
+	default:
+		w.Header().Set("Allow", http.MethodGet+", "+http.MethodPut)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(logLevelPayload{Level: s.logLevel.Level().String()})
+}
@@ -0,0 +1,97 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var labelTemplatePattern = regexp.MustCompile(`\{([a-z0-9_]+)(?::([^}]+))?\}`)
+
+// ec2MetadataURL is the instance metadata service endpoint; a variable so tests can point it elsewhere
+var ec2MetadataURL = "http://169.254.169.254"
+
+// resolveLabelValue expands {hostname} and {ec2_tag:Key} placeholders in a label value
+func resolveLabelValue(ctx context.Context, value string) (string, error) {
+	var resolveErr error
+
+	resolved := labelTemplatePattern.ReplaceAllStringFunc(value, func(placeholder string) string {
+		match := labelTemplatePattern.FindStringSubmatch(placeholder)
+
+		var (
+			result string
+			err    error
+		)
+		switch match[1] {
+		case "hostname":
+			result, err = os.Hostname()
+		case "ec2_tag":
+			result, err = ec2InstanceTag(ctx, match[2])
+		default:
+			err = fmt.Errorf("unknown placeholder %s", placeholder)
+		}
+
+		if err != nil && resolveErr == nil {
+			resolveErr = fmt.Errorf("failed to resolve %s: %w", placeholder, err)
+		}
+		return result
+	})
+
+	return resolved, resolveErr
+}
+
+// ec2InstanceTag reads an instance tag through IMDSv2. Tags must be exposed in
+// instance metadata (InstanceMetadataTags=enabled) on the EC2 instance.
+func ec2InstanceTag(ctx context.Context, key string) (string, error) {
+	if key == "" {
+		return "", fmt.Errorf("tag key is required")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	client := &http.Client{}
+
+	tokenReq, err := http.NewRequestWithContext(ctx, http.MethodPut, ec2MetadataURL+"/latest/api/token", nil)
+	if err != nil {
+		return "", err
+	}
+	tokenReq.Header.Set("X-aws-ec2-metadata-token-ttl-seconds", "60")
+
+	token, err := doMetadataRequest(client, tokenReq)
+	if err != nil {
+		return "", fmt.Errorf("failed to get metadata token: %w", err)
+	}
+
+	tagReq, err := http.NewRequestWithContext(ctx, http.MethodGet, ec2MetadataURL+"/latest/meta-data/tags/instance/"+key, nil)
+	if err != nil {
+		return "", err
+	}
+	tagReq.Header.Set("X-aws-ec2-metadata-token", token)
+
+	return doMetadataRequest(client, tagReq)
+}
+
+func doMetadataRequest(client *http.Client, req *http.Request) (string, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if err != nil {
+		return "", err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("metadata service returned %s", resp.Status)
+	}
+
+	return strings.TrimSpace(string(body)), nil
+}
@@ -0,0 +1,15 @@
+package server
+
+import (
+	"net/http"
+	"net/http/pprof"
+)
+
+// registerPprofHandlers exposes the runtime profiling endpoints behind admin auth
+func (s *Server) registerPprofHandlers(mux *http.ServeMux) {
+	mux.Handle("/debug/pprof/", s.requireAdminAuth(http.HandlerFunc(pprof.Index)))
+	mux.Handle("/debug/pprof/cmdline", s.requireAdminAuth(http.HandlerFunc(pprof.Cmdline)))
+	mux.Handle("/debug/pprof/profile", s.requireAdminAuth(http.HandlerFunc(pprof.Profile)))
+	mux.Handle("/debug/pprof/symbol", s.requireAdminAuth(http.HandlerFunc(pprof.Symbol)))
+	mux.Handle("/debug/pprof/trace", s.requireAdminAuth(http.HandlerFunc(pprof.Trace)))
+}
@@ -0,0 +1,31 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/prometheus/common/expfmt"
+)
+
+// WriteMetrics runs every enabled collector once and writes the result to w in
+// the Prometheus text exposition format, without starting the HTTP server
+func (s *Server) WriteMetrics(ctx context.Context, w io.Writer) error {
+	if err := s.registerCollectors(ctx); err != nil {
+		return err
+	}
+	defer s.collectorManager.Shutdown(ctx)
+
+	families, err := s.registry.Gather()
+	if err != nil {
+		return fmt.Errorf("failed to gather metrics: %w", err)
+	}
+
+	for _, family := range families {
+		if _, err := expfmt.MetricFamilyToText(w, family); err != nil {
+			return fmt.Errorf("failed to write metrics: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/jimohabdol/mongodb-exporter/collector"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.uber.org/zap"
+)
+
+// scrapeProfiles serves /metrics?profile=<name> with only the collectors of
+// that scrape profile, so jobs scraping at different intervals can share one
+// exporter. Requests without a profile go to next
+func (s *Server) scrapeProfiles(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := r.URL.Query().Get("profile")
+		if name == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		profile, err := s.collectorManager.ScrapeProfileCollector(name)
+		switch {
+		case errors.Is(err, collector.ErrUnknownScrapeProfile):
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		case err != nil:
+			s.logger.Error("Failed to prepare scrape profile", zap.String("profile", name), zap.Error(err))
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		registry := prometheus.NewRegistry()
+		registerer := prometheus.Registerer(registry)
+		if len(s.collectorLabels) > 0 {
+			registerer = prometheus.WrapRegistererWith(s.collectorLabels, registry)
+		}
+		if err := registerer.Register(profile); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		promhttp.HandlerFor(registry, promhttp.HandlerOpts{
+			EnableOpenMetrics: s.config.Metrics.OpenMetrics,
+		}).ServeHTTP(w, r)
+	})
+}
@@ -3,13 +3,16 @@ package server
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
+	"runtime"
 	"time"
 
 	"github.com/jimohabdol/mongodb-exporter/collector"
 	"github.com/jimohabdol/mongodb-exporter/config"
 	"github.com/jimohabdol/mongodb-exporter/database"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.uber.org/zap"
 )
@@ -21,6 +24,15 @@ type Server struct {
 	collectorManager  *collector.CollectorManager
 	server            *http.Server
 	registry          *prometheus.Registry
+	gatherer          prometheus.Gatherer
+	cancel            context.CancelFunc
+	rejectedRequests  *prometheus.CounterVec
+	buildInfo         BuildInfo
+	logLevel          *zap.AtomicLevel
+	// collectorLabels are the const labels applied to collector metrics
+	collectorLabels prometheus.Labels
+	// scrapeLimiter bounds concurrent scrapes across every scraping endpoint (nil = unlimited)
+	scrapeLimiter *scrapeLimiter
 }
 
 func NewServer(cfg *config.Config, logger *zap.Logger, connManager *database.ConnectionManager) *Server {
@@ -31,35 +43,212 @@ func NewServer(cfg *config.Config, logger *zap.Logger, connManager *database.Con
 		EnabledMetrics:  cfg.Metrics.EnabledMetrics,
 		DisabledMetrics: cfg.Metrics.DisabledMetrics,
 		Collectors:      make(map[string]interface{}),
+
+		MaxSeriesPerMetric: cfg.Metrics.MaxSeriesPerMetric,
+		SeriesLimits:       cfg.Metrics.SeriesLimits,
+		Intervals:          cfg.Collectors.Intervals(),
+		StaleAfterRuns:     cfg.Metrics.StaleAfterRuns,
+		NamespaceCacheTTL:  cfg.Metrics.NamespaceCacheTTL,
+		Parallelism:        cfg.Metrics.Parallelism,
+		Namespace:          cfg.Metrics.Namespace,
+		InstanceLabel:      cfg.Metrics.InstanceLabel,
+		StripInstancePort:  cfg.Metrics.StripInstancePort,
+		Watchdog:           cfg.Metrics.CollectorWatchdog,
+		Tracing:            cfg.Tracing.Enabled,
+		MemberClient:       connManager.MemberClient,
+		TargetFlavor:       cfg.MongoDB.TargetFlavor,
+		ScrapeProfiles:     cfg.Metrics.ScrapeProfiles,
+		ClusterRoleLabel:   cfg.Metrics.ClusterRoleLabel,
+	}
+
+	if cfg.Metrics.AdaptiveTimeouts {
+		collectorConfig.TimeoutMin = cfg.Metrics.TimeoutMin
+		collectorConfig.TimeoutMax = cfg.Metrics.TimeoutMax
 	}
 
 	// Add collector-specific configurations
-	if len(cfg.Collectors.CollStats.MonitoredCollections) > 0 {
-		collectorConfig.Collectors["collstats"] = map[string]interface{}{
-			"monitored_collections": cfg.Collectors.CollStats.MonitoredCollections,
-		}
+	collectorConfig.Collectors["collstats"] = map[string]interface{}{
+		"monitored_collections":   cfg.Collectors.CollStats.MonitoredCollections,
+		"top_n_by_size":           cfg.Collectors.CollStats.TopNBySize,
+		"top_n_by_activity":       cfg.Collectors.CollStats.TopNByActivity,
+		"wiredtiger_detail":       cfg.Collectors.CollStats.WiredTigerDetail,
+		"count_mode":              cfg.Collectors.CollStats.CountMode,
+		"exact_count_collections": cfg.Collectors.CollStats.ExactCountCollections,
+	}
+	collectorConfig.Collectors["sharding"] = map[string]interface{}{
+		"mongos_ping_freshness": cfg.Collectors.Sharding.MongosPingFreshness,
+	}
+	collectorConfig.Collectors["connection_pool"] = map[string]interface{}{
+		"session_states":    cfg.Collectors.ConnectionPool.SessionStates,
+		"client_metadata":   cfg.Collectors.ConnectionPool.ClientMetadata,
+		"max_client_groups": cfg.Collectors.ConnectionPool.MaxClientGroups,
+	}
+	collectorConfig.Collectors["index_stats"] = map[string]interface{}{
+		"unused_lookback": cfg.Collectors.IndexStats.UnusedLookback,
+	}
+	collectorConfig.Collectors["index_selectivity"] = map[string]interface{}{
+		"sample_size": cfg.Collectors.IndexSelectivity.SampleSize,
+		"collections": cfg.Collectors.IndexSelectivity.Collections,
+	}
+	collectorConfig.Collectors["shard_key_distribution"] = map[string]interface{}{
+		"collections": cfg.Collectors.ShardKeyDistribution.Collections,
+		"sample_size": cfg.Collectors.ShardKeyDistribution.SampleSize,
+	}
+	collectorConfig.Collectors["dbhash"] = map[string]interface{}{
+		"databases":     cfg.Collectors.DBHash.Databases,
+		"confirmations": cfg.Collectors.DBHash.Confirmations,
+	}
+	collectorConfig.Collectors["atlas"] = map[string]interface{}{
+		"public_key":           cfg.Collectors.Atlas.PublicKey,
+		"private_key":          cfg.Collectors.Atlas.PrivateKey,
+		"project_id":           cfg.Collectors.Atlas.ProjectID,
+		"cluster":              cfg.Collectors.Atlas.Cluster,
+		"base_url":             cfg.Collectors.Atlas.BaseURL,
+		"process_measurements": cfg.Collectors.Atlas.ProcessMeasurements,
+		"disk_measurements":    cfg.Collectors.Atlas.DiskMeasurements,
+	}
+	collectorConfig.Collectors["opsmanager"] = map[string]interface{}{
+		"base_url":    cfg.Collectors.OpsManager.BaseURL,
+		"public_key":  cfg.Collectors.OpsManager.PublicKey,
+		"private_key": cfg.Collectors.OpsManager.PrivateKey,
+		"project_id":  cfg.Collectors.OpsManager.ProjectID,
+		"clusters":    cfg.Collectors.OpsManager.Clusters,
+	}
+	pingOptions := map[string]interface{}{
+		"count": cfg.Collectors.Ping.Count,
+	}
+	if summaries := cfg.Metrics.LatencySummaries; summaries.Includes(config.LatencyPingRTT) {
+		pingOptions["rtt_objectives"] = summaries.QuantileObjectives()
+		pingOptions["rtt_summary_max_age"] = summaries.MaxAge
+		pingOptions["rtt_replace_histogram"] = summaries.ReplaceHistograms
+	}
+	collectorConfig.Collectors["ping"] = pingOptions
+	collectorConfig.Collectors["canary"] = map[string]interface{}{
+		"database":   cfg.Collectors.Canary.Database,
+		"collection": cfg.Collectors.Canary.Collection,
+	}
+	collectorConfig.Collectors["replica_set_status"] = map[string]interface{}{
+		"sample_oplog_timestamps": cfg.Collectors.ReplicaSet.SampleOplogTimestamps,
+	}
+	profileOptions := map[string]interface{}{
+		"max_tracked_operations": cfg.Collectors.Profile.MaxTrackedOperations,
+		"max_plan_summaries":     cfg.Collectors.Profile.MaxPlanSummaries,
+		"max_lock_types":         cfg.Collectors.Profile.MaxLockTypes,
+		"duration_histogram":     cfg.Collectors.Profile.DurationHistogram,
+		"member_scope":           cfg.Collectors.Profile.MemberScope,
+		"databases":              cfg.Collectors.Profile.Databases,
 	}
+	if summaries := cfg.Metrics.LatencySummaries; summaries.Includes(config.LatencyProfileDuration) {
+		profileOptions["duration_objectives"] = summaries.QuantileObjectives()
+		profileOptions["duration_summary_max_age"] = summaries.MaxAge
+	}
+	collectorConfig.Collectors["profile"] = profileOptions
 
 	collectorManager := collector.NewCollectorManager(connManager.GetClient(), logger, collectorConfig)
 
+	var limiter *scrapeLimiter
+	if cfg.Server.MaxConcurrentScrapes > 0 {
+		limiter = newScrapeLimiter(cfg.Server.MaxConcurrentScrapes, cfg.Server.ScrapeQueueTimeout)
+	}
+
 	return &Server{
 		config:            cfg,
 		logger:            logger,
 		connectionManager: connManager,
 		collectorManager:  collectorManager,
 		registry:          registry,
+		rejectedRequests:  newRejectedRequestsCounter(),
+		scrapeLimiter:     limiter,
+		buildInfo:         BuildInfo{Version: "unknown", GitCommit: "unknown", BuildTime: "unknown", GoVersion: runtime.Version()},
 	}
 }
 
-func (s *Server) Start(ctx context.Context) error {
+// registerCollectors initializes the MongoDB collectors and registers them with the const labels applied
+func (s *Server) registerCollectors(ctx context.Context) error {
 	if err := s.collectorManager.InitializeCollectors(); err != nil {
 		return fmt.Errorf("failed to initialize collectors: %w", err)
 	}
 
-	if err := s.registry.Register(s.collectorManager.GetCollector()); err != nil {
+	collectorRegisterer := prometheus.Registerer(s.registry)
+	if labels := s.constLabels(ctx); len(labels) > 0 {
+		s.collectorLabels = labels
+		collectorRegisterer = prometheus.WrapRegistererWith(labels, s.registry)
+	}
+
+	if err := collectorRegisterer.Register(s.collectorManager.GetCollector()); err != nil {
 		return fmt.Errorf("failed to register collector: %w", err)
 	}
 
+	return nil
+}
+
+func (s *Server) Start(ctx context.Context) error {
+	if err := s.registerCollectors(ctx); err != nil {
+		return err
+	}
+
+	if err := s.registry.Register(s.rejectedRequests); err != nil {
+		return fmt.Errorf("failed to register request limit metrics: %w", err)
+	}
+
+	s.gatherer = s.registry
+	if s.config.Server.CoalesceScrapes {
+		coalescing := newCoalescingGatherer(s.registry)
+		if err := s.registry.Register(coalescing.coalesced); err != nil {
+			return fmt.Errorf("failed to register scrape coalescing metrics: %w", err)
+		}
+		s.gatherer = coalescing
+	}
+	if s.config.Metrics.DerivedMetrics {
+		s.gatherer = newDerivedGatherer(s.gatherer, s.config.Metrics.Namespace)
+	}
+
+	if pool := s.connectionManager.PoolMetrics(); pool != nil {
+		if err := s.registry.Register(pool); err != nil {
+			return fmt.Errorf("failed to register driver pool metrics: %w", err)
+		}
+	}
+
+	if commands := s.connectionManager.CommandMetrics(); commands != nil {
+		if err := s.registry.Register(commands); err != nil {
+			return fmt.Errorf("failed to register command duration metrics: %w", err)
+		}
+	}
+
+	if audit := s.connectionManager.CommandAudit(); audit != nil {
+		if err := s.registry.Register(audit); err != nil {
+			return fmt.Errorf("failed to register command audit metrics: %w", err)
+		}
+	}
+
+	if s.config.Metrics.RuntimeMetrics {
+		if err := s.registry.Register(collectors.NewGoCollector()); err != nil {
+			return fmt.Errorf("failed to register Go runtime collector: %w", err)
+		}
+		if err := s.registry.Register(collectors.NewProcessCollector(collectors.ProcessCollectorOpts{})); err != nil {
+			return fmt.Errorf("failed to register process collector: %w", err)
+		}
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	s.cancel = cancel
+
+	if s.config.EMF.Enabled {
+		sink, err := NewEMFSink(s.gatherer, s.config.EMF.Namespace, s.config.EMF.Interval,
+			s.config.EMF.OutputPath, s.config.EMF.MetricFamilies, s.config.EMF.Dimensions, s.logger)
+		if err != nil {
+			cancel()
+			return fmt.Errorf("failed to create EMF sink: %w", err)
+		}
+
+		s.logger.Info("Starting CloudWatch EMF output",
+			zap.String("namespace", s.config.EMF.Namespace),
+			zap.Duration("interval", s.config.EMF.Interval),
+			zap.String("output_path", s.config.EMF.OutputPath))
+
+		go sink.Run(runCtx)
+	}
+
 	s.server = &http.Server{
 		Addr:         ":" + s.config.Server.Port,
 		ReadTimeout:  s.config.Server.ReadTimeout,
@@ -68,13 +257,33 @@ func (s *Server) Start(ctx context.Context) error {
 		Handler:      s.createHandler(),
 	}
 
-	s.logger.Info("Starting MongoDB exporter server",
-		zap.String("port", s.config.Server.Port),
-		zap.Duration("read_timeout", s.config.Server.ReadTimeout),
-		zap.Duration("write_timeout", s.config.Server.WriteTimeout))
+	listener, err := systemdListener()
+	if err != nil {
+		cancel()
+		return fmt.Errorf("failed to set up systemd socket activation: %w", err)
+	}
+
+	if listener != nil {
+		s.logger.Info("Starting MongoDB exporter server on systemd-activated socket",
+			zap.String("address", listener.Addr().String()),
+			zap.Duration("read_timeout", s.config.Server.ReadTimeout),
+			zap.Duration("write_timeout", s.config.Server.WriteTimeout))
+	} else {
+		// Binding before returning lets callers report readiness only once
+		// the port accepts connections, and surfaces a port already in use
+		listener, err = net.Listen("tcp", s.server.Addr)
+		if err != nil {
+			cancel()
+			return fmt.Errorf("failed to listen on port %s: %w", s.config.Server.Port, err)
+		}
+		s.logger.Info("Starting MongoDB exporter server",
+			zap.String("port", s.config.Server.Port),
+			zap.Duration("read_timeout", s.config.Server.ReadTimeout),
+			zap.Duration("write_timeout", s.config.Server.WriteTimeout))
+	}
 
 	go func() {
-		if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		if err := s.server.Serve(listener); err != nil && err != http.ErrServerClosed {
 			s.logger.Error("Server error", zap.Error(err))
 		}
 	}()
@@ -85,8 +294,16 @@ func (s *Server) Start(ctx context.Context) error {
 func (s *Server) Stop(ctx context.Context) error {
 	s.logger.Info("Stopping MongoDB exporter server")
 
-	// Shutdown collector manager first
-	s.collectorManager.Shutdown()
+	// Stop background outputs before the collectors they read from
+	if s.cancel != nil {
+		s.cancel()
+	}
+
+	// Cancel in-flight collector runs and wait for them, so the MongoDB client
+	// is not disconnected while they still use it
+	if err := s.collectorManager.Shutdown(ctx); err != nil {
+		s.logger.Warn("Collector runs did not finish before the shutdown deadline", zap.Error(err))
+	}
 
 	// Shutdown HTTP server
 	if s.server != nil {
@@ -108,70 +325,41 @@ func (s *Server) Stop(ctx context.Context) error {
 func (s *Server) createHandler() http.Handler {
 	mux := http.NewServeMux()
 
-	mux.Handle("/metrics", s.addMiddleware(promhttp.HandlerFor(s.registry, promhttp.HandlerOpts{})))
+	// /metrics/cluster aggregates what the last full /metrics scrape collected
+	scraped := &lastGather{gatherer: s.gatherer}
+	metricsHandler := promhttp.HandlerFor(scraped, promhttp.HandlerOpts{
+		// OpenMetrics is the only exposition format that carries exemplars
+		EnableOpenMetrics: s.config.Metrics.OpenMetrics,
+	})
+	mux.Handle("/metrics", s.addMiddleware(s.limitConcurrentScrapes(s.scrapeProfiles(metricsHandler))))
+
+	clusterRegistry := prometheus.NewRegistry()
+	clusterRegistry.MustRegister(newClusterCollector(scraped.last(), s.config.Metrics.Namespace, s.logger))
+	clusterHandler := promhttp.HandlerFor(clusterRegistry, promhttp.HandlerOpts{})
+	mux.Handle("/metrics/cluster", s.addMiddleware(s.limitConcurrentScrapes(clusterHandler)))
+	mux.Handle(databaseMetricsPath, s.addMiddleware(s.limitConcurrentScrapes(http.HandlerFunc(s.databaseMetricsHandler))))
 	mux.HandleFunc("/health", s.healthHandler)
-	mux.HandleFunc("/", s.rootHandler)
+	mux.HandleFunc("/-/healthy", s.livenessHandler)
+	mux.HandleFunc("/-/ready", s.readinessHandler)
+	mux.HandleFunc("/version", s.versionHandler)
+	if s.config.Server.LandingPage {
+		mux.HandleFunc("/", s.rootHandler)
+	}
 
-	return s.addMiddleware(mux)
-}
+	s.registerAdminHandlers(mux)
 
-func (s *Server) healthHandler(w http.ResponseWriter, r *http.Request) {
-	if err := s.connectionManager.HealthCheck(r.Context()); err != nil {
-		s.logger.Error("Health check failed", zap.Error(err))
-		http.Error(w, "Health check failed", http.StatusServiceUnavailable)
-		return
+	if s.config.Server.EnablePprof {
+		s.registerPprofHandlers(mux)
+		if s.config.Server.AdminToken == "" {
+			s.logger.Warn("pprof endpoints enabled without admin token; they will reject all requests")
+		}
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(`{"status":"healthy"}`))
-}
+	if s.connectionManager.CommandAudit() != nil {
+		mux.Handle("/debug/commands", s.requireAdminAuth(http.HandlerFunc(s.commandAuditHandler)))
+	}
 
-func (s *Server) rootHandler(w http.ResponseWriter, r *http.Request) {
-	if r.URL.Path != "/" {
-		http.NotFound(w, r)
-		return
-	}
-
-	w.Header().Set("Content-Type", "text/html")
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(`<!DOCTYPE html>
-<html>
-<head>
-    <title>MongoDB Exporter</title>
-    <style>
-        body { font-family: Arial, sans-serif; margin: 40px; }
-        .container { max-width: 800px; margin: 0 auto; }
-        h1 { color: #333; }
-        .endpoint { background: #f5f5f5; padding: 10px; margin: 10px 0; border-radius: 5px; }
-        .endpoint h3 { margin: 0 0 10px 0; color: #666; }
-        .endpoint p { margin: 5px 0; }
-        a { color: #007cba; text-decoration: none; }
-        a:hover { text-decoration: underline; }
-    </style>
-</head>
-<body>
-    <div class="container">
-        <h1>MongoDB Exporter</h1>
-        <p>A Prometheus exporter for MongoDB metrics.</p>
-        
-        <div class="endpoint">
-            <h3>Available Endpoints:</h3>
-            <p><strong>Metrics:</strong> <a href="/metrics">/metrics</a> - Prometheus metrics</p>
-            <p><strong>Health:</strong> <a href="/health">/health</a> - Health check endpoint</p>
-        </div>
-        
-        <div class="endpoint">
-            <h3>Usage:</h3>
-            <p>Add this exporter to your Prometheus configuration:</p>
-            <pre>scrape_configs:
-  - job_name: 'mongodb'
-    static_configs:
-      - targets: ['localhost:8080']</pre>
-        </div>
-    </div>
-</body>
-</html>`))
+	return s.addMiddleware(s.rateLimit(mux))
 }
 
 func (s *Server) addMiddleware(handler http.Handler) http.Handler {
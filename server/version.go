@@ -0,0 +1,28 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"runtime"
+)
+
+// BuildInfo identifies the running exporter binary
+type BuildInfo struct {
+	Version   string `json:"version"`
+	GitCommit string `json:"git_commit"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+}
+
+// SetBuildInfo records the build details served at /version
+func (s *Server) SetBuildInfo(info BuildInfo) {
+	if info.GoVersion == "" {
+		info.GoVersion = runtime.Version()
+	}
+	s.buildInfo = info
+}
+
+func (s *Server) versionHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s.buildInfo)
+}